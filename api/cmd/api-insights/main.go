@@ -20,11 +20,13 @@ import (
 	"github.com/cisco-developer/api-insights/api/internal/db"
 	"github.com/cisco-developer/api-insights/api/internal/endpoints"
 	"github.com/cisco-developer/api-insights/api/internal/info"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
 	"github.com/cisco-developer/api-insights/api/internal/models"
 	"github.com/cisco-developer/api-insights/api/pkg/analyzer/completeness"
 	"github.com/cisco-developer/api-insights/api/pkg/analyzer/security"
 	"github.com/cisco-developer/api-insights/api/pkg/apiclarity"
 	openapidiff "github.com/cisco-developer/api-insights/api/pkg/differ/openapi-diff"
+	"github.com/cisco-developer/api-insights/api/pkg/mailer"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
 	"github.com/emicklei/go-restful/v3"
 	"github.com/urfave/cli/v2"
@@ -64,6 +66,8 @@ func App(version string) *cli.App {
 	additionalFlags = shared.MergeFlags(additionalFlags, security.Flags())
 	additionalFlags = shared.MergeFlags(additionalFlags, info.Flags())
 	additionalFlags = shared.MergeFlags(additionalFlags, models.Flags())
+	additionalFlags = shared.MergeFlags(additionalFlags, mailer.Flags())
+	additionalFlags = shared.MergeFlags(additionalFlags, middleware.Flags())
 
 	return shared.HTTPApp(config, additionalFlags)
 }
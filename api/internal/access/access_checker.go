@@ -27,6 +27,9 @@ type Checker interface {
 }
 
 type Input struct {
+	// User identifies the user of the request (e.g. the email or subject of its token),
+	// as verified by the authentication middleware setting the Input.
+	User      string
 	UserRoles models.Roles
 	Resources []*models.Resource
 }
@@ -0,0 +1,132 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"time"
+)
+
+// AuditDAO is the interface to access database.
+// The audit log is append-only: events can only be created & read.
+type AuditDAO interface {
+	List(context context.Context, filter *ListFilter, from, to *time.Time) ([]*models.AuditEvent, int, error)
+	Create(context context.Context, event *models.AuditEvent) error
+	Get(context context.Context, id string) (*models.AuditEvent, error)
+}
+
+// NewAuditDAO create AuditDAO
+var NewAuditDAO = func(config *shared.AppConfig) (AuditDAO, error) {
+	client, err := NewDBClient(config)
+	if err != nil {
+		return nil, err
+	}
+	err = client.AutoMigrate(models.AuditEvent{})
+	if err != nil {
+		return nil, err
+	}
+
+	dao := &blobAuditDAO{client: client, config: config}
+	return dao, nil
+}
+
+type blobAuditDAO struct {
+	client *Client
+	config *shared.AppConfig
+}
+
+// Create object in database
+func (dao *blobAuditDAO) Create(ctx context.Context, event *models.AuditEvent) error {
+	span, ctx := shared.StartSpan(ctx, "auditEvent.id", event.GetID())
+	defer span.Finish()
+
+	err := dao.client.WithContext(ctx).Create(event).Error
+	if err != nil {
+		shared.LogErrorf("failed to create audit event for %s %s: %s", event.ResourceType, event.ResourceID, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Get an object with specified id from database
+func (dao *blobAuditDAO) Get(ctx context.Context, id string) (*models.AuditEvent, error) {
+	span, ctx := shared.StartSpan(ctx, "auditEvent.id", id)
+	defer span.Finish()
+
+	event := &models.AuditEvent{}
+	err := dao.client.WithContext(ctx).Where("id = ?", id).First(event).Error
+	if err != nil {
+		shared.LogErrorf("failed to get audit event %s: %s", id, err.Error())
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// List all objects in database with specified filter, optionally created within [from, to], along with their total number
+// regardless of pagination. Objects are listed by descending creation time by default.
+func (dao *blobAuditDAO) List(ctx context.Context, filter *ListFilter, from, to *time.Time) ([]*models.AuditEvent, int, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	shared.LogDebugf("fetching audit events: %#v ...", filter)
+
+	var events []*models.AuditEvent
+	db := dao.client.WithContext(ctx).Table(models.AuditEventTableName)
+	query := map[string]interface{}{}
+	for k, v := range filter.Indexes {
+		query[k] = v
+	}
+	if len(query) != 0 {
+		db = db.Where(query)
+	}
+	if from != nil {
+		db = db.Where("created_at >= ?", *from)
+	}
+	if to != nil {
+		db = db.Where("created_at <= ?", *to)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for _, sorter := range filter.Sorters {
+		db = db.Order(sorter.OrderBy())
+	}
+	if len(filter.Sorters) == 0 {
+		db = db.Order("created_at desc")
+	}
+	db = db.Order("id desc")
+
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		db = db.Offset(filter.Offset)
+	}
+	err := db.Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, int(total), nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+)
+
+// ConsumerDAO is the interface to access database
+type ConsumerDAO interface {
+	List(context context.Context, filter *ListFilter, serviceIDs []string) ([]*models.Consumer, error)
+	Save(context context.Context, consumer *models.Consumer) error
+	Get(context context.Context, id string) (*models.Consumer, error)
+	GetByName(context context.Context, serviceID, name string) (*models.Consumer, error)
+	Delete(context context.Context, id string) error
+}
+
+// NewConsumerDAO create ConsumerDAO
+var NewConsumerDAO = func(config *shared.AppConfig) (ConsumerDAO, error) {
+	client, err := NewDBClient(config)
+	if err != nil {
+		return nil, err
+	}
+	err = client.AutoMigrate(models.Consumer{})
+	if err != nil {
+		return nil, err
+	}
+
+	dao := &blobConsumerDAO{client: client, config: config}
+	return dao, nil
+}
+
+type blobConsumerDAO struct {
+	client *Client
+	config *shared.AppConfig
+}
+
+// Save object to database
+func (dao *blobConsumerDAO) Save(ctx context.Context, consumer *models.Consumer) error {
+	span, ctx := shared.StartSpan(ctx, "consumer.id", consumer.GetID())
+	defer span.Finish()
+
+	err := dao.client.WithContext(ctx).Save(consumer).Error
+	if err != nil {
+		shared.LogErrorf("failed to save consumer %s: %s", consumer.GetID(), err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Get an object with specified id from database
+func (dao *blobConsumerDAO) Get(ctx context.Context, id string) (*models.Consumer, error) {
+	span, ctx := shared.StartSpan(ctx, "consumer.id", id)
+	defer span.Finish()
+
+	consumer := &models.Consumer{}
+	err := dao.client.WithContext(ctx).Where("id = ?", id).First(consumer).Error
+	if err != nil {
+		shared.LogErrorf("failed to get consumer %s: %s", id, err.Error())
+		return nil, err
+	}
+
+	return consumer, nil
+}
+
+// GetByName gets the consumer with specified name of the service with specified serviceID from database
+func (dao *blobConsumerDAO) GetByName(ctx context.Context, serviceID, name string) (*models.Consumer, error) {
+	span, ctx := shared.StartSpan(ctx, "service.id", serviceID)
+	defer span.Finish()
+
+	consumer := &models.Consumer{}
+	err := dao.client.WithContext(ctx).Where("service_id = ? AND name = ?", serviceID, name).First(consumer).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return consumer, nil
+}
+
+// Delete an object with specified id from database
+func (dao *blobConsumerDAO) Delete(ctx context.Context, id string) error {
+	span, ctx := shared.StartSpan(ctx, "consumer.id", id)
+	defer span.Finish()
+
+	consumer := models.Consumer{}
+	result := dao.client.WithContext(ctx).Where("id = ?", id).Delete(consumer)
+	if result.Error != nil || result.RowsAffected == 0 {
+		shared.LogErrorf("could not find object with %s: %v", id, result.Error)
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// List all objects in database with specified filter, of the services with serviceIDs if not nil
+func (dao *blobConsumerDAO) List(ctx context.Context, filter *ListFilter, serviceIDs []string) ([]*models.Consumer, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	shared.LogDebugf("fetching consumers: %#v ...", filter)
+
+	var consumers []*models.Consumer
+	db := dao.client.WithContext(ctx).Table(models.ConsumerTableName)
+	query := map[string]interface{}{}
+	for k, v := range filter.Indexes {
+		query[k] = v
+	}
+	if len(query) != 0 {
+		db = db.Where(query)
+	}
+	if serviceIDs != nil {
+		db = db.Where("service_id IN ?", serviceIDs)
+	}
+
+	for _, sorter := range filter.Sorters {
+		db = db.Order(sorter.OrderBy())
+	}
+
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		db = db.Offset(filter.Offset)
+	}
+	err := db.Find(&consumers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return consumers, nil
+}
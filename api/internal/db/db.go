@@ -17,8 +17,11 @@
 package db
 
 import (
+	"errors"
 	"fmt"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
 	"github.com/urfave/cli/v2"
 	"github.com/urfave/cli/v2/altsrc"
 	"gorm.io/driver/mysql"
@@ -32,6 +35,9 @@ import (
 const (
 	dbTypeMySQL    = "mysql"
 	dbTypePostgres = "postgres"
+
+	mysqlErrDuplicateEntry  = 1062
+	postgresUniqueViolation = "23505"
 )
 
 var (
@@ -166,3 +172,16 @@ func initPostgres() (gorm.Dialector, string) {
 
 	return postgres.Open(dsn), safeDSN
 }
+
+// isDuplicateError returns true if err is a unique constraint violation of either db type.
+func isDuplicateError(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDuplicateEntry
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresUniqueViolation
+	}
+	return false
+}
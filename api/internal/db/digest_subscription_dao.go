@@ -0,0 +1,160 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"time"
+)
+
+// DigestSubscriptionDAO is the interface to access database
+type DigestSubscriptionDAO interface {
+	List(context context.Context, filter *ListFilter) ([]*models.DigestSubscription, error)
+	Save(context context.Context, subscription *models.DigestSubscription) error
+	Get(context context.Context, id string) (*models.DigestSubscription, error)
+	Delete(context context.Context, id string) error
+	Claim(context context.Context, subscription *models.DigestSubscription, now, until time.Time) (bool, error)
+}
+
+// NewDigestSubscriptionDAO create DigestSubscriptionDAO
+var NewDigestSubscriptionDAO = func(config *shared.AppConfig) (DigestSubscriptionDAO, error) {
+	client, err := NewDBClient(config)
+	if err != nil {
+		return nil, err
+	}
+	err = client.AutoMigrate(models.DigestSubscription{})
+	if err != nil {
+		return nil, err
+	}
+
+	dao := &blobDigestSubscriptionDAO{client: client, config: config}
+	return dao, nil
+}
+
+type blobDigestSubscriptionDAO struct {
+	client *Client
+	config *shared.AppConfig
+}
+
+// Save object to database
+func (dao *blobDigestSubscriptionDAO) Save(ctx context.Context, subscription *models.DigestSubscription) error {
+	span, ctx := shared.StartSpan(ctx, "digestSubscription.id", subscription.GetID())
+	defer span.Finish()
+
+	err := dao.client.WithContext(ctx).Save(subscription).Error
+	if err != nil {
+		shared.LogErrorf("failed to save digest subscription %s: %s", subscription.GetID(), err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Get an object with specified id from database
+func (dao *blobDigestSubscriptionDAO) Get(ctx context.Context, id string) (*models.DigestSubscription, error) {
+	span, ctx := shared.StartSpan(ctx, "digestSubscription.id", id)
+	defer span.Finish()
+
+	subscription := &models.DigestSubscription{}
+	err := dao.client.WithContext(ctx).Where("id = ?", id).First(subscription).Error
+	if err != nil {
+		shared.LogErrorf("failed to get digest subscription %s: %s", id, err.Error())
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// Delete an object with specified id from database
+func (dao *blobDigestSubscriptionDAO) Delete(ctx context.Context, id string) error {
+	span, ctx := shared.StartSpan(ctx, "digestSubscription.id", id)
+	defer span.Finish()
+
+	subscription := models.DigestSubscription{}
+	result := dao.client.WithContext(ctx).Where("id = ?", id).Delete(subscription)
+	if result.Error != nil || result.RowsAffected == 0 {
+		shared.LogErrorf("could not find object with %s: %v", id, result.Error)
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// List all objects in database with specified filter
+func (dao *blobDigestSubscriptionDAO) List(ctx context.Context, filter *ListFilter) ([]*models.DigestSubscription, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	shared.LogDebugf("fetching digest subscriptions: %#v ...", filter)
+
+	var subscriptions []*models.DigestSubscription
+	db := dao.client.WithContext(ctx).Table(models.DigestSubscriptionTableName)
+	query := map[string]interface{}{}
+	for k, v := range filter.Indexes {
+		switch k {
+		case "active":
+			query[k] = v == "true"
+		default:
+			query[k] = v
+		}
+	}
+	if len(query) != 0 {
+		db = db.Where(query)
+	}
+
+	for _, sorter := range filter.Sorters {
+		db = db.Order(sorter.OrderBy())
+	}
+
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		db = db.Offset(filter.Offset)
+	}
+	err := db.Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// Claim claims the digest of subscription due at now until then, so that a single replica sends it: it returns false
+// if the digest isn't due anymore, e.g. was sent, or is claimed by another replica.
+func (dao *blobDigestSubscriptionDAO) Claim(ctx context.Context, subscription *models.DigestSubscription, now, until time.Time) (bool, error) {
+	span, ctx := shared.StartSpan(ctx, "digestSubscription.id", subscription.GetID())
+	defer span.Finish()
+
+	dueBefore := now.Add(-subscription.Period())
+	result := dao.client.WithContext(ctx).Model(&models.DigestSubscription{}).
+		Where("id = ? AND active = ?", subscription.ID, true).
+		Where("(claimed_until IS NULL OR claimed_until <= ?)", now).
+		Where("((last_sent_at IS NULL AND created_at <= ?) OR last_sent_at <= ?)", dueBefore, dueBefore).
+		UpdateColumn("claimed_until", until)
+	if result.Error != nil {
+		shared.LogErrorf("failed to claim digest subscription %s: %s", subscription.GetID(), result.Error.Error())
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	subscription.ClaimedUntil = &until
+	return true, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/gorm"
+)
+
+// DuplicateSnapshotDAO is the interface to access database
+type DuplicateSnapshotDAO interface {
+	Save(context context.Context, snapshot *models.DuplicateSnapshot) error
+	Get(context context.Context, id string) (*models.DuplicateSnapshot, error)
+}
+
+// NewDuplicateSnapshotDAO create DuplicateSnapshotDAO
+var NewDuplicateSnapshotDAO = func(config *shared.AppConfig) (DuplicateSnapshotDAO, error) {
+	client, err := NewDBClient(config)
+	if err != nil {
+		return nil, err
+	}
+	err = client.AutoMigrate(models.DuplicateSnapshot{})
+	if err != nil {
+		return nil, err
+	}
+
+	dao := &blobDuplicateSnapshotDAO{client: client, config: config}
+	return dao, nil
+}
+
+type blobDuplicateSnapshotDAO struct {
+	client *Client
+	config *shared.AppConfig
+}
+
+// Save object to database
+func (dao *blobDuplicateSnapshotDAO) Save(ctx context.Context, snapshot *models.DuplicateSnapshot) error {
+	span, ctx := shared.StartSpan(ctx, "duplicateSnapshot.id", snapshot.ID)
+	defer span.Finish()
+
+	err := dao.client.WithContext(ctx).Save(snapshot).Error
+	if err != nil {
+		shared.LogErrorf("failed to save duplicate snapshot %s: %s", snapshot.ID, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Get an object with specified id from database, or ErrNotFound if none
+func (dao *blobDuplicateSnapshotDAO) Get(ctx context.Context, id string) (*models.DuplicateSnapshot, error) {
+	span, ctx := shared.StartSpan(ctx, "duplicateSnapshot.id", id)
+	defer span.Finish()
+
+	snapshot := &models.DuplicateSnapshot{}
+	err := dao.client.WithContext(ctx).Where("id = ?", id).First(snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		shared.LogErrorf("failed to get duplicate snapshot %s: %s", id, err.Error())
+		return nil, err
+	}
+
+	return snapshot, nil
+}
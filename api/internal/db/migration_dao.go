@@ -0,0 +1,125 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// MigrationDAO is the interface to access database
+type MigrationDAO interface {
+	Get(context context.Context, id string) (*models.Migration, error)
+	Claim(context context.Context, id string, now, until time.Time) (bool, error)
+	Complete(context context.Context, id string, at time.Time) error
+	Release(context context.Context, id string) error
+}
+
+// NewMigrationDAO create MigrationDAO
+var NewMigrationDAO = func(config *shared.AppConfig) (MigrationDAO, error) {
+	client, err := NewDBClient(config)
+	if err != nil {
+		return nil, err
+	}
+	err = client.AutoMigrate(models.Migration{})
+	if err != nil {
+		return nil, err
+	}
+
+	dao := &blobMigrationDAO{client: client, config: config}
+	return dao, nil
+}
+
+type blobMigrationDAO struct {
+	client *Client
+	config *shared.AppConfig
+}
+
+// Get an object with specified id from database, or ErrNotFound if none
+func (dao *blobMigrationDAO) Get(ctx context.Context, id string) (*models.Migration, error) {
+	span, ctx := shared.StartSpan(ctx, "migration.id", id)
+	defer span.Finish()
+
+	migration := &models.Migration{}
+	err := dao.client.WithContext(ctx).Where("id = ?", id).First(migration).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		shared.LogErrorf("failed to get migration %s: %s", id, err.Error())
+		return nil, err
+	}
+
+	return migration, nil
+}
+
+// Claim claims the migration with specified id at now until then, so that a single replica runs it: it returns false
+// if the migration is completed, or claimed by another replica.
+func (dao *blobMigrationDAO) Claim(ctx context.Context, id string, now, until time.Time) (bool, error) {
+	span, ctx := shared.StartSpan(ctx, "migration.id", id)
+	defer span.Finish()
+
+	err := dao.client.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Migration{ID: id}).Error
+	if err != nil {
+		shared.LogErrorf("failed to create migration %s: %s", id, err.Error())
+		return false, err
+	}
+	result := dao.client.WithContext(ctx).Model(&models.Migration{}).
+		Where("id = ? AND completed_at IS NULL", id).
+		Where("(claimed_until IS NULL OR claimed_until <= ?)", now).
+		UpdateColumn("claimed_until", until)
+	if result.Error != nil {
+		shared.LogErrorf("failed to claim migration %s: %s", id, result.Error.Error())
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// Complete marks the migration with specified id completed at the at time.
+func (dao *blobMigrationDAO) Complete(ctx context.Context, id string, at time.Time) error {
+	span, ctx := shared.StartSpan(ctx, "migration.id", id)
+	defer span.Finish()
+
+	err := dao.client.WithContext(ctx).Model(&models.Migration{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"completed_at": at, "claimed_until": nil}).Error
+	if err != nil {
+		shared.LogErrorf("failed to complete migration %s: %s", id, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Release releases the claim of the migration with specified id, e.g. as it failed, so that it's retried.
+func (dao *blobMigrationDAO) Release(ctx context.Context, id string) error {
+	span, ctx := shared.StartSpan(ctx, "migration.id", id)
+	defer span.Finish()
+
+	err := dao.client.WithContext(ctx).Model(&models.Migration{}).Where("id = ?", id).UpdateColumn("claimed_until", nil).Error
+	if err != nil {
+		shared.LogErrorf("failed to release migration %s: %s", id, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -34,6 +34,9 @@ const (
 // ErrNotFound represents a not found error
 var ErrNotFound = errors.New("object not found")
 
+// ErrDuplicate represents a unique constraint violation error
+var ErrDuplicate = errors.New("duplicate object")
+
 // ListFilter represents request for List operation
 type ListFilter struct {
 	Model   models.ModelObject
@@ -20,6 +20,7 @@ import (
 	"context"
 	"github.com/cisco-developer/api-insights/api/internal/models"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/gorm"
 )
 
 // SpecAnalysisDAO is the interface to access database
@@ -37,7 +38,7 @@ var NewSpecAnalysisDAO = func(config *shared.AppConfig) (SpecAnalysisDAO, error)
 	if err != nil {
 		return nil, err
 	}
-	err = client.AutoMigrate(models.SpecAnalysis{})
+	err = client.AutoMigrate(models.SpecAnalysis{}, models.SpecTrend{})
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +57,13 @@ func (dao *blobSpecAnalysisDAO) Save(ctx context.Context, specAnalysis *models.S
 	span, ctx := shared.StartSpan(ctx, "specAnalysis.id", specAnalysis.GetID())
 	defer span.Finish()
 
-	err := dao.client.WithContext(ctx).Save(specAnalysis).Error
+	// The spec trend is updated along with the analysis, so that it never lags behind.
+	err := dao.client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(specAnalysis).Error; err != nil {
+			return err
+		}
+		return saveSpecTrendAnalysis(tx, specAnalysis)
+	})
 	if err != nil {
 		shared.LogErrorf("failed to save specAnalysis %s: %s", specAnalysis.GetID(), err.Error())
 		return err
@@ -142,5 +149,11 @@ func (dao *blobSpecAnalysisDAO) BatchDeleteBySpecID(ctx context.Context, specID
 		return err
 	}
 
+	err = dao.client.WithContext(ctx).Where("spec_id = ?", specID).Delete(models.SpecTrend{}).Error
+	if err != nil {
+		shared.LogErrorf("failed to delete spec %s trend: %s", specID, err.Error())
+		return err
+	}
+
 	return nil
 }
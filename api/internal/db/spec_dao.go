@@ -20,6 +20,8 @@ import (
 	"context"
 	"github.com/cisco-developer/api-insights/api/internal/models"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // SpecDAO is the interface to access database
@@ -28,15 +30,19 @@ type SpecDAO interface {
 	Save(context context.Context, spec *models.Spec) error
 	Get(context context.Context, id string, withDoc bool) (*models.Spec, error)
 	Delete(context context.Context, id string) error
+	Rehash(context context.Context) error
 }
 
+// specRehashBatchSize is the number of specs rehashed at a time (see blobSpecDAO.Rehash).
+const specRehashBatchSize = 100
+
 // NewSpecDAO create SpecDAO
 var NewSpecDAO = func(config *shared.AppConfig) (SpecDAO, error) {
 	client, err := NewDBClient(config)
 	if err != nil {
 		return nil, err
 	}
-	err = client.AutoMigrate(models.Spec{})
+	err = client.AutoMigrate(models.Spec{}, models.SpecBlob{}, models.SpecTrend{}, models.SpecSearchEntry{}, models.SpecEndpoint{})
 	if err != nil {
 		return nil, err
 	}
@@ -55,12 +61,61 @@ func (dao *blobSpecDAO) Save(ctx context.Context, spec *models.Spec) error {
 	span, ctx := shared.StartSpan(ctx, "spec.id", spec.GetID())
 	defer span.Finish()
 
-	err := dao.client.WithContext(ctx).Save(spec).Error
-	if err != nil {
+	var omit []string
+
+	// Content-addressed specs share a single SpecBlob per Spec.DocHash, instead of storing their own copy of Spec.Doc.
+	if spec.DocHash != "" && spec.Doc != nil {
+		blob, err := models.NewSpecBlob(spec)
+		if err != nil {
+			return err
+		}
+		err = dao.client.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(blob).Error
+		if err != nil {
+			shared.LogErrorf("failed to save spec %s blob %s: %s", spec.GetID(), spec.DocHash, err.Error())
+			return err
+		}
+		omit = append(omit, "doc", "doc_compressed")
+	}
+	if spec.OriginalDocHash != "" && spec.OriginalDoc != nil {
+		blob, err := models.NewOriginalSpecBlob(spec)
+		if err != nil {
+			return err
+		}
+		err = dao.client.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(blob).Error
+		if err != nil {
+			shared.LogErrorf("failed to save spec %s original blob %s: %s", spec.GetID(), spec.OriginalDocHash, err.Error())
+			return err
+		}
+	}
+
+	// The spec trend is updated along with the spec, so that it never lags behind.
+	err := dao.client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(omit...).Save(spec).Error; err != nil {
+			return err
+		}
+		return saveSpecTrendSpec(tx, spec)
+	})
+	if isDuplicateError(err) {
+		// Another spec of the service has an identical doc (see Spec.DedupeHash).
+		return ErrDuplicate
+	} else if err != nil {
 		shared.LogErrorf("failed to save spec %s: %s", spec.GetID(), err.Error())
 		return err
 	}
 
+	// The search index & endpoint catalog are secondary, so failing to update them doesn't fail saving the spec.
+	if err := dao.indexSpecSearch(ctx, spec); err != nil {
+		shared.LogErrorf("failed to index spec %s for search: %s", spec.GetID(), err.Error())
+	}
+	if err := dao.catalogSpecEndpoints(ctx, spec); err == nil {
+		err = dao.updateSpecEndpointsLatest(ctx, spec.ServiceID)
+		if err != nil {
+			shared.LogErrorf("failed to update service %s latest endpoints: %s", spec.ServiceID, err.Error())
+		}
+	} else {
+		shared.LogErrorf("failed to catalog spec %s endpoints: %s", spec.GetID(), err.Error())
+	}
+
 	return nil
 }
 
@@ -73,7 +128,7 @@ func (dao *blobSpecDAO) Get(ctx context.Context, id string, withDoc bool) (*mode
 	db := dao.client.WithContext(ctx).Table(models.SpecTableName)
 
 	if !withDoc {
-		db.Omit("doc")
+		db.Omit("doc", "sources")
 	}
 
 	err := db.Where("id = ?", id).First(spec).Error
@@ -82,6 +137,12 @@ func (dao *blobSpecDAO) Get(ctx context.Context, id string, withDoc bool) (*mode
 		return nil, err
 	}
 
+	if withDoc {
+		if err := dao.loadBlobs(ctx, spec); err != nil {
+			return nil, err
+		}
+	}
+
 	return spec, nil
 }
 
@@ -90,6 +151,12 @@ func (dao *blobSpecDAO) Delete(ctx context.Context, id string) error {
 	span, ctx := shared.StartSpan(ctx, "spec.id", id)
 	defer span.Finish()
 
+	var hashes struct {
+		DocHash         string
+		OriginalDocHash string
+	}
+	_ = dao.client.WithContext(ctx).Table(models.SpecTableName).Select("doc_hash", "original_doc_hash").Where("id = ?", id).Scan(&hashes).Error
+
 	spec := models.Spec{}
 	err := dao.client.WithContext(ctx).Where("id = ?", id).Delete(spec).Error
 	if err != nil {
@@ -97,6 +164,138 @@ func (dao *blobSpecDAO) Delete(ctx context.Context, id string) error {
 		return ErrNotFound
 	}
 
+	var indexed struct {
+		ServiceID string
+	}
+	_ = dao.client.WithContext(ctx).Table(models.SpecSearchEntryTableName).Select("service_id").Where("spec_id = ? AND kind = ?", id, models.SpecSearchKindSpec).Scan(&indexed).Error
+
+	err = dao.client.WithContext(ctx).Where("spec_id = ?", id).Delete(models.SpecTrend{}).Error
+	if err != nil {
+		shared.LogErrorf("failed to delete spec %s trend: %s", id, err.Error())
+	}
+
+	var serviceIDs []string
+	_ = dao.client.WithContext(ctx).Table(models.SpecEndpointTableName).Where("spec_id = ?", id).Limit(1).Pluck("service_id", &serviceIDs).Error
+	for _, serviceID := range serviceIDs {
+		err = dao.client.WithContext(ctx).Where("spec_id = ?", id).Delete(models.SpecEndpoint{}).Error
+		if err == nil {
+			err = dao.updateSpecEndpointsLatest(ctx, serviceID)
+		}
+		if err != nil {
+			shared.LogErrorf("failed to delete spec %s endpoints: %s", id, err.Error())
+		}
+	}
+
+	// Index the spec now the latest of its service instead.
+	if indexed.ServiceID != "" {
+		if err := dao.reindexSpecSearch(ctx, indexed.ServiceID); err != nil {
+			shared.LogErrorf("failed to reindex service %s specs for search: %s", indexed.ServiceID, err.Error())
+		}
+	}
+
+	dao.deleteUnreferencedBlobs(ctx, id, hashes.DocHash, hashes.OriginalDocHash)
+
+	return nil
+}
+
+// deleteUnreferencedBlobs deletes the spec blobs with the given hashes, formerly referenced by spec id, once no other spec references them.
+func (dao *blobSpecDAO) deleteUnreferencedBlobs(ctx context.Context, id string, hashes ...string) {
+	for _, hash := range hashes {
+		if hash == "" {
+			continue
+		}
+		var count int64
+		err := dao.client.WithContext(ctx).Table(models.SpecTableName).Where("doc_hash = ? OR original_doc_hash = ?", hash, hash).Count(&count).Error
+		if err == nil && count == 0 {
+			err = dao.client.WithContext(ctx).Where("hash = ?", hash).Delete(models.SpecBlob{}).Error
+		}
+		if err != nil {
+			shared.LogErrorf("failed to delete spec %s blob %s: %s", id, hash, err.Error())
+		}
+	}
+}
+
+// Rehash re-derives Spec.DocHash of the specs hashed with an older models.SpecDocHashVersion (see models.Spec.SetDocHash),
+// re-keying their blobs, & sets Spec.DedupeHash of the earliest spec of every service with a given doc.
+// Specs that fail to rehash are logged & skipped, so that they don't block the others.
+// It's run once per models.SpecDocHashVersion by a single replica (see models.SpecRehashMigrationID).
+func (dao *blobSpecDAO) Rehash(ctx context.Context) error {
+	skipped := 0
+	for {
+		var specs []*models.Spec
+		err := dao.client.WithContext(ctx).
+			Where("doc_hash_version < ? OR doc_hash_version IS NULL", models.SpecDocHashVersion).
+			Order("created_at asc").Order("id asc").
+			Offset(skipped).Limit(specRehashBatchSize).
+			Find(&specs).Error
+		if err != nil {
+			return err
+		}
+		if len(specs) == 0 {
+			return nil
+		}
+		if err := dao.loadBlobs(ctx, specs...); err != nil {
+			return err
+		}
+
+		for _, spec := range specs {
+			if err := dao.rehashSpec(ctx, spec); err != nil {
+				shared.LogErrorf("failed to rehash spec %s: %s", spec.GetID(), err.Error())
+				skipped++
+			}
+		}
+		shared.LogInfof("rehashed %d spec(s)", len(specs))
+	}
+}
+
+func (dao *blobSpecDAO) rehashSpec(ctx context.Context, spec *models.Spec) error {
+	oldDocHash, oldOriginalDocHash := spec.DocHash, spec.OriginalDocHash
+	columns := map[string]interface{}{"doc_hash_version": models.SpecDocHashVersion}
+
+	if spec.Doc != nil && len(*spec.Doc) > 0 {
+		if err := spec.SetDocHash(); err != nil {
+			return err
+		}
+		// Specs stored before content-addressing keep their own copy of Spec.Doc, hence need no blob.
+		blobs := make([]*models.SpecBlob, 0, 2)
+		if oldDocHash != "" && oldDocHash != spec.DocHash {
+			blob, err := models.NewSpecBlob(spec)
+			if err != nil {
+				return err
+			}
+			blobs = append(blobs, blob)
+		}
+		if oldOriginalDocHash != "" && oldOriginalDocHash != spec.OriginalDocHash && spec.OriginalDoc != nil {
+			blob, err := models.NewOriginalSpecBlob(spec)
+			if err != nil {
+				return err
+			}
+			blobs = append(blobs, blob)
+		}
+		for _, blob := range blobs {
+			if err := dao.client.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(blob).Error; err != nil {
+				return err
+			}
+		}
+		columns["doc_hash"] = spec.DocHash
+		columns["original_doc_hash"] = spec.OriginalDocHash
+	}
+
+	err := dao.client.WithContext(ctx).Model(&models.Spec{}).Where("id = ?", spec.ID).UpdateColumns(columns).Error
+	if err != nil {
+		return err
+	}
+	if spec.DocHash != "" {
+		// A later identical spec is a duplicate stored before uploads were deduplicated, hence keeps no Spec.DedupeHash.
+		err = dao.client.WithContext(ctx).Model(&models.Spec{}).Where("id = ?", spec.ID).UpdateColumn("dedupe_hash", spec.DocHash).Error
+		if err != nil && !isDuplicateError(err) {
+			return err
+		}
+	}
+
+	if oldDocHash != spec.DocHash || oldOriginalDocHash != spec.OriginalDocHash {
+		dao.deleteUnreferencedBlobs(ctx, spec.ID, oldDocHash, oldOriginalDocHash)
+	}
 	return nil
 }
 
@@ -111,7 +310,7 @@ func (dao *blobSpecDAO) List(ctx context.Context, filter *ListFilter, withDoc bo
 	db := dao.client.WithContext(ctx).Table(models.SpecTableName)
 
 	if !withDoc {
-		db.Omit("doc", "doc_compressed")
+		db.Omit("doc", "doc_compressed", "sources", "validation_errors")
 	}
 
 	query := map[string]interface{}{}
@@ -137,5 +336,49 @@ func (dao *blobSpecDAO) List(ctx context.Context, filter *ListFilter, withDoc bo
 		return nil, err
 	}
 
+	if withDoc {
+		if err := dao.loadBlobs(ctx, specs...); err != nil {
+			return nil, err
+		}
+	}
+
 	return specs, nil
 }
+
+// loadBlobs populates Spec.Doc (& Spec.OriginalDoc) of content-addressed specs from their SpecBlob(s).
+func (dao *blobSpecDAO) loadBlobs(ctx context.Context, specs ...*models.Spec) error {
+	var hashes []string
+	for _, spec := range specs {
+		if spec.Doc == nil && spec.DocHash != "" {
+			hashes = append(hashes, spec.DocHash)
+		}
+		if spec.OriginalDoc == nil && spec.OriginalDocHash != "" {
+			hashes = append(hashes, spec.OriginalDocHash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	var blobs []*models.SpecBlob
+	err := dao.client.WithContext(ctx).Where("hash IN ?", hashes).Find(&blobs).Error
+	if err != nil {
+		shared.LogErrorf("failed to get spec blobs: %s", err.Error())
+		return err
+	}
+
+	blobsByHash := make(map[string]*models.SpecBlob, len(blobs))
+	for _, blob := range blobs {
+		blobsByHash[blob.Hash] = blob
+	}
+	for _, spec := range specs {
+		if blob, ok := blobsByHash[spec.DocHash]; ok && spec.Doc == nil {
+			spec.Doc = blob.Doc
+		}
+		if blob, ok := blobsByHash[spec.OriginalDocHash]; ok && spec.OriginalDoc == nil {
+			spec.OriginalDoc = blob.Doc
+		}
+	}
+
+	return nil
+}
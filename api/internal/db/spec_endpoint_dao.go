@@ -0,0 +1,207 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const specEndpointsBatchSize = 500
+
+// SpecEndpointDAO is the interface to access database
+type SpecEndpointDAO interface {
+	List(context context.Context, filter *ListFilter, endpointFilter *models.SpecEndpointFilter) ([]*models.SpecEndpoint, int, error)
+	IndexedServiceIDs(context context.Context) (map[string]bool, error)
+	Reindex(context context.Context, serviceID string) error
+}
+
+// NewSpecEndpointDAO create SpecEndpointDAO
+var NewSpecEndpointDAO = func(config *shared.AppConfig) (SpecEndpointDAO, error) {
+	client, err := NewDBClient(config)
+	if err != nil {
+		return nil, err
+	}
+	err = client.AutoMigrate(models.SpecEndpoint{})
+	if err != nil {
+		return nil, err
+	}
+
+	dao := &blobSpecEndpointDAO{client: client, config: config}
+	return dao, nil
+}
+
+type blobSpecEndpointDAO struct {
+	client *Client
+	config *shared.AppConfig
+}
+
+// List all objects in database with specified filters, along with their total number regardless of pagination.
+func (dao *blobSpecEndpointDAO) List(ctx context.Context, filter *ListFilter, endpointFilter *models.SpecEndpointFilter) ([]*models.SpecEndpoint, int, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	shared.LogDebugf("fetching specEndpoints: %#v %#v ...", filter, endpointFilter)
+
+	var endpoints []*models.SpecEndpoint
+	db := dao.client.WithContext(ctx).Table(models.SpecEndpointTableName)
+	query := map[string]interface{}{}
+	for k, v := range filter.Indexes {
+		switch k {
+		case "deprecated", "latest":
+			query[k] = v == "true"
+		default:
+			query[k] = v
+		}
+	}
+	if len(query) != 0 {
+		db = db.Where(query)
+	}
+	if endpointFilter != nil {
+		db = db.Where("service_id IN ?", endpointFilter.ServiceIDs)
+		if endpointFilter.PathPrefix != "" {
+			db = db.Where("path LIKE ?", escapeLike(endpointFilter.PathPrefix)+"%")
+		}
+		if endpointFilter.Tag != "" {
+			db = db.Where("tags LIKE ?", models.DelimitedListPattern(endpointFilter.Tag))
+		}
+		if endpointFilter.SecurityScheme != "" {
+			db = db.Where("security_schemes LIKE ?", models.DelimitedListPattern(endpointFilter.SecurityScheme))
+		}
+		if endpointFilter.ContentType != "" {
+			pattern := models.DelimitedListPattern(strings.ToLower(endpointFilter.ContentType))
+			db = db.Where("LOWER(request_content_types) LIKE ? OR LOWER(response_content_types) LIKE ?", pattern, pattern)
+		}
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for _, sorter := range filter.Sorters {
+		db = db.Order(sorter.OrderBy())
+	}
+	if len(filter.Sorters) == 0 {
+		db = db.Order("service_id asc").Order("path asc").Order("method asc")
+	}
+
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		db = db.Offset(filter.Offset)
+	}
+	err := db.Find(&endpoints).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return endpoints, int(total), nil
+}
+
+// IndexedServiceIDs returns the IDs of the services with catalogued endpoints.
+func (dao *blobSpecEndpointDAO) IndexedServiceIDs(ctx context.Context) (map[string]bool, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	var serviceIDs []string
+	err := dao.client.WithContext(ctx).Table(models.SpecEndpointTableName).Distinct().Pluck("service_id", &serviceIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	indexed := make(map[string]bool, len(serviceIDs))
+	for _, serviceID := range serviceIDs {
+		indexed[serviceID] = true
+	}
+	return indexed, nil
+}
+
+// Reindex catalogs the endpoints of the specs of the service with specified serviceID, which aren't yet,
+// e.g. for services whose specs were saved before endpoints were catalogued.
+func (dao *blobSpecEndpointDAO) Reindex(ctx context.Context, serviceID string) error {
+	span, ctx := shared.StartSpan(ctx, "service.id", serviceID)
+	defer span.Finish()
+
+	specDAO := &blobSpecDAO{client: dao.client, config: dao.config}
+
+	var specs []*models.Spec
+	err := dao.client.WithContext(ctx).Table(models.SpecTableName).
+		Omit("doc", "doc_compressed", "sources", "validation_errors").
+		Where("service_id = ?", serviceID).
+		Where("id NOT IN (?)", dao.client.WithContext(ctx).Table(models.SpecEndpointTableName).Select("spec_id").Where("service_id = ?", serviceID)).
+		Find(&specs).Error
+	if err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		if err := specDAO.loadBlobs(ctx, spec); err != nil {
+			return err
+		}
+		if err := specDAO.catalogSpecEndpoints(ctx, spec); err != nil {
+			shared.LogErrorf("failed to catalog spec %s endpoints: %s", spec.GetID(), err.Error())
+		}
+		// Release the doc, as specs are catalogued one at a time.
+		spec.Doc, spec.DocOAS = nil, nil
+	}
+	return specDAO.updateSpecEndpointsLatest(ctx, serviceID)
+}
+
+// catalogSpecEndpoints stores the endpoints of spec (see models.NewSpecEndpoints), unless they're already stored.
+func (dao *blobSpecDAO) catalogSpecEndpoints(ctx context.Context, spec *models.Spec) error {
+	if spec.Doc == nil {
+		return nil
+	}
+	db := dao.client.WithContext(ctx)
+
+	var count int64
+	err := db.Table(models.SpecEndpointTableName).Where("spec_id = ?", spec.ID).Count(&count).Error
+	if err != nil || count > 0 {
+		return err
+	}
+
+	endpoints, err := models.NewSpecEndpoints(ctx, spec)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+	return db.CreateInBatches(endpoints, specEndpointsBatchSize).Error
+}
+
+// updateSpecEndpointsLatest flags the endpoints of the latest spec of the service with serviceID as the latest.
+func (dao *blobSpecDAO) updateSpecEndpointsLatest(ctx context.Context, serviceID string) error {
+	spec := &models.Spec{}
+	err := dao.client.WithContext(ctx).Table(models.SpecTableName).Select("id").
+		Where("service_id = ?", serviceID).
+		Order("created_at desc").
+		First(spec).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return dao.client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.SpecEndpoint{}).Where("service_id = ? AND latest = ? AND spec_id <> ?", serviceID, true, spec.ID).Update("latest", false).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.SpecEndpoint{}).Where("spec_id = ? AND latest = ?", spec.ID, false).Update("latest", true).Error
+	})
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const specSearchEntriesBatchSize = 500
+
+// SpecSearchDAO is the interface to access database
+type SpecSearchDAO interface {
+	Search(context context.Context, req *models.SpecSearchRequest) ([]*models.SpecSearchEntry, int, error)
+	IndexedServiceIDs(context context.Context) (map[string]bool, error)
+	Reindex(context context.Context, serviceID string) error
+}
+
+// NewSpecSearchDAO create SpecSearchDAO
+var NewSpecSearchDAO = func(config *shared.AppConfig) (SpecSearchDAO, error) {
+	client, err := NewDBClient(config)
+	if err != nil {
+		return nil, err
+	}
+	err = client.AutoMigrate(models.SpecSearchEntry{})
+	if err != nil {
+		return nil, err
+	}
+
+	dao := &blobSpecSearchDAO{client: client, config: config}
+	return dao, nil
+}
+
+type blobSpecSearchDAO struct {
+	client *Client
+	config *shared.AppConfig
+}
+
+// Search objects in database matching req, returning up to req.Limit of them along with the total number of matches.
+func (dao *blobSpecSearchDAO) Search(ctx context.Context, req *models.SpecSearchRequest) ([]*models.SpecSearchEntry, int, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	shared.LogDebugf("searching specs: %#v ...", req)
+
+	var entries []*models.SpecSearchEntry
+	if len(req.ServiceIDs) == 0 {
+		return entries, 0, nil
+	}
+
+	db := dao.client.WithContext(ctx).Table(models.SpecSearchEntryTableName).Where("service_id IN ?", req.ServiceIDs)
+	if len(req.Kinds) > 0 {
+		db = db.Where("kind IN ?", req.Kinds)
+	}
+	if query := strings.TrimSpace(req.Query); query != "" {
+		if req.Exact {
+			db = db.Where("term = ?", strings.ToLower(query))
+		} else {
+			like := "%" + escapeLike(strings.ToLower(query)) + "%"
+			db = db.Where("term LIKE ? OR text LIKE ? OR path LIKE ?", like, like, "%"+escapeLike(query)+"%")
+		}
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if req.Limit > 0 {
+		db = db.Limit(req.Limit)
+	}
+	err := db.Order("service_id asc").Order("id asc").Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, int(total), nil
+}
+
+// IndexedServiceIDs returns the IDs of the services with an indexed spec.
+func (dao *blobSpecSearchDAO) IndexedServiceIDs(ctx context.Context) (map[string]bool, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	var serviceIDs []string
+	err := dao.client.WithContext(ctx).Table(models.SpecSearchEntryTableName).
+		Where("kind = ?", models.SpecSearchKindSpec).
+		Distinct().Pluck("service_id", &serviceIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	indexed := make(map[string]bool, len(serviceIDs))
+	for _, serviceID := range serviceIDs {
+		indexed[serviceID] = true
+	}
+	return indexed, nil
+}
+
+// Reindex the latest spec of the service with specified serviceID, e.g. for services whose specs were saved before
+// specs were indexed.
+func (dao *blobSpecSearchDAO) Reindex(ctx context.Context, serviceID string) error {
+	span, ctx := shared.StartSpan(ctx, "service.id", serviceID)
+	defer span.Finish()
+
+	specDAO := &blobSpecDAO{client: dao.client, config: dao.config}
+	return specDAO.reindexSpecSearch(ctx, serviceID)
+}
+
+// indexSpecSearch indexes spec (see models.NewSpecSearchEntries), replacing the entries of its service,
+// unless it isn't the latest spec of its service or it's already indexed.
+func (dao *blobSpecDAO) indexSpecSearch(ctx context.Context, spec *models.Spec) error {
+	if spec.Doc == nil {
+		return nil
+	}
+	db := dao.client.WithContext(ctx)
+
+	var count int64
+	err := db.Table(models.SpecTableName).Where("service_id = ? AND created_at > ?", spec.ServiceID, spec.CreatedAt).Count(&count).Error
+	if err != nil || count > 0 {
+		return err
+	}
+	err = db.Table(models.SpecSearchEntryTableName).Where("spec_id = ? AND kind = ?", spec.ID, models.SpecSearchKindSpec).Count(&count).Error
+	if err != nil || count > 0 {
+		return err
+	}
+
+	entries, err := models.NewSpecSearchEntries(ctx, spec)
+	if err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("service_id = ?", spec.ServiceID).Delete(models.SpecSearchEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(entries, specSearchEntriesBatchSize).Error
+	})
+}
+
+// reindexSpecSearch indexes the latest spec of the service with serviceID (see indexSpecSearch),
+// removing the entries of the service if it has no spec.
+func (dao *blobSpecDAO) reindexSpecSearch(ctx context.Context, serviceID string) error {
+	spec := &models.Spec{}
+	err := dao.client.WithContext(ctx).Table(models.SpecTableName).
+		Where("service_id = ?", serviceID).
+		Order("created_at desc").
+		First(spec).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dao.client.WithContext(ctx).Where("service_id = ?", serviceID).Delete(models.SpecSearchEntry{}).Error
+	} else if err != nil {
+		return err
+	}
+	if err := dao.loadBlobs(ctx, spec); err != nil {
+		return err
+	}
+	return dao.indexSpecSearch(ctx, spec)
+}
+
+// escapeLike escapes the wildcards of s for LIKE patterns.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
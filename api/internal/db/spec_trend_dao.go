@@ -0,0 +1,255 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// SpecTrendDAO is the interface to access database
+type SpecTrendDAO interface {
+	List(context context.Context, serviceID string, from, to *time.Time) ([]*models.SpecTrend, error)
+	ListLatest(context context.Context, serviceIDs []string, at *time.Time) (map[string]*models.SpecTrend, error)
+	ListByServices(context context.Context, serviceIDs []string) (map[string][]*models.SpecTrend, error)
+	Rebuild(context context.Context, serviceID string) error
+	Backfill(context context.Context) error
+}
+
+// NewSpecTrendDAO create SpecTrendDAO
+var NewSpecTrendDAO = func(config *shared.AppConfig) (SpecTrendDAO, error) {
+	client, err := NewDBClient(config)
+	if err != nil {
+		return nil, err
+	}
+	err = client.AutoMigrate(models.SpecTrend{})
+	if err != nil {
+		return nil, err
+	}
+
+	dao := &blobSpecTrendDAO{client: client, config: config}
+	return dao, nil
+}
+
+type blobSpecTrendDAO struct {
+	client *Client
+	config *shared.AppConfig
+}
+
+// List all objects of the service with specified serviceID in database, by ascending spec creation time,
+// optionally created within [from, to].
+func (dao *blobSpecTrendDAO) List(ctx context.Context, serviceID string, from, to *time.Time) ([]*models.SpecTrend, error) {
+	span, ctx := shared.StartSpan(ctx, "service.id", serviceID)
+	defer span.Finish()
+
+	var trends []*models.SpecTrend
+	db := dao.client.WithContext(ctx).Table(models.SpecTrendTableName).Where("service_id = ?", serviceID)
+	if from != nil {
+		db = db.Where("spec_created_at >= ?", *from)
+	}
+	if to != nil {
+		db = db.Where("spec_created_at <= ?", *to)
+	}
+	err := db.Order("spec_created_at asc").Find(&trends).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return trends, nil
+}
+
+// ListLatest lists the latest object of each service with specified serviceIDs in database, mapped by service ID,
+// optionally as of the at time, i.e. of the latest spec created up to at.
+func (dao *blobSpecTrendDAO) ListLatest(ctx context.Context, serviceIDs []string, at *time.Time) (map[string]*models.SpecTrend, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	latest := make(map[string]*models.SpecTrend, len(serviceIDs))
+	if len(serviceIDs) == 0 {
+		return latest, nil
+	}
+
+	db := dao.client.WithContext(ctx).Table(models.SpecTrendTableName)
+	sub := db.Session(&gorm.Session{NewDB: true}).Table(models.SpecTrendTableName).
+		Select("service_id, MAX(spec_created_at)").
+		Where("service_id IN ?", serviceIDs)
+	if at != nil {
+		sub = sub.Where("spec_created_at <= ?", *at)
+	}
+	sub = sub.Group("service_id")
+
+	var trends []*models.SpecTrend
+	err := db.Where("(service_id, spec_created_at) IN (?)", sub).Find(&trends).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, trend := range trends {
+		// Specs created at the same time are unlikely, but still only keep one.
+		if existing, ok := latest[trend.ServiceID]; !ok || existing.SpecID < trend.SpecID {
+			latest[trend.ServiceID] = trend
+		}
+	}
+
+	return latest, nil
+}
+
+// ListByServices lists all objects of the services with specified serviceIDs in database, mapped by service ID,
+// by ascending spec creation time.
+func (dao *blobSpecTrendDAO) ListByServices(ctx context.Context, serviceIDs []string) (map[string][]*models.SpecTrend, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	byService := make(map[string][]*models.SpecTrend, len(serviceIDs))
+	if len(serviceIDs) == 0 {
+		return byService, nil
+	}
+
+	var trends []*models.SpecTrend
+	err := dao.client.WithContext(ctx).Table(models.SpecTrendTableName).
+		Where("service_id IN ?", serviceIDs).
+		Order("spec_created_at asc").
+		Find(&trends).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, trend := range trends {
+		byService[trend.ServiceID] = append(byService[trend.ServiceID], trend)
+	}
+
+	return byService, nil
+}
+
+// Rebuild the objects of the service with specified serviceID from its specs & spec analyses,
+// e.g. for services whose specs were saved before spec trends were maintained.
+func (dao *blobSpecTrendDAO) Rebuild(ctx context.Context, serviceID string) error {
+	span, ctx := shared.StartSpan(ctx, "service.id", serviceID)
+	defer span.Finish()
+
+	return dao.client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var specs []*models.Spec
+		err := tx.Table(models.SpecTableName).Select(specTrendSpecFields).Where("service_id = ?", serviceID).Find(&specs).Error
+		if err != nil {
+			return err
+		}
+		trends := make(map[string]*models.SpecTrend, len(specs))
+		for _, spec := range specs {
+			trends[spec.ID] = models.NewSpecTrend(spec)
+		}
+
+		var specAnalyses []*models.SpecAnalysis
+		err = tx.Table(models.SpecAnalysisTableName).Where("service_id = ?", serviceID).Find(&specAnalyses).Error
+		if err != nil {
+			return err
+		}
+		for _, specAnalysis := range specAnalyses {
+			if trend, ok := trends[specAnalysis.SpecID]; ok {
+				trend.SetAnalysis(specAnalysis)
+			}
+		}
+
+		if err := tx.Where("service_id = ?", serviceID).Delete(models.SpecTrend{}).Error; err != nil {
+			return err
+		}
+		for _, trend := range trends {
+			if err := tx.Create(trend).Error; err != nil {
+				shared.LogErrorf("failed to save spec %s trend: %s", trend.SpecID, err.Error())
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Backfill rebuilds the objects of the services whose specs were saved before spec trends (& their rules) were maintained,
+// i.e. with specs but no spec trends, or spec trends without rules (see models.SpecTrend.HasRules).
+// It's run once by a single replica (see models.SpecTrendBackfillMigrationID).
+func (dao *blobSpecTrendDAO) Backfill(ctx context.Context) error {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	var serviceIDs []string
+	err := dao.client.WithContext(ctx).Table(models.SpecTableName).Distinct("service_id").Pluck("service_id", &serviceIDs).Error
+	if err != nil {
+		return err
+	}
+
+	rebuilt := 0
+	for _, serviceID := range serviceIDs {
+		trends, err := dao.List(ctx, serviceID, nil, nil)
+		if err != nil {
+			return err
+		}
+		stale := len(trends) == 0
+		for _, trend := range trends {
+			stale = stale || !trend.HasRules()
+		}
+		if !stale {
+			continue
+		}
+		if err := dao.Rebuild(ctx, serviceID); err != nil {
+			shared.LogErrorf("failed to rebuild service %s spec trends: %s", serviceID, err.Error())
+			return err
+		}
+		rebuilt++
+	}
+	shared.LogInfof("rebuilt the spec trends of %d service(s)", rebuilt)
+
+	return nil
+}
+
+// specTrendSpecFields are the fields of models.Spec needed by models.SpecTrend.
+var specTrendSpecFields = []string{"id", "service_id", "version", "revision", "score", "created_at"}
+
+// saveSpecTrendSpec updates the models.SpecTrend of spec, creating it if needed.
+func saveSpecTrendSpec(tx *gorm.DB, spec *models.Spec) error {
+	trend := &models.SpecTrend{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("spec_id = ?", spec.ID).First(trend).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		trend = models.NewSpecTrend(spec)
+	} else if err != nil {
+		return err
+	} else {
+		trend.SetSpec(spec)
+	}
+	return tx.Save(trend).Error
+}
+
+// saveSpecTrendAnalysis updates the models.SpecTrend of specAnalysis's spec, creating it if needed.
+// Analyses of unknown specs, e.g. stateless analyses, are ignored.
+func saveSpecTrendAnalysis(tx *gorm.DB, specAnalysis *models.SpecAnalysis) error {
+	trend := &models.SpecTrend{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("spec_id = ?", specAnalysis.SpecID).First(trend).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		spec := &models.Spec{}
+		err = tx.Table(models.SpecTableName).Select(specTrendSpecFields).Where("id = ?", specAnalysis.SpecID).First(spec).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		trend = models.NewSpecTrend(spec)
+	} else if err != nil {
+		return err
+	}
+	trend.SetAnalysis(specAnalysis)
+	return tx.Save(trend).Error
+}
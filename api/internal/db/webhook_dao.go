@@ -0,0 +1,271 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package db
+
+import (
+	"context"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/gorm"
+	"time"
+)
+
+// WebhookDAO is the interface to access database
+type WebhookDAO interface {
+	List(context context.Context, filter *ListFilter) ([]*models.Webhook, error)
+	ListSubscribed(context context.Context, event, serviceID, organizationID string) ([]*models.Webhook, error)
+	Save(context context.Context, webhook *models.Webhook) error
+	Get(context context.Context, id string) (*models.Webhook, error)
+	Delete(context context.Context, id string) error
+
+	ListDeliveries(context context.Context, filter *ListFilter) ([]*models.WebhookDelivery, error)
+	SaveDelivery(context context.Context, delivery *models.WebhookDelivery) error
+	GetDelivery(context context.Context, webhookID, id string) (*models.WebhookDelivery, error)
+	ListDueDeliveries(context context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error)
+	ClaimDelivery(context context.Context, delivery *models.WebhookDelivery, now, until time.Time) (bool, error)
+}
+
+// NewWebhookDAO create WebhookDAO
+var NewWebhookDAO = func(config *shared.AppConfig) (WebhookDAO, error) {
+	client, err := NewDBClient(config)
+	if err != nil {
+		return nil, err
+	}
+	err = client.AutoMigrate(models.Webhook{}, models.WebhookDelivery{})
+	if err != nil {
+		return nil, err
+	}
+
+	dao := &blobWebhookDAO{client: client, config: config}
+	return dao, nil
+}
+
+type blobWebhookDAO struct {
+	client *Client
+	config *shared.AppConfig
+}
+
+// Save object to database
+func (dao *blobWebhookDAO) Save(ctx context.Context, webhook *models.Webhook) error {
+	span, ctx := shared.StartSpan(ctx, "webhook.id", webhook.GetID())
+	defer span.Finish()
+
+	err := dao.client.WithContext(ctx).Save(webhook).Error
+	if err != nil {
+		shared.LogErrorf("failed to save webhook %s: %s", webhook.GetID(), err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Get an object with specified id from database
+func (dao *blobWebhookDAO) Get(ctx context.Context, id string) (*models.Webhook, error) {
+	span, ctx := shared.StartSpan(ctx, "webhook.id", id)
+	defer span.Finish()
+
+	webhook := &models.Webhook{}
+	err := dao.client.WithContext(ctx).Where("id = ?", id).First(webhook).Error
+	if err != nil {
+		shared.LogErrorf("failed to get webhook %s: %s", id, err.Error())
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// Delete an object with specified id from database, along with its deliveries
+func (dao *blobWebhookDAO) Delete(ctx context.Context, id string) error {
+	span, ctx := shared.StartSpan(ctx, "webhook.id", id)
+	defer span.Finish()
+
+	return dao.client.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(models.Webhook{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			shared.LogErrorf("could not find object with %s: %v", id, result.Error)
+			return ErrNotFound
+		}
+		return tx.Where("webhook_id = ?", id).Delete(models.WebhookDelivery{}).Error
+	})
+}
+
+// List all objects in database with specified filter
+func (dao *blobWebhookDAO) List(ctx context.Context, filter *ListFilter) ([]*models.Webhook, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	shared.LogDebugf("fetching webhooks: %#v ...", filter)
+
+	var webhooks []*models.Webhook
+	db := dao.client.WithContext(ctx).Table(models.WebhookTableName)
+	query := map[string]interface{}{}
+	for k, v := range filter.Indexes {
+		switch k {
+		case "active":
+			query[k] = v == "true"
+		default:
+			query[k] = v
+		}
+	}
+	if len(query) != 0 {
+		db = db.Where(query)
+	}
+
+	for _, sorter := range filter.Sorters {
+		db = db.Order(sorter.OrderBy())
+	}
+
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		db = db.Offset(filter.Offset)
+	}
+	err := db.Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// ListSubscribed lists the active webhooks subscribed to event, of either the service with serviceID
+// or its organization with organizationID.
+func (dao *blobWebhookDAO) ListSubscribed(ctx context.Context, event, serviceID, organizationID string) ([]*models.Webhook, error) {
+	span, ctx := shared.StartSpan(ctx, "service.id", serviceID)
+	defer span.Finish()
+
+	var webhooks []*models.Webhook
+	db := dao.client.WithContext(ctx).Table(models.WebhookTableName).
+		Where("active = ?", true).
+		Where("events LIKE ?", models.DelimitedListPattern(event))
+	if organizationID != "" {
+		db = db.Where("service_id = ? OR organization_id = ?", serviceID, organizationID)
+	} else {
+		db = db.Where("service_id = ?", serviceID)
+	}
+	err := db.Order("created_at asc").Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// SaveDelivery saves a webhook delivery to database
+func (dao *blobWebhookDAO) SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	span, ctx := shared.StartSpan(ctx, "webhook.delivery.id", delivery.GetID())
+	defer span.Finish()
+
+	err := dao.client.WithContext(ctx).Save(delivery).Error
+	if err != nil {
+		shared.LogErrorf("failed to save webhook delivery %s: %s", delivery.GetID(), err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetDelivery gets the delivery with specified id of the webhook with specified webhookID from database
+func (dao *blobWebhookDAO) GetDelivery(ctx context.Context, webhookID, id string) (*models.WebhookDelivery, error) {
+	span, ctx := shared.StartSpan(ctx, "webhook.delivery.id", id)
+	defer span.Finish()
+
+	delivery := &models.WebhookDelivery{}
+	err := dao.client.WithContext(ctx).Where("webhook_id = ? AND id = ?", webhookID, id).First(delivery).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries lists all webhook deliveries in database with specified filter, the latest first by default
+func (dao *blobWebhookDAO) ListDeliveries(ctx context.Context, filter *ListFilter) ([]*models.WebhookDelivery, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	shared.LogDebugf("fetching webhook deliveries: %#v ...", filter)
+
+	var deliveries []*models.WebhookDelivery
+	db := dao.client.WithContext(ctx).Table(models.WebhookDeliveryTableName)
+	query := map[string]interface{}{}
+	for k, v := range filter.Indexes {
+		query[k] = v
+	}
+	if len(query) != 0 {
+		db = db.Where(query)
+	}
+
+	for _, sorter := range filter.Sorters {
+		db = db.Order(sorter.OrderBy())
+	}
+	if len(filter.Sorters) == 0 {
+		db = db.Order("created_at desc")
+	}
+
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		db = db.Offset(filter.Offset)
+	}
+	err := db.Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ListDueDeliveries lists the pending webhook deliveries due at now, the earliest first, up to limit
+func (dao *blobWebhookDAO) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	span, ctx := shared.StartSpan(ctx)
+	defer span.Finish()
+
+	var deliveries []*models.WebhookDelivery
+	err := dao.client.WithContext(ctx).Table(models.WebhookDeliveryTableName).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDelivery claims the pending delivery due at now for an attempt, postponing its next attempt until then
+// so that no other replica attempts it meanwhile (or until then, if the attempt is interrupted).
+// It returns false if the delivery was claimed by another replica, or is no longer due.
+func (dao *blobWebhookDAO) ClaimDelivery(ctx context.Context, delivery *models.WebhookDelivery, now, until time.Time) (bool, error) {
+	span, ctx := shared.StartSpan(ctx, "webhook.delivery.id", delivery.GetID())
+	defer span.Finish()
+
+	result := dao.client.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", delivery.ID, models.WebhookDeliveryStatusPending, now).
+		Update("next_attempt_at", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	delivery.NextAttemptAt = &until
+	return true, nil
+}
@@ -17,13 +17,18 @@
 package endpoints
 
 import (
+	"fmt"
 	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
+	"github.com/cisco-developer/api-insights/api/internal/models"
 	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
 	"gopkg.in/go-playground/validator.v9"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -32,6 +37,8 @@ type analyzerResource struct {
 	dao             db.AnalyzerDAO
 	validate        *validator.Validate
 	analyzerRuleDao db.AnalyzerRuleDAO
+	serviceDAO      db.ServiceDAO
+	specTrendDAO    db.SpecTrendDAO
 }
 
 // Register the API
@@ -53,6 +60,11 @@ func (r *analyzerResource) Register(config *shared.AppConfig, container *restful
 	var offset = ws.QueryParameter("offset", "starting offset").DataType("string")
 	var status = ws.QueryParameter("status", "analyzer status, e.g. active").DataType("string")
 	var withRules = ws.QueryParameter("withRules", "flag indicating if rules should be included, by default false").DataType("boolean")
+	var ruleStats models.RuleStats
+	var ruleStatsRanking models.RuleStatsRanking
+	var statsAnalyzer = ws.QueryParameter("analyzer", "analyzer (Name ID) to rank the rules of, by default all").DataType("string")
+	var statsInterval = ws.QueryParameter("interval", "interval (day, week or month) of the trend").DataType("string").DefaultValue(models.ServiceTrendsIntervalWeek)
+	var statsPeriods = ws.QueryParameter("periods", "number of intervals of the trend").DataType("integer").DefaultValue(strconv.Itoa(models.RuleStatsDefaultPeriods))
 
 	ws.Route(
 		ws.GET("").
@@ -147,6 +159,31 @@ func (r *analyzerResource) Register(config *shared.AppConfig, container *restful
 			Metadata(restfulspec.KeyOpenAPITags, []string{"analyzer"}).
 			Notes("Get a analyzer rule with specified id"))
 
+	ws.Route(
+		ws.GET("/{id}/rules/{ruleID}/stats").
+			To(r.getRuleStats).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(ruleStats, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(ruleStats)).
+			Do(shared.RouteParams(id)).
+			Do(shared.RouteParams(ruleID)).
+			Do(shared.RouteParams(statsInterval, statsPeriods)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"analyzer"}).
+			Notes("Get the violations of an analyzer rule across services, per the latest spec analyses of each service: the number of services violating it, its occurrences, their trend & the services where it was most recently fixed"))
+
+	ws.Route(
+		ws.GET("/rules/stats").
+			To(r.rankRuleStats).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(ruleStatsRanking, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(ruleStatsRanking)).
+			Do(shared.RouteParams(statsAnalyzer, statsInterval, statsPeriods)).
+			Do(shared.RouteParams(limit)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"analyzer"}).
+			Notes("Rank the analyzer rules by the number of services violating them, then their occurrences, per the latest spec analyses of each service"))
+
 	ws.Route(
 		ws.POST("/{id}/rules").
 			To(r.saveRule).
@@ -256,6 +293,7 @@ func (r *analyzerResource) save(req *restful.Request, res *restful.Response) {
 		}
 		analyzer.ID = existing.ID
 		analyzer.CreatedAt = existing.CreatedAt
+		middleware.SetAuditBefore(req, existing)
 	}
 	analyzer.UpdatedAt = time.Now().UTC()
 
@@ -271,6 +309,7 @@ func (r *analyzerResource) save(req *restful.Request, res *restful.Response) {
 		handleError(res, err)
 		return
 	}
+	middleware.SetAuditAfter(req, analyzer)
 	if id == "" {
 		res.Header().Add("Location", "/v1/apiregistry/analyzers/"+analyzer.ID)
 		_ = res.WriteHeaderAndEntity(http.StatusCreated, analyzer)
@@ -283,6 +322,10 @@ func (r *analyzerResource) delete(req *restful.Request, res *restful.Response) {
 	id := req.PathParameter("id")
 	shared.LogDebugf("get request to delete analyzer: %v", id)
 
+	if existing, err := r.dao.Get(req.Request.Context(), id); err == nil {
+		middleware.SetAuditBefore(req, existing)
+	}
+
 	err := r.dao.Delete(req.Request.Context(), id)
 	if err == db.ErrNotFound {
 		res.WriteHeader(http.StatusNotFound)
@@ -354,6 +397,7 @@ func (r *analyzerResource) saveRule(req *restful.Request, res *restful.Response)
 		}
 		ar.ID = existing.ID
 		ar.CreatedAt = existing.CreatedAt
+		middleware.SetAuditBefore(req, existing)
 	}
 	ar.UpdatedAt = time.Now().UTC()
 
@@ -369,6 +413,7 @@ func (r *analyzerResource) saveRule(req *restful.Request, res *restful.Response)
 		handleError(res, err)
 		return
 	}
+	middleware.SetAuditAfter(req, ar)
 	if id == "" {
 		res.Header().Add("Location", "/v1/apiregistry/analyzers/"+ar.AnalyzerNameID+"/rules/"+ar.ID)
 		_ = res.WriteHeaderAndEntity(http.StatusCreated, ar)
@@ -381,6 +426,10 @@ func (r *analyzerResource) deleteRule(req *restful.Request, res *restful.Respons
 	id := req.PathParameter("ruleID")
 	shared.LogDebugf("get request to delete analyzer rule: %v", id)
 
+	if existing, err := r.analyzerRuleDao.Get(req.Request.Context(), id); err == nil {
+		middleware.SetAuditBefore(req, existing)
+	}
+
 	err := r.analyzerRuleDao.Delete(req.Request.Context(), id)
 	if err == db.ErrNotFound {
 		res.WriteHeader(http.StatusNotFound)
@@ -407,6 +456,132 @@ func (r *analyzerResource) importRules(req *restful.Request, res *restful.Respon
 		handleError(res, err)
 		return
 	}
+	middleware.SetAuditAfter(req, ars)
 
 	res.WriteHeader(http.StatusNoContent)
 }
+
+// GET /{id}/rules/{ruleID}/stats
+func (r *analyzerResource) getRuleStats(req *restful.Request, res *restful.Response) {
+	var (
+		id     = req.PathParameter("id")
+		ruleID = req.PathParameter("ruleID")
+	)
+	shared.LogDebugf("get request to get analyzer (%v) rule (%v) stats", id, ruleID)
+
+	interval, periods, ok := parseRuleStatsParams(req, res)
+	if !ok {
+		return
+	}
+
+	ctx := req.Request.Context()
+	a, err := r.dao.Get(ctx, id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	ar, err := r.analyzerRuleDao.Get(ctx, ruleID)
+	if err != nil || ar.AnalyzerNameID != a.NameID {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	key := models.RuleStatsKey{Analyzer: analyzer.SpecAnalyzer(a.NameID), Rule: rule.NameID(ar.NameID)}
+	stats, err := r.listRuleStats(req, []models.RuleStatsKey{key}, interval, periods)
+	if err != nil {
+		shared.LogErrorf("failed to compute analyzer (%v) rule (%v) stats: %v", a.NameID, ar.NameID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	stats[key].SetRule(ar)
+	_ = res.WriteHeaderAndEntity(http.StatusOK, stats[key])
+}
+
+// GET /rules/stats
+func (r *analyzerResource) rankRuleStats(req *restful.Request, res *restful.Response) {
+	analyzerNameID := req.QueryParameter("analyzer")
+	shared.LogDebugf("get request to rank analyzer (%v) rule stats", analyzerNameID)
+
+	interval, periods, ok := parseRuleStatsParams(req, res)
+	if !ok {
+		return
+	}
+	limit, err := parseQueryInt(req, "limit", 0)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := req.Request.Context()
+	stats, err := r.listRuleStats(req, nil, interval, periods)
+	if err != nil {
+		shared.LogErrorf("failed to compute rule stats: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if analyzerNameID != "" {
+		for key := range stats {
+			if string(key.Analyzer) != analyzerNameID {
+				delete(stats, key)
+			}
+		}
+	}
+	ranking := models.NewRuleStatsRanking(stats, interval, limit, time.Now().UTC())
+
+	rules, err := r.analyzerRuleDao.List(ctx, &db.ListFilter{Model: &analyzer.Rule{}})
+	if err != nil {
+		shared.LogErrorf("failed to list analyzer rules: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rulesByKey := make(map[models.RuleStatsKey]*analyzer.Rule, len(rules))
+	for _, ar := range rules {
+		rulesByKey[models.RuleStatsKey{Analyzer: analyzer.SpecAnalyzer(ar.AnalyzerNameID), Rule: rule.NameID(ar.NameID)}] = ar
+	}
+	for _, s := range ranking.Rules {
+		s.SetRule(rulesByKey[models.RuleStatsKey{Analyzer: s.Analyzer, Rule: s.Rule}])
+	}
+	_ = res.WriteHeaderAndEntity(http.StatusOK, ranking)
+}
+
+// parseRuleStatsParams is a utility method that parses the interval & periods query parameters of req.
+// Important to note that parseRuleStatsParams does write to res on failure (returning false), so handle accordingly.
+func parseRuleStatsParams(req *restful.Request, res *restful.Response) (string, int, bool) {
+	interval := req.QueryParameter("interval")
+	if interval == "" {
+		interval = models.ServiceTrendsIntervalWeek
+	}
+	if interval == models.ServiceTrendsIntervalRevision || !models.ValidServiceTrendsInterval(interval) {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported rule stats interval: %s", interval))
+		return "", 0, false
+	}
+	periods, err := parseQueryInt(req, "periods", models.RuleStatsDefaultPeriods)
+	if err != nil || periods < 1 || periods > models.RuleStatsMaxPeriods {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("periods must be between 1 and %d", models.RuleStatsMaxPeriods))
+		return "", 0, false
+	}
+	return interval, periods, true
+}
+
+// listRuleStats is a utility method that computes the stats of the rules of keys (all rules if none),
+// from the spec trends of all services.
+func (r *analyzerResource) listRuleStats(req *restful.Request, keys []models.RuleStatsKey, interval string, periods int) (map[models.RuleStatsKey]*models.RuleStats, error) {
+	ctx := req.Request.Context()
+	services, err := r.serviceDAO.List(ctx, &db.ListFilter{Model: &models.Service{}}, orgServiceAccessDataFilterFromReq(req))
+	if err != nil {
+		return nil, err
+	}
+	servicesByID := make(map[string]*models.Service, len(services))
+	serviceIDs := make([]string, 0, len(services))
+	for _, service := range services {
+		servicesByID[service.ID] = service
+		serviceIDs = append(serviceIDs, service.ID)
+	}
+
+	trends, err := r.specTrendDAO.ListByServices(ctx, serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewRuleStats(trends, servicesByID, keys, interval, periods, time.Now().UTC()), nil
+}
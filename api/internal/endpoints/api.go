@@ -18,6 +18,7 @@ package endpoints
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/cisco-developer/api-insights/api/internal/access"
 	"github.com/cisco-developer/api-insights/api/internal/db"
 	"github.com/cisco-developer/api-insights/api/internal/info"
@@ -56,6 +57,13 @@ func API(cfg *shared.AppConfig) (*restful.Container, error) {
 	container := restful.NewContainer()
 	container.EnableContentEncoding(true)
 	container.Filter(middleware.NewTracingMiddleware())
+	container.Filter(middleware.NewAuthenticationMiddleware())
+
+	auditDao, err := db.NewAuditDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+	container.Filter(middleware.NewAuditMiddleware(auditDao))
 
 	validate := validator.New()
 
@@ -84,6 +92,26 @@ func API(cfg *shared.AppConfig) (*restful.Container, error) {
 		return nil, err
 	}
 
+	serviceDao, err := db.NewServiceDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	specTrendDao, err := db.NewSpecTrendDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	migrationDao, err := db.NewMigrationDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+	migrator := newMigrator(migrationDao,
+		&migration{id: models.SpecRehashMigrationID(), run: specDao.Rehash},
+		&migration{id: models.SpecTrendBackfillMigrationID, run: specTrendDao.Backfill},
+	)
+	migrator.start()
+
 	infoRes := &infoResource{
 		config:   cfg,
 		validate: validate,
@@ -96,6 +124,8 @@ func API(cfg *shared.AppConfig) (*restful.Container, error) {
 		dao:             analyzerDao,
 		validate:        validate,
 		analyzerRuleDao: analyzerRuleDao,
+		serviceDAO:      serviceDao,
+		specTrendDAO:    specTrendDao,
 	}
 	analyzerRes.Register(cfg, container, "/v1/apiregistry/analyzers")
 
@@ -131,11 +161,6 @@ func API(cfg *shared.AppConfig) (*restful.Container, error) {
 	}
 	specDiffRes.Register(cfg, container, "/v1/apiregistry/specs/diffs")
 
-	serviceDao, err := db.NewServiceDAO(cfg)
-	if err != nil {
-		return nil, err
-	}
-
 	apiclarityClient, err := apiclarity.New(nil)
 	if err != nil {
 		return nil, err
@@ -159,6 +184,47 @@ func API(cfg *shared.AppConfig) (*restful.Container, error) {
 	}
 	organizationRes.Register(cfg, container, "/v1/apiregistry/organizations")
 
+	consumerDao, err := db.NewConsumerDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	consumerRes := &consumerResource{
+		config:           cfg,
+		dao:              consumerDao,
+		validate:         validate,
+		serviceDAO:       serviceDao,
+		specDAO:          specDao,
+		apiclarityClient: apiclarityClient,
+		accessChecker:    accessChecker,
+	}
+	consumerRes.Register(cfg, container, "/v1/apiregistry/consumers")
+
+	webhookDao, err := db.NewWebhookDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+	webhookDispatcher := newWebhookDispatcher(webhookDao, consumerDao)
+	webhookDispatcher.start()
+
+	duplicateSnapshotDao, err := db.NewDuplicateSnapshotDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+	duplicateIndexer := newDuplicateIndexer(duplicateSnapshotDao, serviceDao, specDao)
+	duplicateIndexer.start()
+
+	webhookRes := &webhookResource{
+		config:          cfg,
+		dao:             webhookDao,
+		validate:        validate,
+		serviceDAO:      serviceDao,
+		organizationDAO: organizationDao,
+		dispatcher:      webhookDispatcher,
+		accessChecker:   accessChecker,
+	}
+	webhookRes.Register(cfg, container, "/v1/apiregistry/webhooks")
+
 	serviceRes := &serviceResource{
 		config:           cfg,
 		dao:              serviceDao,
@@ -166,6 +232,10 @@ func API(cfg *shared.AppConfig) (*restful.Container, error) {
 		specDAO:          specDao,
 		specDiffDAO:      specDiffDao,
 		specAnalysisDAO:  specAnalysisDao,
+		specTrendDAO:     specTrendDao,
+		consumerDAO:      consumerDao,
+		webhooks:         webhookDispatcher,
+		duplicates:       duplicateIndexer,
 		analyzerDAO:      analyzerDao,
 		organizationDAO:  organizationDao,
 		analyzerSvc:      analyzerSvc,
@@ -176,6 +246,82 @@ func API(cfg *shared.AppConfig) (*restful.Container, error) {
 	}
 	serviceRes.Register(cfg, container, "/v1/apiregistry/services")
 
+	scorecardRes := &scorecardResource{
+		config:          cfg,
+		serviceDAO:      serviceDao,
+		organizationDAO: organizationDao,
+		specTrendDAO:    specTrendDao,
+		accessChecker:   accessChecker,
+	}
+	scorecardRes.Register(cfg, container, "/v1/apiregistry/scorecard")
+
+	specSearchDao, err := db.NewSpecSearchDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	searchRes := &searchResource{
+		config:        cfg,
+		dao:           specSearchDao,
+		serviceDAO:    serviceDao,
+		specDAO:       specDao,
+		accessChecker: accessChecker,
+	}
+	searchRes.Register(cfg, container, "/v1/apiregistry/search")
+
+	specEndpointDao, err := db.NewSpecEndpointDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointRes := &endpointResource{
+		config:        cfg,
+		dao:           specEndpointDao,
+		serviceDAO:    serviceDao,
+		accessChecker: accessChecker,
+	}
+	endpointRes.Register(cfg, container, "/v1/apiregistry/endpoints")
+
+	duplicateRes := &duplicateResource{
+		config:        cfg,
+		dao:           duplicateSnapshotDao,
+		serviceDAO:    serviceDao,
+		accessChecker: accessChecker,
+	}
+	duplicateRes.Register(cfg, container, "/v1/apiregistry/duplicates")
+
+	digestSubscriptionDao, err := db.NewDigestSubscriptionDAO(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	digestScheduler := &digestScheduler{
+		dao:             digestSubscriptionDao,
+		serviceDAO:      serviceDao,
+		specAnalysisDAO: specAnalysisDao,
+		specDiffDAO:     specDiffDao,
+		specTrendDAO:    specTrendDao,
+		client:          newWebhookClient(),
+	}
+	digestScheduler.start()
+
+	digestRes := &digestResource{
+		config:          cfg,
+		dao:             digestSubscriptionDao,
+		validate:        validate,
+		organizationDAO: organizationDao,
+		scheduler:       digestScheduler,
+		accessChecker:   accessChecker,
+	}
+	digestRes.Register(cfg, container, "/v1/apiregistry/digests")
+
+	auditRes := &auditResource{
+		config:        cfg,
+		dao:           auditDao,
+		accessChecker: accessChecker,
+	}
+	auditRes.Register(cfg, container, "/v1/apiregistry/audit")
+
 	hr := HealthCheckResource{}
 	hr.Register(container, "/v1/healthz")
 
@@ -210,6 +356,19 @@ func parseQueryBool(req *restful.Request, flag string) bool {
 	return false
 }
 
+// parseQueryInt parses the integer query parameter param, returning defaultValue if absent.
+func parseQueryInt(req *restful.Request, param string, defaultValue int) (int, error) {
+	value := req.QueryParameter(param)
+	if value == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", param, value)
+	}
+	return i, nil
+}
+
 // ValidationError - Validation error class.
 type ValidationError struct {
 	jsonErrors []byte
@@ -0,0 +1,228 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"net/http"
+	"time"
+)
+
+const (
+	mimeNDJSON       = "application/x-ndjson"
+	auditExportBatch = 1000
+)
+
+type auditResource struct {
+	config        *shared.AppConfig
+	dao           db.AuditDAO
+	accessChecker access.Checker
+}
+
+// Register the API
+// prefix: /v1/apiregistry/audit
+func (r *auditResource) Register(config *shared.AppConfig, container *restful.Container, prefix string) {
+	ws := &restful.WebService{}
+	ws.Path(prefix).Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON, mimeTextCSV, mimeNDJSON).ApiVersion(config.AppVersion).Doc("APIs for Audit.")
+	ws.Filter(middleware.ResourceAccessChecker(r.accessChecker))
+
+	var event models.AuditEvent
+	var eventResponse models.AuditEventResponse
+	var id = ws.PathParameter("id", "unique identifier for audit event.").DataType("string")
+	var actor = ws.QueryParameter("actor", "actor (user) of audit events").DataType("string")
+	var action = ws.QueryParameter("action", "action (create, update or delete) of audit events").DataType("string")
+	var resourceType = ws.QueryParameter("resource_type", "type of the resources of audit events, e.g. service, spec, analyzer, analyzer_rule or organization").DataType("string")
+	var resourceID = ws.QueryParameter("resource_id", "unique identifier of the resource of audit events").DataType("string")
+	var requestID = ws.QueryParameter("request_id", "request ID ("+middleware.HeaderRequestID+" header) of audit events").DataType("string")
+	var from = ws.QueryParameter("from", "start time (RFC 3339 or YYYY-MM-DD) of audit events, inclusive").DataType("string")
+	var to = ws.QueryParameter("to", "end time (RFC 3339 or YYYY-MM-DD) of audit events, inclusive").DataType("string")
+	var format = ws.QueryParameter("format", "format (csv or ndjson) of the export").DataType("string").DefaultValue(models.AuditExportFormatCSV)
+	var limit = ws.QueryParameter("limit", "max items to return at one time").DataType("string")
+	var offset = ws.QueryParameter("offset", "starting offset").DataType("string")
+
+	ws.Route(
+		ws.GET("").
+			To(r.list).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteParams(offset)).
+			Do(shared.RouteParams(limit, max)).
+			Do(shared.RouteReturns(eventResponse, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(eventResponse)).
+			Do(shared.RouteParams(actor, action, resourceType, resourceID, requestID, from, to)).
+			Do(shared.RouteParams(sortOrder)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"audit"}).
+			Notes("List the audit events of the mutations of resources, the latest first by default, with specified filters"))
+
+	ws.Route(
+		ws.GET("/export").
+			To(r.export).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(nil, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteParams(actor, action, resourceType, resourceID, requestID, from, to)).
+			Do(shared.RouteParams(format)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"audit"}).
+			Notes("Export all the audit events with specified filters, oldest first, as CSV (changes as JSON) or NDJSON (with before & after snapshots)"))
+
+	ws.Route(
+		ws.GET("/{id}").
+			To(r.get).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(event, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(event)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"audit"}).
+			Notes("Get an audit event with specified id, along with the before & after snapshots of its resource"))
+
+	container.Add(ws)
+}
+
+// auditListFilter returns the filter & time range of the audit events of req.
+func auditListFilter(req *restful.Request) (*db.ListFilter, *time.Time, *time.Time, error) {
+	var filter = &db.ListFilter{Model: &models.AuditEvent{}}
+	if err := filter.From(req); err != nil {
+		return nil, nil, nil, err
+	}
+	if order := req.QueryParameter("order"); len(filter.Sorters) == 0 && (order == db.OrderAsc || order == db.OrderDesc) {
+		filter.Sorters = []*db.Sorter{{Field: "created_at", Order: order}}
+	}
+	from, err := parseTrendsTime(req.QueryParameter("from"), false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	to, err := parseTrendsTime(req.QueryParameter("to"), true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return filter, from, to, nil
+}
+
+// GET /
+func (r *auditResource) list(req *restful.Request, res *restful.Response) {
+	shared.LogDebugf("get request to list audit events.")
+
+	filter, from, to, err := auditListFilter(req)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events, total, err := r.dao.List(req.Request.Context(), filter, from, to)
+	if err != nil {
+		shared.LogErrorf("failed to list audit events: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for _, event := range events {
+		// Snapshots are only returned by GET /{id} & exports.
+		event.Before, event.After = nil, nil
+	}
+	shared.LogDebugf("total %v audit event(s) returned", len(events))
+	_ = res.WriteEntity(&models.AuditEventResponse{Pagination: filter.Pagination(total), Data: events})
+}
+
+// GET /{id}
+func (r *auditResource) get(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to retrieve audit event: %v", id)
+
+	event, err := r.dao.Get(req.Request.Context(), id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = res.WriteEntity(event)
+}
+
+// GET /export
+func (r *auditResource) export(req *restful.Request, res *restful.Response) {
+	shared.LogDebugf("get request to export audit events.")
+
+	format := req.QueryParameter("format")
+	if format == "" {
+		format = models.AuditExportFormatCSV
+	}
+	if format != models.AuditExportFormatCSV && format != models.AuditExportFormatNDJSON {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported audit export format: %s", format))
+		return
+	}
+	filter, from, to, err := auditListFilter(req)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.Sorters = []*db.Sorter{{Field: "created_at", Order: db.OrderAsc}}
+	filter.Limit, filter.Offset = auditExportBatch, 0
+
+	ctx := req.Request.Context()
+	events, _, err := r.dao.List(ctx, filter, from, to)
+	if err != nil {
+		shared.LogErrorf("failed to export audit events: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+	res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	var csvWriter *csv.Writer
+	if format == models.AuditExportFormatCSV {
+		res.Header().Set(restful.HEADER_ContentType, mimeTextCSV)
+		csvWriter = csv.NewWriter(res)
+		_ = csvWriter.Write((&models.AuditEvent{}).CSVHeader())
+	} else {
+		res.Header().Set(restful.HEADER_ContentType, mimeNDJSON)
+	}
+	res.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(res)
+
+	// Export in batches, so that the whole audit log isn't loaded at once.
+	for len(events) > 0 {
+		for _, event := range events {
+			if csvWriter != nil {
+				_ = csvWriter.Write(event.CSVRecord())
+			} else if err := encoder.Encode(event); err != nil {
+				shared.LogErrorf("failed to export audit event %s: %v", event.ID, err)
+				return
+			}
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+		}
+		if len(events) < filter.Limit {
+			break
+		}
+		filter.Offset += filter.Limit
+		if events, _, err = r.dao.List(ctx, filter, from, to); err != nil {
+			shared.LogErrorf("failed to export audit events: %v", err)
+			return
+		}
+	}
+	if csvWriter != nil {
+		csvWriter.Flush()
+	}
+}
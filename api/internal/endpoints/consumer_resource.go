@@ -0,0 +1,452 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	modelsanalyzer "github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/cisco-developer/api-insights/api/pkg/apiclarity"
+	apiclarityclient "github.com/cisco-developer/api-insights/api/pkg/apiclarity/client"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"time"
+)
+
+const (
+	// consumerTrafficDefaultDays is the default number of days of traffic consumers are detected from.
+	consumerTrafficDefaultDays = 7
+)
+
+type consumerResource struct {
+	config           *shared.AppConfig
+	dao              db.ConsumerDAO
+	validate         *validator.Validate
+	serviceDAO       db.ServiceDAO
+	specDAO          db.SpecDAO
+	apiclarityClient *apiclarityclient.APIClarityAPIs
+	accessChecker    access.Checker
+}
+
+// Register the API
+// prefix: /v1/apiregistry/consumers
+func (r *consumerResource) Register(config *shared.AppConfig, container *restful.Container, prefix string) {
+	ws := &restful.WebService{}
+	ws.Path(prefix).Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON).ApiVersion(config.AppVersion).Doc("APIs for Consumer.")
+	ws.Filter(middleware.ResourceAccessChecker(r.accessChecker))
+
+	var consumer models.Consumer
+	var consumers []models.Consumer
+	var id = ws.PathParameter("id", "unique identifier for consumer.").DataType("string")
+	var service = ws.QueryParameter("service", "unique identifier (UUID or Name ID) of the consumed service").DataType("string")
+	var consumerServiceID = ws.QueryParameter("consumer_service_id", "unique identifier of the consumer service").DataType("string")
+	var source = ws.QueryParameter("source", "source (manual, spec or traffic) of consumers").DataType("string")
+	var limit = ws.QueryParameter("limit", "max items to return at one time").DataType("string")
+	var offset = ws.QueryParameter("offset", "starting offset").DataType("string")
+	var detectSource = ws.QueryParameter("source", "source (spec or traffic) to detect consumers from").DataType("string").Required(true)
+	var detectService = ws.QueryParameter("service", "unique identifier (UUID or Name ID) of the consumed service").DataType("string").Required(true)
+	var detectConsumer = ws.QueryParameter("consumer", "unique identifier (UUID or Name ID) of the consumer service to detect from its spec, by default all services").DataType("string")
+	var detectDays = ws.QueryParameter("days", "number of days of traffic to detect consumers from").DataType("integer").DefaultValue(fmt.Sprint(consumerTrafficDefaultDays))
+
+	ws.Route(
+		ws.GET("").
+			To(r.list).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteParams(offset)).
+			Do(shared.RouteParams(limit, max)).
+			Do(shared.RouteReturns(consumers, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(consumers)).
+			Do(shared.RouteParams(service, consumerServiceID, source)).
+			Do(shared.RouteParams(sort, sortOrder)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"consumer"}).
+			Notes("List the consumers of services, with specified filters"))
+
+	ws.Route(
+		ws.GET("/{id}").
+			To(r.get).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(consumer, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(consumer)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"consumer"}).
+			Notes("Get a consumer with specified id"))
+
+	ws.Route(
+		ws.POST("").
+			To(r.save).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(consumer, http.StatusCreated)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError)).
+			Do(shared.RouteReads(consumer), shared.RouteWrites(consumer)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"consumer"}).
+			Notes("Register a consumer of the operations (method & path) of a service, notified of their breaking changes"))
+
+	ws.Route(
+		ws.PUT("/{id}").
+			To(r.save).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(consumer, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError)).
+			Do(shared.RouteReads(consumer), shared.RouteWrites(consumer)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"consumer"}).
+			Notes("Update existing consumer"))
+
+	ws.Route(
+		ws.DELETE("/{id}").
+			To(r.delete).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(nil, http.StatusNoContent)).
+			Do(shared.RouteReturns(&se, http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(nil)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"consumer"}).
+			Notes("Delete existing consumer"))
+
+	ws.Route(
+		ws.POST("/detect").
+			To(r.detect).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(consumers, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(consumers)).
+			Do(shared.RouteParams(detectService, detectSource)).
+			Do(shared.RouteParams(detectConsumer, detectDays)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"consumer"}).
+			Notes("Detect & register the consumers of a service, either from the specs of other services (URLs of the service's servers, e.g. in their servers, links or extensions), " +
+				"or from the traffic of the service (by source IP). Detected operations replace those of the same source, & are added to the others"))
+
+	container.Add(ws)
+}
+
+// GET /
+func (r *consumerResource) list(req *restful.Request, res *restful.Response) {
+	shared.LogDebugf("get request to list consumers.")
+
+	var filter = &db.ListFilter{Model: &models.Consumer{}}
+	err := filter.From(req)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx := req.Request.Context()
+	services, err := r.serviceDAO.List(ctx, &db.ListFilter{Model: &models.Service{}}, orgServiceAccessDataFilterFromReq(req))
+	if err != nil {
+		shared.LogErrorf("failed to list services for consumers: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	serviceIDs := make([]string, 0, len(services))
+	for _, service := range services {
+		serviceIDs = append(serviceIDs, service.ID)
+	}
+	if serviceID := req.QueryParameter("service"); serviceID != "" {
+		service, err := r.serviceDAO.Get(ctx, serviceID)
+		if err != nil {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		filter.Indexes["service_id"] = service.ID
+	}
+
+	consumers, err := r.dao.List(ctx, filter, serviceIDs)
+	if err != nil {
+		shared.LogErrorf("failed to list consumers: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	shared.LogDebugf("total %v consumer(s) returned", len(consumers))
+	_ = res.WriteEntity(consumers)
+}
+
+// GET /{id}
+func (r *consumerResource) get(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to retrieve consumer: %v", id)
+
+	consumer, err := r.dao.Get(req.Request.Context(), id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = res.WriteEntity(consumer)
+}
+
+// POST / & PUT /{id}
+func (r *consumerResource) save(req *restful.Request, res *restful.Response) {
+	consumer := &models.Consumer{}
+	id := req.PathParameter("id")
+	if err := req.ReadEntity(consumer); err != nil {
+		shared.LogErrorf("failed to get consumer from body: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+	shared.LogDebugf("get request to save consumer with body: %#v", consumer)
+
+	ctx := req.Request.Context()
+	now := time.Now().UTC()
+	if id == "" {
+		consumer.ID = ""
+		consumer.CreatedAt = now
+	} else {
+		existing, err := r.dao.Get(ctx, id)
+		if err != nil {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		middleware.SetAuditBefore(req, existing)
+		consumer.ID = existing.ID
+		consumer.CreatedAt = existing.CreatedAt
+	}
+	consumer.UpdatedAt = now
+	if consumer.Source == "" {
+		consumer.Source = models.ConsumerSourceManual
+	}
+	consumer.Operations = consumer.Operations.Merge(nil)
+
+	if err := r.validate.Struct(consumer); err != nil {
+		shared.LogErrorf("failed to validate consumer %s - %v", consumer.ID, err.Error())
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	if consumer.WebhookURL != "" {
+		if err := models.CheckWebhookURL(ctx, consumer.WebhookURL); err != nil {
+			_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("invalid webhook_url: %v", err))
+			return
+		}
+	}
+
+	service, err := r.serviceDAO.Get(ctx, consumer.ServiceID)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusNotFound, fmt.Sprintf("service not found: %s", consumer.ServiceID))
+		return
+	}
+	consumer.ServiceID = service.ID
+	if consumer.ConsumerServiceID != "" {
+		consumerService, err := r.serviceDAO.Get(ctx, consumer.ConsumerServiceID)
+		if err != nil {
+			_ = res.WriteErrorString(http.StatusNotFound, fmt.Sprintf("consumer service not found: %s", consumer.ConsumerServiceID))
+			return
+		}
+		consumer.ConsumerServiceID = consumerService.ID
+	}
+	if existing, err := r.dao.GetByName(ctx, consumer.ServiceID, consumer.Name); err == nil && existing.ID != consumer.ID {
+		_ = res.WriteErrorString(http.StatusConflict, fmt.Sprintf("consumer %s of service %s already exists: %s", consumer.Name, service.NameID, existing.ID))
+		return
+	}
+
+	if err := r.dao.Save(ctx, consumer); err != nil {
+		handleError(res, err)
+		return
+	}
+	middleware.SetAuditAfter(req, consumer)
+	if id == "" {
+		res.Header().Add("Location", "/v1/apiregistry/consumers/"+consumer.ID)
+		_ = res.WriteHeaderAndEntity(http.StatusCreated, consumer)
+	} else {
+		_ = res.WriteEntity(consumer)
+	}
+}
+
+// DELETE /{id}
+func (r *consumerResource) delete(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to delete consumer: %v", id)
+
+	if existing, err := r.dao.Get(req.Request.Context(), id); err == nil {
+		middleware.SetAuditBefore(req, existing)
+	}
+
+	err := r.dao.Delete(req.Request.Context(), id)
+	if err == db.ErrNotFound {
+		res.WriteHeader(http.StatusNotFound)
+	} else if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+	} else {
+		res.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// POST /detect
+func (r *consumerResource) detect(req *restful.Request, res *restful.Response) {
+	var (
+		serviceID = req.QueryParameter("service")
+		source    = req.QueryParameter("source")
+	)
+	shared.LogDebugf("get request to detect service (%v) consumers from %v", serviceID, source)
+
+	if source != models.ConsumerSourceSpec && source != models.ConsumerSourceTraffic {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported source: %s", source))
+		return
+	}
+	days, err := parseQueryInt(req, "days", consumerTrafficDefaultDays)
+	if err != nil || days < 1 {
+		_ = res.WriteErrorString(http.StatusBadRequest, "days must be a positive integer")
+		return
+	}
+
+	ctx := req.Request.Context()
+	service, err := r.serviceDAO.Get(ctx, serviceID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	spec, err := r.getLatestSpec(ctx, service.ID)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusNotFound, fmt.Sprintf("service %s has no spec", service.NameID))
+		return
+	}
+	doc, err := spec.LoadDocAsOAS(ctx, false, false, false)
+	if err != nil || spec.DocKind() != models.SpecDocKindOpenAPI {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("consumers can only be detected from OpenAPI specs: %v", err))
+		return
+	}
+
+	detected := map[string]*models.Consumer{}
+	switch source {
+	case models.ConsumerSourceSpec:
+		consumerServices, err := r.serviceDAO.List(ctx, &db.ListFilter{Model: &models.Service{}}, orgServiceAccessDataFilterFromReq(req))
+		if err != nil {
+			shared.LogErrorf("failed to list services for consumers: %v", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if consumerID := req.QueryParameter("consumer"); consumerID != "" {
+			consumerService, err := r.serviceDAO.Get(ctx, consumerID)
+			if err != nil {
+				res.WriteHeader(http.StatusNotFound)
+				return
+			}
+			consumerServices = []*models.Service{consumerService}
+		}
+		for _, consumerService := range consumerServices {
+			if consumerService.ID == service.ID || consumerService.Summary == nil {
+				continue
+			}
+			consumerSpec, err := r.getLatestSpec(ctx, consumerService.ID)
+			if err != nil || consumerSpec.Doc == nil {
+				continue
+			}
+			if ops := models.NewSpecConsumerOperations(doc, *consumerSpec.Doc); len(ops) > 0 {
+				detected[consumerService.NameID] = &models.Consumer{Name: consumerService.NameID, ConsumerServiceID: consumerService.ID, Operations: ops}
+			}
+		}
+	case models.ConsumerSourceTraffic:
+		apiName := service.GetNameID(modelsanalyzer.Drift, nil)
+		events, err := apiclarity.ListConsumerTraffic(ctx, r.apiclarityClient, apiName, time.Now().UTC().AddDate(0, 0, -days))
+		if err != nil {
+			shared.LogErrorf("failed to list service (%v) traffic: %v", service.ID, err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		for name, ops := range models.NewTrafficConsumerOperations(doc, events) {
+			detected[name] = &models.Consumer{Name: name, Operations: ops}
+		}
+	}
+
+	consumers := make([]*models.Consumer, 0, len(detected))
+	var updated []*models.Consumer
+	now := time.Now().UTC()
+	for _, consumer := range detected {
+		existing, err := r.dao.GetByName(ctx, service.ID, consumer.Name)
+		if err == nil {
+			before := *existing
+			updated = append(updated, &before)
+			if existing.Source != source {
+				consumer.Operations = existing.Operations.Merge(consumer.Operations)
+			}
+			existing.Operations = consumer.Operations
+			if existing.ConsumerServiceID == "" {
+				existing.ConsumerServiceID = consumer.ConsumerServiceID
+			}
+			consumer = existing
+		} else {
+			consumer.ServiceID = service.ID
+			consumer.Source = source
+			consumer.CreatedAt = now
+		}
+		consumer.UpdatedAt = now
+		if err := r.dao.Save(ctx, consumer); err != nil {
+			handleError(res, err)
+			return
+		}
+		consumers = append(consumers, consumer)
+	}
+	if len(updated) > 0 {
+		middleware.SetAuditBefore(req, updated)
+	}
+	middleware.SetAuditAfter(req, consumers)
+	shared.LogDebugf("total %v consumer(s) detected", len(consumers))
+	_ = res.WriteEntity(consumers)
+}
+
+func (r *consumerResource) getLatestSpec(ctx context.Context, serviceID string) (*models.Spec, error) {
+	specs, err := r.specDAO.List(ctx, &db.ListFilter{
+		Model:   &models.Spec{},
+		Indexes: map[string]string{"service_id": serviceID},
+		Sorters: []*db.Sorter{{
+			Order: db.OrderDesc,
+			Field: "created_at",
+		}},
+		Limit: 1,
+	}, true)
+	if err != nil {
+		return nil, err
+	} else if len(specs) == 0 {
+		return nil, db.ErrNotFound
+	}
+	return specs[0], nil
+}
+
+// notifyConsumers notifies the consumers affected by impact, the impact of specDiff of service, through their webhook,
+// delivered by dispatcher in the background.
+func notifyConsumers(ctx context.Context, dispatcher *webhookDispatcher, service *models.Service, specDiff *models.SpecDiff, impact *diff.Impact, consumers []*models.Consumer) {
+	if impact == nil || len(impact.Changes) == 0 {
+		return
+	}
+	for _, consumer := range consumers {
+		changes := models.ConsumerImpactChanges(impact, consumer.ID)
+		if len(changes) == 0 {
+			continue
+		}
+		if consumer.WebhookURL == "" {
+			shared.LogInfof("consumer %s (owner: %s) of service %s affected by breaking changes of spec diff %s has no webhook to notify", consumer.Name, consumer.Owner(), service.NameID, specDiff.ID)
+			continue
+		}
+		dispatcher.notify(ctx, consumer, &models.ConsumerNotification{
+			ServiceID:     service.ID,
+			ServiceNameID: service.NameID,
+			ConsumerID:    consumer.ID,
+			Consumer:      consumer.Name,
+			SpecDiffID:    specDiff.ID,
+			OldSpecID:     specDiff.OldSpecID,
+			NewSpecID:     specDiff.NewSpecID,
+			Changes:       changes,
+		})
+	}
+}
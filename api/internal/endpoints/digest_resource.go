@@ -0,0 +1,320 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/mailer"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"time"
+)
+
+type digestResource struct {
+	config          *shared.AppConfig
+	dao             db.DigestSubscriptionDAO
+	validate        *validator.Validate
+	organizationDAO db.OrganizationDAO
+	scheduler       *digestScheduler
+	accessChecker   access.Checker
+}
+
+// Register the API
+// prefix: /v1/apiregistry/digests
+func (r *digestResource) Register(config *shared.AppConfig, container *restful.Container, prefix string) {
+	ws := &restful.WebService{}
+	ws.Path(prefix).Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON, mimeTextMarkdown).ApiVersion(config.AppVersion).Doc("APIs for Digest.")
+	ws.Filter(middleware.ResourceAccessChecker(r.accessChecker))
+
+	var subscription models.DigestSubscription
+	var subscriptions []models.DigestSubscription
+	var digest models.Digest
+	var id = ws.PathParameter("id", "unique identifier for digest subscription.").DataType("string")
+	var organizationID = ws.QueryParameter("organization_id", "name id of the organization of digest subscriptions").DataType("string")
+	var channel = ws.QueryParameter("channel", "channel (email or chat) of digest subscriptions").DataType("string")
+	var active = ws.QueryParameter("active", "whether digest subscriptions are active").DataType("boolean")
+	var format = ws.QueryParameter("format", "format (json or markdown) of digest").DataType("string").DefaultValue(models.DigestFormatJSON)
+	var limit = ws.QueryParameter("limit", "max items to return at one time").DataType("string")
+	var offset = ws.QueryParameter("offset", "starting offset").DataType("string")
+
+	ws.Route(
+		ws.GET("").
+			To(r.list).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteParams(offset)).
+			Do(shared.RouteParams(limit, max)).
+			Do(shared.RouteReturns(subscriptions, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(subscriptions)).
+			Do(shared.RouteParams(organizationID, channel, active)).
+			Do(shared.RouteParams(sort, sortOrder)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"digest"}).
+			Notes("List digest subscriptions, with specified filters"))
+
+	ws.Route(
+		ws.GET("/{id}").
+			To(r.get).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(subscription, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(subscription)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"digest"}).
+			Notes("Get a digest subscription with specified id"))
+
+	ws.Route(
+		ws.POST("").
+			To(r.save).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(subscription, http.StatusCreated)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteReads(subscription), shared.RouteWrites(subscription)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"digest"}).
+			Notes("Subscribe a recipient (an email address, or a chat webhook URL) to daily or weekly digests of the services of an organization: " +
+				"score changes, new error findings, breaking changes released, & services whose spec hasn't been updated in stale_days (default 30)"))
+
+	ws.Route(
+		ws.PUT("/{id}").
+			To(r.save).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(subscription, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteReads(subscription), shared.RouteWrites(subscription)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"digest"}).
+			Notes("Update existing digest subscription"))
+
+	ws.Route(
+		ws.DELETE("/{id}").
+			To(r.delete).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(nil, http.StatusNoContent)).
+			Do(shared.RouteReturns(&se, http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(nil)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"digest"}).
+			Notes("Delete existing digest subscription"))
+
+	ws.Route(
+		ws.GET("/{id}/preview").
+			To(r.preview).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(digest, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(digest)).
+			Do(shared.RouteParams(id, format)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"digest"}).
+			Notes("Compose the digest of a subscription as it would be sent now, without sending it"))
+
+	ws.Route(
+		ws.POST("/{id}/send").
+			To(r.send).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(digest, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError, http.StatusBadGateway)).
+			Do(shared.RouteWrites(digest)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"digest"}).
+			Notes("Send the digest of a subscription now, regardless of its frequency"))
+
+	container.Add(ws)
+}
+
+// GET /
+func (r *digestResource) list(req *restful.Request, res *restful.Response) {
+	shared.LogDebugf("get request to list digest subscriptions.")
+
+	var filter = &db.ListFilter{Model: &models.DigestSubscription{}}
+	err := filter.From(req)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	subscriptions, err := r.dao.List(req.Request.Context(), filter)
+	if err != nil {
+		shared.LogErrorf("failed to list digest subscriptions: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	shared.LogDebugf("total %v digest subscription(s) returned", len(subscriptions))
+	_ = res.WriteEntity(subscriptions)
+}
+
+// GET /{id}
+func (r *digestResource) get(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to retrieve digest subscription: %v", id)
+
+	subscription, err := r.dao.Get(req.Request.Context(), id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = res.WriteEntity(subscription)
+}
+
+// POST / & PUT /{id}
+func (r *digestResource) save(req *restful.Request, res *restful.Response) {
+	subscription := &models.DigestSubscription{
+		Frequency: models.DigestFrequencyWeekly,
+		StaleDays: models.DefaultDigestStaleDays,
+		Active:    true,
+	}
+	id := req.PathParameter("id")
+	if err := req.ReadEntity(subscription); err != nil {
+		shared.LogErrorf("failed to get digest subscription from body: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+	shared.LogDebugf("get request to save digest subscription with body: %#v", subscription)
+
+	ctx := req.Request.Context()
+	now := time.Now().UTC()
+	if id == "" {
+		subscription.ID = ""
+		subscription.CreatedAt = now
+		subscription.LastSentAt = nil
+	} else {
+		existing, err := r.dao.Get(ctx, id)
+		if err != nil {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		middleware.SetAuditBefore(req, existing)
+		subscription.ID = existing.ID
+		subscription.CreatedAt = existing.CreatedAt
+		subscription.LastSentAt = existing.LastSentAt
+		subscription.ClaimedUntil = existing.ClaimedUntil
+	}
+	subscription.UpdatedAt = now
+
+	if err := r.validate.Struct(subscription); err != nil {
+		shared.LogErrorf("failed to validate digest subscription %s - %v", subscription.ID, err.Error())
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := subscription.ValidateRecipient(ctx); err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	if subscription.Channel == models.DigestChannelEmail && !mailer.Configured() {
+		_ = res.WriteErrorString(http.StatusBadRequest, "email digests require an SMTP server (--smtp-host)")
+		return
+	}
+	organization, err := r.organizationDAO.Get(ctx, subscription.OrganizationID)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusNotFound, fmt.Sprintf("organization not found: %s", subscription.OrganizationID))
+		return
+	}
+	subscription.OrganizationID = organization.NameID
+
+	if err := r.dao.Save(ctx, subscription); err != nil {
+		handleError(res, err)
+		return
+	}
+	middleware.SetAuditAfter(req, subscription)
+	if id == "" {
+		res.Header().Add("Location", "/v1/apiregistry/digests/"+subscription.ID)
+		_ = res.WriteHeaderAndEntity(http.StatusCreated, subscription)
+	} else {
+		_ = res.WriteEntity(subscription)
+	}
+}
+
+// DELETE /{id}
+func (r *digestResource) delete(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to delete digest subscription: %v", id)
+
+	if existing, err := r.dao.Get(req.Request.Context(), id); err == nil {
+		middleware.SetAuditBefore(req, existing)
+	}
+
+	err := r.dao.Delete(req.Request.Context(), id)
+	if err == db.ErrNotFound {
+		res.WriteHeader(http.StatusNotFound)
+	} else if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+	} else {
+		res.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GET /{id}/preview
+func (r *digestResource) preview(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to preview digest of subscription: %v", id)
+
+	format := req.QueryParameter("format")
+	if format == "" {
+		format = models.DigestFormatJSON
+	}
+	if format != models.DigestFormatJSON && format != models.DigestFormatMarkdown {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported digest format: %s", format))
+		return
+	}
+
+	ctx := req.Request.Context()
+	subscription, err := r.dao.Get(ctx, id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	digest, err := r.scheduler.compose(ctx, subscription, time.Now().UTC())
+	if err != nil {
+		shared.LogErrorf("failed to compose digest of subscription %s: %v", subscription.ID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if format == models.DigestFormatMarkdown {
+		res.Header().Set(restful.HEADER_ContentType, mimeTextMarkdown)
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(digest.Markdown()))
+		return
+	}
+	_ = res.WriteHeaderAndEntity(http.StatusOK, digest)
+}
+
+// POST /{id}/send
+func (r *digestResource) send(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to send digest of subscription: %v", id)
+
+	ctx := req.Request.Context()
+	subscription, err := r.dao.Get(ctx, id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	middleware.SetAuditBefore(req, subscription)
+	digest, err := r.scheduler.deliver(ctx, subscription, time.Now().UTC())
+	if err != nil {
+		shared.LogErrorf("failed to send digest of subscription %s: %v", subscription.ID, err)
+		_ = res.WriteErrorString(http.StatusBadGateway, err.Error())
+		return
+	}
+	middleware.SetAuditAfter(req, subscription)
+	_ = res.WriteEntity(digest)
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/mailer"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"github.com/emicklei/go-restful/v3"
+	"net/http"
+	"time"
+)
+
+// digestSchedulerInterval is how often the digest scheduler checks for due digests.
+var digestSchedulerInterval = 15 * time.Minute
+
+// digestClaimLease is how long a replica claims a due digest for: if it fails to send it, the digest is sent again
+// (by any replica) after.
+var digestClaimLease = time.Hour
+
+// digestScheduler composes digests from the stored service summaries, spec trends, analyses & diffs,
+// & periodically sends them to their subscribers by email or chat webhook.
+type digestScheduler struct {
+	dao             db.DigestSubscriptionDAO
+	serviceDAO      db.ServiceDAO
+	specAnalysisDAO db.SpecAnalysisDAO
+	specDiffDAO     db.SpecDiffDAO
+	specTrendDAO    db.SpecTrendDAO
+	client          *http.Client
+}
+
+// start sends the due digests every digestSchedulerInterval, in the background.
+func (s *digestScheduler) start() {
+	go func() {
+		ticker := time.NewTicker(digestSchedulerInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			s.sendDue(context.Background(), now.UTC())
+		}
+	}()
+}
+
+// sendDue sends the digests of the active subscriptions due at now, which this replica claims.
+func (s *digestScheduler) sendDue(ctx context.Context, now time.Time) {
+	subscriptions, err := s.dao.List(ctx, &db.ListFilter{Indexes: map[string]string{"active": "true"}})
+	if err != nil {
+		shared.LogErrorf("failed to list digest subscriptions: %v", err)
+		return
+	}
+	for _, subscription := range subscriptions {
+		if !subscription.Due(now) {
+			continue
+		}
+		claimed, err := s.dao.Claim(ctx, subscription, now, now.Add(digestClaimLease))
+		if err != nil || !claimed {
+			continue
+		}
+		if _, err := s.deliver(ctx, subscription, now); err != nil {
+			shared.LogErrorf("failed to send digest of subscription %s: %v", subscription.ID, err)
+		}
+	}
+}
+
+// deliver composes & sends the digest of subscription at now, recording when it was sent.
+func (s *digestScheduler) deliver(ctx context.Context, subscription *models.DigestSubscription, now time.Time) (*models.Digest, error) {
+	digest, err := s.compose(ctx, subscription, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.send(ctx, subscription, digest); err != nil {
+		return nil, err
+	}
+	subscription.LastSentAt = &now
+	subscription.ClaimedUntil = nil
+	return digest, s.dao.Save(ctx, subscription)
+}
+
+// compose returns the digest of the services of the organization of subscription at now.
+func (s *digestScheduler) compose(ctx context.Context, subscription *models.DigestSubscription, now time.Time) (*models.Digest, error) {
+	digest := models.NewDigest(subscription, now)
+
+	services, err := s.serviceDAO.List(ctx, &db.ListFilter{
+		Model:   &models.Service{},
+		Indexes: map[string]string{"organization_id": subscription.OrganizationID},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	serviceIDs := make([]string, 0, len(services))
+	for _, service := range services {
+		serviceIDs = append(serviceIDs, service.ID)
+	}
+	previous, err := s.specTrendDAO.ListLatest(ctx, serviceIDs, &digest.From)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range services {
+		digest.AddService(service, previous[service.ID])
+
+		trends, err := s.specTrendDAO.List(ctx, service.ID, &digest.From, &digest.To)
+		if err != nil {
+			return nil, err
+		}
+		if len(trends) == 0 {
+			continue
+		}
+
+		latest := trends[len(trends)-1]
+		latestAnalyses, err := s.specAnalysisDAO.List(ctx, &db.ListFilter{Indexes: map[string]string{"spec_id": latest.SpecID}})
+		if err != nil {
+			return nil, err
+		}
+		var previousAnalyses []*models.SpecAnalysis
+		if p := previous[service.ID]; p != nil {
+			if previousAnalyses, err = s.specAnalysisDAO.List(ctx, &db.ListFilter{Indexes: map[string]string{"spec_id": p.SpecID}}); err != nil {
+				return nil, err
+			}
+		}
+		digest.AddFindings(service, latest.SpecID, latestAnalyses, previousAnalyses)
+
+		oldSpecID := ""
+		if p := previous[service.ID]; p != nil {
+			oldSpecID = p.SpecID
+		}
+		for _, trend := range trends {
+			specDiff, err := s.releasedSpecDiff(ctx, oldSpecID, trend.SpecID)
+			if err != nil {
+				return nil, err
+			}
+			if specDiff != nil {
+				digest.AddBreakingChange(service, specDiff, trend.Version)
+			}
+			oldSpecID = trend.SpecID
+		}
+	}
+	digest.Sort()
+	return digest, nil
+}
+
+// releasedSpecDiff returns the stored breaking diff of the spec with newSpecID, preferably from the spec with oldSpecID
+// (i.e. the spec it was released after), or nil if none.
+func (s *digestScheduler) releasedSpecDiff(ctx context.Context, oldSpecID, newSpecID string) (*models.SpecDiff, error) {
+	specDiffs, err := s.specDiffDAO.List(ctx, &db.ListFilter{Indexes: map[string]string{"new_spec_id": newSpecID}})
+	if err != nil {
+		return nil, err
+	}
+	var released *models.SpecDiff
+	for _, specDiff := range specDiffs {
+		if specDiff.Result == nil || specDiff.Result.JSON == nil || !specDiff.Result.JSON.Breaking {
+			continue
+		}
+		if specDiff.SpecDiffRequest != nil && specDiff.OldSpecID == oldSpecID {
+			return specDiff, nil
+		}
+		if released == nil {
+			released = specDiff
+		}
+	}
+	return released, nil
+}
+
+// send sends digest to the recipient of subscription.
+func (s *digestScheduler) send(ctx context.Context, subscription *models.DigestSubscription, digest *models.Digest) error {
+	switch subscription.Channel {
+	case models.DigestChannelEmail:
+		return mailer.Send([]string{subscription.Recipient}, digest.Subject(), digest.Markdown())
+	case models.DigestChannelChat:
+		// Most chat webhooks (e.g. Slack, Mattermost, Webex, Microsoft Teams) accept a text (markdown) message.
+		data, err := json.Marshal(struct {
+			Text string `json:"text"`
+		}{digest.Markdown()})
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Recipient, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", restful.MIME_JSON)
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("chat webhook responded %s", resp.Status)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported digest channel: %s", subscription.Channel)
+	}
+}
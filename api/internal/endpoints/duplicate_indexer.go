@@ -0,0 +1,118 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"context"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"time"
+)
+
+var (
+	// duplicateIndexerInterval is how often the duplicate indexer refreshes the duplicate snapshot,
+	// e.g. as specs are saved by other replicas.
+	duplicateIndexerInterval = time.Hour
+	// duplicateIndexerDelay is how long the duplicate indexer waits after a refresh is requested,
+	// so that e.g. the specs of a batch upload are indexed at once.
+	duplicateIndexerDelay = 30 * time.Second
+)
+
+// duplicateIndexer maintains the models.DuplicateSnapshot of the latest specs of all services,
+// from which the duplicate report & findings are read.
+type duplicateIndexer struct {
+	dao        db.DuplicateSnapshotDAO
+	serviceDAO db.ServiceDAO
+	specDAO    db.SpecDAO
+	refreshes  chan struct{}
+}
+
+func newDuplicateIndexer(dao db.DuplicateSnapshotDAO, serviceDAO db.ServiceDAO, specDAO db.SpecDAO) *duplicateIndexer {
+	return &duplicateIndexer{dao: dao, serviceDAO: serviceDAO, specDAO: specDAO, refreshes: make(chan struct{}, 1)}
+}
+
+// start indexes the duplicates, then again every duplicateIndexerInterval & after a refresh, in the background.
+func (i *duplicateIndexer) start() {
+	go func() {
+		ctx := context.Background()
+		i.index(ctx)
+
+		ticker := time.NewTicker(duplicateIndexerInterval)
+		defer ticker.Stop()
+		var due <-chan time.Time
+		for {
+			select {
+			case <-ticker.C:
+				i.index(ctx)
+			case <-i.refreshes:
+				if due == nil {
+					due = time.After(duplicateIndexerDelay)
+				}
+			case <-due:
+				due = nil
+				i.index(ctx)
+			}
+		}
+	}()
+}
+
+// refresh requests the duplicates to be indexed again, e.g. as the latest spec of a service changed.
+func (i *duplicateIndexer) refresh() {
+	select {
+	case i.refreshes <- struct{}{}:
+	default:
+	}
+}
+
+// index saves the models.DuplicateSnapshot of the latest specs of all services.
+func (i *duplicateIndexer) index(ctx context.Context) {
+	services, err := i.serviceDAO.List(ctx, &db.ListFilter{Model: &models.Service{}}, nil)
+	if err != nil {
+		shared.LogErrorf("failed to list services for duplicates: %v", err)
+		return
+	}
+	servicesByID := make(map[string]*models.Service, len(services))
+	var specs []*models.Spec
+	for _, service := range services {
+		servicesByID[service.ID] = service
+		if service.Summary == nil {
+			continue
+		}
+		latest, err := i.specDAO.List(ctx, &db.ListFilter{
+			Model:   &models.Spec{},
+			Indexes: map[string]string{"service_id": service.ID},
+			Sorters: []*db.Sorter{{
+				Order: db.OrderDesc,
+				Field: "created_at",
+			}},
+			Limit: 1,
+		}, true)
+		if err != nil {
+			shared.LogErrorf("failed to get service (%v) latest spec for duplicates: %v", service.ID, err)
+			return
+		}
+		specs = append(specs, latest...)
+	}
+
+	snapshot := models.NewDuplicateSnapshot(ctx, servicesByID, specs)
+	if err := i.dao.Save(ctx, snapshot); err != nil {
+		shared.LogErrorf("failed to save duplicates: %v", err)
+		return
+	}
+	shared.LogDebugf("indexed %d duplicate cluster(s) of %d service(s)", len(snapshot.Report.Clusters), snapshot.Report.Services)
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type duplicateResource struct {
+	config        *shared.AppConfig
+	dao           db.DuplicateSnapshotDAO
+	serviceDAO    db.ServiceDAO
+	accessChecker access.Checker
+}
+
+// duplicateQueryParams returns the query params of the duplicate report & findings.
+func duplicateQueryParams(ws *restful.WebService) []*restful.Parameter {
+	return []*restful.Parameter{
+		ws.QueryParameter("threshold", "similarity (0 to 1) from which schemas or endpoints are near-duplicates, at least the default: a higher threshold only keeps the members of the clusters as similar to their canonical definition").DataType("number").DefaultValue(strconv.FormatFloat(models.DefaultDuplicateThreshold, 'f', -1, 64)),
+		ws.QueryParameter("kind", fmt.Sprintf("kind (%s) of duplicates, by default all", strings.Join(models.DuplicateKinds, " or "))).DataType("string"),
+	}
+}
+
+// Register the API
+// prefix: /v1/apiregistry/duplicates
+func (r *duplicateResource) Register(config *shared.AppConfig, container *restful.Container, prefix string) {
+	ws := &restful.WebService{}
+	ws.Path(prefix).Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON).ApiVersion(config.AppVersion).Doc("APIs for Duplicate.")
+	ws.Filter(middleware.ResourceAccessChecker(r.accessChecker))
+
+	var duplicateReport models.DuplicateReport
+
+	ws.Route(
+		ws.GET("").
+			To(r.getReport).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(duplicateReport, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError, http.StatusServiceUnavailable)).
+			Do(shared.RouteWrites(duplicateReport)).
+			Do(shared.RouteParams(duplicateQueryParams(ws)...)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"duplicate"}).
+			Notes("Report the clusters of duplicated or near-duplicated component schemas (by structural fingerprint & similarity of property names & types) " +
+				"& endpoints (by method & normalized path) across the latest specs of all services, each with a suggested canonical shared definition. " +
+				"The clusters are indexed in the background as specs are saved"))
+
+	container.Add(ws)
+}
+
+// GET /
+func (r *duplicateResource) getReport(req *restful.Request, res *restful.Response) {
+	shared.LogDebugf("get request to get duplicate report.")
+
+	report, ok := buildDuplicateReport(req, res, r.serviceDAO, r.dao)
+	if !ok {
+		return
+	}
+	_ = res.WriteHeaderAndEntity(http.StatusOK, report)
+}
+
+// buildDuplicateReport returns the stored duplicate report (see models.DuplicateSnapshot) restricted to the services
+// accessible to req, writing res on failure.
+func buildDuplicateReport(req *restful.Request, res *restful.Response, serviceDAO db.ServiceDAO, dao db.DuplicateSnapshotDAO) (*models.DuplicateReport, bool) {
+	threshold := models.DefaultDuplicateThreshold
+	if value := req.QueryParameter("threshold"); value != "" {
+		var err error
+		threshold, err = strconv.ParseFloat(value, 64)
+		if err != nil || threshold < models.DefaultDuplicateThreshold || threshold > 1 {
+			_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("invalid threshold: %s, must be at least %v & at most 1", value, models.DefaultDuplicateThreshold))
+			return nil, false
+		}
+	}
+	kind := req.QueryParameter("kind")
+	if kind != "" && !models.ValidDuplicateKind(kind) {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported kind: %s", kind))
+		return nil, false
+	}
+
+	ctx := req.Request.Context()
+	snapshot, err := dao.Get(ctx, models.DuplicateSnapshotID)
+	if err == db.ErrNotFound {
+		_ = res.WriteErrorString(http.StatusServiceUnavailable, "duplicates are being indexed, try again later")
+		return nil, false
+	} else if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	services, err := serviceDAO.List(ctx, &db.ListFilter{Model: &models.Service{}}, orgServiceAccessDataFilterFromReq(req))
+	if err != nil {
+		shared.LogErrorf("failed to list services for duplicates: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	serviceIDs := make(map[string]bool, len(services))
+	for _, service := range services {
+		serviceIDs[service.ID] = true
+	}
+
+	report := snapshot.Filter(serviceIDs, threshold)
+	if kind != "" {
+		report.FilterKind(kind)
+	}
+	return report, true
+}
@@ -0,0 +1,163 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type endpointResource struct {
+	config        *shared.AppConfig
+	dao           db.SpecEndpointDAO
+	serviceDAO    db.ServiceDAO
+	accessChecker access.Checker
+}
+
+// Register the API
+// prefix: /v1/apiregistry/endpoints
+func (r *endpointResource) Register(config *shared.AppConfig, container *restful.Container, prefix string) {
+	ws := &restful.WebService{}
+	ws.Path(prefix).Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON).ApiVersion(config.AppVersion).Doc("APIs for Endpoint.")
+	ws.Filter(middleware.ResourceAccessChecker(r.accessChecker))
+
+	var endpoints models.SpecEndpointResponse
+	var service = ws.QueryParameter("service", "unique identifier (UUID or Name ID) of the service of endpoints").DataType("string")
+	var specID = ws.QueryParameter("spec_id", "spec of endpoints, implies latest=false").DataType("string")
+	var endpointID = ws.QueryParameter("endpoint_id", "stable identifier of an endpoint of a service across its specs").DataType("string")
+	var method = ws.QueryParameter("method", "HTTP method of endpoints").DataType("string")
+	var path = ws.QueryParameter("path", "path prefix of endpoints, e.g. /v1/orders").DataType("string")
+	var operationID = ws.QueryParameter("operation_id", "operationId of endpoints").DataType("string")
+	var tag = ws.QueryParameter("tag", "tag of endpoints").DataType("string")
+	var deprecated = ws.QueryParameter("deprecated", "flag indicating if endpoints are deprecated").DataType("boolean")
+	var securityScheme = ws.QueryParameter("security_scheme", "security scheme of endpoints").DataType("string")
+	var contentType = ws.QueryParameter("content_type", "request or response content type of endpoints").DataType("string")
+	var latest = ws.QueryParameter("latest", "flag indicating if only endpoints of the latest spec of each service are listed, by default true").DataType("boolean").DefaultValue("true")
+	var limit = ws.QueryParameter("limit", "max items to return at one time").DataType("string")
+	var offset = ws.QueryParameter("offset", "starting offset").DataType("string")
+
+	ws.Route(
+		ws.GET("").
+			To(r.list).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteParams(offset)).
+			Do(shared.RouteParams(limit, max)).
+			Do(shared.RouteReturns(endpoints, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(endpoints)).
+			Do(shared.RouteParams(service, specID, endpointID)).
+			Do(shared.RouteParams(method, path, operationID, tag, deprecated)).
+			Do(shared.RouteParams(securityScheme, contentType, latest)).
+			Do(shared.RouteParams(sort, sortOrder)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"endpoint"}).
+			Notes("List the endpoints catalogued from the specs of all services, with specified filters"))
+
+	container.Add(ws)
+}
+
+// GET /
+func (r *endpointResource) list(req *restful.Request, res *restful.Response) {
+	shared.LogDebugf("get request to list endpoints.")
+
+	var filter = &db.ListFilter{Model: &models.SpecEndpoint{}}
+	err := filter.From(req)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if m, ok := filter.Indexes["method"]; ok {
+		filter.Indexes["method"] = strings.ToUpper(m)
+	}
+	for _, flag := range []string{"deprecated", "latest"} {
+		if v, ok := filter.Indexes[flag]; ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				_ = res.WriteErrorString(http.StatusBadRequest, "invalid "+flag+": "+v)
+				return
+			}
+			filter.Indexes[flag] = strconv.FormatBool(b)
+		}
+	}
+	if _, ok := filter.Indexes["spec_id"]; ok {
+		delete(filter.Indexes, "latest")
+	} else if filter.Indexes["latest"] == "false" {
+		delete(filter.Indexes, "latest")
+	} else {
+		filter.Indexes["latest"] = "true"
+	}
+
+	ctx := req.Request.Context()
+	services, err := r.serviceDAO.List(ctx, &db.ListFilter{Model: &models.Service{}}, orgServiceAccessDataFilterFromReq(req))
+	if err != nil {
+		shared.LogErrorf("failed to list services for endpoints: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	endpointFilter := &models.SpecEndpointFilter{
+		PathPrefix:     req.QueryParameter("path"),
+		Tag:            req.QueryParameter("tag"),
+		SecurityScheme: req.QueryParameter("security_scheme"),
+		ContentType:    req.QueryParameter("content_type"),
+	}
+	servicesByID := make(map[string]*models.Service, len(services))
+	for _, service := range services {
+		servicesByID[service.ID] = service
+	}
+	if serviceID := req.QueryParameter("service"); serviceID != "" {
+		service, err := r.serviceDAO.Get(ctx, serviceID)
+		if err != nil || servicesByID[service.ID] == nil {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		servicesByID = map[string]*models.Service{service.ID: service}
+	}
+	for serviceID := range servicesByID {
+		endpointFilter.ServiceIDs = append(endpointFilter.ServiceIDs, serviceID)
+	}
+
+	// Services whose specs were saved before endpoints were catalogued have none yet.
+	indexed, err := r.dao.IndexedServiceIDs(ctx)
+	if err != nil {
+		shared.LogErrorf("failed to list catalogued services: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for serviceID, service := range servicesByID {
+		if !indexed[serviceID] && service.Summary != nil {
+			if err := r.dao.Reindex(ctx, serviceID); err != nil {
+				shared.LogErrorf("failed to catalog service (%v) endpoints: %v", serviceID, err)
+			}
+		}
+	}
+
+	endpoints, total, err := r.dao.List(ctx, filter, endpointFilter)
+	if err != nil {
+		shared.LogErrorf("failed to list endpoints: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	shared.LogDebugf("total %v endpoint(s) returned", len(endpoints))
+	_ = res.WriteEntity(&models.SpecEndpointResponse{Pagination: filter.Pagination(total), Data: endpoints})
+}
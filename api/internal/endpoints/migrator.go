@@ -0,0 +1,104 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"context"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"time"
+)
+
+var (
+	// migratorInterval is how often the migrator retries the migrations not completed yet,
+	// e.g. as they failed, or the replica which claimed them stopped.
+	migratorInterval = 15 * time.Minute
+	// migrationLease is how long a migration is claimed by a replica, after which another replica may run it again.
+	migrationLease = time.Hour
+)
+
+// migration is a one-off data migration, run by a single replica at a time. It must be idempotent, as it's run again
+// if interrupted.
+type migration struct {
+	id  string
+	run func(ctx context.Context) error
+}
+
+// migrator runs the migrations in the background, so that they neither delay the start of the API nor run on every replica.
+type migrator struct {
+	dao        db.MigrationDAO
+	migrations []*migration
+}
+
+func newMigrator(dao db.MigrationDAO, migrations ...*migration) *migrator {
+	return &migrator{dao: dao, migrations: migrations}
+}
+
+// start runs the migrations, then again those not completed every migratorInterval, in the background.
+func (m *migrator) start() {
+	go func() {
+		ctx := context.Background()
+		pending := m.migrate(ctx, m.migrations)
+		if len(pending) == 0 {
+			return
+		}
+
+		ticker := time.NewTicker(migratorInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if pending = m.migrate(ctx, pending); len(pending) == 0 {
+				return
+			}
+		}
+	}()
+}
+
+// migrate runs the migrations, returning those not completed yet.
+func (m *migrator) migrate(ctx context.Context, migrations []*migration) []*migration {
+	var pending []*migration
+	for _, migration := range migrations {
+		if !m.run(ctx, migration) {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// run runs migration unless it's claimed by another replica, returning whether it's completed.
+func (m *migrator) run(ctx context.Context, migration *migration) bool {
+	now := time.Now().UTC()
+	claimed, err := m.dao.Claim(ctx, migration.id, now, now.Add(migrationLease))
+	if err != nil {
+		return false
+	}
+	if !claimed {
+		existing, err := m.dao.Get(ctx, migration.id)
+		return err == nil && existing.CompletedAt != nil
+	}
+
+	shared.LogInfof("running migration %s", migration.id)
+	if err := migration.run(ctx); err != nil {
+		shared.LogErrorf("failed to run migration %s: %v", migration.id, err)
+		_ = m.dao.Release(ctx, migration.id)
+		return false
+	}
+	if err := m.dao.Complete(ctx, migration.id, time.Now().UTC()); err != nil {
+		return false
+	}
+	shared.LogInfof("completed migration %s", migration.id)
+	return true
+}
@@ -176,6 +176,7 @@ func (r *organizationResource) save(req *restful.Request, res *restful.Response)
 		}
 		organization.ID = existing.ID
 		organization.CreatedAt = existing.CreatedAt
+		middleware.SetAuditBefore(req, existing)
 	}
 	organization.UpdatedAt = time.Now().UTC()
 
@@ -191,6 +192,7 @@ func (r *organizationResource) save(req *restful.Request, res *restful.Response)
 		handleError(res, err)
 		return
 	}
+	middleware.SetAuditAfter(req, organization)
 	if id == "" {
 		res.Header().Add("Location", "/v1/apiregistry/organizations/"+organization.ID)
 		_ = res.WriteHeaderAndEntity(http.StatusCreated, organization)
@@ -217,6 +219,7 @@ func (r *organizationResource) patch(req *restful.Request, res *restful.Response
 		res.WriteHeader(http.StatusNotFound)
 		return
 	}
+	middleware.SetAuditBefore(req, org)
 
 	org.UpdatedAt = time.Now().UTC()
 
@@ -256,6 +259,7 @@ func (r *organizationResource) patch(req *restful.Request, res *restful.Response
 		handleError(res, err)
 		return
 	}
+	middleware.SetAuditAfter(req, org)
 	_ = res.WriteEntity(org)
 }
 
@@ -263,6 +267,10 @@ func (r *organizationResource) delete(req *restful.Request, res *restful.Respons
 	id := req.PathParameter("id")
 	shared.LogDebugf("get request to delete organization: %v", id)
 
+	if existing, err := r.dao.Get(req.Request.Context(), id); err == nil {
+		middleware.SetAuditBefore(req, existing)
+	}
+
 	err := r.dao.Delete(req.Request.Context(), id)
 	if err == db.ErrNotFound {
 		res.WriteHeader(http.StatusNotFound)
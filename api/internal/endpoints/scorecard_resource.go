@@ -0,0 +1,177 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const mimeTextCSV = "text/csv"
+
+type scorecardResource struct {
+	config          *shared.AppConfig
+	serviceDAO      db.ServiceDAO
+	organizationDAO db.OrganizationDAO
+	specTrendDAO    db.SpecTrendDAO
+	accessChecker   access.Checker
+}
+
+// Register the API
+// prefix: /v1/apiregistry/scorecard
+func (r *scorecardResource) Register(config *shared.AppConfig, container *restful.Container, prefix string) {
+	ws := &restful.WebService{}
+	ws.Path(prefix).Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON).ApiVersion(config.AppVersion).Doc("APIs for Scorecard.")
+	ws.Filter(middleware.ResourceAccessChecker(r.accessChecker))
+
+	var (
+		scorecard models.Scorecard
+		groupBy   = ws.QueryParameter("group_by", "grouping (organization, product_tag or registry) of services").DataType("string").DefaultValue(models.ScorecardGroupByOrganization)
+		threshold = ws.QueryParameter("threshold", "score below which services are listed").DataType("integer").DefaultValue(strconv.Itoa(models.ScorecardDefaultThreshold))
+		staleDays = ws.QueryParameter("stale_days", "number of days without a new spec after which services are listed as stale").DataType("integer").DefaultValue(strconv.Itoa(models.ScorecardDefaultStaleDays))
+		format    = ws.QueryParameter("format", "format (json or csv) of scorecard").DataType("string").DefaultValue(models.ScorecardFormatJSON)
+		download  = ws.QueryParameter("download", "flag indicating if response content is to be served as a downloadable attachment (Content-Disposition), by default false").DataType("boolean").DefaultValue("false")
+	)
+
+	ws.Route(
+		ws.GET("").
+			To(r.get).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(scorecard, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(scorecard)).
+			Do(shared.RouteParams(groupBy, threshold, staleDays)).
+			Do(shared.RouteParams(format, download)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"scorecard"}).
+			Notes("Get the scorecard of services grouped by organization, product tag or for the whole registry: score distribution, average & median score per analyzer, services below the threshold, services with stale specs & week-over-week change").
+			Produces(restful.MIME_JSON, mimeTextCSV))
+
+	container.Add(ws)
+}
+
+// GET /
+func (r *scorecardResource) get(req *restful.Request, res *restful.Response) {
+	var (
+		groupBy = req.QueryParameter("group_by")
+		format  = req.QueryParameter("format")
+	)
+	shared.LogDebugf("get request to get scorecard (group_by=%v, format=%v)", groupBy, format)
+
+	if groupBy == "" {
+		groupBy = models.ScorecardGroupByOrganization
+	}
+	if !models.ValidScorecardGroupBy(groupBy) {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported scorecard grouping: %s", groupBy))
+		return
+	}
+	if format == "" {
+		format = models.ScorecardFormatJSON
+	}
+	if format != models.ScorecardFormatJSON && format != models.ScorecardFormatCSV {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported scorecard format: %s", format))
+		return
+	}
+	threshold, err := parseQueryInt(req, "threshold", models.ScorecardDefaultThreshold)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	staleDays, err := parseQueryInt(req, "stale_days", models.ScorecardDefaultStaleDays)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := req.Request.Context()
+	accessFilter := orgServiceAccessDataFilterFromReq(req)
+	services, err := r.serviceDAO.List(ctx, &db.ListFilter{Model: &models.Service{}}, accessFilter)
+	if err != nil {
+		shared.LogErrorf("failed to list services for scorecard: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	serviceIDs := make([]string, 0, len(services))
+	for _, service := range services {
+		serviceIDs = append(serviceIDs, service.ID)
+	}
+
+	now := time.Now().UTC()
+	latest, err := r.specTrendDAO.ListLatest(ctx, serviceIDs, nil)
+	if err != nil {
+		shared.LogErrorf("failed to list latest spec trends for scorecard: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	previousAt := now.Add(-models.ScorecardChangePeriod)
+	previous, err := r.specTrendDAO.ListLatest(ctx, serviceIDs, &previousAt)
+	if err != nil {
+		shared.LogErrorf("failed to list previous spec trends for scorecard: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]*models.ScorecardEntry, 0, len(services))
+	for _, service := range services {
+		entries = append(entries, &models.ScorecardEntry{
+			Service:  service,
+			Latest:   latest[service.ID],
+			Previous: previous[service.ID],
+		})
+	}
+
+	var titles map[string]string
+	if groupBy == models.ScorecardGroupByOrganization {
+		orgs, err := r.organizationDAO.List(ctx, &db.ListFilter{Model: &models.Organization{}}, accessFilter)
+		if err != nil {
+			shared.LogErrorf("failed to list organizations for scorecard: %v", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		titles = make(map[string]string, len(orgs))
+		for _, org := range orgs {
+			titles[org.ID] = org.Title
+		}
+	}
+
+	scorecard := models.NewScorecard(groupBy, threshold, staleDays, now, entries, titles)
+	if parseQueryBool(req, "download") {
+		res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=scorecard.%s", format))
+	}
+	if format == models.ScorecardFormatCSV {
+		var buf bytes.Buffer
+		if err := scorecard.WriteCSV(&buf); err != nil {
+			shared.LogErrorf("failed to write scorecard csv: %v", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		res.Header().Set(restful.HEADER_ContentType, mimeTextCSV+"; charset=utf-8")
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write(buf.Bytes())
+		return
+	}
+	_ = res.WriteHeaderAndEntity(http.StatusOK, scorecard)
+}
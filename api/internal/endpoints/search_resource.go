@@ -0,0 +1,173 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type searchResource struct {
+	config        *shared.AppConfig
+	dao           db.SpecSearchDAO
+	serviceDAO    db.ServiceDAO
+	specDAO       db.SpecDAO
+	accessChecker access.Checker
+}
+
+// Register the API
+// prefix: /v1/apiregistry/search
+func (r *searchResource) Register(config *shared.AppConfig, container *restful.Container, prefix string) {
+	ws := &restful.WebService{}
+	ws.Path(prefix).Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON).ApiVersion(config.AppVersion).Doc("APIs for Search.")
+	ws.Filter(middleware.ResourceAccessChecker(r.accessChecker))
+
+	var (
+		searchRes models.SpecSearchResponse
+		q         = ws.QueryParameter("q", "text matched (case-insensitively) against names (e.g. paths, operationIds, parameter, schema & property names), summaries & descriptions").DataType("string")
+		kind      = ws.QueryParameter("kind", fmt.Sprintf("csv kinds (%s) of spec elements to search", strings.Join(models.SpecSearchKinds, ", "))).DataType("string").CollectionFormat(restful.CollectionFormatCSV)
+		service   = ws.QueryParameter("service", "unique identifier (UUID or Name ID) of the service to search, by default all").DataType("string")
+		exact     = ws.QueryParameter("exact", "flag indicating if names should match q exactly (case-insensitively), by default false").DataType("boolean").DefaultValue("false")
+		limit     = ws.QueryParameter("limit", "max results to return").DataType("integer").DefaultValue(strconv.Itoa(models.SpecSearchDefaultLimit))
+	)
+
+	ws.Route(
+		ws.GET("").
+			To(r.search).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(searchRes, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(searchRes)).
+			Do(shared.RouteParams(q, kind)).
+			Do(shared.RouteParams(service, exact, limit)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"search"}).
+			Notes("Search the latest spec of every service for paths, operations, parameters, schemas & properties, e.g. kind=property&q=customerId&exact=true, or kind=path&q=/v1/orders. Results link to the service, spec & JSON path of the matching element"))
+
+	container.Add(ws)
+}
+
+// GET /
+func (r *searchResource) search(req *restful.Request, res *restful.Response) {
+	searchReq := &models.SpecSearchRequest{
+		Query: req.QueryParameter("q"),
+		Exact: parseQueryBool(req, "exact"),
+	}
+	shared.LogDebugf("get request to search specs (q=%v)", searchReq.Query)
+
+	if kinds := req.QueryParameter("kind"); kinds != "" {
+		for _, kind := range strings.Split(kinds, ",") {
+			kind = strings.TrimSpace(kind)
+			if !models.ValidSpecSearchKind(kind) {
+				_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported kind: %s", kind))
+				return
+			}
+			searchReq.Kinds = append(searchReq.Kinds, kind)
+		}
+	}
+	limit, err := parseQueryInt(req, "limit", models.SpecSearchDefaultLimit)
+	if err != nil || limit < 1 || limit > models.SpecSearchMaxLimit {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", models.SpecSearchMaxLimit))
+		return
+	}
+	searchReq.Limit = limit
+	if strings.TrimSpace(searchReq.Query) == "" && len(searchReq.Kinds) == 0 {
+		_ = res.WriteErrorString(http.StatusBadRequest, "q or kind is required")
+		return
+	}
+
+	ctx := req.Request.Context()
+	services, err := r.serviceDAO.List(ctx, &db.ListFilter{Model: &models.Service{}}, orgServiceAccessDataFilterFromReq(req))
+	if err != nil {
+		shared.LogErrorf("failed to list services for search: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	servicesByID := make(map[string]*models.Service, len(services))
+	for _, service := range services {
+		servicesByID[service.ID] = service
+	}
+	if serviceID := req.QueryParameter("service"); serviceID != "" {
+		service, err := r.serviceDAO.Get(ctx, serviceID)
+		if err != nil || servicesByID[service.ID] == nil {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		servicesByID = map[string]*models.Service{service.ID: service}
+	}
+	for serviceID := range servicesByID {
+		searchReq.ServiceIDs = append(searchReq.ServiceIDs, serviceID)
+	}
+
+	// Services whose specs were saved before specs were indexed have none yet.
+	indexed, err := r.dao.IndexedServiceIDs(ctx)
+	if err != nil {
+		shared.LogErrorf("failed to list indexed services for search: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for serviceID, service := range servicesByID {
+		if !indexed[serviceID] && service.Summary != nil {
+			if err := r.dao.Reindex(ctx, serviceID); err != nil {
+				shared.LogErrorf("failed to index service (%v) specs for search: %v", serviceID, err)
+			}
+		}
+	}
+
+	entries, total, err := r.dao.Search(ctx, searchReq)
+	if err != nil {
+		shared.LogErrorf("failed to search specs: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	searchRes := &models.SpecSearchResponse{Query: searchReq.Query, Total: total, Results: make([]*models.SpecSearchResult, 0, len(entries))}
+	specs := map[string]*models.Spec{}
+	resultServices := map[string]bool{}
+	for _, entry := range entries {
+		spec, ok := specs[entry.SpecID]
+		if !ok {
+			if spec, err = r.specDAO.Get(ctx, entry.SpecID, false); err != nil {
+				shared.LogErrorf("failed to get spec (%v) of search result: %v", entry.SpecID, err)
+				spec = nil
+			}
+			specs[entry.SpecID] = spec
+		}
+		result := &models.SpecSearchResult{SpecSearchEntry: entry}
+		if service := servicesByID[entry.ServiceID]; service != nil {
+			result.ServiceNameID = service.NameID
+			result.ServiceTitle = service.Title
+		}
+		if spec != nil {
+			result.Version = spec.Version
+			result.Revision = spec.Revision
+		}
+		resultServices[entry.ServiceID] = true
+		searchRes.Results = append(searchRes.Results, result)
+	}
+	searchRes.Services = len(resultServices)
+
+	_ = res.WriteHeaderAndEntity(http.StatusOK, searchRes)
+}
@@ -17,7 +17,10 @@
 package endpoints
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"github.com/cisco-developer/api-insights/api/internal/access"
@@ -27,10 +30,14 @@ import (
 	modelsanalyzer "github.com/cisco-developer/api-insights/api/internal/models/analyzer"
 	"github.com/cisco-developer/api-insights/api/internal/models/diff"
 	"github.com/cisco-developer/api-insights/api/pkg/analyzer"
+	"github.com/cisco-developer/api-insights/api/pkg/analyzer/fixer"
 	"github.com/cisco-developer/api-insights/api/pkg/apiclarity"
 	apiclarityclient "github.com/cisco-developer/api-insights/api/pkg/apiclarity/client"
 	"github.com/cisco-developer/api-insights/api/pkg/differ"
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/oasvalidator"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/specbundler"
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
 	"gopkg.in/go-playground/validator.v9"
@@ -46,6 +53,10 @@ type serviceResource struct {
 	specDAO          db.SpecDAO
 	specDiffDAO      db.SpecDiffDAO
 	specAnalysisDAO  db.SpecAnalysisDAO
+	specTrendDAO     db.SpecTrendDAO
+	consumerDAO      db.ConsumerDAO
+	webhooks         *webhookDispatcher
+	duplicates       *duplicateIndexer
 	analyzerDAO      db.AnalyzerDAO
 	organizationDAO  db.OrganizationDAO
 	analyzerSvc      analyzer.Service
@@ -58,6 +69,8 @@ type serviceResource struct {
 const (
 	mimeTextMarkdown = "text/markdown"
 	mimeTextPlain    = "text/plain"
+	mimeTextHTML     = "text/html"
+	mimeAtomXML      = "application/atom+xml"
 )
 
 // Register the API
@@ -76,6 +89,8 @@ func (r *serviceResource) Register(config *shared.AppConfig, container *restful.
 	var oldSpecID = ws.PathParameter("oldSpecID", "old spec ID").DataType("string")
 	var newSpecID = ws.PathParameter("newSpecID", "new spec ID").DataType("string")
 	var specDiffFormat = ws.QueryParameter("format", "format of spec diff result").DataType("string").DefaultValue("json")
+	var specDocFormat = ws.QueryParameter("format", "format (json or yaml) to convert spec doc to, by default the format it was uploaded in").DataType("string")
+	var normalize = ws.QueryParameter("normalize", "flag indicating if spec doc should be normalized (sorted keys, consistent indentation & resolved internal $ref aliases), by default false").DataType("boolean").DefaultValue("false")
 	var withDoc = ws.QueryParameter("withDoc", "flag indicating if doc should be included, by default false").DataType("boolean").DefaultValue("false")
 	var download = ws.QueryParameter("download", "flag indicating if response content is to be served as a downloadable attachment (Content-Disposition), by default true").DataType("boolean").DefaultValue("true")
 	var withFindings = ws.QueryParameter("withFindings", "flag indicating if result findings should be included, by default false").DataType("boolean").DefaultValue("false")
@@ -158,27 +173,47 @@ func (r *serviceResource) Register(config *shared.AppConfig, container *restful.
 	//
 
 	var (
-		spec            models.Spec
-		specs           []models.Spec
-		specAnalysisReq models.SpecAnalysisRequest
-		specAnalysisRes models.SpecAnalysisResponse
-		specDiff        models.SpecDiff
-		specDiffReq     models.SpecDiffRequest
-		specID          = ws.PathParameter("specID", "unique identifier for service spec.").DataType("string")
-		specTags        = ws.QueryParameter("tags", "tags for getting service specs").DataType("string")
-		specQ           = ws.QueryParameter("q", "searching criteria for service specs").DataType("string")
+		spec             models.Spec
+		specs            []models.Spec
+		specAnalysisReq  models.SpecAnalysisRequest
+		specAnalysisRes  models.SpecAnalysisResponse
+		specDiff         models.SpecDiff
+		specDiffReq      models.SpecDiffRequest
+		specFixReq       models.SpecFixRequest
+		specFixRes       models.SpecFixResponse
+		specReport       models.SpecReport
+		specReviewReq    models.SpecReviewRequest
+		specReviewRes    models.SpecReviewResponse
+		changelog        models.Changelog
+		changelogFeed    models.ChangelogFeed
+		serviceTrends    models.ServiceTrends
+		duplicates       models.DuplicateFindings
+		specID           = ws.PathParameter("specID", "unique identifier for service spec.").DataType("string")
+		specReportFormat = ws.QueryParameter("format", "format (html or json) of spec report").DataType("string").DefaultValue(models.SpecReportFormatHTML)
+		specReviewFormat = ws.QueryParameter("format", "format (json or markdown) of spec review").DataType("string").DefaultValue(models.SpecReviewFormatJSON)
+		changelogFormat  = ws.QueryParameter("format", "format (json or markdown) of changelog").DataType("string").DefaultValue(models.ChangelogFormatJSON)
+		changelogFrom    = ws.QueryParameter("from", "version the changelog starts from (exclusive), by default the first spec").DataType("string")
+		changelogTo      = ws.QueryParameter("to", "version the changelog ends at (inclusive), by default the latest spec").DataType("string")
+		trendsInterval   = ws.QueryParameter("interval", "interval (revision, day, week or month) of the trend buckets").DataType("string").DefaultValue(models.ServiceTrendsIntervalDay)
+		trendsFrom       = ws.QueryParameter("from", "start time (RFC 3339 or YYYY-MM-DD) of the trends, inclusive").DataType("string")
+		trendsTo         = ws.QueryParameter("to", "end time (RFC 3339 or YYYY-MM-DD) of the trends, inclusive").DataType("string")
+		specTags         = ws.QueryParameter("tags", "tags for getting service specs").DataType("string")
+		specQ            = ws.QueryParameter("q", "searching criteria for service specs").DataType("string")
 	)
 
 	ws.Route(
 		ws.POST("/{id}/specs").
 			To(r.saveSpec).
 			Do(shared.RouteAuthHeader(ws)).
-			Do(shared.RouteReturns(spec, http.StatusOK)).
-			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteReturns(spec, http.StatusOK, http.StatusCreated, http.StatusConflict)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestEntityTooLarge, http.StatusInternalServerError)).
 			Do(shared.RouteReads(spec, "spec"), shared.RouteWrites(spec)).
 			Do(shared.RouteParams(id)).
 			Metadata(restfulspec.KeyOpenAPITags, []string{"spec"}).
-			Notes("Create a new service spec"))
+			Notes("Create a new service spec; uploading a doc identical to an existing service spec returns the existing spec (200), or a 409 referencing it, depending on the spec-duplicate-policy. "+
+				"Multi-file specs are uploaded as multipart/form-data with either an archive (zip, tar or tar.gz) or files, and an optional root document path, and are bundled into a single doc. "+
+				"Specs are validated & stored with their validation errors, unless the service rejects invalid specs (400)").
+			Consumes(restful.MIME_JSON, "multipart/form-data"))
 
 	ws.Route(
 		ws.GET("/{id}/specs").
@@ -227,13 +262,14 @@ func (r *serviceResource) Register(config *shared.AppConfig, container *restful.
 			To(r.getSpecDoc).
 			Do(shared.RouteAuthHeader(ws)).
 			Do(shared.RouteReturns(specDoc, http.StatusOK)).
-			Do(shared.RouteReturns(&se, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
 			Do(shared.RouteWrites(specDoc)).
 			Do(shared.RouteParams(id)).
 			Do(shared.RouteParams(specID)).
 			Do(shared.RouteParams(download)).
+			Do(shared.RouteParams(specDocFormat, normalize)).
 			Metadata(restfulspec.KeyOpenAPITags, []string{"spec"}).
-			Notes("Get a service spec doc with specified id").
+			Notes("Get a service spec doc with specified id, optionally converted to json or yaml and/or normalized").
 			Produces(restful.MIME_JSON, mimeTextPlain))
 
 	ws.Route(
@@ -263,6 +299,36 @@ func (r *serviceResource) Register(config *shared.AppConfig, container *restful.
 			Metadata(restfulspec.KeyOpenAPITags, []string{"spec"}).
 			Notes("Get the service spec analyses (report)"))
 
+	ws.Route(
+		ws.POST("/{id}/specs/{specID}/fixes").
+			To(r.fixSpec).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(specFixRes, http.StatusOK)).
+			Do(shared.RouteReturns(spec, http.StatusCreated, http.StatusConflict)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteReads(specFixReq, "spec fix request"), shared.RouteWrites(specFixRes)).
+			Do(shared.RouteParams(id)).
+			Do(shared.RouteParams(specID)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"spec"}).
+			Notes("Apply the selected fixes (by fix IDs or rules, defaults to all) of the service spec analyses findings; " +
+				"returns the fixed doc, or saves it as a new service spec revision (201). Specs bundled from multiple files can't be fixed (400)"))
+
+	ws.Route(
+		ws.GET("/{id}/specs/{specID}/report").
+			To(r.getSpecReport).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(specReport, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(specReport)).
+			Do(shared.RouteParams(id)).
+			Do(shared.RouteParams(specID)).
+			Do(shared.RouteParams(specReportFormat)).
+			Do(shared.RouteParams(download)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"spec"}).
+			Notes("Get a self-contained report of the service spec: service metadata, scores, analyses findings by analyzer, severity & rule, "+
+				"breaking changes since the previous Release spec & the score trend").
+			Produces(restful.MIME_JSON, mimeTextHTML))
+
 	ws.Route(
 		ws.GET("/{id}/specs/analyses").
 			To(r.getServiceAnalyses).
@@ -306,6 +372,75 @@ func (r *serviceResource) Register(config *shared.AppConfig, container *restful.
 			Produces(restful.MIME_JSON, mimeTextMarkdown),
 	)
 
+	ws.Route(
+		ws.POST("/{id}/specs/review").
+			To(r.reviewSpec).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(specReviewRes, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteReads(specReviewReq, "spec review request"), shared.RouteWrites(specReviewRes)).
+			Do(shared.RouteParams(id)).
+			Do(shared.RouteParams(specReviewFormat)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"spec"}).
+			Metadata(middleware.KeyAuditSkip, true).
+			Notes("Review a spec doc, e.g. the spec changed by a pull request, against a service spec (by default the latest): "+
+				"its breaking changes, score delta & new findings, with the review as a markdown pull request comment").
+			Produces(restful.MIME_JSON, mimeTextMarkdown))
+
+	ws.Route(
+		ws.GET("/{id}/changelog").
+			To(r.getChangelog).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(changelog, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(changelog)).
+			Do(shared.RouteParams(id)).
+			Do(shared.RouteParams(changelogFrom, changelogTo)).
+			Do(shared.RouteParams(changelogFormat)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"service"}).
+			Notes("Get the changelog of the service across its specs (from & to versions), diffing consecutive specs & grouping their added, deprecated, removed & changed endpoints by version").
+			Produces(restful.MIME_JSON, mimeTextMarkdown))
+
+	ws.Route(
+		ws.GET("/{id}/changelog/feed").
+			To(r.getChangelogFeed).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(changelogFeed, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(changelogFeed)).
+			Do(shared.RouteParams(id)).
+			Do(shared.RouteParams(changelogFrom, changelogTo)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"service"}).
+			Notes("Get the changelog of the service as an Atom feed, one entry per version").
+			Produces(mimeAtomXML))
+
+	ws.Route(
+		ws.GET("/{id}/trends").
+			To(r.getTrends).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(serviceTrends, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(serviceTrends)).
+			Do(shared.RouteParams(id)).
+			Do(shared.RouteParams(trendsInterval, trendsFrom, trendsTo)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"service"}).
+			Notes("Get the score, per-analyzer scores & finding counts by severity of the service's specs, bucketed by interval. Each bucket holds the latest spec revision created within it").
+			Produces(restful.MIME_JSON))
+
+	ws.Route(
+		ws.GET("/{id}/duplicates").
+			To(r.getDuplicates).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(duplicates, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable)).
+			Do(shared.RouteWrites(duplicates)).
+			Do(shared.RouteParams(id)).
+			Do(shared.RouteParams(duplicateQueryParams(ws)...)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"service"}).
+			Notes("Get the component schemas & endpoints of the service's latest spec duplicated or near-duplicated within the latest specs of other services, " +
+				"each with its matches & a suggested canonical shared definition").
+			Produces(restful.MIME_JSON))
+
 	ws.Route(
 		ws.POST("/{id}/specs/reconstruct").
 			To(r.reconstructSpec).
@@ -375,6 +510,7 @@ func (r *serviceResource) save(req *restful.Request, res *restful.Response) {
 		}
 		service.ID = existing.ID
 		service.CreatedAt = existing.CreatedAt
+		middleware.SetAuditBefore(req, existing)
 	}
 	service.UpdatedAt = now
 
@@ -390,6 +526,7 @@ func (r *serviceResource) save(req *restful.Request, res *restful.Response) {
 		handleError(res, err)
 		return
 	}
+	middleware.SetAuditAfter(req, service)
 	if id == "" {
 		// Auto-create an organization, if it doesn't exist, for unauthenticated version.
 		if !r.info.Auth.Enabled {
@@ -435,6 +572,7 @@ func (r *serviceResource) patch(req *restful.Request, res *restful.Response) {
 		res.WriteHeader(http.StatusNotFound)
 		return
 	}
+	middleware.SetAuditBefore(req, service)
 
 	service.UpdatedAt = time.Now().UTC()
 
@@ -472,6 +610,9 @@ func (r *serviceResource) patch(req *restful.Request, res *restful.Response) {
 			service.AnalyzersConfigs[k] = v
 		}
 	}
+	if patch.RejectInvalidSpecs != nil {
+		service.RejectInvalidSpecs = *patch.RejectInvalidSpecs
+	}
 
 	err = r.validate.Struct(service)
 	if err != nil {
@@ -485,6 +626,7 @@ func (r *serviceResource) patch(req *restful.Request, res *restful.Response) {
 		handleError(res, err)
 		return
 	}
+	middleware.SetAuditAfter(req, service)
 	_ = res.WriteEntity(service)
 }
 
@@ -492,12 +634,21 @@ func (r *serviceResource) delete(req *restful.Request, res *restful.Response) {
 	id := req.PathParameter("id")
 	shared.LogDebugf("get request to delete service: %v", id)
 
-	err := r.dao.Delete(req.Request.Context(), id)
+	service, err := r.dao.Get(req.Request.Context(), id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err = r.dao.Delete(req.Request.Context(), service.ID)
 	if err == db.ErrNotFound {
 		res.WriteHeader(http.StatusNotFound)
 	} else if err != nil {
 		res.WriteHeader(http.StatusInternalServerError)
 	} else {
+		middleware.SetAuditBefore(req, service)
+		r.webhooks.dispatch(req.Request.Context(), models.WebhookEventServiceDeleted, service, nil)
+		r.duplicates.refresh()
 		res.WriteHeader(http.StatusNoContent)
 	}
 }
@@ -510,14 +661,24 @@ func (r *serviceResource) saveSpec(req *restful.Request, res *restful.Response)
 	)
 	shared.LogDebugf("get request to save service (%v) spec", serviceID)
 
-	if err := req.ReadEntity(spec); err != nil {
+	if err := spec.From(req); err != nil {
 		shared.LogErrorf("failed to get spec from body: %#v", err)
+		if errors.Is(err, specbundler.ErrArchiveTooLarge) {
+			_ = res.WriteErrorString(http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
 		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
 		return
 	}
 
-	if _, err := spec.LoadDocAsOAS(req.Request.Context(), false, true, true); err != nil {
-		shared.LogErrorf("failed to load Spec.Doc as OAS from body: %#v", err)
+	if _, err := spec.ConvertToOAS3(); err != nil {
+		shared.LogErrorf("failed to convert Spec.Doc to OAS3 from body: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+
+	if err := spec.LoadDoc(req.Request.Context(), false, true, true); err != nil {
+		shared.LogErrorf("failed to load Spec.Doc from body: %#v", err)
 		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
 		return
 	}
@@ -529,6 +690,46 @@ func (r *serviceResource) saveSpec(req *restful.Request, res *restful.Response)
 	}
 
 	serviceID = service.ID
+
+	if err := spec.SetDocHash(); err != nil {
+		shared.LogErrorf("failed to hash Spec.Doc from body: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+
+	r.createSpec(req, res, service, spec)
+}
+
+// createSpec is a utility method that validates, saves & analyzes a new (loaded & hashed) service spec,
+// unless the service already has a spec with an identical doc.
+func (r *serviceResource) createSpec(req *restful.Request, res *restful.Response, service *models.Service, spec *models.Spec) {
+	serviceID := service.ID
+
+	// Return (or reject with a reference to) the existing spec, if the service already has a spec with an identical doc.
+	existingSpec, err := r.getSpecByDocHash(req.Request.Context(), serviceID, spec.DocHash)
+	if err != nil {
+		shared.LogErrorf("failed to find service (%v) spec by doc hash (%v): %v", serviceID, spec.DocHash, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	} else if existingSpec != nil {
+		r.writeDuplicateSpec(req, res, existingSpec)
+		return
+	}
+
+	if err := spec.Validate(req.Request.Context()); err != nil {
+		shared.LogErrorf("failed to validate Spec.Doc from body: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+	if !spec.IsValid() && service.RejectInvalidSpecs {
+		shared.LogDebugf("service (%v) rejects invalid spec with %d validation error(s)", serviceID, len(spec.ValidationErrors))
+		_ = res.WriteHeaderAndEntity(http.StatusBadRequest, struct {
+			Valid            string                `json:"valid"`
+			ValidationErrors []*oasvalidator.Error `json:"validation_errors"`
+		}{spec.Valid, spec.ValidationErrors})
+		return
+	}
+
 	spec.ID = shared.TimeUUID()
 	spec.ServiceID = serviceID
 	now := time.Now().UTC()
@@ -542,9 +743,13 @@ func (r *serviceResource) saveSpec(req *restful.Request, res *restful.Response)
 	}
 
 	if err := r.specDAO.Save(req.Request.Context(), spec); err != nil {
-		handleError(res, err)
+		r.handleSaveSpecError(req, res, spec, err)
 		return
 	}
+	middleware.SetAuditResource(req, models.AuditResourceTypeSpec, spec.ID)
+	middleware.SetAuditAfter(req, spec)
+	r.webhooks.dispatch(req.Request.Context(), models.WebhookEventSpecCreated, service, models.NewWebhookSpec(spec))
+	r.duplicates.refresh()
 
 	go func() {
 		if _, err := r.runSpecAnalysisRequest(context.Background(), res, &models.SpecAnalysisRequest{
@@ -554,21 +759,106 @@ func (r *serviceResource) saveSpec(req *restful.Request, res *restful.Response)
 			true, true); err != nil {
 			shared.LogErrorf("failed to analyze service (%v) spec (%v): %#v", serviceID, spec.ID, err)
 		}
+		if err := r.notifySpecUpload(context.Background(), service, spec); err != nil {
+			shared.LogErrorf("failed to notify service (%v) spec (%v) breaking changes: %v", serviceID, spec.ID, err)
+		}
 	}()
 
 	res.Header().Add("Location", "/v1/apiregistry/services/"+serviceID+"/specs/"+spec.ID)
 	_ = res.WriteHeaderAndEntity(http.StatusCreated, spec)
 }
 
+// writeDuplicateSpec is a utility method that returns (or rejects with a reference to) the existing service spec with an identical doc,
+// as per models.SpecDuplicatePolicy.
+func (r *serviceResource) writeDuplicateSpec(req *restful.Request, res *restful.Response, existingSpec *models.Spec) {
+	shared.LogDebugf("service (%v) spec (%v) has an identical doc hash (%v)", existingSpec.ServiceID, existingSpec.ID, existingSpec.DocHash)
+	middleware.SkipAudit(req)
+	res.Header().Add("Location", "/v1/apiregistry/services/"+existingSpec.ServiceID+"/specs/"+existingSpec.ID)
+	if models.SpecDuplicatePolicy() == models.SpecDuplicatePolicyConflict {
+		_ = res.WriteHeaderAndEntity(http.StatusConflict, existingSpec)
+		return
+	}
+	_ = res.WriteHeaderAndEntity(http.StatusOK, existingSpec)
+}
+
+// handleSaveSpecError is a utility method that handles the error saving a new service spec,
+// including an identical spec having been stored concurrently (see models.Spec.DedupeHash).
+func (r *serviceResource) handleSaveSpecError(req *restful.Request, res *restful.Response, spec *models.Spec, err error) {
+	if err == db.ErrDuplicate {
+		existingSpec, getErr := r.getSpecByDocHash(req.Request.Context(), spec.ServiceID, spec.DocHash)
+		if getErr == nil && existingSpec != nil {
+			r.writeDuplicateSpec(req, res, existingSpec)
+			return
+		}
+		shared.LogErrorf("failed to find service (%v) spec by doc hash (%v): %v", spec.ServiceID, spec.DocHash, getErr)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	handleError(res, err)
+}
+
+// getSpecByDocHash is a utility method that returns the latest service spec with the given Spec.DocHash, or nil if none exists.
+func (r *serviceResource) getSpecByDocHash(ctx context.Context, serviceID, docHash string) (*models.Spec, error) {
+	specs, err := r.specDAO.List(ctx, &db.ListFilter{
+		Model: &models.Spec{},
+		Indexes: map[string]string{
+			"service_id": serviceID,
+			"doc_hash":   docHash,
+		},
+		Limit: 1,
+		Sorters: []*db.Sorter{{
+			Order: db.OrderDesc,
+			Field: "created_at",
+		}},
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	return specs[0], nil
+}
+
 // runSpecAnalysisRequest is a utility method that runs a SpecAnalysisRequest.
 // Important to note that runSpecAnalysisRequest does write to res, so handle accordingly.
 func (r *serviceResource) runSpecAnalysisRequest(ctx context.Context, res *restful.Response, specAnalysisReq *models.SpecAnalysisRequest, updateSpec, updateService bool) (*models.SpecAnalysisResponse, error) {
-	activeAnalyzers, err := r.analyzerDAO.List(context.Background(), &db.ListFilter{Indexes: map[string]string{"status": modelsanalyzer.AnalyzerStatusActive}}, true)
-	if err != nil {
+	if err := r.prepareSpecAnalysisRequest(specAnalysisReq); err != nil {
 		shared.LogErrorf("failed to list active analyzers: %s", err.Error())
 		handleError(res, err)
 		return nil, err
 	}
+	service := specAnalysisReq.Service
+
+	specAnalysisRes, err := r.analyzerSvc.Analyze(specAnalysisReq)
+	if err != nil {
+		shared.LogErrorf("failed to analyze service (%v) spec (%v): %#v", service.ID, specAnalysisReq.Spec.ID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return nil, err
+	}
+
+	for _, specAnalysis := range specAnalysisRes.Results {
+		if err := r.specAnalysisDAO.Save(ctx, specAnalysis); err != nil {
+			handleError(res, err)
+			return nil, err
+		}
+	}
+	if updateSpec {
+		if err := r.updateSpecScore(ctx, res, specAnalysisRes.SpecScore, specAnalysisReq.Spec, updateService, service); err != nil {
+			return nil, err
+		}
+	}
+	r.webhooks.dispatch(ctx, models.WebhookEventAnalysisCompleted, service, models.NewWebhookAnalysis(specAnalysisReq.Spec, specAnalysisRes))
+	return specAnalysisRes, nil
+}
+
+// prepareSpecAnalysisRequest is a utility method that sets the analyzers of a SpecAnalysisRequest to the active ones
+// (filtered by SpecAnalysisRequest.Analyzers, if any) & merges their configs with the service & request ones.
+func (r *serviceResource) prepareSpecAnalysisRequest(specAnalysisReq *models.SpecAnalysisRequest) error {
+	activeAnalyzers, err := r.analyzerDAO.List(context.Background(), &db.ListFilter{Indexes: map[string]string{"status": modelsanalyzer.AnalyzerStatusActive}}, true)
+	if err != nil {
+		return err
+	}
 
 	var sas []modelsanalyzer.SpecAnalyzer
 	for _, a := range activeAnalyzers {
@@ -610,26 +900,7 @@ func (r *serviceResource) runSpecAnalysisRequest(ctx context.Context, res *restf
 		analyzersConfigs.Merge(specAnalysisReq.AnalyzersConfigs)
 	}
 	specAnalysisReq.AnalyzersConfigs = analyzersConfigs
-
-	specAnalysisRes, err := r.analyzerSvc.Analyze(specAnalysisReq)
-	if err != nil {
-		shared.LogErrorf("failed to analyze service (%v) spec (%v): %#v", service.ID, specAnalysisReq.Spec.ID, err)
-		res.WriteHeader(http.StatusInternalServerError)
-		return nil, err
-	}
-
-	for _, specAnalysis := range specAnalysisRes.Results {
-		if err := r.specAnalysisDAO.Save(ctx, specAnalysis); err != nil {
-			handleError(res, err)
-			return nil, err
-		}
-	}
-	if updateSpec {
-		if err := r.updateSpecScore(ctx, res, specAnalysisRes.SpecScore, specAnalysisReq.Spec, updateService, service); err != nil {
-			return nil, err
-		}
-	}
-	return specAnalysisRes, nil
+	return nil
 }
 
 // runSpecAnalysisRequest is a utility method that updates the spec score (and optionally, the service score as well).
@@ -644,6 +915,10 @@ func (r *serviceResource) updateSpecScore(ctx context.Context, res *restful.Resp
 		return err
 	}
 	if updateService {
+		var previousScore *int
+		if service.Summary != nil {
+			previousScore = service.Summary.Score
+		}
 		service.UpdatedAt = now
 		service.SetSummary(score, spec.Version, spec.Revision, now)
 		err = r.dao.Save(ctx, service, nil)
@@ -651,6 +926,14 @@ func (r *serviceResource) updateSpecScore(ctx context.Context, res *restful.Resp
 			handleError(res, err)
 			return err
 		}
+		r.duplicates.refresh()
+		if previousScore != nil && score < *previousScore {
+			r.webhooks.dispatch(ctx, models.WebhookEventScoreRegressed, service, &models.WebhookScoreRegression{
+				Spec:          models.NewWebhookSpec(spec),
+				PreviousScore: *previousScore,
+				Score:         score,
+			})
+		}
 	}
 	return nil
 }
@@ -722,6 +1005,10 @@ func (r *serviceResource) deleteSpec(req *restful.Request, res *restful.Response
 	)
 	shared.LogDebugf("get request to delete service (%v) spec: %v", serviceID, specID)
 
+	if spec, err := r.specDAO.Get(req.Request.Context(), specID, false); err == nil {
+		middleware.SetAuditBefore(req, spec)
+	}
+
 	err := r.specDAO.Delete(req.Request.Context(), specID)
 	if err == db.ErrNotFound {
 		res.WriteHeader(http.StatusNotFound)
@@ -768,6 +1055,7 @@ func (r *serviceResource) deleteSpec(req *restful.Request, res *restful.Response
 			}
 		}
 	}
+	r.duplicates.refresh()
 
 	// Delete all spec-related entities (e.g. spec analyses, spec diffs).
 	if err := r.specAnalysisDAO.BatchDeleteBySpecID(req.Request.Context(), specID); err != nil {
@@ -795,6 +1083,14 @@ func (r *serviceResource) getSpecDoc(req *restful.Request, res *restful.Response
 	if err != nil {
 		download = true
 	}
+	var (
+		format    = req.QueryParameter("format")
+		normalize = parseQueryBool(req, "normalize")
+	)
+	if format != "" && format != utils.DocFormatJSON && format != utils.DocFormatYAML {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported spec doc format: %s", format))
+		return
+	}
 	shared.LogDebugf("get request to get service (%v) spec doc: %v", serviceID, specID)
 
 	s, err := r.dao.Get(req.Request.Context(), serviceID)
@@ -810,30 +1106,58 @@ func (r *serviceResource) getSpecDoc(req *restful.Request, res *restful.Response
 		return
 	}
 
-	_, isJSON, err := spec.GetDocAsMap()
-	if err != nil {
-		shared.LogErrorf("failed to get spec doc as map for service (%v) spec (%v): %v", serviceID, specID, err)
-		res.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
 	var (
 		filename    = "spec-" + spec.Version + "-" + spec.Revision
 		contentType string
+		isJSON      bool
+		doc         = []byte(*spec.Doc)
 	)
-	if isJSON {
-		filename += ".json"
-		contentType = restful.MIME_JSON
-	} else {
-		filename += ".yaml"
+	switch {
+	case spec.IsProtobuf() && !json.Valid(doc), spec.IsGraphQL():
+		// .proto & .graphql sources are not maps.
+		if format != "" || normalize {
+			_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("%s spec docs cannot be converted or normalized", spec.DocKind()))
+			return
+		}
+		if spec.IsGraphQL() {
+			filename += ".graphql"
+		} else {
+			filename += ".proto"
+		}
 		contentType = mimeTextPlain
+	default:
+		if _, isJSON, err = spec.GetDocAsMap(); err != nil {
+			shared.LogErrorf("failed to get spec doc as map for service (%v) spec (%v): %v", serviceID, specID, err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if format != "" {
+			isJSON = format == utils.DocFormatJSON
+		}
+		if normalize {
+			doc, err = utils.NormalizeDoc(doc, format)
+		} else {
+			doc, err = utils.ConvertDoc(doc, format)
+		}
+		if err != nil {
+			shared.LogErrorf("failed to convert spec doc for service (%v) spec (%v): %v", serviceID, specID, err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if isJSON {
+			filename += ".json"
+			contentType = restful.MIME_JSON
+		} else {
+			filename += ".yaml"
+			contentType = mimeTextPlain
+		}
 	}
 	res.Header().Set(restful.HEADER_ContentType, contentType)
 	if download {
 		res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%v", filename))
 	}
 
-	_, _ = res.Write([]byte(*spec.Doc))
+	_, _ = res.Write(doc)
 }
 
 // POST /{id}/specs/{specID}/analyses
@@ -914,13 +1238,20 @@ func (r *serviceResource) getAnalyses(req *restful.Request, res *restful.Respons
 	_ = res.WriteHeaderAndEntity(http.StatusOK, specAnalyses)
 }
 
-// GET /{id}/specs/analyses
-func (r *serviceResource) getServiceAnalyses(req *restful.Request, res *restful.Response) {
+// POST /{id}/specs/{specID}/fixes
+func (r *serviceResource) fixSpec(req *restful.Request, res *restful.Response) {
 	var (
-		serviceID    = req.PathParameter("id")
-		withFindings = parseQueryBool(req, "withFindings")
+		serviceID  = req.PathParameter("id")
+		specID     = req.PathParameter("specID")
+		specFixReq = &models.SpecFixRequest{}
 	)
-	shared.LogDebugf("get request to get service (%v) analyses", serviceID)
+	shared.LogDebugf("get request to fix service (%v) spec (%v)", serviceID, specID)
+
+	if err := req.ReadEntity(specFixReq); err != nil {
+		shared.LogErrorf("failed to read spec fix request: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
 
 	service, err := r.dao.Get(req.Request.Context(), serviceID)
 	if err != nil {
@@ -929,10 +1260,26 @@ func (r *serviceResource) getServiceAnalyses(req *restful.Request, res *restful.
 	}
 
 	serviceID = service.ID
+	spec, err := r.specDAO.Get(req.Request.Context(), specID, true)
+	if err != nil || spec.ServiceID != serviceID {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if spec.Doc == nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, "spec has no doc")
+		return
+	}
+	if spec.Sources != nil {
+		// Fixes are patches against a single doc, which can't be mapped back to the files a bundled spec was uploaded as.
+		_ = res.WriteErrorString(http.StatusBadRequest, "fixes can't be applied to specs bundled from multiple files")
+		return
+	}
+
 	specAnalyses, err := r.specAnalysisDAO.List(req.Request.Context(), &db.ListFilter{
 		Model: &models.SpecAnalysis{},
 		Indexes: map[string]string{
 			"service_id": serviceID,
+			"spec_id":    spec.ID,
 		},
 		Sorters: []*db.Sorter{{
 			Order: db.OrderDesc,
@@ -945,77 +1292,416 @@ func (r *serviceResource) getServiceAnalyses(req *restful.Request, res *restful.
 	}
 	specAnalyses = models.DistinctSpecAnalyses(specAnalyses)
 
-	if !withFindings {
+	// Attach fixes to findings of analyses which predate fixes (see analyzer.Service.Analyze).
+	if spec.OriginalDocType == "" && (spec.DocKind() == models.SpecDocKindOpenAPI || spec.DocKind() == models.SpecDocKindAsyncAPI) {
 		for _, specAnalysis := range specAnalyses {
-			specAnalysis.OmitResultFindings()
+			if err := fixer.Attach([]byte(*spec.Doc), specAnalysis.Result); err != nil {
+				shared.LogErrorf("failed to attach fixes to service (%v) spec (%v) analysis (%v): %v", serviceID, spec.ID, specAnalysis.ID, err)
+				res.WriteHeader(http.StatusInternalServerError)
+				return
+			}
 		}
 	}
 
-	_ = res.WriteHeaderAndEntity(http.StatusOK, specAnalyses)
-}
-
-// POST /{id}/specs/diff
-func (r *serviceResource) createDiff(req *restful.Request, res *restful.Response) {
-	var (
-		serviceID   = req.PathParameter("id")
-		specDiffReq = &models.SpecDiffRequest{}
-	)
-	if err := req.ReadEntity(specDiffReq); err != nil {
-		shared.LogErrorf("failed to get specDiffReq from body: %#v", err)
-		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+	fixes := specFixReq.SelectedFixes(specAnalyses)
+	applied, err := fixer.Apply([]byte(*spec.Doc), fixes)
+	if err != nil {
+		shared.LogErrorf("failed to apply %d fix(es) to service (%v) spec (%v): %v", len(fixes), serviceID, spec.ID, err)
+		res.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	shared.LogDebugf("get request to diff service (%v) specs (old=%v, new=%v)", serviceID, specDiffReq.OldSpecID, specDiffReq.NewSpecID)
+	shared.LogDebugf("applied %d fix(es), skipped %d fix(es)", len(applied.Applied), len(applied.Skipped))
 
-	s, err := r.dao.Get(req.Request.Context(), serviceID)
-	if err != nil {
-		res.WriteHeader(http.StatusNotFound)
+	if !specFixReq.Save {
+		middleware.SkipAudit(req)
+		_ = res.WriteHeaderAndEntity(http.StatusOK, &models.SpecFixResponse{
+			Doc:     string(applied.Doc),
+			Applied: applied.Applied,
+			Skipped: applied.Skipped,
+		})
 		return
 	}
 
-	serviceID = s.ID
+	revision := specFixReq.Revision
+	if revision == "" {
+		revision = models.NextSpecRevision(spec.Revision)
+	}
+	fixedSpec := &models.Spec{
+		Doc:      models.NewSpecDocFromBytes(applied.Doc),
+		Revision: revision,
+		State:    spec.State,
+		Version:  spec.Version,
+	}
 
-	// Return the spec diff if it already exists.
-	if specDiffList, err := r.specDiffDAO.List(req.Request.Context(), &db.ListFilter{
-		Model: new(models.Spec),
-		Indexes: map[string]string{
-			"old_spec_id": specDiffReq.OldSpecID,
-			"new_spec_id": specDiffReq.NewSpecID,
-		},
-	}); err != nil {
+	if err := fixedSpec.LoadDoc(req.Request.Context(), false, true, true); err != nil {
+		shared.LogErrorf("failed to load fixed Spec.Doc: %#v", err)
 		res.WriteHeader(http.StatusInternalServerError)
 		return
-	} else if len(specDiffList) > 0 {
-		var matchingStoredSpecDiff *models.SpecDiff
-		for _, storedSpecDiff := range specDiffList {
-			if specDiffReq.Compare(storedSpecDiff.SpecDiffRequest) {
-				matchingStoredSpecDiff = storedSpecDiff
-				break
-			}
-		}
-		if matchingStoredSpecDiff != nil {
-			res.Header().Add("Location", "/v1/apiregistry/services/"+serviceID+"/specs/diff/"+matchingStoredSpecDiff.ID)
-			_ = res.WriteHeaderAndEntity(http.StatusCreated, matchingStoredSpecDiff)
-			return
-		}
 	}
 
-	oldSpec, err := r.specDAO.Get(req.Request.Context(), specDiffReq.OldSpecID, true)
-	if err != nil {
-		res.WriteHeader(http.StatusNotFound)
+	if err := fixedSpec.SetDocHash(); err != nil {
+		shared.LogErrorf("failed to hash fixed Spec.Doc: %#v", err)
+		res.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	specDiffReq.OldSpecDoc = oldSpec.Doc
 
-	newSpec, err := r.specDAO.Get(req.Request.Context(), specDiffReq.NewSpecID, true)
-	if err != nil {
-		res.WriteHeader(http.StatusNotFound)
-		return
-	}
-	specDiffReq.NewSpecDoc = newSpec.Doc
+	r.createSpec(req, res, service, fixedSpec)
+}
 
-	now := time.Now().UTC()
-	specDiff := &models.SpecDiff{
+// GET /{id}/specs/analyses
+func (r *serviceResource) getServiceAnalyses(req *restful.Request, res *restful.Response) {
+	var (
+		serviceID    = req.PathParameter("id")
+		withFindings = parseQueryBool(req, "withFindings")
+	)
+	shared.LogDebugf("get request to get service (%v) analyses", serviceID)
+
+	service, err := r.dao.Get(req.Request.Context(), serviceID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	serviceID = service.ID
+	specAnalyses, err := r.specAnalysisDAO.List(req.Request.Context(), &db.ListFilter{
+		Model: &models.SpecAnalysis{},
+		Indexes: map[string]string{
+			"service_id": serviceID,
+		},
+		Sorters: []*db.Sorter{{
+			Order: db.OrderDesc,
+			Field: "created_at",
+		}},
+	})
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	specAnalyses = models.DistinctSpecAnalyses(specAnalyses)
+
+	if !withFindings {
+		for _, specAnalysis := range specAnalyses {
+			specAnalysis.OmitResultFindings()
+		}
+	}
+
+	_ = res.WriteHeaderAndEntity(http.StatusOK, specAnalyses)
+}
+
+// GET /{id}/specs/{specID}/report
+func (r *serviceResource) getSpecReport(req *restful.Request, res *restful.Response) {
+	var (
+		serviceID = req.PathParameter("id")
+		specID    = req.PathParameter("specID")
+		format    = req.QueryParameter("format")
+	)
+	// Default download as true.
+	download, err := strconv.ParseBool(req.QueryParameter("download"))
+	if err != nil {
+		download = true
+	}
+	if format == "" {
+		format = models.SpecReportFormatHTML
+	}
+	if format != models.SpecReportFormatHTML && format != models.SpecReportFormatJSON {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported spec report format: %s", format))
+		return
+	}
+	shared.LogDebugf("get request to get service (%v) spec (%v) report", serviceID, specID)
+
+	ctx := req.Request.Context()
+	service, err := r.dao.Get(ctx, serviceID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	serviceID = service.ID
+	spec, err := r.specDAO.Get(ctx, specID, false)
+	if err != nil || spec.ServiceID != serviceID {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	specAnalyses, err := r.specAnalysisDAO.List(ctx, &db.ListFilter{
+		Model: &models.SpecAnalysis{},
+		Indexes: map[string]string{
+			"service_id": serviceID,
+			"spec_id":    spec.ID,
+		},
+		Sorters: []*db.Sorter{{
+			Order: db.OrderDesc,
+			Field: "created_at",
+		}},
+	})
+	if err != nil {
+		shared.LogErrorf("failed to list service (%v) spec (%v) analyses: %v", serviceID, specID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	analyzers, err := r.analyzerDAO.List(ctx, &db.ListFilter{}, true)
+	if err != nil {
+		shared.LogErrorf("failed to list analyzers: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	specs, err := r.specDAO.List(ctx, &db.ListFilter{
+		Model:   &models.Spec{},
+		Indexes: map[string]string{"service_id": serviceID},
+		Sorters: []*db.Sorter{{
+			Order: db.OrderAsc,
+			Field: "created_at",
+		}},
+	}, false)
+	if err != nil {
+		shared.LogErrorf("failed to list service (%v) specs: %v", serviceID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	report := models.NewSpecReport(service, spec, models.DistinctSpecAnalyses(specAnalyses), analyzers, specs)
+	if previous := models.PreviousReleaseSpec(spec, specs); previous != nil {
+		result, err := r.getOrCreateSpecDiffJSON(ctx, serviceID, previous.ID, spec.ID)
+		if err != nil {
+			// The report remains useful without the changes: log & leave them out.
+			shared.LogErrorf("failed to diff service (%v) specs (old=%v, new=%v) for report: %v", serviceID, previous.ID, spec.ID, err)
+		} else {
+			report.SetChanges(previous, result)
+		}
+	}
+
+	if download {
+		res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%v", report.Filename(format)))
+	}
+	if format == models.SpecReportFormatJSON {
+		_ = res.WriteHeaderAndEntity(http.StatusOK, report)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteHTML(&buf); err != nil {
+		shared.LogErrorf("failed to render service (%v) spec (%v) report: %v", serviceID, specID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set(restful.HEADER_ContentType, mimeTextHTML+"; charset=utf-8")
+	res.WriteHeader(http.StatusOK)
+	_, _ = res.Write(buf.Bytes())
+}
+
+// getOrCreateSpecDiffJSON is a utility method that returns the JSON diff result of the given service specs,
+// from their stored spec diff if one exists, or diffs them & stores the spec diff otherwise (see getOrCreateSpecDiff).
+func (r *serviceResource) getOrCreateSpecDiffJSON(ctx context.Context, serviceID, oldSpecID, newSpecID string) (*diff.JSONResult, error) {
+	specDiff, err := r.getOrCreateSpecDiff(ctx, serviceID, oldSpecID, newSpecID)
+	if err != nil {
+		return nil, err
+	}
+	return specDiff.Result.JSON, nil
+}
+
+// getOrCreateSpecDiff is a utility method that returns the stored JSON spec diff of the given service specs if one exists,
+// or diffs them & stores the spec diff otherwise.
+// It notifies no one of the spec diff, so that it serves read-only requests (e.g. reports): see notifySpecDiff.
+func (r *serviceResource) getOrCreateSpecDiff(ctx context.Context, serviceID, oldSpecID, newSpecID string) (*models.SpecDiff, error) {
+	specDiffReq := &models.SpecDiffRequest{
+		OldSpecID: oldSpecID,
+		NewSpecID: newSpecID,
+		SpecDiffConfig: models.SpecDiffConfig{
+			Config: &diff.Config{OutputFormat: "json"},
+		},
+	}
+
+	specDiffList, err := r.specDiffDAO.List(ctx, &db.ListFilter{
+		Model: new(models.Spec),
+		Indexes: map[string]string{
+			"old_spec_id": oldSpecID,
+			"new_spec_id": newSpecID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, storedSpecDiff := range specDiffList {
+		if specDiffReq.Compare(storedSpecDiff.SpecDiffRequest) && storedSpecDiff.Result != nil && storedSpecDiff.Result.JSON != nil {
+			return storedSpecDiff, nil
+		}
+	}
+
+	oldSpec, err := r.specDAO.Get(ctx, oldSpecID, true)
+	if err != nil {
+		return nil, err
+	}
+	specDiffReq.OldSpecDoc = oldSpec.Doc
+	newSpec, err := r.specDAO.Get(ctx, newSpecID, true)
+	if err != nil {
+		return nil, err
+	}
+	specDiffReq.NewSpecDoc = newSpec.Doc
+
+	result, err := r.differSvc.Diff(specDiffReq)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	specDiff := &models.SpecDiff{
+		ID: shared.TimeUUID(),
+		SpecDiffRequest: &models.SpecDiffRequest{
+			NewSpecID: newSpecID,
+			OldSpecID: oldSpecID,
+		},
+		ServiceID: serviceID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	specDiff.Config = specDiffReq.Config
+	if err := specDiff.SetResult(result, "Diffed"); err != nil {
+		return nil, err
+	}
+	if err := r.specDiffDAO.Save(ctx, specDiff); err != nil {
+		return nil, err
+	}
+	if result.JSON == nil {
+		return nil, fmt.Errorf("no json diff result")
+	}
+	return specDiff, nil
+}
+
+// notifySpecUpload diffs spec, just uploaded to service, from the spec it's released after (see models.PreviousReleaseSpec),
+// & notifies the consumers impacted by its breaking changes & the webhooks subscribed to them (see notifySpecDiff).
+func (r *serviceResource) notifySpecUpload(ctx context.Context, service *models.Service, spec *models.Spec) error {
+	specs, err := r.specDAO.List(ctx, &db.ListFilter{
+		Model:   &models.Spec{},
+		Indexes: map[string]string{"service_id": service.ID},
+		Sorters: []*db.Sorter{{
+			Order: db.OrderAsc,
+			Field: "created_at",
+		}},
+	}, false)
+	if err != nil {
+		return err
+	}
+	previous := models.PreviousReleaseSpec(spec, specs)
+	if previous == nil {
+		return nil
+	}
+	specDiff, err := r.getOrCreateSpecDiff(ctx, service.ID, previous.ID, spec.ID)
+	if err != nil {
+		return err
+	}
+	impact, consumers := r.consumerImpact(ctx, service.ID, specDiff.Result.JSON)
+	r.notifySpecDiff(ctx, service, specDiff, impact, consumers)
+	return nil
+}
+
+// notifySpecDiff notifies the consumers impacted by the breaking changes of specDiff of service,
+// & the webhooks subscribed to them.
+func (r *serviceResource) notifySpecDiff(ctx context.Context, service *models.Service, specDiff *models.SpecDiff, impact *diff.Impact, consumers []*models.Consumer) {
+	if specDiff.Result == nil || specDiff.Result.JSON == nil || !specDiff.Result.JSON.Breaking {
+		return
+	}
+	notifyConsumers(ctx, r.webhooks, service, specDiff, impact, consumers)
+	r.webhooks.dispatch(ctx, models.WebhookEventDiffBreaking, service, &models.WebhookDiff{
+		ID:        specDiff.ID,
+		OldSpecID: specDiff.OldSpecID,
+		NewSpecID: specDiff.NewSpecID,
+		Result:    specDiff.Result.JSON,
+	})
+}
+
+// consumerImpact returns the impact of the breaking changes of result on the consumers of the service with serviceID,
+// along with the consumers, unless result has no breaking change.
+func (r *serviceResource) consumerImpact(ctx context.Context, serviceID string, result *diff.JSONResult) (*diff.Impact, []*models.Consumer) {
+	if result == nil || !result.Breaking {
+		return nil, nil
+	}
+	consumers, err := r.consumerDAO.List(ctx, &db.ListFilter{
+		Model:   &models.Consumer{},
+		Indexes: map[string]string{"service_id": serviceID},
+	}, nil)
+	if err != nil {
+		// The diff remains useful without its impact: log & leave it out.
+		shared.LogErrorf("failed to list service (%v) consumers: %v", serviceID, err)
+		return nil, nil
+	}
+	return models.NewConsumerImpact(result, consumers), consumers
+}
+
+// setSpecDiffImpact sets the impact of the breaking changes of specDiff on the consumers of the service with serviceID,
+// returning it along with the consumers.
+func (r *serviceResource) setSpecDiffImpact(ctx context.Context, serviceID string, specDiff *models.SpecDiff) (*diff.Impact, []*models.Consumer) {
+	if specDiff.Result == nil || specDiff.Result.JSON == nil {
+		return nil, nil
+	}
+	impact, consumers := r.consumerImpact(ctx, serviceID, specDiff.Result.JSON)
+	specDiff.Result.JSON.Impact = impact
+	return impact, consumers
+}
+
+// POST /{id}/specs/diff
+func (r *serviceResource) createDiff(req *restful.Request, res *restful.Response) {
+	var (
+		serviceID   = req.PathParameter("id")
+		specDiffReq = &models.SpecDiffRequest{}
+	)
+	if err := req.ReadEntity(specDiffReq); err != nil {
+		shared.LogErrorf("failed to get specDiffReq from body: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+	shared.LogDebugf("get request to diff service (%v) specs (old=%v, new=%v)", serviceID, specDiffReq.OldSpecID, specDiffReq.NewSpecID)
+
+	s, err := r.dao.Get(req.Request.Context(), serviceID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	serviceID = s.ID
+
+	// Return the spec diff if it already exists.
+	if specDiffList, err := r.specDiffDAO.List(req.Request.Context(), &db.ListFilter{
+		Model: new(models.Spec),
+		Indexes: map[string]string{
+			"old_spec_id": specDiffReq.OldSpecID,
+			"new_spec_id": specDiffReq.NewSpecID,
+		},
+	}); err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	} else if len(specDiffList) > 0 {
+		var matchingStoredSpecDiff *models.SpecDiff
+		for _, storedSpecDiff := range specDiffList {
+			if specDiffReq.Compare(storedSpecDiff.SpecDiffRequest) {
+				matchingStoredSpecDiff = storedSpecDiff
+				break
+			}
+		}
+		if matchingStoredSpecDiff != nil {
+			// Nothing is created for a stored diff.
+			middleware.SkipAudit(req)
+			r.setSpecDiffImpact(req.Request.Context(), serviceID, matchingStoredSpecDiff)
+			res.Header().Add("Location", "/v1/apiregistry/services/"+serviceID+"/specs/diff/"+matchingStoredSpecDiff.ID)
+			_ = res.WriteHeaderAndEntity(http.StatusCreated, matchingStoredSpecDiff)
+			return
+		}
+	}
+
+	oldSpec, err := r.specDAO.Get(req.Request.Context(), specDiffReq.OldSpecID, true)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	specDiffReq.OldSpecDoc = oldSpec.Doc
+
+	newSpec, err := r.specDAO.Get(req.Request.Context(), specDiffReq.NewSpecID, true)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	specDiffReq.NewSpecDoc = newSpec.Doc
+
+	now := time.Now().UTC()
+	specDiff := &models.SpecDiff{
 		ID: shared.TimeUUID(),
 		SpecDiffRequest: &models.SpecDiffRequest{
 			NewSpecID: specDiffReq.NewSpecID,
@@ -1041,6 +1727,8 @@ func (r *serviceResource) createDiff(req *restful.Request, res *restful.Response
 		handleError(res, err)
 		return
 	}
+	impact, consumers := r.setSpecDiffImpact(req.Request.Context(), serviceID, specDiff)
+	r.notifySpecDiff(req.Request.Context(), s, specDiff, impact, consumers)
 
 	res.Header().Add("Location", "/v1/apiregistry/services/"+serviceID+"/specs/diff/"+specDiff.ID)
 	_ = res.WriteHeaderAndEntity(http.StatusCreated, specDiff)
@@ -1079,10 +1767,14 @@ func (r *serviceResource) getOrCreateDiff(req *restful.Request, res *restful.Res
 	var writeSpecDiffResult = func(res *restful.Response, specDiff *models.SpecDiff, format string) error {
 		switch format {
 		case "markdown":
+			markdown := specDiff.Result.Markdown
+			if specDiff.Result.JSON != nil && specDiff.Result.JSON.Impact != nil {
+				markdown += "\n" + specDiff.Result.JSON.Impact.Markdown()
+			}
 			res.Header().Set(restful.HEADER_ContentType, mimeTextMarkdown)
 			res.Header().Set("Content-Disposition", "attachment; filename=changelog.md")
 			res.WriteHeader(http.StatusOK)
-			_, _ = res.Write([]byte(specDiff.Result.Markdown))
+			_, _ = res.Write([]byte(markdown))
 		case "json":
 			res.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
 			return res.WriteHeaderAndEntity(http.StatusOK, specDiff.Result.JSON)
@@ -1109,6 +1801,7 @@ func (r *serviceResource) getOrCreateDiff(req *restful.Request, res *restful.Res
 			}
 		}
 		if matchingStoredSpecDiff != nil {
+			r.setSpecDiffImpact(req.Request.Context(), serviceID, matchingStoredSpecDiff)
 			res.Header().Add("Location", "/v1/apiregistry/services/"+serviceID+"/specs/diff/"+matchingStoredSpecDiff.ID)
 			_ = writeSpecDiffResult(res, matchingStoredSpecDiff, specDiffFormat)
 			return
@@ -1156,11 +1849,330 @@ func (r *serviceResource) getOrCreateDiff(req *restful.Request, res *restful.Res
 		handleError(res, err)
 		return
 	}
+	// Consumers are notified of breaking changes by the POST diff & spec upload only, not by a GET.
+	r.setSpecDiffImpact(req.Request.Context(), serviceID, specDiff)
 
 	res.Header().Add("Location", "/v1/apiregistry/services/"+serviceID+"/specs/diff/"+specDiff.ID)
 	_ = writeSpecDiffResult(res, specDiff, specDiffFormat)
 }
 
+// POST /{id}/specs/review
+func (r *serviceResource) reviewSpec(req *restful.Request, res *restful.Response) {
+	var (
+		serviceID     = req.PathParameter("id")
+		format        = req.QueryParameter("format")
+		specReviewReq = &models.SpecReviewRequest{}
+	)
+	if format == "" {
+		format = models.SpecReviewFormatJSON
+	}
+	if format != models.SpecReviewFormatJSON && format != models.SpecReviewFormatMarkdown {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported spec review format: %s", format))
+		return
+	}
+	shared.LogDebugf("get request to review service (%v) spec", serviceID)
+
+	if err := req.ReadEntity(specReviewReq); err != nil || specReviewReq.Doc == nil {
+		shared.LogErrorf("failed to read spec review request: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+
+	ctx := req.Request.Context()
+	service, err := r.dao.Get(ctx, serviceID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	serviceID = service.ID
+	var base *models.Spec
+	if specReviewReq.SpecID != "" {
+		base, err = r.specDAO.Get(ctx, specReviewReq.SpecID, true)
+		if err != nil || base.ServiceID != serviceID {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+	} else {
+		specs, err := r.specDAO.List(ctx, &db.ListFilter{
+			Model:   &models.Spec{},
+			Indexes: map[string]string{"service_id": serviceID},
+			Limit:   1,
+			Sorters: []*db.Sorter{{
+				Order: db.OrderDesc,
+				Field: "created_at",
+			}},
+		}, true)
+		if err != nil {
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if len(specs) > 0 {
+			base = specs[0]
+		}
+	}
+
+	spec := &models.Spec{Doc: specReviewReq.Doc, ServiceID: serviceID}
+	if _, err := spec.ConvertToOAS3(); err != nil {
+		shared.LogErrorf("failed to convert Spec.Doc to OAS3: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := spec.LoadDoc(ctx, false, true, true); err != nil {
+		shared.LogErrorf("failed to load Spec.Doc: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var (
+		diffResult   *diff.JSONResult
+		baseAnalyses []*models.SpecAnalysis
+	)
+	if base != nil {
+		result, err := r.differSvc.Diff(&models.SpecDiffRequest{
+			OldSpecDoc: base.Doc,
+			NewSpecDoc: spec.Doc,
+			SpecDiffConfig: models.SpecDiffConfig{
+				Config: &diff.Config{OutputFormat: "json"},
+			},
+		})
+		if err != nil {
+			// The review remains useful without the changes, e.g. when the spec kind changed: log & leave them out.
+			shared.LogErrorf("failed to diff service (%v) spec (%v) for review: %v", serviceID, base.ID, err)
+		} else {
+			diffResult = result.JSON
+		}
+
+		specAnalyses, err := r.specAnalysisDAO.List(ctx, &db.ListFilter{
+			Model: &models.SpecAnalysis{},
+			Indexes: map[string]string{
+				"service_id": serviceID,
+				"spec_id":    base.ID,
+			},
+			Sorters: []*db.Sorter{{
+				Order: db.OrderDesc,
+				Field: "created_at",
+			}},
+		})
+		if err != nil {
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		baseAnalyses = models.DistinctSpecAnalyses(specAnalyses)
+	}
+
+	specAnalysisReq := &models.SpecAnalysisRequest{
+		Analyzers: specReviewReq.Analyzers,
+		Spec:      spec,
+		Service:   service,
+	}
+	if err := r.prepareSpecAnalysisRequest(specAnalysisReq); err != nil {
+		shared.LogErrorf("failed to list active analyzers: %s", err.Error())
+		handleError(res, err)
+		return
+	}
+	specAnalysisRes, err := r.analyzerSvc.Analyze(specAnalysisReq)
+	if err != nil {
+		shared.LogErrorf("failed to analyze service (%v) spec for review: %#v", serviceID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	specReviewRes := models.NewSpecReviewResponse(base, baseAnalyses, diffResult, specAnalysisRes)
+	if format == models.SpecReviewFormatMarkdown {
+		res.Header().Set(restful.HEADER_ContentType, mimeTextMarkdown)
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(specReviewRes.Markdown))
+		return
+	}
+	_ = res.WriteHeaderAndEntity(http.StatusOK, specReviewRes)
+}
+
+// GET /{id}/changelog
+func (r *serviceResource) getChangelog(req *restful.Request, res *restful.Response) {
+	format := req.QueryParameter("format")
+	if format == "" {
+		format = models.ChangelogFormatJSON
+	}
+	if format != models.ChangelogFormatJSON && format != models.ChangelogFormatMarkdown {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported changelog format: %s", format))
+		return
+	}
+
+	changelog, ok := r.buildChangelog(req, res)
+	if !ok {
+		return
+	}
+	if format == models.ChangelogFormatMarkdown {
+		res.Header().Set(restful.HEADER_ContentType, mimeTextMarkdown)
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(changelog.Markdown()))
+		return
+	}
+	_ = res.WriteHeaderAndEntity(http.StatusOK, changelog)
+}
+
+// GET /{id}/changelog/feed
+func (r *serviceResource) getChangelogFeed(req *restful.Request, res *restful.Response) {
+	changelog, ok := r.buildChangelog(req, res)
+	if !ok {
+		return
+	}
+
+	scheme := "http"
+	if req.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := req.Request.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	data, err := xml.MarshalIndent(changelog.Feed(scheme+"://"+req.Request.Host+req.Request.URL.RequestURI()), "", "  ")
+	if err != nil {
+		shared.LogErrorf("failed to marshal service (%v) changelog feed: %v", changelog.ServiceID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set(restful.HEADER_ContentType, mimeAtomXML+"; charset=utf-8")
+	res.WriteHeader(http.StatusOK)
+	_, _ = res.Write([]byte(xml.Header))
+	_, _ = res.Write(data)
+}
+
+// buildChangelog is a utility method that builds the changelog of the service across the from & to versions of req,
+// diffing consecutive specs (see getOrCreateSpecDiffJSON).
+// Important to note that buildChangelog does write to res on failure (returning false), so handle accordingly.
+func (r *serviceResource) buildChangelog(req *restful.Request, res *restful.Response) (*models.Changelog, bool) {
+	var (
+		serviceID = req.PathParameter("id")
+		from      = req.QueryParameter("from")
+		to        = req.QueryParameter("to")
+	)
+	shared.LogDebugf("get request to get service (%v) changelog (from=%v, to=%v)", serviceID, from, to)
+
+	ctx := req.Request.Context()
+	service, err := r.dao.Get(ctx, serviceID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return nil, false
+	}
+
+	serviceID = service.ID
+	specs, err := r.specDAO.List(ctx, &db.ListFilter{
+		Model:   &models.Spec{},
+		Indexes: map[string]string{"service_id": serviceID},
+		Sorters: []*db.Sorter{{
+			Order: db.OrderAsc,
+			Field: "created_at",
+		}},
+	}, false)
+	if err != nil {
+		shared.LogErrorf("failed to list service (%v) specs: %v", serviceID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	rangeSpecs, err := models.ChangelogSpecs(specs, from, to)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	changelog := models.NewChangelog(service, from, to)
+	if from == "" && len(rangeSpecs) > 0 {
+		changelog.AddInitial(rangeSpecs[0])
+	}
+	for i := 1; i < len(rangeSpecs); i++ {
+		oldSpec, newSpec := rangeSpecs[i-1], rangeSpecs[i]
+		result, err := r.getOrCreateSpecDiffJSON(ctx, serviceID, oldSpec.ID, newSpec.ID)
+		if err != nil {
+			// Specs which cannot be diffed, e.g. when the spec kind changed, are listed without changes.
+			shared.LogErrorf("failed to diff service (%v) specs (old=%v, new=%v) for changelog: %v", serviceID, oldSpec.ID, newSpec.ID, err)
+		}
+		changelog.Add(newSpec, result)
+	}
+	return changelog, true
+}
+
+// GET /{id}/duplicates
+func (r *serviceResource) getDuplicates(req *restful.Request, res *restful.Response) {
+	serviceID := req.PathParameter("id")
+	shared.LogDebugf("get request to get service (%v) duplicates", serviceID)
+
+	service, err := r.dao.Get(req.Request.Context(), serviceID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	report, ok := buildDuplicateReport(req, res, r.dao, r.duplicates.dao)
+	if !ok {
+		return
+	}
+	_ = res.WriteHeaderAndEntity(http.StatusOK, report.Findings(service.ID))
+}
+
+// GET /{id}/trends
+func (r *serviceResource) getTrends(req *restful.Request, res *restful.Response) {
+	var (
+		serviceID = req.PathParameter("id")
+		interval  = req.QueryParameter("interval")
+	)
+	shared.LogDebugf("get request to get service (%v) trends (interval=%v)", serviceID, interval)
+
+	if interval == "" {
+		interval = models.ServiceTrendsIntervalDay
+	}
+	if !models.ValidServiceTrendsInterval(interval) {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported trends interval: %s", interval))
+		return
+	}
+	from, err := parseTrendsTime(req.QueryParameter("from"), false)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("invalid from: %v", err))
+		return
+	}
+	to, err := parseTrendsTime(req.QueryParameter("to"), true)
+	if err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("invalid to: %v", err))
+		return
+	}
+
+	ctx := req.Request.Context()
+	service, err := r.dao.Get(ctx, serviceID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	serviceID = service.ID
+	trends, err := r.specTrendDAO.List(ctx, serviceID, from, to)
+	if err != nil {
+		shared.LogErrorf("failed to list service (%v) trends: %v", serviceID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_ = res.WriteHeaderAndEntity(http.StatusOK, models.NewServiceTrends(serviceID, interval, trends))
+}
+
+// parseTrendsTime parses value, either an RFC 3339 time or a YYYY-MM-DD date, returning nil if empty.
+// Dates are inclusive, i.e. if end, a date is parsed as its last instant.
+func parseTrendsTime(value string, end bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither an RFC 3339 time nor a YYYY-MM-DD date", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &t, nil
+}
+
 // POST /{id}/specs/reconstruct
 func (r *serviceResource) reconstructSpec(req *restful.Request, res *restful.Response) {
 	var (
@@ -1205,6 +2217,12 @@ func (r *serviceResource) reconstructSpec(req *restful.Request, res *restful.Res
 		return
 	}
 
+	if err := spec.SetDocHash(); err != nil {
+		shared.LogErrorf("failed to hash reconstructed Spec.Doc: %#v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	if err := r.validate.Struct(spec); err != nil {
 		shared.LogErrorf("failed to validate Spec %s - %v", spec.ID, err.Error())
 		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
@@ -1212,9 +2230,11 @@ func (r *serviceResource) reconstructSpec(req *restful.Request, res *restful.Res
 	}
 
 	if err := r.specDAO.Save(req.Request.Context(), spec); err != nil {
-		handleError(res, err)
+		r.handleSaveSpecError(req, res, spec, err)
 		return
 	}
+	middleware.SetAuditAfter(req, spec)
+	r.duplicates.refresh()
 
 	res.Header().Add("Location", "/v1/apiregistry/services/"+serviceID+"/specs/"+spec.ID)
 	_ = res.WriteHeaderAndEntity(http.StatusCreated, spec)
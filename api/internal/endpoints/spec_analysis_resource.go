@@ -18,8 +18,10 @@ package endpoints
 
 import (
 	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
 	"github.com/cisco-developer/api-insights/api/internal/models"
 	"github.com/cisco-developer/api-insights/api/pkg/analyzer"
+	"github.com/cisco-developer/api-insights/api/pkg/analyzer/fixer"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
@@ -42,6 +44,8 @@ func (r *specAnalysisResource) Register(config *shared.AppConfig, container *res
 
 	var specAnalysisReq models.SpecAnalysisRequest
 	var specAnalysisRes models.SpecAnalysisResponse
+	var specFixApplyReq models.SpecFixApplyRequest
+	var specFixRes models.SpecFixResponse
 
 	ws.Route(
 		ws.POST("/analyze").
@@ -51,8 +55,20 @@ func (r *specAnalysisResource) Register(config *shared.AppConfig, container *res
 			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
 			Do(shared.RouteReads(specAnalysisReq, "spec analysis request"), shared.RouteWrites(specAnalysisRes)).
 			Metadata(restfulspec.KeyOpenAPITags, []string{"stateless"}).
+			Metadata(middleware.KeyAuditSkip, true).
 			Notes("Create a new spec analysis"))
 
+	ws.Route(
+		ws.POST("/fix").
+			To(r.fix).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(specFixRes, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteReads(specFixApplyReq, "spec fix apply request"), shared.RouteWrites(specFixRes)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"stateless"}).
+			Metadata(middleware.KeyAuditSkip, true).
+			Notes("Apply fixes, e.g. of a spec analysis, to a spec doc"))
+
 	container.Add(ws)
 }
 
@@ -82,3 +98,31 @@ func (r *specAnalysisResource) analyze(req *restful.Request, res *restful.Respon
 
 	_ = res.WriteHeaderAndEntity(http.StatusOK, specAnalysisRes)
 }
+
+// fix applies the fixes of the request to its doc, conflicting fixes being skipped (see fixer.Apply).
+func (r *specAnalysisResource) fix(req *restful.Request, res *restful.Response) {
+	specFixApplyReq := &models.SpecFixApplyRequest{}
+	if err := req.ReadEntity(specFixApplyReq); err != nil {
+		shared.LogErrorf("failed to get specFixApplyReq from body: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+	if err := r.validate.Struct(specFixApplyReq); err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	applied, err := fixer.Apply([]byte(specFixApplyReq.Doc), specFixApplyReq.Fixes)
+	if err != nil {
+		shared.LogErrorf("failed to apply %d fix(es): %v", len(specFixApplyReq.Fixes), err)
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	shared.LogDebugf("applied %d fix(es), skipped %d fix(es)", len(applied.Applied), len(applied.Skipped))
+
+	_ = res.WriteHeaderAndEntity(http.StatusOK, &models.SpecFixResponse{
+		Doc:     string(applied.Doc),
+		Applied: applied.Applied,
+		Skipped: applied.Skipped,
+	})
+}
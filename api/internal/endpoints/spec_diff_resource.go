@@ -18,6 +18,7 @@ package endpoints
 
 import (
 	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
 	"github.com/cisco-developer/api-insights/api/internal/models"
 	"github.com/cisco-developer/api-insights/api/pkg/differ"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
@@ -54,6 +55,7 @@ func (r *specDiffResource) Register(config *shared.AppConfig, container *restful
 			Do(shared.RouteReads(specDiffReq, "spec diff request"), shared.RouteWrites(specDiff)).
 			Do(shared.RouteParams(id)).
 			Metadata(restfulspec.KeyOpenAPITags, []string{"stateless"}).
+			Metadata(middleware.KeyAuditSkip, true).
 			Notes("Perform a stateless spec diff").
 			Consumes(restful.MIME_JSON, "multipart/form-data"))
 
@@ -0,0 +1,287 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"github.com/emicklei/go-restful/v3"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	webhookDeliveryTimeout     = 10 * time.Second
+	webhookDeliveryMaxAttempts = 5
+	// webhookDeliveryLease is how long an attempt of a delivery is claimed for, after which it's attempted again
+	// (e.g. by another replica) if the attempt was interrupted.
+	webhookDeliveryLease = 2 * webhookDeliveryTimeout
+	// webhookDeliveryBatch is the maximum number of due deliveries attempted at once.
+	webhookDeliveryBatch = 100
+)
+
+var (
+	// webhookDeliveryBackoff is the delay before the 2nd attempt of a webhook delivery, doubled for every next attempt.
+	webhookDeliveryBackoff = 2 * time.Second
+	// webhookDispatcherInterval is how often the webhook dispatcher checks for due deliveries, e.g. retries.
+	webhookDispatcherInterval = time.Second
+)
+
+// webhookDispatcher delivers event payloads to the webhooks subscribed to them, & notifications to consumers,
+// through models.WebhookDelivery(s) it attempts until they succeed or webhookDeliveryMaxAttempts are made.
+// As deliveries are stored before being attempted, pending deliveries resume after a restart.
+type webhookDispatcher struct {
+	dao         db.WebhookDAO
+	consumerDAO db.ConsumerDAO
+	client      *http.Client
+	wakeups     chan struct{}
+}
+
+func newWebhookDispatcher(dao db.WebhookDAO, consumerDAO db.ConsumerDAO) *webhookDispatcher {
+	return &webhookDispatcher{dao: dao, consumerDAO: consumerDAO, client: newWebhookClient(), wakeups: make(chan struct{}, 1)}
+}
+
+// newWebhookClient returns a client which only connects to addresses allowed (see models.WebhookAddressAllowed),
+// whatever the webhook hosts resolve to as they're delivered, & doesn't follow redirects.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookDeliveryTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !models.WebhookAddressAllowed(net.ParseIP(host)) {
+				return fmt.Errorf("%w: %s", models.ErrWebhookAddressNotAllowed, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookDeliveryTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// start attempts the due deliveries every webhookDispatcherInterval, or as soon as deliveries are dispatched,
+// in the background.
+func (d *webhookDispatcher) start() {
+	go func() {
+		ticker := time.NewTicker(webhookDispatcherInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-d.wakeups:
+			}
+			d.deliverDue(context.Background(), time.Now().UTC())
+		}
+	}()
+}
+
+// wakeup requests the due deliveries to be attempted now.
+func (d *webhookDispatcher) wakeup() {
+	select {
+	case d.wakeups <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch delivers a payload of event of service with data to the webhooks subscribed to it, in the background.
+func (d *webhookDispatcher) dispatch(ctx context.Context, event string, service *models.Service, data interface{}) {
+	if d == nil || service == nil {
+		return
+	}
+	webhooks, err := d.dao.ListSubscribed(ctx, event, service.ID, service.OrganizationID)
+	if err != nil {
+		shared.LogErrorf("failed to list webhooks subscribed to %s of service %s: %v", event, service.NameID, err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload := models.NewWebhookPayload(event, service, data)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		shared.LogErrorf("failed to marshal %s webhook payload: %v", event, err)
+		return
+	}
+	for _, webhook := range webhooks {
+		d.enqueue(ctx, &models.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Event:     event,
+			PayloadID: payload.ID,
+			Payload:   body,
+		})
+	}
+}
+
+// notify delivers notification to the webhook URL of consumer, in the background.
+func (d *webhookDispatcher) notify(ctx context.Context, consumer *models.Consumer, notification *models.ConsumerNotification) {
+	if d == nil {
+		return
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		shared.LogErrorf("failed to marshal consumer %s notification: %v", notification.Consumer, err)
+		return
+	}
+	d.enqueue(ctx, &models.WebhookDelivery{
+		ConsumerID: consumer.ID,
+		Event:      models.WebhookEventConsumerImpacted,
+		PayloadID:  notification.SpecDiffID,
+		Payload:    body,
+	})
+}
+
+// redeliver delivers the payload of delivery to webhook again, as a new delivery, in the background.
+func (d *webhookDispatcher) redeliver(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	redelivery := &models.WebhookDelivery{
+		WebhookID:    webhook.ID,
+		Event:        delivery.Event,
+		PayloadID:    delivery.PayloadID,
+		Payload:      delivery.Payload,
+		RedeliveryOf: delivery.ID,
+	}
+	if err := d.enqueue(ctx, redelivery); err != nil {
+		return nil, err
+	}
+	return redelivery, nil
+}
+
+// enqueue stores delivery as pending & due now, to be attempted in the background.
+func (d *webhookDispatcher) enqueue(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.ScheduleRetry(time.Now().UTC())
+	if err := d.dao.SaveDelivery(ctx, delivery); err != nil {
+		return err
+	}
+	d.wakeup()
+	return nil
+}
+
+// deliverDue attempts the deliveries due at now which this replica claims, in the background.
+func (d *webhookDispatcher) deliverDue(ctx context.Context, now time.Time) {
+	deliveries, err := d.dao.ListDueDeliveries(ctx, now, webhookDeliveryBatch)
+	if err != nil {
+		shared.LogErrorf("failed to list due webhook deliveries: %v", err)
+		return
+	}
+	for _, delivery := range deliveries {
+		claimed, err := d.dao.ClaimDelivery(ctx, delivery, now, now.Add(webhookDeliveryLease))
+		if err != nil {
+			shared.LogErrorf("failed to claim webhook delivery %s: %v", delivery.ID, err)
+			continue
+		}
+		if claimed {
+			go d.deliver(ctx, delivery)
+		}
+	}
+}
+
+// deliver attempts delivery, logging the attempt in it: a failed attempt worth retrying is scheduled again,
+// backing off exponentially, unless webhookDeliveryMaxAttempts were made.
+func (d *webhookDispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	url, secret, err := d.target(ctx, delivery)
+	if err != nil {
+		delivery.SetAttempt(0, nil, err, 0)
+		_ = d.dao.SaveDelivery(ctx, delivery)
+		shared.LogErrorf("failed to deliver %s (delivery %s): %v", delivery.Event, delivery.ID, err)
+		return
+	}
+
+	status, body, duration, err := d.post(ctx, url, secret, delivery)
+	delivery.SetAttempt(status, body, err, duration)
+	if delivery.Status == models.WebhookDeliveryStatusFailed && delivery.Attempts < webhookDeliveryMaxAttempts && retryableWebhookStatus(status, err) {
+		delivery.ScheduleRetry(time.Now().UTC().Add(webhookDeliveryBackoff << (delivery.Attempts - 1)))
+	}
+	_ = d.dao.SaveDelivery(ctx, delivery)
+
+	if delivery.Status == models.WebhookDeliveryStatusFailed {
+		shared.LogErrorf("failed to deliver %s (delivery %s) after %d attempt(s): %s %s",
+			delivery.Event, delivery.ID, delivery.Attempts, http.StatusText(status), delivery.Error)
+	}
+}
+
+// target returns the URL delivery is posted to, & the secret its payload is signed with, if any.
+func (d *webhookDispatcher) target(ctx context.Context, delivery *models.WebhookDelivery) (string, string, error) {
+	if delivery.ConsumerID != "" {
+		consumer, err := d.consumerDAO.Get(ctx, delivery.ConsumerID)
+		if err != nil {
+			return "", "", err
+		}
+		if consumer.WebhookURL == "" {
+			return "", "", fmt.Errorf("consumer %s has no webhook", consumer.Name)
+		}
+		return consumer.WebhookURL, "", nil
+	}
+	webhook, err := d.dao.Get(ctx, delivery.WebhookID)
+	if err != nil {
+		return "", "", err
+	}
+	return webhook.URL, webhook.Secret, nil
+}
+
+func (d *webhookDispatcher) post(ctx context.Context, url, secret string, delivery *models.WebhookDelivery) (int, []byte, time.Duration, error) {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, nil, time.Since(start), err
+	}
+	timestamp := start.Unix()
+	req.Header.Set("Content-Type", restful.MIME_JSON)
+	req.Header.Set(models.WebhookEventHeader, delivery.Event)
+	req.Header.Set(models.WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(models.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	if secret != "" {
+		req.Header.Set(models.WebhookSignatureHeader, models.SignWebhookPayload(secret, timestamp, delivery.Payload))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, nil, time.Since(start), err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return resp.StatusCode, body, time.Since(start), err
+}
+
+// retryableWebhookStatus checks if a webhook delivery attempt which got status or err is worth retrying,
+// i.e. on network & server errors, timeouts & rate limiting, but not to addresses not allowed.
+func retryableWebhookStatus(status int, err error) bool {
+	if errors.Is(err, models.ErrWebhookAddressNotAllowed) {
+		return false
+	}
+	return err != nil || status >= http.StatusInternalServerError ||
+		status == http.StatusRequestTimeout || status == http.StatusTooManyRequests
+}
@@ -0,0 +1,398 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpoints
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/cisco-developer/api-insights/api/internal/db"
+	"github.com/cisco-developer/api-insights/api/internal/middleware"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type webhookResource struct {
+	config          *shared.AppConfig
+	dao             db.WebhookDAO
+	validate        *validator.Validate
+	serviceDAO      db.ServiceDAO
+	organizationDAO db.OrganizationDAO
+	dispatcher      *webhookDispatcher
+	accessChecker   access.Checker
+}
+
+// Register the API
+// prefix: /v1/apiregistry/webhooks
+func (r *webhookResource) Register(config *shared.AppConfig, container *restful.Container, prefix string) {
+	ws := &restful.WebService{}
+	ws.Path(prefix).Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON).ApiVersion(config.AppVersion).Doc("APIs for Webhook.")
+	ws.Filter(middleware.ResourceAccessChecker(r.accessChecker))
+
+	var webhook models.Webhook
+	var webhooks []models.Webhook
+	var delivery models.WebhookDelivery
+	var deliveries []models.WebhookDelivery
+	var id = ws.PathParameter("id", "unique identifier for webhook.").DataType("string")
+	var deliveryID = ws.PathParameter("deliveryID", "unique identifier for webhook delivery.").DataType("string")
+	var organizationID = ws.QueryParameter("organization_id", "name id of the organization of webhooks").DataType("string")
+	var service = ws.QueryParameter("service", "unique identifier (UUID or Name ID) of the service of webhooks").DataType("string")
+	var event = ws.QueryParameter("event", fmt.Sprintf("event (%s) of webhooks", strings.Join(models.WebhookEvents, ", "))).DataType("string")
+	var active = ws.QueryParameter("active", "whether webhooks are active").DataType("boolean")
+	var status = ws.QueryParameter("status", "status (pending, succeeded or failed) of deliveries").DataType("string")
+	var limit = ws.QueryParameter("limit", "max items to return at one time").DataType("string")
+	var offset = ws.QueryParameter("offset", "starting offset").DataType("string")
+
+	ws.Route(
+		ws.GET("").
+			To(r.list).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteParams(offset)).
+			Do(shared.RouteParams(limit, max)).
+			Do(shared.RouteReturns(webhooks, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(webhooks)).
+			Do(shared.RouteParams(organizationID, service, event, active)).
+			Do(shared.RouteParams(sort, sortOrder)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"webhook"}).
+			Notes("List webhooks, with specified filters"))
+
+	ws.Route(
+		ws.GET("/{id}").
+			To(r.get).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(webhook, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(webhook)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"webhook"}).
+			Notes("Get a webhook with specified id"))
+
+	ws.Route(
+		ws.POST("").
+			To(r.save).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(webhook, http.StatusCreated)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteReads(webhook), shared.RouteWrites(webhook)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"webhook"}).
+			Notes("Subscribe a URL to events of the services of an organization, or of a service. " +
+				"Payloads are POSTed as JSON, signed with the webhook secret (generated if not given, & only returned here) in the " + models.WebhookSignatureHeader + " header " +
+				"as sha256=<hex-encoded HMAC-SHA256 of the " + models.WebhookTimestampHeader + " header, a dot & the body>, & retried with exponential backoff on failure. " +
+				"Receivers should reject payloads whose timestamp isn't recent, e.g. replays. " +
+				"The URL must not be (or resolve to) a loopback, link-local or instance metadata address"))
+
+	ws.Route(
+		ws.PUT("/{id}").
+			To(r.save).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(webhook, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteReads(webhook), shared.RouteWrites(webhook)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"webhook"}).
+			Notes("Update existing webhook, keeping its secret unless a new one is given"))
+
+	ws.Route(
+		ws.DELETE("/{id}").
+			To(r.delete).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(nil, http.StatusNoContent)).
+			Do(shared.RouteReturns(&se, http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(nil)).
+			Do(shared.RouteParams(id)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"webhook"}).
+			Notes("Delete existing webhook, along with its deliveries"))
+
+	ws.Route(
+		ws.GET("/{id}/deliveries").
+			To(r.listDeliveries).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteParams(offset)).
+			Do(shared.RouteParams(limit, max)).
+			Do(shared.RouteReturns(deliveries, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(deliveries)).
+			Do(shared.RouteParams(id, event, status)).
+			Do(shared.RouteParams(sort, sortOrder)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"webhook"}).
+			Notes("List the deliveries of a webhook, the latest first by default"))
+
+	ws.Route(
+		ws.GET("/{id}/deliveries/{deliveryID}").
+			To(r.getDelivery).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(delivery, http.StatusOK)).
+			Do(shared.RouteReturns(&se, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(delivery)).
+			Do(shared.RouteParams(id, deliveryID)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"webhook"}).
+			Notes("Get a delivery of a webhook, along with its payload & the response to its last attempt"))
+
+	ws.Route(
+		ws.POST("/{id}/deliveries/{deliveryID}/redeliver").
+			To(r.redeliver).
+			Do(shared.RouteAuthHeader(ws)).
+			Do(shared.RouteReturns(delivery, http.StatusAccepted)).
+			Do(shared.RouteReturns(&se, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError)).
+			Do(shared.RouteWrites(delivery)).
+			Do(shared.RouteParams(id, deliveryID)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{"webhook"}).
+			Notes("Deliver the payload of a delivery of a webhook again, as a new delivery"))
+
+	container.Add(ws)
+}
+
+// GET /
+func (r *webhookResource) list(req *restful.Request, res *restful.Response) {
+	shared.LogDebugf("get request to list webhooks.")
+
+	var filter = &db.ListFilter{Model: &models.Webhook{}}
+	err := filter.From(req)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx := req.Request.Context()
+	if serviceID := req.QueryParameter("service"); serviceID != "" {
+		service, err := r.serviceDAO.Get(ctx, serviceID)
+		if err != nil {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		filter.Indexes["service_id"] = service.ID
+	}
+	event := req.QueryParameter("event")
+	if event != "" && !models.ValidWebhookEvent(event) {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("unsupported event: %s", event))
+		return
+	}
+
+	webhooks, err := r.dao.List(ctx, filter)
+	if err != nil {
+		shared.LogErrorf("failed to list webhooks: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	result := make([]*models.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if event != "" && !webhook.Subscribes(event) {
+			continue
+		}
+		webhook.Secret = ""
+		result = append(result, webhook)
+	}
+	shared.LogDebugf("total %v webhook(s) returned", len(result))
+	_ = res.WriteEntity(result)
+}
+
+// GET /{id}
+func (r *webhookResource) get(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to retrieve webhook: %v", id)
+
+	webhook, err := r.dao.Get(req.Request.Context(), id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	webhook.Secret = ""
+	_ = res.WriteEntity(webhook)
+}
+
+// POST / & PUT /{id}
+func (r *webhookResource) save(req *restful.Request, res *restful.Response) {
+	webhook := &models.Webhook{Active: true}
+	id := req.PathParameter("id")
+	if err := req.ReadEntity(webhook); err != nil {
+		shared.LogErrorf("failed to get webhook from body: %#v", err)
+		_ = res.WriteErrorString(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+		return
+	}
+	shared.LogDebugf("get request to save webhook for %s%s", webhook.OrganizationID, webhook.ServiceID)
+
+	ctx := req.Request.Context()
+	now := time.Now().UTC()
+	if id == "" {
+		webhook.ID = ""
+		webhook.CreatedAt = now
+	} else {
+		existing, err := r.dao.Get(ctx, id)
+		if err != nil {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		middleware.SetAuditBefore(req, existing)
+		webhook.ID = existing.ID
+		webhook.CreatedAt = existing.CreatedAt
+		if webhook.Secret == "" {
+			webhook.Secret = existing.Secret
+		}
+	}
+	webhook.UpdatedAt = now
+
+	if err := r.validate.Struct(webhook); err != nil {
+		shared.LogErrorf("failed to validate webhook %s - %v", webhook.ID, err.Error())
+		_ = res.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := models.CheckWebhookURL(ctx, webhook.URL); err != nil {
+		_ = res.WriteErrorString(http.StatusBadRequest, fmt.Sprintf("invalid url: %v", err))
+		return
+	}
+	if (webhook.OrganizationID == "") == (webhook.ServiceID == "") {
+		_ = res.WriteErrorString(http.StatusBadRequest, "either organization_id or service_id is required")
+		return
+	}
+	if webhook.ServiceID != "" {
+		service, err := r.serviceDAO.Get(ctx, webhook.ServiceID)
+		if err != nil {
+			_ = res.WriteErrorString(http.StatusNotFound, fmt.Sprintf("service not found: %s", webhook.ServiceID))
+			return
+		}
+		webhook.ServiceID = service.ID
+	} else {
+		organization, err := r.organizationDAO.Get(ctx, webhook.OrganizationID)
+		if err != nil {
+			_ = res.WriteErrorString(http.StatusNotFound, fmt.Sprintf("organization not found: %s", webhook.OrganizationID))
+			return
+		}
+		webhook.OrganizationID = organization.NameID
+	}
+
+	secret := ""
+	if webhook.Secret == "" {
+		var err error
+		if webhook.Secret, err = models.NewWebhookSecret(); err != nil {
+			shared.LogErrorf("failed to generate webhook secret: %v", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+	if id == "" {
+		secret = webhook.Secret
+	}
+
+	if err := r.dao.Save(ctx, webhook); err != nil {
+		handleError(res, err)
+		return
+	}
+	middleware.SetAuditAfter(req, webhook)
+	webhook.Secret = secret
+	if id == "" {
+		res.Header().Add("Location", "/v1/apiregistry/webhooks/"+webhook.ID)
+		_ = res.WriteHeaderAndEntity(http.StatusCreated, webhook)
+	} else {
+		_ = res.WriteEntity(webhook)
+	}
+}
+
+// DELETE /{id}
+func (r *webhookResource) delete(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to delete webhook: %v", id)
+
+	if existing, err := r.dao.Get(req.Request.Context(), id); err == nil {
+		middleware.SetAuditBefore(req, existing)
+	}
+
+	err := r.dao.Delete(req.Request.Context(), id)
+	if err == db.ErrNotFound {
+		res.WriteHeader(http.StatusNotFound)
+	} else if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+	} else {
+		res.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GET /{id}/deliveries
+func (r *webhookResource) listDeliveries(req *restful.Request, res *restful.Response) {
+	id := req.PathParameter("id")
+	shared.LogDebugf("get request to list webhook %v deliveries.", id)
+
+	var filter = &db.ListFilter{Model: &models.WebhookDelivery{}}
+	err := filter.From(req)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx := req.Request.Context()
+	webhook, err := r.dao.Get(ctx, id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	filter.Indexes["webhook_id"] = webhook.ID
+
+	deliveries, err := r.dao.ListDeliveries(ctx, filter)
+	if err != nil {
+		shared.LogErrorf("failed to list webhook %s deliveries: %v", webhook.ID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	shared.LogDebugf("total %v webhook delivery(ies) returned", len(deliveries))
+	_ = res.WriteEntity(deliveries)
+}
+
+// GET /{id}/deliveries/{deliveryID}
+func (r *webhookResource) getDelivery(req *restful.Request, res *restful.Response) {
+	id, deliveryID := req.PathParameter("id"), req.PathParameter("deliveryID")
+	shared.LogDebugf("get request to retrieve webhook %v delivery: %v", id, deliveryID)
+
+	delivery, err := r.dao.GetDelivery(req.Request.Context(), id, deliveryID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = res.WriteEntity(delivery)
+}
+
+// POST /{id}/deliveries/{deliveryID}/redeliver
+func (r *webhookResource) redeliver(req *restful.Request, res *restful.Response) {
+	id, deliveryID := req.PathParameter("id"), req.PathParameter("deliveryID")
+	shared.LogDebugf("get request to redeliver webhook %v delivery: %v", id, deliveryID)
+
+	ctx := req.Request.Context()
+	webhook, err := r.dao.Get(ctx, id)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+	delivery, err := r.dao.GetDelivery(ctx, webhook.ID, deliveryID)
+	if err != nil {
+		res.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	redelivery, err := r.dispatcher.redeliver(ctx, webhook, delivery)
+	if err != nil {
+		handleError(res, err)
+		return
+	}
+	middleware.SetAuditResource(req, models.AuditResourceTypeWebhookDelivery, redelivery.ID)
+	middleware.SetAuditAfter(req, redelivery)
+	res.Header().Add("Location", fmt.Sprintf("/v1/apiregistry/webhooks/%s/deliveries/%s", webhook.ID, redelivery.ID))
+	_ = res.WriteHeaderAndEntity(http.StatusAccepted, redelivery)
+}
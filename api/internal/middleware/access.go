@@ -35,6 +35,11 @@ func AccessCheckerInputFromReq(req *restful.Request) *access.Input {
 	return v
 }
 
+// SetAccessCheckerInput sets the access.Input of the user of req, as authenticated by an authentication middleware.
+func SetAccessCheckerInput(req *restful.Request, input *access.Input) {
+	req.SetAttribute(requestAttrAccessCheckerInput, input)
+}
+
 func AccessDataFiltersFromReq(req *restful.Request) models.AccessDataFilters {
 	raw := req.Attribute(requestAttrAccessDataFilters)
 	v, _ := raw.(models.AccessDataFilters)
@@ -0,0 +1,247 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
+	"gorm.io/datatypes"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// KeyAuditSkip is the route metadata key flagging routes which don't mutate resources despite their method,
+	// e.g. POST /specs/diffs/diff, so that the audit middleware skips them.
+	KeyAuditSkip = "audit.skip"
+
+	// HeaderRequestID is the header of the request ID, set on every response (& generated, unless given in the request).
+	HeaderRequestID = "X-Request-Id"
+
+	auditActorAnonymous = "anonymous"
+
+	requestAttrRequestID     = "request_id"
+	requestAttrAuditSnapshot = "audit_snapshots"
+	requestAttrAuditResource = "audit_resource"
+	requestAttrAuditSkip     = "audit_skip"
+)
+
+// trustedProxies are the comma-separated addresses or CIDRs of the proxies trusted to set the proxyUserHeaders
+// & X-Forwarded-For headers, none by default.
+var trustedProxies string
+
+func Flags() []cli.Flag {
+	return []cli.Flag{
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        "trusted-proxies",
+			Usage:       "Comma-separated addresses or CIDRs of the authenticating proxies trusted to set the X-Forwarded-For & user (e.g. X-Forwarded-Email) headers of requests; none by default",
+			Value:       trustedProxies,
+			Destination: &trustedProxies,
+			EnvVars:     []string{"TRUSTED_PROXIES"},
+		}),
+	}
+}
+
+// AuditRecorder records AuditEvent(s), e.g. db.AuditDAO.
+type AuditRecorder interface {
+	Create(ctx context.Context, event *models.AuditEvent) error
+}
+
+type auditSnapshots struct {
+	before, after datatypes.JSON
+}
+
+type auditResource struct {
+	resourceType, id string
+}
+
+// RequestIDFromReq returns the request ID of req, set by the audit middleware.
+func RequestIDFromReq(req *restful.Request) string {
+	v, _ := req.Attribute(requestAttrRequestID).(string)
+	return v
+}
+
+// SetAuditBefore snapshots the resource v mutated by req before the mutation (none for creations), recorded in its audit event.
+// v is snapshotted right away, so that it can be mutated in place afterwards.
+func SetAuditBefore(req *restful.Request, v interface{}) {
+	auditSnapshotsFromReq(req).before = newAuditSnapshot(req, v)
+}
+
+// SetAuditAfter snapshots the resource v mutated by req after the mutation (none for deletions), recorded in its audit event.
+func SetAuditAfter(req *restful.Request, v interface{}) {
+	auditSnapshotsFromReq(req).after = newAuditSnapshot(req, v)
+}
+
+func auditSnapshotsFromReq(req *restful.Request) *auditSnapshots {
+	snapshots, ok := req.Attribute(requestAttrAuditSnapshot).(*auditSnapshots)
+	if !ok {
+		snapshots = &auditSnapshots{}
+		req.SetAttribute(requestAttrAuditSnapshot, snapshots)
+	}
+	return snapshots
+}
+
+func newAuditSnapshot(req *restful.Request, v interface{}) datatypes.JSON {
+	snapshot, err := models.NewAuditSnapshot(v)
+	if err != nil {
+		shared.LogErrorf("failed to snapshot %T for audit (request %s): %v", v, RequestIDFromReq(req), err)
+	}
+	return snapshot
+}
+
+// SetAuditResource sets the type & ID of the resource mutated by req, when they can't be inferred from its route (see models.AuditResourceFromRoute).
+func SetAuditResource(req *restful.Request, resourceType, id string) {
+	req.SetAttribute(requestAttrAuditResource, &auditResource{resourceType: resourceType, id: id})
+}
+
+// SkipAudit flags req as not mutating any resource, e.g. a dry run, so that the audit middleware skips it.
+func SkipAudit(req *restful.Request) {
+	req.SetAttribute(requestAttrAuditSkip, true)
+}
+
+// NewAuditMiddleware records an audit event of every successful mutation (POST, PUT, PATCH & DELETE requests) with recorder,
+// along with the resource snapshots set by its handler, if any (see SetAuditBefore & SetAuditAfter).
+// It also sets the request ID of every request (see RequestIDFromReq).
+func NewAuditMiddleware(recorder AuditRecorder) restful.FilterFunction {
+	return func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+		requestID := req.HeaderParameter(HeaderRequestID)
+		if requestID == "" {
+			requestID = shared.TimeUUID()
+		}
+		req.SetAttribute(requestAttrRequestID, requestID)
+		res.AddHeader(HeaderRequestID, requestID)
+
+		action := models.AuditActionFromMethod(req.Request.Method)
+		if action == "" || auditSkippedRoute(req) {
+			chain.ProcessFilter(req, res)
+			return
+		}
+
+		chain.ProcessFilter(req, res)
+		if skip, _ := req.Attribute(requestAttrAuditSkip).(bool); skip || res.StatusCode() >= http.StatusBadRequest {
+			return
+		}
+
+		event := newAuditEvent(req, res, action, requestID)
+		if err := recorder.Create(req.Request.Context(), event); err != nil {
+			shared.LogErrorf("failed to record audit event of %s %s (request %s): %v", req.Request.Method, req.Request.URL.Path, requestID, err)
+		}
+	}
+}
+
+func auditSkippedRoute(req *restful.Request) bool {
+	route := req.SelectedRoute()
+	if route == nil {
+		return true
+	}
+	skip, _ := route.Metadata()[KeyAuditSkip].(bool)
+	return skip
+}
+
+func newAuditEvent(req *restful.Request, res *restful.Response, action, requestID string) *models.AuditEvent {
+	event := &models.AuditEvent{
+		Actor:      auditActor(req),
+		Action:     action,
+		Method:     req.Request.Method,
+		Path:       req.Request.URL.Path,
+		Status:     res.StatusCode(),
+		RequestID:  requestID,
+		RemoteAddr: auditRemoteAddr(req.Request),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if route := req.SelectedRoute(); route != nil {
+		event.Operation = route.Operation()
+	}
+
+	if resource, ok := req.Attribute(requestAttrAuditResource).(*auditResource); ok {
+		event.ResourceType, event.ResourceID = resource.resourceType, resource.id
+	} else {
+		event.ResourceType, event.ResourceID = models.AuditResourceFromRoute(req.SelectedRoutePath(), req.PathParameters())
+	}
+	if event.ResourceID == "" && action == models.AuditActionCreate {
+		if location := res.Header().Get("Location"); location != "" {
+			event.ResourceID = path.Base(location)
+		}
+	}
+
+	if snapshots, ok := req.Attribute(requestAttrAuditSnapshot).(*auditSnapshots); ok {
+		event.SetSnapshots(snapshots.before, snapshots.after)
+	}
+	return event
+}
+
+// auditActor returns the user of req, as authenticated by the authentication middleware (see NewAuthenticationMiddleware),
+// or else anonymous: request headers aren't trusted here.
+func auditActor(req *restful.Request) string {
+	if input := AccessCheckerInputFromReq(req); input != nil && input.User != "" {
+		return input.User
+	}
+	return auditActorAnonymous
+}
+
+// auditRemoteAddr returns the address of the client of req: the peer address, unless it's a trusted proxy,
+// in which case the last address of X-Forwarded-For not of a trusted proxy.
+func auditRemoteAddr(req *http.Request) string {
+	addr := req.RemoteAddr
+	if !trustedProxy(addr) {
+		return addr
+	}
+	forwarded := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if hop == "" {
+			continue
+		}
+		addr = hop
+		if !trustedProxy(hop) {
+			break
+		}
+	}
+	return addr
+}
+
+// trustedProxy checks if addr (an IP, optionally with a port) is one of trustedProxies.
+func trustedProxy(addr string) bool {
+	if trustedProxies == "" {
+		return false
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range strings.Split(trustedProxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		} else if proxyIP := net.ParseIP(proxy); proxyIP != nil && proxyIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
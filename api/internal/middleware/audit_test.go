@@ -0,0 +1,115 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditRecorderFunc func(ctx context.Context, event *models.AuditEvent) error
+
+func (f auditRecorderFunc) Create(ctx context.Context, event *models.AuditEvent) error {
+	return f(ctx, event)
+}
+
+func TestAuditMiddleware_Actor(t *testing.T) {
+	defer func(proxies string) { trustedProxies = proxies }(trustedProxies)
+
+	var events []*models.AuditEvent
+	container := restful.NewContainer()
+	container.Filter(NewAuthenticationMiddleware())
+	container.Filter(NewAuditMiddleware(auditRecorderFunc(func(ctx context.Context, event *models.AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})))
+	ws := new(restful.WebService).Path("/v1/apiregistry")
+	ws.Route(ws.POST("/services").To(func(req *restful.Request, res *restful.Response) {
+		res.WriteHeader(http.StatusCreated)
+	}))
+	container.Add(ws)
+
+	actor := func(remoteAddr string, headers map[string]string) string {
+		r := httptest.NewRequest(http.MethodPost, "/v1/apiregistry/services", nil)
+		r.RemoteAddr = remoteAddr
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		events = nil
+		container.ServeHTTP(httptest.NewRecorder(), r)
+		require.Len(t, events, 1)
+		return events[0].Actor
+	}
+	spoofed := map[string]string{
+		"X-Forwarded-Email": "spoofed@example.com",
+		"Authorization":     "Bearer eyJhbGciOiJub25lIn0.eyJlbWFpbCI6ImZvcmdlZEBleGFtcGxlLmNvbSJ9.",
+	}
+
+	trustedProxies = ""
+	assert.Equal(t, auditActorAnonymous, actor("10.0.0.5:1234", spoofed))
+
+	trustedProxies = "10.0.0.0/8, 192.0.2.1"
+	assert.Equal(t, "spoofed@example.com", actor("10.0.0.5:1234", spoofed))
+	assert.Equal(t, "alice", actor("192.0.2.1:1234", map[string]string{"X-Forwarded-User": "alice"}))
+	assert.Equal(t, auditActorAnonymous, actor("203.0.113.9:1234", spoofed))
+	assert.Equal(t, auditActorAnonymous, actor("10.0.0.5:1234", nil))
+}
+
+func TestAuditActor(t *testing.T) {
+	defer func(proxies string) { trustedProxies = proxies }(trustedProxies)
+	trustedProxies = "10.0.0.0/8"
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/apiregistry/services", nil)
+	r.RemoteAddr = "10.0.0.5:1234"
+	r.Header.Set("X-Forwarded-Email", "spoofed@example.com")
+	req := restful.NewRequest(r)
+	// Headers are only trusted by the authentication middleware.
+	assert.Equal(t, auditActorAnonymous, auditActor(req))
+	SetAccessCheckerInput(req, &access.Input{User: "alice@example.com"})
+	assert.Equal(t, "alice@example.com", auditActor(req))
+}
+
+func TestAuditRemoteAddr(t *testing.T) {
+	defer func(proxies string) { trustedProxies = proxies }(trustedProxies)
+
+	newReq := func(remoteAddr string, forwarded ...string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/v1/apiregistry/services", nil)
+		r.RemoteAddr = remoteAddr
+		for _, f := range forwarded {
+			r.Header.Add("X-Forwarded-For", f)
+		}
+		return r
+	}
+
+	trustedProxies = ""
+	assert.Equal(t, "10.0.0.5:1234", auditRemoteAddr(newReq("10.0.0.5:1234", "198.51.100.7")))
+
+	trustedProxies = "10.0.0.0/8,192.0.2.1"
+	assert.Equal(t, "198.51.100.7", auditRemoteAddr(newReq("10.0.0.5:1234", "198.51.100.7")))
+	// The client may prepend any address: the last one not of a trusted proxy is the client.
+	assert.Equal(t, "198.51.100.7", auditRemoteAddr(newReq("10.0.0.5:1234", "127.0.0.1, 198.51.100.7", "192.0.2.1")))
+	assert.Equal(t, "203.0.113.9:1234", auditRemoteAddr(newReq("203.0.113.9:1234", "198.51.100.7")))
+	assert.Equal(t, "10.0.0.5:1234", auditRemoteAddr(newReq("10.0.0.5:1234")))
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/access"
+	"github.com/emicklei/go-restful/v3"
+)
+
+// proxyUserHeaders are the headers identifying the user of a request, set by an authenticating proxy.
+// They're only trusted from trusted proxies (see Flags).
+var proxyUserHeaders = []string{"X-Forwarded-Email", "X-Auth-Request-Email", "X-Forwarded-User", "X-Auth-Request-User"}
+
+// NewAuthenticationMiddleware sets the access.Input of the user of every request authenticated by a trusted proxy
+// (see SetAccessCheckerInput), consumed by ResourceAccessChecker & the audit middleware.
+// Requests not from a trusted proxy, or without a user header, are left unauthenticated.
+func NewAuthenticationMiddleware() restful.FilterFunction {
+	return func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+		if user := proxyUser(req); user != "" {
+			SetAccessCheckerInput(req, &access.Input{User: user})
+		}
+		chain.ProcessFilter(req, res)
+	}
+}
+
+// proxyUser returns the user of req set by a trusted proxy in the proxyUserHeaders, if any.
+func proxyUser(req *restful.Request) string {
+	if !trustedProxy(req.Request.RemoteAddr) {
+		return ""
+	}
+	for _, header := range proxyUserHeaders {
+		if v := req.Request.Header.Get(header); v != "" {
+			return v
+		}
+	}
+	return ""
+}
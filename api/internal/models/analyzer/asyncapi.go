@@ -0,0 +1,68 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analyzer
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/speciterator"
+)
+
+// AsyncAPIFinding represents a finding of an AsyncAPI analyzer.
+type AsyncAPIFinding struct {
+	Rule     string
+	Severity rule.SeverityName
+	Message  string
+	Path     []string
+}
+
+// AsyncAPIResult represents the findings of an AsyncAPI analyzer (e.g. AsyncAPICompleteness) in Doc.
+type AsyncAPIResult struct {
+	Analyzer SpecAnalyzer
+	Doc      []byte
+	Findings []*AsyncAPIFinding
+}
+
+func (m *AsyncAPIResult) Result() (*Result, error) {
+	result := NewResult()
+	if m == nil {
+		return result, nil
+	}
+
+	var positions *speciterator.Positions
+	if len(m.Findings) > 0 {
+		positions = speciterator.NewPositions(m.Doc)
+	}
+
+	for _, f := range m.Findings {
+		ruleNameID := rule.NameID(f.Rule)
+		result.storeRuleInCache(f.Severity, ruleNameID, &Rule{
+			NameID:         f.Rule,
+			AnalyzerNameID: string(m.Analyzer),
+			Title:          f.Rule,
+			Description:    f.Message,
+			Severity:       f.Severity.String(),
+		})
+
+		result.AddFinding(f.Severity, ruleNameID, &Finding{
+			Type:  rule.FindingTypeRange,
+			Path:  f.Path,
+			Range: positionRange(positions, f.Path),
+		})
+	}
+
+	return result, nil
+}
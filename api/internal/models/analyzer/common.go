@@ -25,6 +25,12 @@ const (
 	Drift              = SpecAnalyzer("drift")
 	Completeness       = SpecAnalyzer("completeness")
 	Security           = SpecAnalyzer("security")
+
+	AsyncAPICompleteness = SpecAnalyzer("asyncapi-completeness")
+	AsyncAPIGuidelines   = SpecAnalyzer("asyncapi-guidelines")
+
+	ProtobufStyle = SpecAnalyzer("protobuf-style")
+	GraphQLLint   = SpecAnalyzer("graphql-lint")
 )
 
 type Resulter interface{ Result() (*Result, error) }
@@ -33,4 +39,7 @@ var (
 	_ Resulter = (*SpectralResult)(nil)
 	_ Resulter = (*WokeResult)(nil)
 	_ Resulter = (*APIClarityDriftResult)(nil)
+	_ Resulter = (*AsyncAPIResult)(nil)
+	_ Resulter = (*ProtobufResult)(nil)
+	_ Resulter = (*GraphQLResult)(nil)
 )
@@ -0,0 +1,71 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analyzer
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+)
+
+// GraphQLFinding represents a finding of a GraphQL analyzer.
+// Path identifies the definition in the schema, e.g. ["types", type, "fields", field], while Range is its range in the SDL.
+type GraphQLFinding struct {
+	Rule     string
+	Severity rule.SeverityName
+	Message  string
+	Path     []string
+	Range    *FindingPositionRange
+}
+
+// GraphQLResult represents the findings of a GraphQL analyzer (e.g. GraphQLLint).
+type GraphQLResult struct {
+	Analyzer SpecAnalyzer
+	Findings []*GraphQLFinding
+}
+
+func (m *GraphQLResult) Result() (*Result, error) {
+	result := NewResult()
+	if m == nil {
+		return result, nil
+	}
+
+	for _, f := range m.Findings {
+		ruleNameID := rule.NameID(f.Rule)
+		result.storeRuleInCache(f.Severity, ruleNameID, &Rule{
+			NameID:         f.Rule,
+			AnalyzerNameID: string(m.Analyzer),
+			Title:          f.Rule,
+			Description:    f.Message,
+			Severity:       f.Severity.String(),
+		})
+
+		r := f.Range
+		if r == nil {
+			r = &FindingPositionRange{
+				Start: &FindingPosition{Line: 1, Column: 1},
+				End:   &FindingPosition{Line: 1, Column: 1},
+			}
+		}
+
+		result.AddFinding(f.Severity, ruleNameID, &Finding{
+			Type:  rule.FindingTypeRange,
+			Path:  f.Path,
+			Range: r,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analyzer
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/specbundler"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/speciterator"
+)
+
+// ResolvePositions fills the missing Finding.Range or Finding.Path of all findings of r from doc (JSON or YAML),
+// whatever analyzer produced them:
+//   - a finding with a range (e.g. Spectral's exact ranges) keeps it,
+//   - a finding without a path (e.g. a text-based finding) gets the path of the item at the start of its range,
+//   - a finding without a range gets the position of the item at its path, or of the closest located ancestor.
+func (r Result) ResolvePositions(doc []byte) {
+	var positions *speciterator.Positions
+	r.EachFinding(func(_ rule.SeverityName, _ rule.NameID, finding *Finding) {
+		if positions == nil {
+			positions = speciterator.NewPositions(doc)
+		}
+		if positions.Len() == 0 {
+			return
+		}
+		if finding.Range != nil && finding.Range.Start != nil {
+			if len(finding.Path) == 0 {
+				finding.Path = positions.PathAt(finding.Range.Start.Line, finding.Range.Start.Column)
+				if finding.Path == nil {
+					finding.Path = []string{}
+				}
+			}
+			return
+		}
+		finding.Range = positionRange(positions, finding.Path)
+	})
+}
+
+// ResolveSources fills the Finding.Source of all findings of r, for a doc bundled from the original files of a multi-file spec:
+// locate maps the JSON pointer of a finding's Finding.Path to its original file & JSON pointer (see specbundler.Locate),
+// which is then located in that file.
+func (r Result) ResolveSources(files map[string]string, locate func(pointer string) specbundler.Source) {
+	positions := map[string]*speciterator.Positions{}
+	r.EachFinding(func(_ rule.SeverityName, _ rule.NameID, finding *Finding) {
+		if finding.Path == nil {
+			return
+		}
+		src := locate(specbundler.Pointer(finding.Path))
+		data, ok := files[src.File]
+		if !ok {
+			return
+		}
+		finding.Source = &FindingSource{File: src.File, Path: specbundler.Path(src.Pointer)}
+
+		if positions[src.File] == nil {
+			positions[src.File] = speciterator.NewPositions([]byte(data))
+		}
+		if positions[src.File].Len() > 0 {
+			finding.Source.Range = positionRange(positions[src.File], finding.Source.Path)
+		}
+	})
+}
+
+// positionRange returns the range of the item at path in positions (see speciterator.Positions.Position).
+func positionRange(positions *speciterator.Positions, path []string) *FindingPositionRange {
+	pos, _ := positions.Position(path)
+	return &FindingPositionRange{
+		Start: &FindingPosition{Line: pos.Line, Column: pos.Column},
+		End:   &FindingPosition{Line: pos.Line, Column: pos.Column},
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analyzer
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestResult_ResolvePositions(t *testing.T) {
+	yamlDoc := `openapi: 3.0.0
+info:
+  title: Pets
+  description: A list of blacklisted pets.
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+`
+	// A minified doc, on a single line longer than the default bufio.Scanner buffer.
+	jsonDoc := `{"openapi":"3.0.0","info":{"title":"` + strings.Repeat("x", 70000) + `"},"paths":{"/pets":{"get":{}}}}`
+
+	rng := func(line, column int) *FindingPositionRange {
+		return &FindingPositionRange{
+			Start: &FindingPosition{Line: line, Column: column},
+			End:   &FindingPosition{Line: line, Column: column},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		doc       string
+		finding   *Finding
+		wantPath  []string
+		wantRange *FindingPositionRange
+	}{
+		{
+			name:      "path",
+			doc:       yamlDoc,
+			finding:   &Finding{Path: []string{"paths", "/pets", "get"}},
+			wantPath:  []string{"paths", "/pets", "get"},
+			wantRange: rng(7, 5),
+		},
+		{
+			name:      "path keeps range",
+			doc:       yamlDoc,
+			finding:   &Finding{Path: []string{"paths", "/pets", "get", "responses", "200"}, Range: rng(1, 1)},
+			wantPath:  []string{"paths", "/pets", "get", "responses", "200"},
+			wantRange: rng(1, 1),
+		},
+		{
+			name: "multi-line range is kept",
+			doc:  yamlDoc,
+			finding: &Finding{Path: []string{"paths", "/pets", "get", "responses", "200"}, Range: &FindingPositionRange{
+				Start: &FindingPosition{Line: 9, Column: 9},
+				End:   &FindingPosition{Line: 10, Column: 27},
+			}},
+			wantPath: []string{"paths", "/pets", "get", "responses", "200"},
+			wantRange: &FindingPositionRange{
+				Start: &FindingPosition{Line: 9, Column: 9},
+				End:   &FindingPosition{Line: 10, Column: 27},
+			},
+		},
+		{
+			name:      "unlocated path falls back to ancestor",
+			doc:       yamlDoc,
+			finding:   &Finding{Path: []string{"paths", "/pets", "post"}},
+			wantPath:  []string{"paths", "/pets", "post"},
+			wantRange: rng(6, 3),
+		},
+		{
+			name:      "unlocated path keeps range",
+			doc:       yamlDoc,
+			finding:   &Finding{Path: []string{"paths", "/pets", "post"}, Range: rng(2, 2)},
+			wantPath:  []string{"paths", "/pets", "post"},
+			wantRange: rng(2, 2),
+		},
+		{
+			name:      "range without path",
+			doc:       yamlDoc,
+			finding:   &Finding{Path: []string{}, Range: rng(4, 24)},
+			wantPath:  []string{"info", "description"},
+			wantRange: rng(4, 24),
+		},
+		{
+			name:      "long json line",
+			doc:       jsonDoc,
+			finding:   &Finding{Path: []string{"paths", "/pets", "get"}},
+			wantPath:  []string{"paths", "/pets", "get"},
+			wantRange: rng(1, 70064), // JSON items are located at their values.
+		},
+		{
+			name:     "unparsable doc",
+			doc:      "{",
+			finding:  &Finding{Path: []string{"paths"}},
+			wantPath: []string{"paths"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResult()
+			r.Findings[rule.SeverityNameWarning].Rules = map[rule.NameID]*Findings{"rule": {Data: []*Finding{tt.finding}}}
+			r.ResolvePositions([]byte(tt.doc))
+			assert.Equal(t, tt.wantPath, tt.finding.Path)
+			assert.Equal(t, tt.wantRange, tt.finding.Range)
+		})
+	}
+}
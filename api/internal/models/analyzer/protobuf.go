@@ -0,0 +1,77 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analyzer
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+)
+
+// ProtobufFinding represents a finding of a protobuf analyzer.
+// Path identifies the definition in its .proto file, e.g. [file, "message", full name, "field", name],
+// while Line & Column are its position in the .proto file, if known.
+type ProtobufFinding struct {
+	Rule     string
+	Severity rule.SeverityName
+	Message  string
+	Path     []string
+	Line     int
+	Column   int
+}
+
+// ProtobufResult represents the findings of a protobuf analyzer (e.g. ProtobufStyle).
+type ProtobufResult struct {
+	Analyzer SpecAnalyzer
+	Findings []*ProtobufFinding
+}
+
+func (m *ProtobufResult) Result() (*Result, error) {
+	result := NewResult()
+	if m == nil {
+		return result, nil
+	}
+
+	for _, f := range m.Findings {
+		ruleNameID := rule.NameID(f.Rule)
+		result.storeRuleInCache(f.Severity, ruleNameID, &Rule{
+			NameID:         f.Rule,
+			AnalyzerNameID: string(m.Analyzer),
+			Title:          f.Rule,
+			Description:    f.Message,
+			Severity:       f.Severity.String(),
+		})
+
+		// Compiled FileDescriptorSets have no positions.
+		line, column := f.Line, f.Column
+		if line < 1 {
+			line = 1
+		}
+		if column < 1 {
+			column = 1
+		}
+
+		result.AddFinding(f.Severity, ruleNameID, &Finding{
+			Type: rule.FindingTypeRange,
+			Path: f.Path,
+			Range: &FindingPositionRange{
+				Start: &FindingPosition{Line: line, Column: column},
+				End:   &FindingPosition{Line: line, Column: column},
+			},
+		})
+	}
+
+	return result, nil
+}
@@ -61,6 +61,23 @@ func (r Result) AddFinding(severity rule.SeverityName, ruleNameID rule.NameID, f
 	r.updateSummaryStatsAfterAddFinding(severity, ruleNameID)
 }
 
+// EachFinding calls fn for every finding of the result.
+func (r Result) EachFinding(fn func(severity rule.SeverityName, ruleNameID rule.NameID, finding *Finding)) {
+	for severity, ruleFindings := range r.Findings {
+		if ruleFindings == nil {
+			continue
+		}
+		for ruleNameID, findings := range ruleFindings.Rules {
+			if findings == nil {
+				continue
+			}
+			for _, finding := range findings.Data {
+				fn(severity, ruleNameID, finding)
+			}
+		}
+	}
+}
+
 func (r Result) updateSummaryStatsAfterAddFinding(severity rule.SeverityName, ruleNameID rule.NameID) {
 	if r.Summary == nil {
 		r.Summary = NewResultSummary()
@@ -171,10 +188,16 @@ type Findings struct {
 }
 
 type Finding struct {
-	Type  rule.FindingType      `json:"type"`
-	Path  []string              `json:"path"`
-	Range *FindingPositionRange `json:"range,omitempty"`
-	Diff  *FindingDiff          `json:"diff,omitempty"`
+	Type rule.FindingType `json:"type"`
+	Path []string         `json:"path"`
+	// OriginalPath is the path in the original doc, if the analyzed doc was converted from it (e.g. Swagger 2.0).
+	OriginalPath []string              `json:"original_path,omitempty"`
+	Range        *FindingPositionRange `json:"range,omitempty"`
+	// Source is the location in the original file, if the analyzed doc was bundled from a multi-file spec.
+	Source *FindingSource `json:"source,omitempty"`
+	Diff   *FindingDiff   `json:"diff,omitempty"`
+	// Fix is a machine-applicable fix of the finding, if its rule is fixable.
+	Fix *FindingFix `json:"fix,omitempty"`
 }
 
 type (
@@ -189,7 +212,31 @@ type (
 	}
 )
 
+// FindingSource represents the location of a Finding in the original file of a multi-file spec.
+type FindingSource struct {
+	File  string                `json:"file"`
+	Path  []string              `json:"path"`
+	Range *FindingPositionRange `json:"range,omitempty"`
+}
+
 type FindingDiff struct {
 	Old string `json:"old"`
 	New string `json:"new"`
 }
+
+// FindingFix represents a machine-applicable fix of a Finding, as an RFC 6902 JSON Patch against the analyzed doc.
+type FindingFix struct {
+	// ID identifies the fix among the fixes of a spec, for selecting fixes to apply.
+	ID          string                      `json:"id"`
+	Rule        rule.NameID                 `json:"rule"`
+	Description string                      `json:"description"`
+	Patch       []*FindingFixPatchOperation `json:"patch"`
+}
+
+// FindingFixPatchOperation represents an operation of an RFC 6902 JSON Patch.
+type FindingFixPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
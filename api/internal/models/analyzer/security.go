@@ -109,13 +109,9 @@ func GetSecurityResult(spec string, in *models.APIServiceDrillDownExternal) (*Re
 	c := in.Score.API.Categories[ScoreCategoryAPISecurity]
 	fgs := []*models.ScoreFindingGroup{c.Critical, c.High, c.Medium, c.Low, c.Unclassified}
 
-	var possByPaths = map[string]*speciterator.Pos{}
-	var itemVisited = func(path *speciterator.Path, pos *speciterator.Pos) {
-		possByPaths[path.String()] = pos
-	}
+	var positions *speciterator.Positions
 	if len(fgs) > 0 {
-		si := speciterator.NewSpecIterator([]byte(spec))
-		_ = si.Iterate(itemVisited)
+		positions = speciterator.NewPositions([]byte(spec))
 	}
 
 	for _, fg := range fgs {
@@ -153,19 +149,10 @@ func GetSecurityResult(spec string, in *models.APIServiceDrillDownExternal) (*Re
 
 				p := item.JSONPaths()
 
-				line, column := 1, 1
-				if pos, found := possByPaths[strings.Join(p, "|")]; found && pos != nil {
-					line = pos.Line
-					column = pos.Column
-				}
-
 				result.AddFinding(severity, ruleNameID, &Finding{
-					Type: rule.FindingTypeRange,
-					Path: p,
-					Range: &FindingPositionRange{
-						Start: &FindingPosition{Line: line, Column: column},
-						End:   &FindingPosition{Line: line, Column: column},
-					},
+					Type:  rule.FindingTypeRange,
+					Path:  p,
+					Range: positionRange(positions, p),
 				})
 			}
 		}
@@ -85,8 +85,8 @@ func (m SpectralResult) Result() (*Result, error) {
 					Column: r.Range.Start.Character + 1,
 				},
 				End: &FindingPosition{
-					Line:   r.Range.Start.Line + 1,
-					Column: r.Range.Start.Character + 1,
+					Line:   r.Range.End.Line + 1,
+					Column: r.Range.End.Character + 1,
 				},
 			},
 		})
@@ -0,0 +1,382 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	AuditEventTableName = "audit_events"
+
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+
+	AuditResourceTypeSpec            = "spec"
+	AuditResourceTypeWebhookDelivery = "webhook_delivery"
+
+	AuditExportFormatNDJSON = "ndjson"
+	AuditExportFormatCSV    = "csv"
+
+	auditSnapshotRedacted   = "[REDACTED]"
+	auditSnapshotOmitted    = "[OMITTED]"
+	auditResourcePathPrefix = "/v1/apiregistry/"
+)
+
+// ErrAuditEventImmutable is returned when updating or deleting an AuditEvent, as the audit log is append-only.
+var ErrAuditEventImmutable = errors.New("audit events are append-only")
+
+var (
+	// auditResourceTypes maps the collections of resource paths to their resource types.
+	auditResourceTypes = map[string]string{
+		"analyses":      "spec_analysis",
+		"analyzers":     "analyzer",
+		"consumers":     "consumer",
+		"deliveries":    AuditResourceTypeWebhookDelivery,
+		"diff":          "spec_diff",
+		"diffs":         "spec_diff",
+		"digests":       "digest_subscription",
+		"organizations": "organization",
+		"rules":         "analyzer_rule",
+		"services":      "service",
+		"specs":         AuditResourceTypeSpec,
+		"webhooks":      "webhook",
+	}
+
+	// auditSnapshotRedactedFields are the (JSON) fields whose values are redacted from snapshots.
+	auditSnapshotRedactedFields = map[string]bool{"secret": true, "password": true, "token": true}
+	// auditSnapshotURLFields are the (JSON) fields of webhook URLs, which often embed credentials (e.g. chat webhooks),
+	// so that only their scheme & host are kept in snapshots.
+	auditSnapshotURLFields = map[string]bool{"url": true, "webhook_url": true, "recipient": true}
+	// auditSnapshotOmittedFields are the (JSON) fields whose values are omitted from snapshots, as too large (e.g. spec docs).
+	auditSnapshotOmittedFields = map[string]bool{"doc": true, "old_spec_doc": true, "new_spec_doc": true}
+	// auditChangesIgnoredFields are the (JSON) fields whose changes aren't worth recording.
+	auditChangesIgnoredFields = map[string]bool{"updated_at": true}
+)
+
+// AuditEvent represents a mutation of a resource, recorded in the append-only audit log.
+type AuditEvent struct {
+	ID           string         `json:"id,omitempty" gorm:"column:id;primaryKey"`
+	Actor        string         `json:"actor" gorm:"column:actor;index"`
+	Action       string         `json:"action" gorm:"column:action;index"` // create, update or delete
+	Operation    string         `json:"operation" gorm:"column:operation"` // The API operation, e.g. saveSpec.
+	Method       string         `json:"method" gorm:"column:method"`
+	Path         string         `json:"path" gorm:"column:path"`
+	ResourceType string         `json:"resource_type" gorm:"column:resource_type;index:audit_resource_idx"`
+	ResourceID   string         `json:"resource_id" gorm:"column:resource_id;index:audit_resource_idx"`
+	Status       int            `json:"status" gorm:"column:status"`
+	Before       datatypes.JSON `json:"before,omitempty" gorm:"column:before"`
+	After        datatypes.JSON `json:"after,omitempty" gorm:"column:after"`
+	Changes      AuditChanges   `json:"changes,omitempty" gorm:"column:changes"`
+	RequestID    string         `json:"request_id" gorm:"column:request_id;index"`
+	RemoteAddr   string         `json:"remote_addr" gorm:"column:remote_addr"`
+	CreatedAt    time.Time      `json:"created_at" gorm:"column:created_at;index"`
+}
+
+// AuditEventResponse wrappers audit event response
+type AuditEventResponse struct {
+	Pagination
+	Data []*AuditEvent `json:"data"`
+}
+
+// AuditChange represents the change of a field of a resource, by its JSON path, e.g. summary.score.
+type AuditChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges represents the field changes of an AuditEvent.
+type AuditChanges []*AuditChange
+
+// TableName implements gorm Tabler interface
+func (m *AuditEvent) TableName() string {
+	return AuditEventTableName
+}
+
+func (m *AuditEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = shared.TimeUUID()
+	}
+	return
+}
+
+// BeforeUpdate is a hook called before updates by GORM (https://gorm.io/docs/hooks.html), rejecting them.
+func (m *AuditEvent) BeforeUpdate(tx *gorm.DB) (err error) {
+	return ErrAuditEventImmutable
+}
+
+// BeforeDelete is a hook called before deletions by GORM (https://gorm.io/docs/hooks.html), rejecting them.
+func (m *AuditEvent) BeforeDelete(tx *gorm.DB) (err error) {
+	return ErrAuditEventImmutable
+}
+
+// GetID returns the ID of auditEvent object
+func (m *AuditEvent) GetID() string {
+	return fmt.Sprintf("%v", m.ID)
+}
+
+// String returns the text representation of auditEvent object
+func (m *AuditEvent) String() string {
+	return fmt.Sprintf("%v", *m)
+}
+
+// GetIndex returns an index for specific field
+func (m *AuditEvent) GetIndex(field string) string {
+	return m.GetIndexes()[field]
+}
+
+// GetIndexes returns all the field indexes
+func (m *AuditEvent) GetIndexes() map[string]string {
+	return map[string]string{
+		"actor":         "idx_actor",
+		"action":        "idx_action",
+		"resource_type": "audit_resource_idx",
+		"resource_id":   "audit_resource_idx",
+		"request_id":    "idx_request_id",
+	}
+}
+
+// GetIndexValue return index value for specified field
+func (m *AuditEvent) GetIndexValue(field string) string {
+	return m.GetIndexValues()[field]
+}
+
+// GetIndexValues return all field index values
+func (m *AuditEvent) GetIndexValues() map[string]string {
+	return map[string]string{
+		"actor":         m.Actor,
+		"action":        m.Action,
+		"resource_type": m.ResourceType,
+		"resource_id":   m.ResourceID,
+		"request_id":    m.RequestID,
+	}
+}
+
+// Sortable checks if field is sortable.
+func (m *AuditEvent) Sortable(field string) bool {
+	_, found := m.SortableFields()[field]
+	return found
+}
+
+// SortableFields returns all sortable fields
+func (m *AuditEvent) SortableFields() map[string]struct{} {
+	return map[string]struct{}{
+		"created_at": {},
+	}
+}
+
+// SetSnapshots sets the before & after snapshots of the resource of m (see NewAuditSnapshot), along with their changes.
+func (m *AuditEvent) SetSnapshots(before, after datatypes.JSON) {
+	m.Before, m.After = before, after
+	if m.Before != nil && m.After != nil {
+		m.Changes = NewAuditChanges(m.Before, m.After)
+	}
+}
+
+// CSVHeader returns the header of the CSV export of AuditEvent(s).
+func (m *AuditEvent) CSVHeader() []string {
+	return []string{"id", "created_at", "actor", "action", "operation", "method", "path", "resource_type", "resource_id", "status", "request_id", "remote_addr", "changes"}
+}
+
+// CSVRecord returns the record of m in the CSV export of AuditEvent(s), with its changes as JSON.
+func (m *AuditEvent) CSVRecord() []string {
+	changes := ""
+	if len(m.Changes) > 0 {
+		if data, err := json.Marshal(m.Changes); err == nil {
+			changes = string(data)
+		}
+	}
+	return []string{m.ID, m.CreatedAt.UTC().Format(time.RFC3339Nano), m.Actor, m.Action, m.Operation, m.Method, m.Path,
+		m.ResourceType, m.ResourceID, fmt.Sprint(m.Status), m.RequestID, m.RemoteAddr, changes}
+}
+
+// Scan implements sql.Scanner interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m *AuditChanges) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if s, ok := value.(string); ok {
+			bytes = []byte(s)
+		} else if value == nil {
+			return nil
+		} else {
+			return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+		}
+	}
+
+	return json.Unmarshal(bytes, &m)
+}
+
+// Value implements driver.Valuer interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m AuditChanges) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// AuditActionFromMethod returns the audit action of a request with the HTTP method, or "" if it isn't a mutation.
+func AuditActionFromMethod(method string) string {
+	switch method {
+	case http.MethodPost:
+		return AuditActionCreate
+	case http.MethodPut, http.MethodPatch:
+		return AuditActionUpdate
+	case http.MethodDelete:
+		return AuditActionDelete
+	}
+	return ""
+}
+
+// AuditResourceFromRoute returns the type & ID of the resource of a request of route (e.g. /v1/apiregistry/services/{id}/specs/{specID})
+// with the path params, i.e. of its last collection & the param following it, if any.
+func AuditResourceFromRoute(route string, params map[string]string) (resourceType, resourceID string) {
+	for _, segment := range strings.Split(strings.TrimPrefix(route, auditResourcePathPrefix), "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if resourceType != "" && resourceID == "" {
+				resourceID = params[strings.Trim(segment, "{}")]
+			}
+			continue
+		}
+		if t, ok := auditResourceTypes[segment]; ok {
+			resourceType, resourceID = t, ""
+		}
+	}
+	return resourceType, resourceID
+}
+
+// NewAuditSnapshot returns the JSON snapshot of a resource v for the audit log, with the values of sensitive fields redacted
+// & of large ones (e.g. spec docs) omitted, or nil if v is nil.
+func NewAuditSnapshot(v interface{}) (datatypes.JSON, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return json.Marshal(sanitizeAuditSnapshot(snapshot))
+}
+
+func sanitizeAuditSnapshot(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			switch {
+			case value == nil || value == "":
+			case auditSnapshotRedactedFields[k]:
+				v[k] = auditSnapshotRedacted
+			case auditSnapshotOmittedFields[k]:
+				v[k] = auditSnapshotOmitted
+			case auditSnapshotURLFields[k]:
+				v[k] = redactAuditSnapshotURL(value)
+			default:
+				v[k] = sanitizeAuditSnapshot(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = sanitizeAuditSnapshot(value)
+		}
+	}
+	return v
+}
+
+// redactAuditSnapshotURL returns v without the user info, path & query if it's an absolute URL, or else as is (e.g. an email recipient).
+func redactAuditSnapshotURL(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return v
+	}
+	if u.User == nil && (u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.Fragment == "" {
+		return s
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host}).String() + "/" + auditSnapshotRedacted
+}
+
+// NewAuditChanges returns the changes of the fields (by JSON path, arrays being compared as a whole) between the before & after
+// snapshots, sorted by path.
+func NewAuditChanges(before, after datatypes.JSON) AuditChanges {
+	var b, a interface{}
+	if err := json.Unmarshal(before, &b); err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(after, &a); err != nil {
+		return nil
+	}
+	beforeFields, afterFields := map[string]interface{}{}, map[string]interface{}{}
+	flattenAuditSnapshot("", b, beforeFields)
+	flattenAuditSnapshot("", a, afterFields)
+
+	changes := AuditChanges{}
+	for path, value := range beforeFields {
+		if afterValue, ok := afterFields[path]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changes = append(changes, &AuditChange{Path: path, Before: value, After: afterFields[path]})
+		}
+	}
+	for path, value := range afterFields {
+		if _, ok := beforeFields[path]; !ok {
+			changes = append(changes, &AuditChange{Path: path, After: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func flattenAuditSnapshot(path string, v interface{}, fields map[string]interface{}) {
+	object, ok := v.(map[string]interface{})
+	if !ok || (len(object) == 0 && path != "") {
+		fields[path] = v
+		return
+	}
+	for k, value := range object {
+		if path == "" && auditChangesIgnoredFields[k] {
+			continue
+		}
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		flattenAuditSnapshot(p, value, fields)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditActionFromMethod(t *testing.T) {
+	assert.Equal(t, AuditActionCreate, AuditActionFromMethod(http.MethodPost))
+	assert.Equal(t, AuditActionUpdate, AuditActionFromMethod(http.MethodPut))
+	assert.Equal(t, AuditActionUpdate, AuditActionFromMethod(http.MethodPatch))
+	assert.Equal(t, AuditActionDelete, AuditActionFromMethod(http.MethodDelete))
+	assert.Equal(t, "", AuditActionFromMethod(http.MethodGet))
+}
+
+func TestAuditResourceFromRoute(t *testing.T) {
+	tests := []struct {
+		route, wantType, wantID string
+	}{
+		{"/v1/apiregistry/services", "service", ""},
+		{"/v1/apiregistry/services/{id}", "service", "svc"},
+		{"/v1/apiregistry/services/{id}/specs", AuditResourceTypeSpec, ""},
+		{"/v1/apiregistry/services/{id}/specs/{specID}", AuditResourceTypeSpec, "spec"},
+		{"/v1/apiregistry/analyzers/{id}/rules/{ruleID}", "analyzer_rule", "rule"},
+		{"/v1/apiregistry/organizations/{id}", "organization", "svc"},
+		{"/v1/apiregistry/unknown/{id}", "", ""},
+	}
+	params := map[string]string{"id": "svc", "specID": "spec", "ruleID": "rule"}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			gotType, gotID := AuditResourceFromRoute(tt.route, params)
+			assert.Equal(t, tt.wantType, gotType)
+			assert.Equal(t, tt.wantID, gotID)
+		})
+	}
+}
+
+func TestNewAuditSnapshot(t *testing.T) {
+	snapshot, err := NewAuditSnapshot(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	var webhook *Webhook
+	snapshot, err = NewAuditSnapshot(webhook)
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	snapshot, err = NewAuditSnapshot(&Webhook{ID: "w", URL: "https://example.com/hook", Secret: "s3cr3t"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(snapshot), `"secret":"[REDACTED]"`)
+	assert.NotContains(t, string(snapshot), "s3cr3t")
+	assert.Contains(t, string(snapshot), `"url":"https://example.com/[REDACTED]"`)
+
+	snapshot, err = NewAuditSnapshot(&DigestSubscription{ID: "d", Channel: DigestChannelChat, Recipient: "https://hooks.example.com/services/T1/B2/t0k3n?x=1"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(snapshot), `"recipient":"https://hooks.example.com/[REDACTED]"`)
+	assert.NotContains(t, string(snapshot), "t0k3n")
+
+	snapshot, err = NewAuditSnapshot(&DigestSubscription{ID: "d", Channel: DigestChannelEmail, Recipient: "lead@example.com"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(snapshot), `"recipient":"lead@example.com"`)
+
+	snapshot, err = NewAuditSnapshot(&Spec{ID: "s", Doc: NewSpecDocFromBytes([]byte(`{"openapi":"3.0.0"}`))})
+	assert.NoError(t, err)
+	assert.Contains(t, string(snapshot), `"doc":"[OMITTED]"`)
+	assert.NotContains(t, string(snapshot), "openapi")
+}
+
+func TestNewAuditChanges(t *testing.T) {
+	before, err := NewAuditSnapshot(map[string]interface{}{
+		"title":      "Old",
+		"contact":    map[string]interface{}{"name": "a", "email": "a@example.com"},
+		"tags":       []string{"x"},
+		"updated_at": "2022-01-01T00:00:00Z",
+	})
+	assert.NoError(t, err)
+	after, err := NewAuditSnapshot(map[string]interface{}{
+		"title":      "New",
+		"contact":    map[string]interface{}{"name": "a"},
+		"tags":       []string{"x", "y"},
+		"product":    "p",
+		"updated_at": "2022-01-02T00:00:00Z",
+	})
+	assert.NoError(t, err)
+
+	changes := NewAuditChanges(before, after)
+	assert.Equal(t, AuditChanges{
+		{Path: "contact.email", Before: "a@example.com"},
+		{Path: "product", After: "p"},
+		{Path: "tags", Before: []interface{}{"x"}, After: []interface{}{"x", "y"}},
+		{Path: "title", Before: "Old", After: "New"},
+	}, changes)
+}
+
+func TestAuditEvent(t *testing.T) {
+	m := &AuditEvent{
+		ID:           "e",
+		Actor:        "alice@example.com",
+		Action:       AuditActionUpdate,
+		Method:       http.MethodPatch,
+		Path:         "/v1/apiregistry/services/svc",
+		ResourceType: "service",
+		ResourceID:   "svc",
+		Status:       http.StatusOK,
+		RequestID:    "r",
+		CreatedAt:    time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	before, _ := NewAuditSnapshot(map[string]string{"title": "Old"})
+	after, _ := NewAuditSnapshot(map[string]string{"title": "New"})
+	m.SetSnapshots(before, after)
+	assert.Len(t, m.Changes, 1)
+
+	record := m.CSVRecord()
+	assert.Len(t, record, len(m.CSVHeader()))
+	assert.Equal(t, "2022-01-02T03:04:05Z", record[1])
+	assert.Equal(t, `[{"path":"title","before":"Old","after":"New"}]`, record[len(record)-1])
+
+	assert.ErrorIs(t, m.BeforeUpdate(nil), ErrAuditEventImmutable)
+	assert.ErrorIs(t, m.BeforeDelete(nil), ErrAuditEventImmutable)
+}
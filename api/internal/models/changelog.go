@@ -0,0 +1,288 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"strings"
+	"time"
+)
+
+const (
+	ChangelogFormatJSON     = "json"
+	ChangelogFormatMarkdown = "markdown"
+)
+
+// Changelog represents the changes of a service API across a range of its specs, grouped by version.
+type Changelog struct {
+	ServiceID string `json:"service_id"`
+	Title     string `json:"title"`
+	// From is the version the changes are since, empty if the changelog starts at the first spec.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Versions are the changed versions, latest first.
+	Versions []*ChangelogVersion `json:"versions"`
+}
+
+// ChangelogVersion represents the changes of a version, from the revisions of its specs.
+type ChangelogVersion struct {
+	Version string `json:"version"`
+	// Revision & SpecID are the ones of the latest spec of the version.
+	Revision string    `json:"revision"`
+	SpecID   string    `json:"spec_id"`
+	Date     time.Time `json:"date"`
+	Breaking bool      `json:"breaking"`
+	// Initial marks the first spec of the service, which has no changes.
+	Initial    bool              `json:"initial,omitempty"`
+	Added      []*ChangelogEntry `json:"added"`
+	Deprecated []*ChangelogEntry `json:"deprecated"`
+	Removed    []*ChangelogEntry `json:"removed"`
+	Changed    []*ChangelogEntry `json:"changed"`
+
+	// firstSpecID is the ID of the first spec of the version, identifying it in the Atom feed.
+	firstSpecID string
+}
+
+// ChangelogEntry represents a change of an endpoint.
+type ChangelogEntry struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Description string `json:"description,omitempty"`
+	Breaking    bool   `json:"breaking"`
+	// Revision is the revision of the spec which made the change.
+	Revision string `json:"revision"`
+}
+
+// NewChangelog constructs a new, empty Changelog of service.
+func NewChangelog(service *Service, from, to string) *Changelog {
+	title := service.Title
+	if title == "" {
+		title = service.NameID
+	}
+	return &Changelog{
+		ServiceID: service.ID,
+		Title:     title,
+		From:      from,
+		To:        to,
+		Versions:  []*ChangelogVersion{},
+	}
+}
+
+// ChangelogSpecs returns the specs (sorted by creation, oldest first) the changelog from & to versions spans:
+// from the latest spec of version from (or the first spec, if from is empty) to the latest spec of version to
+// (or the latest spec, if to is empty).
+func ChangelogSpecs(specs []*Spec, from, to string) ([]*Spec, error) {
+	var (
+		start = 0
+		end   = len(specs) - 1
+	)
+	if from != "" {
+		if start = lastSpecOfVersion(specs, from); start < 0 {
+			return nil, fmt.Errorf("changelog: no spec with version %s", from)
+		}
+	}
+	if to != "" {
+		if end = lastSpecOfVersion(specs, to); end < 0 {
+			return nil, fmt.Errorf("changelog: no spec with version %s", to)
+		}
+	}
+	if end < start {
+		return nil, fmt.Errorf("changelog: version %s predates version %s", to, from)
+	}
+	return specs[start : end+1], nil
+}
+
+func lastSpecOfVersion(specs []*Spec, version string) int {
+	for i := len(specs) - 1; i >= 0; i-- {
+		if specs[i].Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddInitial adds spec, the first spec of the service, to the changelog.
+func (m *Changelog) AddInitial(spec *Spec) {
+	m.version(spec).Initial = true
+}
+
+// Add adds the changes of spec, result being the JSON diff result of its previous spec & spec, to the changelog.
+func (m *Changelog) Add(spec *Spec, result *diff.JSONResult) {
+	v := m.version(spec)
+	if result == nil {
+		return
+	}
+	entry := func(method, path, description string, breaking bool) *ChangelogEntry {
+		return &ChangelogEntry{
+			Method:      method,
+			Path:        path,
+			Description: strings.Join(strings.Fields(description), " "),
+			Breaking:    breaking,
+			Revision:    spec.Revision,
+		}
+	}
+	for _, e := range result.Added {
+		v.Added = append(v.Added, entry(e.Method, e.Path, e.Description, false))
+	}
+	for _, e := range result.Deprecated {
+		v.Deprecated = append(v.Deprecated, entry(e.Method, e.Path, e.Description, false))
+	}
+	for _, e := range result.Deleted {
+		v.Removed = append(v.Removed, entry(e.Method, e.Path, e.Description, true))
+	}
+	for _, e := range result.Modified {
+		v.Changed = append(v.Changed, entry(e.Method, e.Path, e.Summary, e.Breaking))
+	}
+	v.Breaking = v.Breaking || result.Breaking
+}
+
+// version returns the ChangelogVersion of spec, adding it first if needed, & updates it to spec.
+func (m *Changelog) version(spec *Spec) *ChangelogVersion {
+	for _, v := range m.Versions {
+		if v.Version == spec.Version {
+			v.Revision, v.SpecID, v.Date = spec.Revision, spec.ID, spec.CreatedAt
+			return v
+		}
+	}
+	v := &ChangelogVersion{
+		Version:    spec.Version,
+		Revision:   spec.Revision,
+		SpecID:     spec.ID,
+		Date:       spec.CreatedAt,
+		Added:      []*ChangelogEntry{},
+		Deprecated: []*ChangelogEntry{},
+		Removed:    []*ChangelogEntry{},
+		Changed:    []*ChangelogEntry{},
+
+		firstSpecID: spec.ID,
+	}
+	// Specs are added oldest first & versions are listed latest first.
+	m.Versions = append([]*ChangelogVersion{v}, m.Versions...)
+	return v
+}
+
+// Markdown returns the changelog as Keep a Changelog (https://keepachangelog.com) markdown.
+func (m *Changelog) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Changelog\n\n")
+	sb.WriteString(fmt.Sprintf("All notable changes to the %s API.\n", m.Title))
+	for _, v := range m.Versions {
+		sb.WriteString("\n")
+		sb.WriteString(v.markdown("##"))
+	}
+	return sb.String()
+}
+
+func (v *ChangelogVersion) markdown(heading string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s [%s] - %s\n", heading, v.Version, v.Date.UTC().Format("2006-01-02")))
+	if v.Breaking {
+		sb.WriteString("\n**This version contains breaking changes.**\n")
+	}
+	if v.Initial {
+		sb.WriteString("\nInitial version.\n")
+	}
+	for _, section := range []struct {
+		title   string
+		entries []*ChangelogEntry
+	}{
+		{"Added", v.Added},
+		{"Deprecated", v.Deprecated},
+		{"Removed", v.Removed},
+		{"Changed", v.Changed},
+	} {
+		if len(section.entries) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n%s# %s\n\n", heading, section.title))
+		for _, e := range section.entries {
+			sb.WriteString("- ")
+			if e.Breaking {
+				sb.WriteString("**BREAKING** ")
+			}
+			sb.WriteString(fmt.Sprintf("`%s` %s", e.Method, e.Path))
+			if e.Description != "" {
+				sb.WriteString(": " + e.Description)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// ChangelogFeed represents a Changelog as an Atom feed (RFC 4287).
+type ChangelogFeed struct {
+	XMLName xml.Name              `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string                `xml:"id"`
+	Title   string                `xml:"title"`
+	Updated string                `xml:"updated"`
+	Link    *ChangelogFeedLink    `xml:"link"`
+	Author  *ChangelogFeedAuthor  `xml:"author"`
+	Entries []*ChangelogFeedEntry `xml:"entry"`
+}
+
+type ChangelogFeedLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type ChangelogFeedAuthor struct {
+	Name string `xml:"name"`
+}
+
+type ChangelogFeedEntry struct {
+	ID      string                `xml:"id"`
+	Title   string                `xml:"title"`
+	Updated string                `xml:"updated"`
+	Content *ChangelogFeedContent `xml:"content"`
+}
+
+type ChangelogFeedContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Feed returns the changelog as an Atom feed, self being its URL.
+func (m *Changelog) Feed(self string) *ChangelogFeed {
+	feed := &ChangelogFeed{
+		ID:      "urn:uuid:" + m.ServiceID,
+		Title:   m.Title + " API changelog",
+		Updated: time.Unix(0, 0).UTC().Format(time.RFC3339),
+		Link:    &ChangelogFeedLink{Rel: "self", Href: self},
+		Author:  &ChangelogFeedAuthor{Name: "API Insights"},
+		Entries: []*ChangelogFeedEntry{},
+	}
+	for i, v := range m.Versions {
+		updated := v.Date.UTC().Format(time.RFC3339)
+		if i == 0 {
+			feed.Updated = updated
+		}
+		title := fmt.Sprintf("%s %s", m.Title, v.Version)
+		if v.Breaking {
+			title += " (breaking)"
+		}
+		feed.Entries = append(feed.Entries, &ChangelogFeedEntry{
+			ID:      "urn:uuid:" + v.firstSpecID,
+			Title:   title,
+			Updated: updated,
+			Content: &ChangelogFeedContent{Type: "text", Body: v.markdown("#")},
+		})
+	}
+	return feed
+}
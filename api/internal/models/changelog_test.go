@@ -0,0 +1,141 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelogSpecs(t *testing.T) {
+	specs := []*Spec{
+		{ID: "1", Version: "1.0.0"},
+		{ID: "2", Version: "1.0.0"},
+		{ID: "3", Version: "1.1.0"},
+		{ID: "4", Version: "2.0.0"},
+	}
+	ids := func(specs []*Spec) (ids []string) {
+		for _, s := range specs {
+			ids = append(ids, s.ID)
+		}
+		return
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "all", want: []string{"1", "2", "3", "4"}},
+		{name: "from", from: "1.0.0", want: []string{"2", "3", "4"}},
+		{name: "to", to: "1.1.0", want: []string{"1", "2", "3"}},
+		{name: "from to", from: "1.1.0", to: "2.0.0", want: []string{"3", "4"}},
+		{name: "unknown from", from: "0.1.0", wantErr: true},
+		{name: "to before from", from: "2.0.0", to: "1.0.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ChangelogSpecs(specs, tt.from, tt.to)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids(got))
+		})
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	var (
+		t0    = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		specs = []*Spec{
+			{ID: "1", Version: "1.0.0", Revision: "1", CreatedAt: t0},
+			{ID: "2", Version: "1.1.0", Revision: "1", CreatedAt: t0.Add(24 * time.Hour)},
+			{ID: "3", Version: "1.1.0", Revision: "2", CreatedAt: t0.Add(48 * time.Hour)},
+		}
+	)
+
+	m := NewChangelog(&Service{ID: "svc", NameID: "carts", Title: "Carts"}, "", "")
+	m.AddInitial(specs[0])
+	m.Add(specs[1], &diff.JSONResult{
+		Added:      []*diff.EndpointSummary{{Method: "GET", Path: "/carts", Description: "List\n  carts."}},
+		Deprecated: []*diff.EndpointSummary{{Method: "GET", Path: "/cart"}},
+	})
+	m.Add(specs[2], &diff.JSONResult{
+		Breaking: true,
+		Deleted:  []*diff.EndpointSummary{{Method: "DELETE", Path: "/cart"}},
+		Modified: []*diff.ModifiedSummary{{Method: "POST", Path: "/carts", Summary: "Create a cart", Breaking: true}},
+	})
+
+	require.Len(t, m.Versions, 2)
+	v := m.Versions[0]
+	assert.Equal(t, "1.1.0", v.Version)
+	assert.Equal(t, "2", v.Revision)
+	assert.Equal(t, "3", v.SpecID)
+	assert.True(t, v.Breaking)
+	assert.Len(t, v.Added, 1)
+	assert.Equal(t, "1", v.Added[0].Revision)
+	assert.Len(t, v.Removed, 1)
+	assert.Equal(t, "2", v.Removed[0].Revision)
+	assert.True(t, m.Versions[1].Initial)
+
+	assert.Equal(t, `# Changelog
+
+All notable changes to the Carts API.
+
+## [1.1.0] - 2022-01-03
+
+**This version contains breaking changes.**
+
+### Added
+
+- `+"`GET`"+` /carts: List carts.
+
+### Deprecated
+
+- `+"`GET`"+` /cart
+
+### Removed
+
+- **BREAKING** `+"`DELETE`"+` /cart
+
+### Changed
+
+- **BREAKING** `+"`POST`"+` /carts: Create a cart
+
+## [1.0.0] - 2022-01-01
+
+Initial version.
+`, m.Markdown())
+
+	feed := m.Feed("http://localhost/v1/apiregistry/services/svc/changelog/feed")
+	assert.Equal(t, "urn:uuid:svc", feed.ID)
+	assert.Equal(t, "2022-01-03T00:00:00Z", feed.Updated)
+	require.Len(t, feed.Entries, 2)
+	assert.Equal(t, "urn:uuid:2", feed.Entries[0].ID)
+	assert.Equal(t, "Carts 1.1.0 (breaking)", feed.Entries[0].Title)
+	data, err := xml.Marshal(feed)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<feed xmlns="http://www.w3.org/2005/Atom"><id>urn:uuid:svc</id>`)
+}
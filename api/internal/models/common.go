@@ -27,9 +27,18 @@ import (
 	"github.com/urfave/cli/v2/altsrc"
 )
 
+const (
+	// SpecDuplicatePolicyReturn returns the existing spec when uploading a spec doc identical to an existing one.
+	SpecDuplicatePolicyReturn = "return"
+	// SpecDuplicatePolicyConflict rejects uploading a spec doc identical to an existing one with a 409 Conflict referencing the existing spec.
+	SpecDuplicatePolicyConflict = "conflict"
+)
+
 var (
 	// 0=compress all,-1=no compression
 	startDataCompressionAtBytes int = -1
+
+	specDuplicatePolicy = SpecDuplicatePolicyReturn
 )
 
 func Flags() []cli.Flag {
@@ -41,9 +50,21 @@ func Flags() []cli.Flag {
 			Destination: &startDataCompressionAtBytes,
 			EnvVars:     []string{"START_DATA_COMPRESSION_AT_BYTES"},
 		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        "spec-duplicate-policy",
+			Usage:       "Policy for uploading a spec doc identical to an existing spec of the service; return=return the existing spec,conflict=respond with 409 referencing the existing spec",
+			Value:       specDuplicatePolicy,
+			Destination: &specDuplicatePolicy,
+			EnvVars:     []string{"SPEC_DUPLICATE_POLICY"},
+		}),
 	}
 }
 
+// SpecDuplicatePolicy returns the configured policy for uploading duplicate spec docs, i.e. SpecDuplicatePolicyReturn or SpecDuplicatePolicyConflict.
+func SpecDuplicatePolicy() string {
+	return specDuplicatePolicy
+}
+
 // ModelObject defines the interface of a model
 type ModelObject interface {
 	GetID() string
@@ -0,0 +1,415 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"github.com/getkin/kin-openapi/openapi3"
+	"gorm.io/gorm"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ConsumerTableName = "consumers"
+
+	ConsumerSourceManual  = "manual"  // Registered by the consumer.
+	ConsumerSourceSpec    = "spec"    // Detected from the URLs of the provider's servers within the consumer's spec.
+	ConsumerSourceTraffic = "traffic" // Detected from the API traffic of the provider (see apiclarity).
+)
+
+// ConsumerSources are the sources of Consumer.Operations.
+var ConsumerSources = []string{ConsumerSourceManual, ConsumerSourceSpec, ConsumerSourceTraffic}
+
+var consumerURL = regexp.MustCompile(`https?://[^\s"'<>\x60\\]+`)
+
+// Consumer represents a consumer (another service or an external client) of the operations of a service.
+type Consumer struct {
+	ID                string             `json:"id,omitempty" gorm:"column:id;primaryKey"`
+	ServiceID         string             `json:"service_id" gorm:"column:service_id;uniqueIndex:idx_consumer_service_name" validate:"required"` // The consumed service.
+	Name              string             `json:"name" gorm:"column:name;uniqueIndex:idx_consumer_service_name" validate:"required"`
+	ConsumerServiceID string             `json:"consumer_service_id,omitempty" gorm:"column:consumer_service_id;index"` // The consumer, if a registered service.
+	Source            string             `json:"source" gorm:"column:source;index" validate:"omitempty,oneof=manual spec traffic"`
+	Contact           *Contact           `json:"contact" gorm:"column:contact"`         // The consumer owner, notified of breaking changes.
+	WebhookURL        string             `json:"webhook_url" gorm:"column:webhook_url"` // Receives ConsumerNotification(s) of breaking changes, if any.
+	Operations        ConsumerOperations `json:"operations" gorm:"column:operations" validate:"dive"`
+	CreatedAt         time.Time          `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt         time.Time          `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// ConsumerOperation represents an operation a Consumer depends on.
+type ConsumerOperation struct {
+	Method string `json:"method" validate:"required"`
+	Path   string `json:"path" validate:"required"` // The path of the operation, whatever its path parameters are named.
+}
+
+// ConsumerOperations represents the operations a Consumer depends on.
+type ConsumerOperations []*ConsumerOperation
+
+// ConsumerTrafficEvent represents an API call to a service from a consumer.
+type ConsumerTrafficEvent struct {
+	Source string // e.g. the source IP.
+	Method string
+	Path   string
+}
+
+// ConsumerNotification represents the notification of a Consumer of the breaking changes affecting it.
+type ConsumerNotification struct {
+	ServiceID     string               `json:"service_id"`
+	ServiceNameID string               `json:"service_name_id"`
+	ConsumerID    string               `json:"consumer_id"`
+	Consumer      string               `json:"consumer"`
+	SpecDiffID    string               `json:"spec_diff_id"`
+	OldSpecID     string               `json:"old_spec_id"`
+	NewSpecID     string               `json:"new_spec_id"`
+	Changes       []*diff.ImpactChange `json:"changes"`
+}
+
+// TableName implements gorm Tabler interface
+func (m *Consumer) TableName() string {
+	return ConsumerTableName
+}
+
+func (m *Consumer) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = shared.TimeUUID()
+	}
+	return
+}
+
+// GetID returns the ID of consumer object
+func (m *Consumer) GetID() string {
+	return fmt.Sprintf("%v", m.ID)
+}
+
+// GetTags returns all the tags
+func (m *Consumer) GetTags() []string {
+	tags := make([]string, 0, 10)
+	tags = append(tags, m.ServiceID)
+	tags = append(tags, m.Name)
+	return tags
+}
+
+// String returns the text representation of consumer object
+func (m *Consumer) String() string {
+	return fmt.Sprintf("%v", *m)
+}
+
+// GetIndex returns an index for specific field
+func (m *Consumer) GetIndex(field string) string {
+	return m.GetIndexes()[field]
+}
+
+// GetIndexes returns all the field indexes
+func (m *Consumer) GetIndexes() map[string]string {
+	return map[string]string{
+		"service_id":          "idx_consumer_service_name",
+		"consumer_service_id": "idx_consumer_service_id",
+		"source":              "idx_source",
+	}
+}
+
+// GetIndexValue return index value for specified field
+func (m *Consumer) GetIndexValue(field string) string {
+	return m.GetIndexValues()[field]
+}
+
+// GetIndexValues return all field index values
+func (m *Consumer) GetIndexValues() map[string]string {
+	return map[string]string{
+		"service_id":          m.ServiceID,
+		"consumer_service_id": m.ConsumerServiceID,
+		"source":              m.Source,
+	}
+}
+
+// Sortable checks if field is sortable.
+func (m *Consumer) Sortable(field string) bool {
+	_, found := m.SortableFields()[field]
+	return found
+}
+
+// SortableFields returns all sortable fields
+func (m *Consumer) SortableFields() map[string]struct{} {
+	return map[string]struct{}{
+		"name":       {},
+		"created_at": {},
+		"updated_at": {},
+	}
+}
+
+// Owner returns the email, or else the name, of the consumer's Contact.
+func (m *Consumer) Owner() string {
+	if m.Contact == nil {
+		return ""
+	}
+	if m.Contact.Email != "" {
+		return m.Contact.Email
+	}
+	return m.Contact.Name
+}
+
+// DependsOn checks if the consumer depends on the operation of method & path.
+func (m *Consumer) DependsOn(method, path string) bool {
+	for _, op := range m.Operations {
+		if op.Matches(method, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidConsumerSource checks if source is one of ConsumerSources.
+func ValidConsumerSource(source string) bool {
+	for _, s := range ConsumerSources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches checks if m is the operation of method & path, whatever its path parameters are named.
+func (m *ConsumerOperation) Matches(method, path string) bool {
+	return strings.EqualFold(m.Method, method) &&
+		specEndpointPathParam.ReplaceAllString(m.Path, "{}") == specEndpointPathParam.ReplaceAllString(path, "{}")
+}
+
+// Merge returns the operations of m & ops, without duplicates & sorted.
+func (m ConsumerOperations) Merge(ops ConsumerOperations) ConsumerOperations {
+	merged := ConsumerOperations{}
+	for _, op := range append(append(ConsumerOperations{}, m...), ops...) {
+		op = &ConsumerOperation{Method: strings.ToUpper(op.Method), Path: op.Path}
+		found := false
+		for _, existing := range merged {
+			if existing.Matches(op.Method, op.Path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, op)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Path != merged[j].Path {
+			return merged[i].Path < merged[j].Path
+		}
+		return merged[i].Method < merged[j].Method
+	})
+	return merged
+}
+
+// Scan implements sql.Scanner interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m *ConsumerOperations) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if s, isString := value.(string); isString {
+			bytes = []byte(s)
+		} else {
+			return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+		}
+	}
+
+	return json.Unmarshal(bytes, &m)
+}
+
+// Value implements driver.Valuer interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m ConsumerOperations) Value() (driver.Value, error) {
+	if m == nil {
+		return json.Marshal(ConsumerOperations{})
+	}
+	return json.Marshal(m)
+}
+
+// NewConsumerImpact returns the consumers, among consumers, affected by the breaking changes of result:
+// its deleted endpoints & breaking modified endpoints.
+func NewConsumerImpact(result *diff.JSONResult, consumers []*Consumer) *diff.Impact {
+	impact := &diff.Impact{Changes: []*diff.ImpactChange{}}
+	affected := map[string]bool{}
+	addChange := func(action diff.Action, method, path string) {
+		change := &diff.ImpactChange{Action: action, Method: method, Path: path}
+		for _, consumer := range consumers {
+			if consumer.DependsOn(method, path) {
+				change.Consumers = append(change.Consumers, &diff.ImpactConsumer{
+					ID:        consumer.ID,
+					Name:      consumer.Name,
+					ServiceID: consumer.ConsumerServiceID,
+					Owner:     consumer.Owner(),
+				})
+				affected[consumer.ID] = true
+			}
+		}
+		if len(change.Consumers) > 0 {
+			impact.Changes = append(impact.Changes, change)
+		}
+	}
+
+	for _, deleted := range result.Deleted {
+		addChange(diff.ActionDeleted, deleted.Method, deleted.Path)
+	}
+	for _, modified := range result.Modified {
+		if modified.Breaking {
+			addChange(diff.ActionModified, modified.Method, modified.Path)
+		}
+	}
+	impact.Consumers = len(affected)
+	return impact
+}
+
+// ConsumerImpactChanges returns the changes of impact affecting the consumer with consumerID.
+func ConsumerImpactChanges(impact *diff.Impact, consumerID string) []*diff.ImpactChange {
+	var changes []*diff.ImpactChange
+	for _, change := range impact.Changes {
+		for _, consumer := range change.Consumers {
+			if consumer.ID == consumerID {
+				changes = append(changes, change)
+				break
+			}
+		}
+	}
+	return changes
+}
+
+// NewSpecConsumerOperations detects the operations of doc, the provider spec, which consumerDoc, the consumer spec,
+// uses: those of the URLs within consumerDoc (e.g. its servers, links or extensions) prefixed by the URL of a server of doc.
+// As a URL has no method, all the operations of its path are used.
+func NewSpecConsumerOperations(doc *openapi3.T, consumerDoc string) ConsumerOperations {
+	servers := specServerURLs(doc)
+	ops := ConsumerOperations{}
+	for _, rawURL := range consumerURL.FindAllString(consumerDoc, -1) {
+		u, err := url.Parse(strings.TrimRight(rawURL, ".,;:)]}"))
+		if err != nil {
+			continue
+		}
+		for _, server := range servers {
+			if !strings.EqualFold(u.Host, server.Host) {
+				continue
+			}
+			basePath := strings.TrimSuffix(server.Path, "/")
+			if !strings.HasPrefix(u.Path, basePath+"/") {
+				continue
+			}
+			if template, pathItem := matchSpecPath(doc, strings.TrimPrefix(u.Path, basePath)); pathItem != nil {
+				for method := range pathItem.Operations() {
+					ops = append(ops, &ConsumerOperation{Method: method, Path: template})
+				}
+			}
+		}
+	}
+	return ops.Merge(nil)
+}
+
+// NewTrafficConsumerOperations detects the operations of doc, the provider spec, called by each source of events.
+func NewTrafficConsumerOperations(doc *openapi3.T, events []*ConsumerTrafficEvent) map[string]ConsumerOperations {
+	var basePaths []string
+	for _, server := range specServerURLs(doc) {
+		if basePath := strings.TrimSuffix(server.Path, "/"); basePath != "" {
+			basePaths = append(basePaths, basePath)
+		}
+	}
+
+	opsBySource := map[string]ConsumerOperations{}
+	for _, event := range events {
+		if event.Source == "" {
+			continue
+		}
+		path := strings.SplitN(event.Path, "?", 2)[0]
+		template, pathItem := matchSpecPath(doc, path)
+		for _, basePath := range basePaths {
+			if pathItem == nil && strings.HasPrefix(path, basePath+"/") {
+				template, pathItem = matchSpecPath(doc, strings.TrimPrefix(path, basePath))
+			}
+		}
+		if pathItem == nil || pathItem.GetOperation(strings.ToUpper(event.Method)) == nil {
+			continue
+		}
+		opsBySource[event.Source] = append(opsBySource[event.Source], &ConsumerOperation{Method: strings.ToUpper(event.Method), Path: template})
+	}
+	for source, ops := range opsBySource {
+		opsBySource[source] = ops.Merge(nil)
+	}
+	return opsBySource
+}
+
+// specServerURLs returns the URLs of the servers of doc, of their variables' default values.
+func specServerURLs(doc *openapi3.T) []*url.URL {
+	var urls []*url.URL
+	for _, server := range doc.Servers {
+		if server == nil {
+			continue
+		}
+		rawURL := server.URL
+		for name, variable := range server.Variables {
+			if variable != nil {
+				rawURL = strings.ReplaceAll(rawURL, "{"+name+"}", variable.Default)
+			}
+		}
+		if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// matchSpecPath returns the path template (& item) of doc matching path, e.g. /orders/{id} for /orders/42,
+// preferring the template of the most literal segments, e.g. /orders/search over /orders/{id} for /orders/search.
+func matchSpecPath(doc *openapi3.T, path string) (string, *openapi3.PathItem) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var (
+		bestTemplate string
+		bestItem     *openapi3.PathItem
+		bestLiterals = -1
+	)
+	for template, pathItem := range doc.Paths {
+		templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+		literals := 0
+		for i, segment := range templateSegments {
+			if specEndpointPathParam.MatchString(segment) {
+				if segments[i] == "" {
+					literals = -1
+					break
+				}
+				continue
+			}
+			if segment != segments[i] {
+				literals = -1
+				break
+			}
+			literals++
+		}
+		if literals > bestLiterals || literals == bestLiterals && literals >= 0 && template < bestTemplate {
+			bestTemplate, bestItem, bestLiterals = template, pathItem, literals
+		}
+	}
+	if bestLiterals < 0 {
+		return "", nil
+	}
+	return bestTemplate, bestItem
+}
@@ -0,0 +1,143 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const consumerTestDoc = `openapi: 3.0.0
+info: {title: Orders, version: 1.0.0}
+servers:
+  - url: https://{region}.orders.example.com/v1
+    variables:
+      region: {default: eu}
+paths:
+  /orders:
+    get: {responses: {'200': {description: OK}}}
+    post: {responses: {'201': {description: Created}}}
+  /orders/{orderId}:
+    get: {responses: {'200': {description: OK}}}
+    delete: {responses: {'204': {description: No Content}}}
+  /orders/search:
+    get: {responses: {'200': {description: OK}}}
+`
+
+func loadConsumerTestDoc(t *testing.T) *openapi3.T {
+	doc := consumerTestDoc
+	spec := &Spec{ID: "spec", ServiceID: "orders", Doc: SpecDoc(&doc)}
+	oas, err := spec.LoadDocAsOAS(context.Background(), false, false, false)
+	require.NoError(t, err)
+	return oas
+}
+
+func TestConsumerOperations(t *testing.T) {
+	ops := ConsumerOperations{{Method: "get", Path: "/orders/{id}"}}.Merge(ConsumerOperations{
+		{Method: "GET", Path: "/orders/{orderId}"},
+		{Method: "DELETE", Path: "/orders/{orderId}"},
+	})
+	assert.Equal(t, ConsumerOperations{{Method: "GET", Path: "/orders/{id}"}, {Method: "DELETE", Path: "/orders/{orderId}"}}, ops)
+
+	consumer := &Consumer{Operations: ops}
+	assert.True(t, consumer.DependsOn("GET", "/orders/{order_id}"))
+	assert.False(t, consumer.DependsOn("POST", "/orders/{id}"))
+	assert.False(t, consumer.DependsOn("GET", "/orders"))
+
+	value, err := ops.Value()
+	require.NoError(t, err)
+	var scanned ConsumerOperations
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, ops, scanned)
+}
+
+func TestNewConsumerImpact(t *testing.T) {
+	consumers := []*Consumer{
+		{ID: "1", Name: "carts", ConsumerServiceID: "carts-svc", Contact: &Contact{openapi3.Contact{Name: "Carts", Email: "carts@example.com"}},
+			Operations: ConsumerOperations{{Method: "GET", Path: "/orders/{id}"}, {Method: "DELETE", Path: "/orders/{id}"}}},
+		{ID: "2", Name: "10.0.0.1", Operations: ConsumerOperations{{Method: "GET", Path: "/orders"}}},
+		{ID: "3", Name: "billing", Operations: ConsumerOperations{{Method: "POST", Path: "/orders"}}},
+	}
+	result := &diff.JSONResult{
+		Breaking: true,
+		Deleted:  []*diff.EndpointSummary{{Method: "DELETE", Path: "/orders/{orderId}"}},
+		Modified: []*diff.ModifiedSummary{
+			{Method: "GET", Path: "/orders/{orderId}", Breaking: true},
+			{Method: "GET", Path: "/orders", Breaking: true},
+			{Method: "POST", Path: "/orders"},
+		},
+	}
+
+	impact := NewConsumerImpact(result, consumers)
+	assert.Equal(t, 2, impact.Consumers)
+	require.Len(t, impact.Changes, 3)
+	assert.Equal(t, diff.ActionDeleted, impact.Changes[0].Action)
+	require.Len(t, impact.Changes[0].Consumers, 1)
+	assert.Equal(t, &diff.ImpactConsumer{ID: "1", Name: "carts", ServiceID: "carts-svc", Owner: "carts@example.com"}, impact.Changes[0].Consumers[0])
+	assert.Equal(t, "10.0.0.1", impact.Changes[2].Consumers[0].Name)
+
+	assert.Len(t, ConsumerImpactChanges(impact, "1"), 2)
+	assert.Empty(t, ConsumerImpactChanges(impact, "3"))
+
+	assert.Equal(t, "## Impact\n\n2 consumer(s) affected by the breaking changes.\n\n"+
+		"### Deleted `DELETE` /orders/{orderId}\n\n- carts (carts@example.com)\n\n"+
+		"### Modified `GET` /orders/{orderId}\n\n- carts (carts@example.com)\n\n"+
+		"### Modified `GET` /orders\n\n- 10.0.0.1\n", impact.Markdown())
+}
+
+func TestNewSpecConsumerOperations(t *testing.T) {
+	doc := loadConsumerTestDoc(t)
+
+	ops := NewSpecConsumerOperations(doc, `openapi: 3.0.0
+info:
+  title: Carts
+  version: 1.0.0
+  description: Carts read orders from https://eu.orders.example.com/v1/orders/42.
+servers:
+  - url: https://carts.example.com
+x-dependencies:
+  - https://eu.orders.example.com/v1/orders/search?q=x
+  - https://eu.orders.example.com/v2/orders
+  - https://us.orders.example.com/v1/orders
+paths: {}
+`)
+	assert.Equal(t, ConsumerOperations{
+		{Method: "GET", Path: "/orders/search"},
+		{Method: "DELETE", Path: "/orders/{orderId}"},
+		{Method: "GET", Path: "/orders/{orderId}"},
+	}, ops)
+}
+
+func TestNewTrafficConsumerOperations(t *testing.T) {
+	doc := loadConsumerTestDoc(t)
+
+	opsBySource := NewTrafficConsumerOperations(doc, []*ConsumerTrafficEvent{
+		{Source: "10.0.0.1", Method: "GET", Path: "/v1/orders/42"},
+		{Source: "10.0.0.1", Method: "GET", Path: "/v1/orders/43?expand=items"},
+		{Source: "10.0.0.1", Method: "POST", Path: "/orders"},
+		{Source: "10.0.0.2", Method: "PUT", Path: "/orders/42"},
+		{Source: "10.0.0.2", Method: "GET", Path: "/unknown"},
+	})
+	assert.Equal(t, map[string]ConsumerOperations{
+		"10.0.0.1": {{Method: "POST", Path: "/orders"}, {Method: "GET", Path: "/orders/{orderId}"}},
+	}, opsBySource)
+}
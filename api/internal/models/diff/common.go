@@ -49,6 +49,9 @@ type JSONResult struct {
 
 	Breaking bool   `json:"breaking"`
 	Message  string `json:"message"`
+
+	// Impact lists the consumers affected by the breaking changes, if any.
+	Impact *Impact `json:"impact,omitempty"`
 }
 
 type EndpointSummary struct {
@@ -75,6 +78,11 @@ type ModifiedSummary struct {
 	RequestBodySummary *RequestBodySummary `json:"requestBody"`
 	ResponsesSummary   *ResponsesSummary   `json:"responses"`
 	SecuritySummary    *SecuritySummary    `json:"security"`
+
+	// MessagesSummary summarizes the changed messages of an AsyncAPI operation, whose Path is its channel & Method its action,
+	// the changed definitions of a protobuf RPC, message or enum, whose Path is its gRPC path or full name & Method its kind,
+	// or the changed fields of a GraphQL root field or type, whose Path is its name & Method its operation or SDL keyword.
+	MessagesSummary *MessagesSummary `json:"messages,omitempty"`
 }
 
 type OperationSummary struct{ openapi3.Operation }
@@ -164,6 +172,22 @@ type (
 	}
 )
 
+type (
+	MessagesSummary struct {
+		Breaking bool                    `json:"breaking"`
+		Message  string                  `json:"message"`
+		Details  []*MessageSummaryDetail `json:"details"`
+	}
+	MessageSummaryDetail struct {
+		Name        string               `json:"name"`
+		Description string               `json:"description"`
+		Action      Action               `json:"action"`
+		Breaking    bool                 `json:"breaking"`
+		Message     string               `json:"message"`
+		Properties  []*PropertiesSummary `json:"properties"`
+	}
+)
+
 type PropertiesSummary struct {
 	Name        string `json:"name"`
 	Type        string `json:"type"`
@@ -0,0 +1,70 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Impact represents the consumers affected by the breaking changes of a JSONResult.
+type Impact struct {
+	Consumers int             `json:"consumers"` // The number of distinct consumers affected.
+	Changes   []*ImpactChange `json:"changes"`
+}
+
+// ImpactChange represents a breaking change of an endpoint & the consumers depending on it.
+type ImpactChange struct {
+	Action    Action            `json:"action"`
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	Consumers []*ImpactConsumer `json:"consumers"`
+}
+
+// ImpactConsumer represents a consumer affected by an ImpactChange.
+type ImpactConsumer struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ServiceID string `json:"service_id,omitempty"` // The consumer service, if a registered service.
+	Owner     string `json:"owner,omitempty"`      // The consumer owner's email, or name.
+}
+
+// Markdown returns the markdown impact section.
+func (m *Impact) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Impact\n\n")
+	if len(m.Changes) == 0 {
+		b.WriteString("No registered consumer is affected by the breaking changes.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d consumer(s) affected by the breaking changes.\n", m.Consumers)
+	for _, change := range m.Changes {
+		action := string(change.Action)
+		if action != "" {
+			action = strings.ToUpper(action[:1]) + action[1:]
+		}
+		fmt.Fprintf(&b, "\n### %s `%s` %s\n\n", action, change.Method, change.Path)
+		for _, consumer := range change.Consumers {
+			if consumer.Owner != "" {
+				fmt.Fprintf(&b, "- %s (%s)\n", consumer.Name, consumer.Owner)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", consumer.Name)
+			}
+		}
+	}
+	return b.String()
+}
@@ -123,6 +123,10 @@ func (m markdownSummaryMessageBuilder) BuildModifiedSummaryMessage(s *ModifiedSu
 		sb.WriteString(s.SecuritySummary.Message)
 	}
 
+	if s.MessagesSummary != nil {
+		sb.WriteString(s.MessagesSummary.Message)
+	}
+
 	sb.WriteString("\n")
 
 	return sb.String()
@@ -246,6 +250,33 @@ func (m markdownSummaryMessageBuilder) BuildSecuritySummaryDetailMessage(d *Secu
 	)
 }
 
+func (m markdownSummaryMessageBuilder) BuildMessagesSummaryMessage(s *MessagesSummary) string {
+	var sb strings.Builder
+	sb.WriteString(markdownHeading6("Messages:"))
+	sb.WriteString("\n")
+	for _, detail := range s.Details {
+		sb.WriteString(detail.Message)
+	}
+	return sb.String()
+}
+
+func (m markdownSummaryMessageBuilder) BuildMessageSummaryDetailMessage(d *MessageSummaryDetail) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s message: %s\n%s",
+		cases.Title(language.Und, cases.NoLower).String(string(d.Action)),
+		markdownCode(d.Name),
+		markdownBlockquote("", d.Description),
+	))
+	if d.Action == ActionModified {
+		sb.WriteString("\n")
+		for _, p := range d.Properties {
+			sb.WriteString(p.Message)
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
 func (m markdownSummaryMessageBuilder) BuildPropertiesSummaryMessage(s *PropertiesSummary, indentLevel int) string {
 	switch s.Action {
 	case ActionAdded:
@@ -34,6 +34,9 @@ type SummaryMessageBuilder interface {
 	securitySummaryMessageBuilder
 	securitySummaryDetailMessageBuilder
 
+	messagesSummaryMessageBuilder
+	messageSummaryDetailMessageBuilder
+
 	propertiesSummaryMessageBuilder
 }
 
@@ -84,6 +87,15 @@ type (
 	}
 )
 
+type (
+	messagesSummaryMessageBuilder interface {
+		BuildMessagesSummaryMessage(s *MessagesSummary) string
+	}
+	messageSummaryDetailMessageBuilder interface {
+		BuildMessageSummaryDetailMessage(d *MessageSummaryDetail) string
+	}
+)
+
 type propertiesSummaryMessageBuilder interface {
 	BuildPropertiesSummaryMessage(s *PropertiesSummary, indentLevel int) string
 }
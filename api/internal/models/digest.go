@@ -0,0 +1,447 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/gorm"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DigestSubscriptionTableName = "digest_subscriptions"
+
+	DigestChannelEmail = "email"
+	DigestChannelChat  = "chat"
+
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+
+	DigestFormatJSON     = "json"
+	DigestFormatMarkdown = "markdown"
+
+	// DefaultDigestStaleDays is the default number of days without spec updates after which a service is reported as stale.
+	DefaultDigestStaleDays = 30
+)
+
+// DigestSubscription represents the subscription of a recipient to periodic digests of the services of an organization.
+type DigestSubscription struct {
+	ID             string     `json:"id,omitempty" gorm:"column:id;primaryKey"`
+	OrganizationID string     `json:"organization_id" gorm:"column:organization_id;index" validate:"required"` // The organization Name ID, as Service.OrganizationID.
+	Channel        string     `json:"channel" gorm:"column:channel;index" validate:"required,oneof=email chat"`
+	Recipient      string     `json:"recipient" gorm:"column:recipient" validate:"required"` // An email address, or a chat webhook URL.
+	Frequency      string     `json:"frequency" gorm:"column:frequency" validate:"required,oneof=daily weekly"`
+	StaleDays      int        `json:"stale_days" gorm:"column:stale_days" validate:"min=1"`
+	Active         bool       `json:"active" gorm:"column:active;index"`
+	LastSentAt     *time.Time `json:"last_sent_at,omitempty" gorm:"column:last_sent_at"`
+	ClaimedUntil   *time.Time `json:"-" gorm:"column:claimed_until"` // Until when a replica claimed its due digest.
+	CreatedAt      time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// Digest represents a summary of the changes to the services of an organization within [From, To].
+type Digest struct {
+	OrganizationID  string                  `json:"organization_id"`
+	Frequency       string                  `json:"frequency"`
+	From            time.Time               `json:"from"`
+	To              time.Time               `json:"to"`
+	StaleDays       int                     `json:"stale_days"`
+	ScoreChanges    []*DigestScoreChange    `json:"score_changes"`
+	Findings        []*DigestFinding        `json:"findings"`
+	BreakingChanges []*DigestBreakingChange `json:"breaking_changes"`
+	StaleServices   []*DigestService        `json:"stale_services"`
+}
+
+// DigestService represents a service of a Digest.
+type DigestService struct {
+	ID        string     `json:"id"`
+	NameID    string     `json:"name_id"`
+	Title     string     `json:"title"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"` // Of the service summary, i.e. of its latest spec.
+}
+
+// DigestScoreChange represents the change of the score of a service within a Digest.
+type DigestScoreChange struct {
+	Service       *DigestService `json:"service"`
+	PreviousScore int            `json:"previous_score"`
+	Score         int            `json:"score"`
+	Change        int            `json:"change"`
+}
+
+// DigestFinding represents the new error-severity findings of a rule in the latest spec of a service within a Digest.
+type DigestFinding struct {
+	Service  *DigestService        `json:"service"`
+	SpecID   string                `json:"spec_id"`
+	Analyzer analyzer.SpecAnalyzer `json:"analyzer"`
+	Rule     rule.NameID           `json:"rule"`
+	Message  string                `json:"message"`
+	Count    int                   `json:"count"` // The number of new occurrences.
+}
+
+// DigestBreakingChange represents a spec with breaking changes released by a service within a Digest.
+type DigestBreakingChange struct {
+	Service    *DigestService `json:"service"`
+	SpecDiffID string         `json:"spec_diff_id"`
+	OldSpecID  string         `json:"old_spec_id"`
+	NewSpecID  string         `json:"new_spec_id"`
+	Version    string         `json:"version"`
+	Changes    []string       `json:"changes"` // The breaking endpoint changes, e.g. "Deleted `GET` /pets".
+}
+
+// TableName implements gorm Tabler interface
+func (m *DigestSubscription) TableName() string {
+	return DigestSubscriptionTableName
+}
+
+func (m *DigestSubscription) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = shared.TimeUUID()
+	}
+	return
+}
+
+// GetID returns the ID of digestSubscription object
+func (m *DigestSubscription) GetID() string {
+	return fmt.Sprintf("%v", m.ID)
+}
+
+// String returns the text representation of digestSubscription object
+func (m *DigestSubscription) String() string {
+	return fmt.Sprintf("%v", *m)
+}
+
+// GetIndex returns an index for specific field
+func (m *DigestSubscription) GetIndex(field string) string {
+	return m.GetIndexes()[field]
+}
+
+// GetIndexes returns all the field indexes
+func (m *DigestSubscription) GetIndexes() map[string]string {
+	return map[string]string{
+		"organization_id": "idx_organization_id",
+		"channel":         "idx_channel",
+		"active":          "idx_active",
+	}
+}
+
+// GetIndexValue return index value for specified field
+func (m *DigestSubscription) GetIndexValue(field string) string {
+	return m.GetIndexValues()[field]
+}
+
+// GetIndexValues return all field index values
+func (m *DigestSubscription) GetIndexValues() map[string]string {
+	return map[string]string{
+		"organization_id": m.OrganizationID,
+		"channel":         m.Channel,
+		"active":          fmt.Sprintf("%v", m.Active),
+	}
+}
+
+// Sortable checks if field is sortable.
+func (m *DigestSubscription) Sortable(field string) bool {
+	_, found := m.SortableFields()[field]
+	return found
+}
+
+// SortableFields returns all sortable fields
+func (m *DigestSubscription) SortableFields() map[string]struct{} {
+	return map[string]struct{}{
+		"created_at":   {},
+		"updated_at":   {},
+		"last_sent_at": {},
+	}
+}
+
+// ValidateRecipient checks that the recipient of m is an email address for DigestChannelEmail, or for DigestChannelChat
+// a URL of a host allowed for webhooks (see CheckWebhookURL).
+func (m *DigestSubscription) ValidateRecipient(ctx context.Context) error {
+	switch m.Channel {
+	case DigestChannelEmail:
+		if _, err := mail.ParseAddress(m.Recipient); err != nil {
+			return fmt.Errorf("invalid email recipient %s: %v", m.Recipient, err)
+		}
+	case DigestChannelChat:
+		if err := CheckWebhookURL(ctx, m.Recipient); err != nil {
+			return fmt.Errorf("invalid chat webhook recipient %s: %v", m.Recipient, err)
+		}
+	}
+	return nil
+}
+
+// Period returns the duration between digests of m.
+func (m *DigestSubscription) Period() time.Duration {
+	if m.Frequency == DigestFrequencyDaily {
+		return 24 * time.Hour
+	}
+	return 7 * 24 * time.Hour
+}
+
+// Due checks if a digest of m is due at now, i.e. m is active & hasn't been sent for a Period (since its creation, if never).
+func (m *DigestSubscription) Due(now time.Time) bool {
+	if !m.Active {
+		return false
+	}
+	last := m.CreatedAt
+	if m.LastSentAt != nil {
+		last = *m.LastSentAt
+	}
+	return !now.Before(last.Add(m.Period()))
+}
+
+// Window returns the time range of the digest of m at now, i.e. since it was last sent, or for a Period if never.
+func (m *DigestSubscription) Window(now time.Time) (time.Time, time.Time) {
+	if m.LastSentAt != nil && m.LastSentAt.Before(now) {
+		return *m.LastSentAt, now
+	}
+	return now.Add(-m.Period()), now
+}
+
+// NewDigest returns an empty Digest of the services of the organization of subscription at now.
+func NewDigest(subscription *DigestSubscription, now time.Time) *Digest {
+	from, to := subscription.Window(now)
+	staleDays := subscription.StaleDays
+	if staleDays <= 0 {
+		staleDays = DefaultDigestStaleDays
+	}
+	return &Digest{
+		OrganizationID:  subscription.OrganizationID,
+		Frequency:       subscription.Frequency,
+		From:            from,
+		To:              to,
+		StaleDays:       staleDays,
+		ScoreChanges:    []*DigestScoreChange{},
+		Findings:        []*DigestFinding{},
+		BreakingChanges: []*DigestBreakingChange{},
+		StaleServices:   []*DigestService{},
+	}
+}
+
+func newDigestService(service *Service) *DigestService {
+	m := &DigestService{ID: service.ID, NameID: service.NameID, Title: service.Title}
+	if service.Summary != nil {
+		updatedAt := service.Summary.UpdatedAt
+		m.UpdatedAt = &updatedAt
+	}
+	return m
+}
+
+// AddService adds the score change of service since previous (its SpecTrend as of m.From, if any) to m,
+// & the service to the stale services of m if its latest spec is older than m.StaleDays.
+func (m *Digest) AddService(service *Service, previous *SpecTrend) {
+	if service.Summary == nil {
+		return
+	}
+	if previous != nil && previous.Score != nil && service.Summary.Score != nil && *previous.Score != *service.Summary.Score {
+		m.ScoreChanges = append(m.ScoreChanges, &DigestScoreChange{
+			Service:       newDigestService(service),
+			PreviousScore: *previous.Score,
+			Score:         *service.Summary.Score,
+			Change:        *service.Summary.Score - *previous.Score,
+		})
+	}
+	if m.To.Sub(service.Summary.UpdatedAt) >= time.Duration(m.StaleDays)*24*time.Hour {
+		m.StaleServices = append(m.StaleServices, newDigestService(service))
+	}
+}
+
+// AddFindings adds the error-severity findings of the latest analyses of the spec with specID of service,
+// which outnumber those of its previous analyses (of the spec as of m.From, if any), to m.
+func (m *Digest) AddFindings(service *Service, specID string, latest, previous []*SpecAnalysis) {
+	previousCounts := digestErrorFindings(previous)
+	for key, current := range digestErrorFindings(latest) {
+		count := current.count
+		if p, ok := previousCounts[key]; ok {
+			count -= p.count
+		}
+		if count <= 0 {
+			continue
+		}
+		m.Findings = append(m.Findings, &DigestFinding{
+			Service:  newDigestService(service),
+			SpecID:   specID,
+			Analyzer: key.analyzer,
+			Rule:     key.rule,
+			Message:  current.message,
+			Count:    count,
+		})
+	}
+}
+
+type digestFindingKey struct {
+	analyzer analyzer.SpecAnalyzer
+	rule     rule.NameID
+}
+
+type digestFindingCount struct {
+	message string
+	count   int
+}
+
+// digestErrorFindings counts the error-severity findings by analyzer & rule of the latest of analyses of each analyzer.
+func digestErrorFindings(analyses []*SpecAnalysis) map[digestFindingKey]*digestFindingCount {
+	latest := map[analyzer.SpecAnalyzer]*SpecAnalysis{}
+	for _, analysis := range analyses {
+		if existing, ok := latest[analysis.Analyzer]; !ok || existing.CreatedAt.Before(analysis.CreatedAt) {
+			latest[analysis.Analyzer] = analysis
+		}
+	}
+
+	counts := map[digestFindingKey]*digestFindingCount{}
+	for name, analysis := range latest {
+		if analysis.Result == nil {
+			continue
+		}
+		ruleFindings, ok := analysis.Result.Findings[rule.SeverityNameError]
+		if !ok || ruleFindings == nil {
+			continue
+		}
+		for ruleNameID, findings := range ruleFindings.Rules {
+			if findings == nil || len(findings.Data) == 0 {
+				continue
+			}
+			counts[digestFindingKey{name, ruleNameID}] = &digestFindingCount{message: findings.Message, count: len(findings.Data)}
+		}
+	}
+	return counts
+}
+
+// AddBreakingChange adds the breaking changes of specDiff, whose new spec of service has version, to m.
+func (m *Digest) AddBreakingChange(service *Service, specDiff *SpecDiff, version string) {
+	if specDiff.Result == nil || specDiff.Result.JSON == nil || !specDiff.Result.JSON.Breaking {
+		return
+	}
+	change := &DigestBreakingChange{
+		Service:    newDigestService(service),
+		SpecDiffID: specDiff.ID,
+		Version:    version,
+		Changes:    digestBreakingChanges(specDiff.Result.JSON),
+	}
+	if specDiff.SpecDiffRequest != nil {
+		change.OldSpecID, change.NewSpecID = specDiff.OldSpecID, specDiff.NewSpecID
+	}
+	m.BreakingChanges = append(m.BreakingChanges, change)
+}
+
+func digestBreakingChanges(result *diff.JSONResult) []string {
+	var changes []string
+	for _, e := range result.Deleted {
+		changes = append(changes, fmt.Sprintf("Deleted `%s` %s", e.Method, e.Path))
+	}
+	for _, e := range result.Modified {
+		if e.Breaking {
+			changes = append(changes, fmt.Sprintf("Modified `%s` %s", e.Method, e.Path))
+		}
+	}
+	return changes
+}
+
+// Sort sorts the entries of m: score changes by ascending change (the worst regressions first),
+// findings by descending count, & the others by service.
+func (m *Digest) Sort() {
+	sort.SliceStable(m.ScoreChanges, func(i, j int) bool {
+		if m.ScoreChanges[i].Change != m.ScoreChanges[j].Change {
+			return m.ScoreChanges[i].Change < m.ScoreChanges[j].Change
+		}
+		return m.ScoreChanges[i].Service.NameID < m.ScoreChanges[j].Service.NameID
+	})
+	sort.SliceStable(m.Findings, func(i, j int) bool {
+		a, b := m.Findings[i], m.Findings[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Service.NameID != b.Service.NameID {
+			return a.Service.NameID < b.Service.NameID
+		}
+		if a.Analyzer != b.Analyzer {
+			return a.Analyzer < b.Analyzer
+		}
+		return a.Rule < b.Rule
+	})
+	sort.SliceStable(m.BreakingChanges, func(i, j int) bool {
+		return m.BreakingChanges[i].Service.NameID < m.BreakingChanges[j].Service.NameID
+	})
+	sort.SliceStable(m.StaleServices, func(i, j int) bool {
+		return m.StaleServices[i].NameID < m.StaleServices[j].NameID
+	})
+}
+
+// Empty checks if m reports nothing.
+func (m *Digest) Empty() bool {
+	return len(m.ScoreChanges) == 0 && len(m.Findings) == 0 && len(m.BreakingChanges) == 0 && len(m.StaleServices) == 0
+}
+
+// Subject returns the title of m, e.g. as an email subject.
+func (m *Digest) Subject() string {
+	frequency := "Weekly"
+	if m.Frequency == DigestFrequencyDaily {
+		frequency = "Daily"
+	}
+	return fmt.Sprintf("%s API Insights digest for %s (%s - %s)", frequency, m.OrganizationID,
+		m.From.UTC().Format("2006-01-02"), m.To.UTC().Format("2006-01-02"))
+}
+
+// Markdown returns the markdown representation of m.
+func (m *Digest) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n", m.Subject()))
+	if m.Empty() {
+		sb.WriteString("\nNothing to report.\n")
+		return sb.String()
+	}
+
+	if len(m.ScoreChanges) > 0 {
+		sb.WriteString("\n## Score changes\n\n")
+		for _, c := range m.ScoreChanges {
+			sb.WriteString(fmt.Sprintf("- %s: %d → %d (%+d)\n", c.Service.Title, c.PreviousScore, c.Score, c.Change))
+		}
+	}
+	if len(m.Findings) > 0 {
+		sb.WriteString("\n## New error findings\n\n")
+		for _, f := range m.Findings {
+			sb.WriteString(fmt.Sprintf("- %s: %d new `%s` (%s)", f.Service.Title, f.Count, f.Rule, f.Analyzer))
+			if f.Message != "" {
+				sb.WriteString(": " + f.Message)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if len(m.BreakingChanges) > 0 {
+		sb.WriteString("\n## Breaking changes\n\n")
+		for _, c := range m.BreakingChanges {
+			sb.WriteString(fmt.Sprintf("- %s %s\n", c.Service.Title, c.Version))
+			for _, change := range c.Changes {
+				sb.WriteString(fmt.Sprintf("  - %s\n", change))
+			}
+		}
+	}
+	if len(m.StaleServices) > 0 {
+		sb.WriteString(fmt.Sprintf("\n## Stale services (no spec update in %d days)\n\n", m.StaleDays))
+		for _, s := range m.StaleServices {
+			sb.WriteString(fmt.Sprintf("- %s: last updated %s\n", s.Title, s.UpdatedAt.UTC().Format("2006-01-02")))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,136 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestSubscription_Due(t *testing.T) {
+	t0 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := &DigestSubscription{Frequency: DigestFrequencyWeekly, Active: true, CreatedAt: t0}
+
+	assert.False(t, m.Due(t0.Add(6*24*time.Hour)))
+	assert.True(t, m.Due(t0.Add(7*24*time.Hour)))
+	from, to := m.Window(t0.Add(7 * 24 * time.Hour))
+	assert.Equal(t, t0, from)
+	assert.Equal(t, t0.Add(7*24*time.Hour), to)
+
+	sent := t0.Add(7 * 24 * time.Hour)
+	m.LastSentAt = &sent
+	m.Frequency = DigestFrequencyDaily
+	assert.False(t, m.Due(sent.Add(time.Hour)))
+	assert.True(t, m.Due(sent.Add(24*time.Hour)))
+	from, _ = m.Window(sent.Add(30 * time.Hour))
+	assert.Equal(t, sent, from)
+
+	m.Active = false
+	assert.False(t, m.Due(sent.Add(48*time.Hour)))
+}
+
+func TestDigestSubscription_ValidateRecipient(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, (&DigestSubscription{Channel: DigestChannelEmail, Recipient: "Team Lead <lead@example.com>"}).ValidateRecipient(ctx))
+	assert.Error(t, (&DigestSubscription{Channel: DigestChannelEmail, Recipient: "lead"}).ValidateRecipient(ctx))
+	assert.NoError(t, (&DigestSubscription{Channel: DigestChannelChat, Recipient: "https://203.0.113.10/T1/B2"}).ValidateRecipient(ctx))
+	assert.Error(t, (&DigestSubscription{Channel: DigestChannelChat, Recipient: "lead@example.com"}).ValidateRecipient(ctx))
+	assert.Error(t, (&DigestSubscription{Channel: DigestChannelChat, Recipient: "http://127.0.0.1:8080/hooks"}).ValidateRecipient(ctx))
+	assert.Error(t, (&DigestSubscription{Channel: DigestChannelChat, Recipient: "http://169.254.169.254/latest/meta-data/"}).ValidateRecipient(ctx))
+}
+
+func TestDigest(t *testing.T) {
+	var (
+		now   = time.Date(2022, 3, 8, 0, 0, 0, 0, time.UTC)
+		score = func(i int) *int { return &i }
+		carts = &Service{ID: "1", NameID: "carts", Title: "Carts", Summary: &ServiceSummary{Score: score(70), UpdatedAt: now.Add(-24 * time.Hour)}}
+		users = &Service{ID: "2", NameID: "users", Title: "Users", Summary: &ServiceSummary{Score: score(90), UpdatedAt: now.Add(-45 * 24 * time.Hour)}}
+	)
+	m := NewDigest(&DigestSubscription{OrganizationID: "shop", Frequency: DigestFrequencyWeekly}, now)
+	assert.Equal(t, DefaultDigestStaleDays, m.StaleDays)
+	assert.True(t, m.Empty())
+
+	m.AddService(carts, &SpecTrend{SpecID: "a", Score: score(80)})
+	m.AddService(users, &SpecTrend{SpecID: "b", Score: score(90)})
+	require.Len(t, m.ScoreChanges, 1)
+	assert.Equal(t, -10, m.ScoreChanges[0].Change)
+	require.Len(t, m.StaleServices, 1)
+	assert.Equal(t, "users", m.StaleServices[0].NameID)
+
+	errorResult := func(counts map[rule.NameID]int) *analyzer.Result {
+		rules := map[rule.NameID]*analyzer.Findings{}
+		for name, count := range counts {
+			rules[name] = &analyzer.Findings{Message: string(name) + " message", Data: make([]*analyzer.Finding, count)}
+		}
+		return &analyzer.Result{Findings: analyzer.SeverityRuleFindings{rule.SeverityNameError: {Rules: rules}}}
+	}
+	previous := []*SpecAnalysis{{Analyzer: "guidelines", SpecAnalysisResult: SpecAnalysisResult{Result: errorResult(map[rule.NameID]int{"path-casing": 2})}}}
+	latest := []*SpecAnalysis{
+		{Analyzer: "guidelines", CreatedAt: now.Add(-time.Hour), SpecAnalysisResult: SpecAnalysisResult{Result: errorResult(map[rule.NameID]int{"path-casing": 9})}},
+		{Analyzer: "guidelines", CreatedAt: now, SpecAnalysisResult: SpecAnalysisResult{Result: errorResult(map[rule.NameID]int{"path-casing": 3, "operation-id": 1})}},
+	}
+	m.AddFindings(carts, "c", latest, previous)
+
+	specDiff := &SpecDiff{ID: "d", SpecDiffRequest: &SpecDiffRequest{OldSpecID: "a", NewSpecID: "c"}}
+	specDiff.Result = &diff.Result{JSON: &diff.JSONResult{
+		Breaking: true,
+		Deleted:  []*diff.EndpointSummary{{Method: "DELETE", Path: "/cart"}},
+		Modified: []*diff.ModifiedSummary{{Method: "GET", Path: "/carts"}, {Method: "POST", Path: "/carts", Breaking: true}},
+	}}
+	m.AddBreakingChange(carts, specDiff, "2.0.0")
+	specDiff = &SpecDiff{ID: "e"}
+	specDiff.Result = &diff.Result{JSON: &diff.JSONResult{}}
+	m.AddBreakingChange(carts, specDiff, "2.0.1")
+	m.Sort()
+
+	require.Len(t, m.Findings, 2)
+	assert.Equal(t, rule.NameID("operation-id"), m.Findings[0].Rule)
+	assert.Equal(t, 1, m.Findings[0].Count)
+	assert.Equal(t, rule.NameID("path-casing"), m.Findings[1].Rule)
+	assert.Equal(t, 1, m.Findings[1].Count)
+	require.Len(t, m.BreakingChanges, 1)
+	assert.Equal(t, []string{"Deleted `DELETE` /cart", "Modified `POST` /carts"}, m.BreakingChanges[0].Changes)
+
+	assert.Equal(t, `# Weekly API Insights digest for shop (2022-03-01 - 2022-03-08)
+
+## Score changes
+
+- Carts: 80 → 70 (-10)
+
+## New error findings
+
+- Carts: 1 new `+"`operation-id`"+` (guidelines): operation-id message
+- Carts: 1 new `+"`path-casing`"+` (guidelines): path-casing message
+
+## Breaking changes
+
+- Carts 2.0.0
+  - Deleted `+"`DELETE`"+` /cart
+  - Modified `+"`POST`"+` /carts
+
+## Stale services (no spec update in 30 days)
+
+- Users: last updated 2022-01-22
+`, m.Markdown())
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	MigrationTableName = "migrations"
+
+	// SpecTrendBackfillMigrationID is the ID of the migration rebuilding the spec trends of the services whose specs
+	// were saved before spec trends (& their rules) were maintained.
+	SpecTrendBackfillMigrationID = "spec-trend-backfill"
+)
+
+// Migration records a one-off data migration, e.g. a backfill, claimed by a single replica at a time until completed.
+type Migration struct {
+	ID           string     `json:"id" gorm:"column:id;primaryKey"`
+	ClaimedUntil *time.Time `json:"claimed_until,omitempty" gorm:"column:claimed_until"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" gorm:"column:completed_at"`
+}
+
+func (m *Migration) TableName() string {
+	return MigrationTableName
+}
+
+// SpecRehashMigrationID returns the ID of the migration rehashing the specs hashed with an older SpecDocHashVersion,
+// so that it runs again as the version is bumped.
+func SpecRehashMigrationID() string {
+	return fmt.Sprintf("spec-rehash-v%d", SpecDocHashVersion)
+}
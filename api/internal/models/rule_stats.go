@@ -0,0 +1,246 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"sort"
+	"time"
+)
+
+const (
+	RuleStatsDefaultPeriods = 12
+	RuleStatsMaxPeriods     = 104
+	RuleStatsMaxFixes       = 10
+)
+
+// RuleStats represents the violations of an analyzer rule across services, per the latest spec of each service.
+type RuleStats struct {
+	Analyzer    analyzer.SpecAnalyzer `json:"analyzer"`
+	Rule        rule.NameID           `json:"rule"`
+	Title       string                `json:"title,omitempty"`
+	Severity    string                `json:"severity,omitempty"`
+	Services    int                   `json:"services"` // The number of services violating the rule.
+	Occurrences int                   `json:"occurrences"`
+
+	Trend         []*RuleStatsPoint `json:"trend"`
+	RecentlyFixed []*RuleStatsFix   `json:"recently_fixed"`
+}
+
+// RuleStatsPoint represents the violations of a rule at the end of the bucket starting at Start.
+type RuleStatsPoint struct {
+	Start       time.Time `json:"start"`
+	Services    int       `json:"services"`
+	Occurrences int       `json:"occurrences"`
+}
+
+// RuleStatsFix represents the spec of a service which fixed all violations of a rule, which weren't reintroduced since.
+type RuleStatsFix struct {
+	ServiceID     string    `json:"service_id"`
+	ServiceNameID string    `json:"service_name_id"`
+	SpecID        string    `json:"spec_id"`
+	Version       string    `json:"version"`
+	Revision      string    `json:"revision"`
+	FixedAt       time.Time `json:"fixed_at"`
+	Occurrences   int       `json:"occurrences"` // The occurrences of the rule in the previous spec.
+}
+
+// RuleStatsRanking represents the ranking of rules by the number of services violating them, then their occurrences.
+type RuleStatsRanking struct {
+	Interval    string       `json:"interval"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Rules       []*RuleStats `json:"rules"`
+}
+
+// RuleStatsKey identifies a rule of an analyzer.
+type RuleStatsKey struct {
+	Analyzer analyzer.SpecAnalyzer
+	Rule     rule.NameID
+}
+
+// NewRuleStats computes the RuleStats of rules from trends, the SpecTrend(s) of services, mapped by service ID.
+// Only the rules of keys are computed if any, otherwise all rules found in trends.
+// The trend consists of periods buckets by interval (day, week or month), up to the one of now.
+func NewRuleStats(trends map[string][]*SpecTrend, services map[string]*Service, keys []RuleStatsKey, interval string, periods int, now time.Time) map[RuleStatsKey]*RuleStats {
+	starts := make([]time.Time, periods)
+	start := serviceTrendBucketStart(now, interval)
+	for p := periods - 1; p >= 0; p-- {
+		starts[p] = start
+		start = previousServiceTrendBucketStart(start, interval)
+	}
+
+	stats := map[RuleStatsKey]*RuleStats{}
+	only := len(keys) > 0
+	for _, key := range keys {
+		stats[key] = newRuleStats(key, starts)
+	}
+	get := func(key RuleStatsKey) *RuleStats {
+		s, ok := stats[key]
+		if !ok && !only {
+			s = newRuleStats(key, starts)
+			stats[key] = s
+		}
+		return s
+	}
+
+	serviceIDs := make([]string, 0, len(trends))
+	for serviceID := range trends {
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	sort.Strings(serviceIDs)
+	for _, serviceID := range serviceIDs {
+		serviceTrends := make([]*SpecTrend, len(trends[serviceID]))
+		copy(serviceTrends, trends[serviceID])
+		sort.SliceStable(serviceTrends, func(i, j int) bool {
+			return serviceTrends[i].SpecCreatedAt.Before(serviceTrends[j].SpecCreatedAt)
+		})
+		if len(serviceTrends) == 0 {
+			continue
+		}
+
+		// Current violations.
+		for key, occurrences := range specTrendRules(serviceTrends[len(serviceTrends)-1]) {
+			if s := get(key); s != nil {
+				s.Services++
+				s.Occurrences += occurrences
+			}
+		}
+
+		// Violations at the end of each bucket.
+		i := -1
+		for p, start := range starts {
+			end := nextServiceTrendBucketStart(start, interval)
+			for i+1 < len(serviceTrends) && serviceTrends[i+1].SpecCreatedAt.Before(end) {
+				i++
+			}
+			if i < 0 {
+				continue
+			}
+			for key, occurrences := range specTrendRules(serviceTrends[i]) {
+				if s := get(key); s != nil {
+					s.Trend[p].Services++
+					s.Trend[p].Occurrences += occurrences
+				}
+			}
+		}
+
+		// Latest fixes, which weren't reintroduced since.
+		fixes := map[RuleStatsKey]*RuleStatsFix{}
+		for i := 1; i < len(serviceTrends); i++ {
+			previous, current := specTrendRules(serviceTrends[i-1]), specTrendRules(serviceTrends[i])
+			for key := range current {
+				delete(fixes, key)
+			}
+			for key, occurrences := range previous {
+				if _, ok := current[key]; ok {
+					continue
+				}
+				// Rules of analyzers which didn't analyze the spec aren't fixed.
+				if _, ok := serviceTrends[i].Analyzers[key.Analyzer]; !ok {
+					continue
+				}
+				fix := &RuleStatsFix{
+					ServiceID:   serviceID,
+					SpecID:      serviceTrends[i].SpecID,
+					Version:     serviceTrends[i].Version,
+					Revision:    serviceTrends[i].Revision,
+					FixedAt:     serviceTrends[i].SpecCreatedAt,
+					Occurrences: occurrences,
+				}
+				if service, ok := services[serviceID]; ok {
+					fix.ServiceNameID = service.NameID
+				}
+				fixes[key] = fix
+			}
+		}
+		for key, fix := range fixes {
+			if s := get(key); s != nil {
+				s.RecentlyFixed = append(s.RecentlyFixed, fix)
+			}
+		}
+	}
+
+	for _, s := range stats {
+		sort.SliceStable(s.RecentlyFixed, func(i, j int) bool { return s.RecentlyFixed[i].FixedAt.After(s.RecentlyFixed[j].FixedAt) })
+		if len(s.RecentlyFixed) > RuleStatsMaxFixes {
+			s.RecentlyFixed = s.RecentlyFixed[:RuleStatsMaxFixes]
+		}
+	}
+	return stats
+}
+
+func newRuleStats(key RuleStatsKey, starts []time.Time) *RuleStats {
+	s := &RuleStats{
+		Analyzer:      key.Analyzer,
+		Rule:          key.Rule,
+		Trend:         make([]*RuleStatsPoint, len(starts)),
+		RecentlyFixed: []*RuleStatsFix{},
+	}
+	for p, start := range starts {
+		s.Trend[p] = &RuleStatsPoint{Start: start}
+	}
+	return s
+}
+
+// specTrendRules returns the rules violated by the spec of trend, mapped to their occurrences.
+func specTrendRules(trend *SpecTrend) map[RuleStatsKey]int {
+	rules := map[RuleStatsKey]int{}
+	for analyzerName, a := range trend.Analyzers {
+		for ruleNameID, occurrences := range a.Rules {
+			if occurrences > 0 {
+				rules[RuleStatsKey{Analyzer: analyzerName, Rule: ruleNameID}] = occurrences
+			}
+		}
+	}
+	return rules
+}
+
+// NewRuleStatsRanking ranks stats by the number of services violating them, then their occurrences, up to limit if positive.
+// Rules only listed for their fixes are ranked last.
+func NewRuleStatsRanking(stats map[RuleStatsKey]*RuleStats, interval string, limit int, now time.Time) *RuleStatsRanking {
+	m := &RuleStatsRanking{Interval: interval, GeneratedAt: now, Rules: make([]*RuleStats, 0, len(stats))}
+	for _, s := range stats {
+		m.Rules = append(m.Rules, s)
+	}
+	sort.Slice(m.Rules, func(i, j int) bool {
+		a, b := m.Rules[i], m.Rules[j]
+		if a.Services != b.Services {
+			return a.Services > b.Services
+		}
+		if a.Occurrences != b.Occurrences {
+			return a.Occurrences > b.Occurrences
+		}
+		if a.Analyzer != b.Analyzer {
+			return a.Analyzer < b.Analyzer
+		}
+		return a.Rule < b.Rule
+	})
+	if limit > 0 && len(m.Rules) > limit {
+		m.Rules = m.Rules[:limit]
+	}
+	return m
+}
+
+// SetRule sets the title & severity of m from r.
+func (m *RuleStats) SetRule(r *analyzer.Rule) {
+	if r == nil {
+		return
+	}
+	m.Title = r.Title
+	m.Severity = r.Severity
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRuleStats(t *testing.T) {
+	// 2022-01-03 & 2022-01-10 are Mondays.
+	now := time.Date(2022, 1, 12, 0, 0, 0, 0, time.UTC)
+	trend := func(id string, createdAt time.Time, rules map[rule.NameID]int) *SpecTrend {
+		return &SpecTrend{SpecID: id, SpecCreatedAt: createdAt, Analyzers: SpecTrendAnalyzers{
+			analyzer.CiscoAPIGuidelines: {Rules: rules},
+		}}
+	}
+	trends := map[string][]*SpecTrend{
+		"svc1": {
+			trend("1a", time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC), map[rule.NameID]int{"r1": 3, "r2": 1}),
+			trend("1b", time.Date(2022, 1, 11, 0, 0, 0, 0, time.UTC), map[rule.NameID]int{"r2": 2}),
+		},
+		"svc2": {
+			trend("2a", time.Date(2022, 1, 4, 0, 0, 0, 0, time.UTC), map[rule.NameID]int{"r1": 1}),
+			// Specs not analyzed by the analyzer don't fix its rules.
+			{SpecID: "2b", SpecCreatedAt: time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC), Analyzers: SpecTrendAnalyzers{}},
+			trend("2c", time.Date(2022, 1, 6, 0, 0, 0, 0, time.UTC), map[rule.NameID]int{"r1": 2}),
+		},
+	}
+	services := map[string]*Service{"svc1": {ID: "svc1", NameID: "service-1"}}
+	r1 := RuleStatsKey{Analyzer: analyzer.CiscoAPIGuidelines, Rule: "r1"}
+	r2 := RuleStatsKey{Analyzer: analyzer.CiscoAPIGuidelines, Rule: "r2"}
+
+	stats := NewRuleStats(trends, services, nil, ServiceTrendsIntervalWeek, 3, now)
+	require.Len(t, stats, 2)
+
+	s := stats[r1]
+	assert.Equal(t, 1, s.Services)
+	assert.Equal(t, 2, s.Occurrences)
+	require.Len(t, s.Trend, 3)
+	assert.Equal(t, time.Date(2021, 12, 27, 0, 0, 0, 0, time.UTC), s.Trend[0].Start)
+	assert.Equal(t, RuleStatsPoint{Start: time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC), Services: 2, Occurrences: 5}, *s.Trend[1])
+	assert.Equal(t, RuleStatsPoint{Start: time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC), Services: 1, Occurrences: 2}, *s.Trend[2])
+	require.Len(t, s.RecentlyFixed, 1)
+	assert.Equal(t, "svc1", s.RecentlyFixed[0].ServiceID)
+	assert.Equal(t, "service-1", s.RecentlyFixed[0].ServiceNameID)
+	assert.Equal(t, "1b", s.RecentlyFixed[0].SpecID)
+	assert.Equal(t, 3, s.RecentlyFixed[0].Occurrences)
+
+	s = stats[r2]
+	assert.Equal(t, 1, s.Services)
+	assert.Equal(t, 2, s.Occurrences)
+	assert.Empty(t, s.RecentlyFixed)
+
+	only := NewRuleStats(trends, services, []RuleStatsKey{r2, {Analyzer: analyzer.Completeness, Rule: "r3"}}, ServiceTrendsIntervalWeek, 3, now)
+	require.Len(t, only, 2)
+	assert.Equal(t, 0, only[RuleStatsKey{Analyzer: analyzer.Completeness, Rule: "r3"}].Services)
+
+	ranking := NewRuleStatsRanking(stats, ServiceTrendsIntervalWeek, 1, now)
+	require.Len(t, ranking.Rules, 1)
+	assert.Equal(t, rule.NameID("r1"), ranking.Rules[0].Rule)
+}
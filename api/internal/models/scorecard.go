@@ -0,0 +1,344 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	ScorecardGroupByOrganization = "organization"
+	ScorecardGroupByProductTag   = "product_tag"
+	ScorecardGroupByRegistry     = "registry"
+
+	ScorecardFormatJSON = "json"
+	ScorecardFormatCSV  = "csv"
+
+	ScorecardDefaultThreshold = 70
+	ScorecardDefaultStaleDays = 90
+
+	// ScorecardChangePeriod is the period over which the change of scores is computed, i.e. week-over-week.
+	ScorecardChangePeriod = 7 * 24 * time.Hour
+
+	// ScorecardDistributionBucketSize is the size of the score ranges of ScorecardGroup.Distribution.
+	ScorecardDistributionBucketSize = 10
+)
+
+// Scorecard represents the rollup of the scores of services, by group (e.g. organization).
+type Scorecard struct {
+	GroupBy     string            `json:"group_by"` // organization, product_tag or registry
+	Threshold   int               `json:"threshold"`
+	StaleDays   int               `json:"stale_days"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	Groups      []*ScorecardGroup `json:"groups"`
+}
+
+// ScorecardGroup represents the rollup of the scores of a group of services.
+type ScorecardGroup struct {
+	Key            string                                    `json:"key"` // The organization ID or product tag of the services, empty if none, or registry.
+	Title          string                                    `json:"title,omitempty"`
+	Services       int                                       `json:"services"`
+	Score          *ScorecardStats                           `json:"score"`
+	Distribution   []*ScorecardBucket                        `json:"distribution"`
+	Analyzers      map[analyzer.SpecAnalyzer]*ScorecardStats `json:"analyzers"`
+	BelowThreshold []*ScorecardService                       `json:"below_threshold"`
+	Stale          []*ScorecardService                       `json:"stale"`
+}
+
+// ScorecardStats represents the stats of scores, along with their change over ScorecardChangePeriod.
+type ScorecardStats struct {
+	Count   int     `json:"count"`
+	Average float64 `json:"average"`
+	Median  float64 `json:"median"`
+	Min     int     `json:"min"`
+	Max     int     `json:"max"`
+
+	// PreviousAverage is the average score ScorecardChangePeriod ago, of the services which had one.
+	PreviousAverage *float64 `json:"previous_average,omitempty"`
+	// Change is Average - PreviousAverage.
+	Change *float64 `json:"change,omitempty"`
+}
+
+// ScorecardBucket represents the number of services scoring within [Min, Max].
+type ScorecardBucket struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
+}
+
+// ScorecardService represents a service listed by a ScorecardGroup, e.g. scoring below the threshold.
+type ScorecardService struct {
+	ID            string     `json:"id"`
+	NameID        string     `json:"name_id"`
+	Title         string     `json:"title"`
+	Score         *int       `json:"score"`
+	PreviousScore *int       `json:"previous_score,omitempty"`
+	LastSpecAt    *time.Time `json:"last_spec_at,omitempty"`
+}
+
+// ScorecardEntry represents a service along with the SpecTrend of its latest spec, now & ScorecardChangePeriod ago.
+type ScorecardEntry struct {
+	Service  *Service
+	Latest   *SpecTrend
+	Previous *SpecTrend
+}
+
+// ValidScorecardGroupBy checks if groupBy is a supported grouping of services.
+func ValidScorecardGroupBy(groupBy string) bool {
+	switch groupBy {
+	case ScorecardGroupByOrganization, ScorecardGroupByProductTag, ScorecardGroupByRegistry:
+		return true
+	}
+	return false
+}
+
+// NewScorecard constructs a new Scorecard of entries grouped by groupBy, where the services scoring below threshold,
+// or without any spec since staleDays, are listed. titles optionally maps group keys to their title.
+func NewScorecard(groupBy string, threshold, staleDays int, now time.Time, entries []*ScorecardEntry, titles map[string]string) *Scorecard {
+	m := &Scorecard{
+		GroupBy:     groupBy,
+		Threshold:   threshold,
+		StaleDays:   staleDays,
+		GeneratedAt: now,
+		Groups:      []*ScorecardGroup{},
+	}
+
+	grouped := map[string][]*ScorecardEntry{}
+	for _, entry := range entries {
+		var key string
+		switch groupBy {
+		case ScorecardGroupByOrganization:
+			key = entry.Service.OrganizationID
+		case ScorecardGroupByProductTag:
+			key = entry.Service.ProductTag
+		default:
+			key = ScorecardGroupByRegistry
+		}
+		grouped[key] = append(grouped[key], entry)
+	}
+	keys := make([]string, 0, len(grouped))
+	for key := range grouped {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	staleBefore := now.AddDate(0, 0, -staleDays)
+	for _, key := range keys {
+		m.Groups = append(m.Groups, newScorecardGroup(key, titles[key], threshold, staleBefore, grouped[key]))
+	}
+	return m
+}
+
+func newScorecardGroup(key, title string, threshold int, staleBefore time.Time, entries []*ScorecardEntry) *ScorecardGroup {
+	g := &ScorecardGroup{
+		Key:            key,
+		Title:          title,
+		Services:       len(entries),
+		Analyzers:      map[analyzer.SpecAnalyzer]*ScorecardStats{},
+		BelowThreshold: []*ScorecardService{},
+		Stale:          []*ScorecardService{},
+	}
+	for min := 0; min < 100; min += ScorecardDistributionBucketSize {
+		max := min + ScorecardDistributionBucketSize - 1
+		if max+ScorecardDistributionBucketSize > 100 {
+			max = 100
+		}
+		g.Distribution = append(g.Distribution, &ScorecardBucket{Min: min, Max: max})
+	}
+
+	var (
+		scores, previousScores = []int{}, []int{}
+		analyzerScores         = map[analyzer.SpecAnalyzer][]int{}
+		analyzerPreviousScores = map[analyzer.SpecAnalyzer][]int{}
+	)
+	for _, entry := range entries {
+		s := &ScorecardService{ID: entry.Service.ID, NameID: entry.Service.NameID, Title: entry.Service.Title}
+		if entry.Service.Summary != nil {
+			s.Score = entry.Service.Summary.Score
+		}
+		if entry.Latest != nil {
+			t := entry.Latest.SpecCreatedAt
+			s.LastSpecAt = &t
+			for analyzerName, a := range entry.Latest.Analyzers {
+				if a.Score != nil {
+					analyzerScores[analyzerName] = append(analyzerScores[analyzerName], *a.Score)
+				}
+			}
+		}
+		if entry.Previous != nil {
+			s.PreviousScore = entry.Previous.Score
+			if s.PreviousScore != nil {
+				previousScores = append(previousScores, *s.PreviousScore)
+			}
+			for analyzerName, a := range entry.Previous.Analyzers {
+				if a.Score != nil {
+					analyzerPreviousScores[analyzerName] = append(analyzerPreviousScores[analyzerName], *a.Score)
+				}
+			}
+		}
+
+		if s.Score != nil {
+			scores = append(scores, *s.Score)
+			for _, b := range g.Distribution {
+				if *s.Score >= b.Min && *s.Score <= b.Max {
+					b.Count++
+				}
+			}
+			if *s.Score < threshold {
+				g.BelowThreshold = append(g.BelowThreshold, s)
+			}
+		}
+		if s.LastSpecAt == nil || s.LastSpecAt.Before(staleBefore) {
+			g.Stale = append(g.Stale, s)
+		}
+	}
+
+	sort.SliceStable(g.BelowThreshold, func(i, j int) bool { return *g.BelowThreshold[i].Score < *g.BelowThreshold[j].Score })
+	g.Score = newScorecardStats(scores, previousScores)
+	for analyzerName, scores := range analyzerScores {
+		g.Analyzers[analyzerName] = newScorecardStats(scores, analyzerPreviousScores[analyzerName])
+	}
+	return g
+}
+
+// newScorecardStats constructs new ScorecardStats of scores, nil if there are none.
+func newScorecardStats(scores, previousScores []int) *ScorecardStats {
+	if len(scores) == 0 {
+		return nil
+	}
+	sorted := make([]int, len(scores))
+	copy(sorted, scores)
+	sort.Ints(sorted)
+
+	m := &ScorecardStats{
+		Count:   len(sorted),
+		Average: averageScore(sorted),
+		Min:     sorted[0],
+		Max:     sorted[len(sorted)-1],
+	}
+	if n := len(sorted); n%2 == 1 {
+		m.Median = float64(sorted[n/2])
+	} else {
+		m.Median = float64(sorted[n/2-1]+sorted[n/2]) / 2
+	}
+	if len(previousScores) > 0 {
+		previousAverage := averageScore(previousScores)
+		change := roundScore(m.Average - previousAverage)
+		m.PreviousAverage, m.Change = &previousAverage, &change
+	}
+	return m
+}
+
+func averageScore(scores []int) float64 {
+	var sum int
+	for _, s := range scores {
+		sum += s
+	}
+	return roundScore(float64(sum) / float64(len(scores)))
+}
+
+// roundScore rounds score to 2 decimals.
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
+
+// Analyzers returns the analyzers of all groups of m, sorted.
+func (m *Scorecard) Analyzers() []analyzer.SpecAnalyzer {
+	seen := map[analyzer.SpecAnalyzer]bool{}
+	var analyzers []analyzer.SpecAnalyzer
+	for _, g := range m.Groups {
+		for analyzerName := range g.Analyzers {
+			if !seen[analyzerName] {
+				seen[analyzerName] = true
+				analyzers = append(analyzers, analyzerName)
+			}
+		}
+	}
+	sort.Slice(analyzers, func(i, j int) bool { return analyzers[i] < analyzers[j] })
+	return analyzers
+}
+
+// WriteCSV writes m as CSV to w, one row per group.
+func (m *Scorecard) WriteCSV(w io.Writer) error {
+	analyzers := m.Analyzers()
+	header := []string{"group", "title", "services", "scored", "average", "median", "min", "max", "previous_average", "change", "below_threshold", "stale"}
+	if len(m.Groups) > 0 {
+		for _, b := range m.Groups[0].Distribution {
+			header = append(header, fmt.Sprintf("score_%d_%d", b.Min, b.Max))
+		}
+	}
+	for _, analyzerName := range analyzers {
+		header = append(header, string(analyzerName)+"_average", string(analyzerName)+"_median", string(analyzerName)+"_change")
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, g := range m.Groups {
+		row := []string{g.Key, g.Title, strconv.Itoa(g.Services)}
+		row = append(row, g.Score.csvColumns(true)...)
+		row = append(row, strconv.Itoa(len(g.BelowThreshold)), strconv.Itoa(len(g.Stale)))
+		for _, b := range g.Distribution {
+			row = append(row, strconv.Itoa(b.Count))
+		}
+		for _, analyzerName := range analyzers {
+			row = append(row, g.Analyzers[analyzerName].csvColumns(false)...)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvColumns returns the CSV columns of m, i.e. its count, average, median, min, max, previous average & change if all,
+// otherwise its average, median & change. Columns are empty if m is nil.
+func (m *ScorecardStats) csvColumns(all bool) []string {
+	formatFloat := func(f *float64) string {
+		if f == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*f, 'f', -1, 64)
+	}
+	if m == nil {
+		if all {
+			return []string{"0", "", "", "", "", "", ""}
+		}
+		return []string{"", "", ""}
+	}
+	if !all {
+		return []string{formatFloat(&m.Average), formatFloat(&m.Median), formatFloat(m.Change)}
+	}
+	return []string{
+		strconv.Itoa(m.Count),
+		formatFloat(&m.Average),
+		formatFloat(&m.Median),
+		strconv.Itoa(m.Min),
+		strconv.Itoa(m.Max),
+		formatFloat(m.PreviousAverage),
+		formatFloat(m.Change),
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScorecard(t *testing.T) {
+	score := func(s int) *int { return &s }
+	now := time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC)
+	entry := func(id, org string, s *int, lastSpecDaysAgo int, previous *int, analyzerScore int) *ScorecardEntry {
+		e := &ScorecardEntry{Service: &Service{ID: id, NameID: id, OrganizationID: org}}
+		if s != nil {
+			e.Service.Summary = &ServiceSummary{Score: s}
+			e.Latest = &SpecTrend{
+				ServiceID:     id,
+				Score:         s,
+				SpecCreatedAt: now.AddDate(0, 0, -lastSpecDaysAgo),
+				Analyzers:     SpecTrendAnalyzers{analyzer.Completeness: {Score: score(analyzerScore)}},
+			}
+		}
+		if previous != nil {
+			e.Previous = &SpecTrend{ServiceID: id, Score: previous}
+		}
+		return e
+	}
+	entries := []*ScorecardEntry{
+		entry("a", "org1", score(95), 1, score(85), 100),
+		entry("b", "org1", score(60), 100, nil, 50),
+		entry("c", "org1", score(45), 2, score(55), 40),
+		entry("d", "org2", score(80), 1, nil, 80),
+		entry("e", "org2", nil, 0, nil, 0),
+	}
+
+	sc := NewScorecard(ScorecardGroupByOrganization, 70, 90, now, entries, map[string]string{"org1": "Org 1"})
+	require.Len(t, sc.Groups, 2)
+
+	g := sc.Groups[0]
+	assert.Equal(t, "org1", g.Key)
+	assert.Equal(t, "Org 1", g.Title)
+	assert.Equal(t, 3, g.Services)
+	require.NotNil(t, g.Score)
+	assert.Equal(t, 3, g.Score.Count)
+	assert.Equal(t, 66.67, g.Score.Average)
+	assert.Equal(t, 60.0, g.Score.Median)
+	assert.Equal(t, 45, g.Score.Min)
+	assert.Equal(t, 95, g.Score.Max)
+	assert.Equal(t, 70.0, *g.Score.PreviousAverage)
+	assert.Equal(t, -3.33, *g.Score.Change)
+	assert.Equal(t, 63.33, g.Analyzers[analyzer.Completeness].Average)
+	assert.Equal(t, 50.0, g.Analyzers[analyzer.Completeness].Median)
+	require.Len(t, g.BelowThreshold, 2)
+	assert.Equal(t, "c", g.BelowThreshold[0].ID)
+	assert.Equal(t, "b", g.BelowThreshold[1].ID)
+	require.Len(t, g.Stale, 1)
+	assert.Equal(t, "b", g.Stale[0].ID)
+	require.Len(t, g.Distribution, 10)
+	assert.Equal(t, 1, g.Distribution[4].Count)
+	assert.Equal(t, 90, g.Distribution[9].Min)
+	assert.Equal(t, 100, g.Distribution[9].Max)
+	assert.Equal(t, 1, g.Distribution[9].Count)
+
+	g = sc.Groups[1]
+	assert.Equal(t, 2, g.Services)
+	assert.Equal(t, 1, g.Score.Count)
+	assert.Nil(t, g.Score.Change)
+	require.Len(t, g.Stale, 1)
+	assert.Equal(t, "e", g.Stale[0].ID)
+
+	sc = NewScorecard(ScorecardGroupByRegistry, 70, 90, now, entries, nil)
+	require.Len(t, sc.Groups, 1)
+	assert.Equal(t, ScorecardGroupByRegistry, sc.Groups[0].Key)
+	assert.Equal(t, 5, sc.Groups[0].Services)
+}
+
+func TestScorecard_WriteCSV(t *testing.T) {
+	score := func(s int) *int { return &s }
+	entries := []*ScorecardEntry{
+		{
+			Service: &Service{ID: "a", ProductTag: "tag", Summary: &ServiceSummary{Score: score(80)}},
+			Latest:  &SpecTrend{Score: score(80), SpecCreatedAt: time.Now(), Analyzers: SpecTrendAnalyzers{analyzer.Completeness: {Score: score(90)}}},
+		},
+		{Service: &Service{ID: "b"}},
+	}
+	sc := NewScorecard(ScorecardGroupByProductTag, 70, 90, time.Now(), entries, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, sc.WriteCSV(&buf))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	header := records[0]
+	assert.Equal(t, []string{"group", "title", "services", "scored", "average"}, header[:5])
+	assert.Equal(t, "completeness_average", header[len(header)-3])
+	for _, record := range records {
+		assert.Len(t, record, len(header))
+	}
+	assert.Equal(t, []string{"", "", "1", "0"}, records[1][:4])
+	assert.Equal(t, []string{"tag", "", "1", "1", "80", "80"}, records[2][:6])
+	assert.Equal(t, "90", records[2][len(header)-3])
+}
@@ -49,6 +49,9 @@ type Service struct {
 
 	AnalyzersConfigs AnalyzerConfigMap `json:"analyzers_configs,omitempty" gorm:"column:analyzers_configs"`
 
+	// RejectInvalidSpecs rejects uploads of specs failing validation (see Spec.Validate), which are otherwise stored as invalid.
+	RejectInvalidSpecs bool `json:"reject_invalid_specs" gorm:"column:reject_invalid_specs"`
+
 	Summary *ServiceSummary `json:"summary" gorm:"column:summary"`
 }
 
@@ -202,4 +205,6 @@ type ServicePatch struct {
 	Title            *string            `json:"title"`
 	AnalyzersConfigs *AnalyzerConfigMap `json:"analyzers_configs"`
 	Visibility       *string            `json:"visibility"`
+
+	RejectInvalidSpecs *bool `json:"reject_invalid_specs"`
 }
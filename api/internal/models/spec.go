@@ -20,17 +20,41 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/cisco-developer/api-insights/api/pkg/asyncapi"
+	"github.com/cisco-developer/api-insights/api/pkg/graphql"
+	"github.com/cisco-developer/api-insights/api/pkg/protobuf"
 	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/oas31"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/oasvalidator"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/specbundler"
+	"github.com/emicklei/go-restful/v3"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/vektah/gqlparser/v2/ast"
 	"gopkg.in/yaml.v3"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
 	"strings"
 	"time"
 )
 
 const (
 	SpecTableName = "specs"
+
+	SpecDocKindOpenAPI  = "openapi"
+	SpecDocKindAsyncAPI = "asyncapi"
+	SpecDocKindProtobuf = "protobuf"
+	SpecDocKindGraphQL  = "graphql"
+
+	SpecStateRelease = "Release"
+
+	// SpecDocHashVersion is the version of the derivation of Spec.DocHash (see Spec.SetDocHash),
+	// bumped whenever the canonical form of docs (see utils.CanonicalJSON) changes, so that stored specs get rehashed.
+	SpecDocHashVersion = 2
 )
 
 // Spec represents a spec
@@ -38,17 +62,40 @@ type Spec struct {
 	ID            string    `json:"id,omitempty" gorm:"primaryKey"`
 	Doc           SpecDoc   `json:"doc" gorm:"column:doc"`
 	DocCompressed []byte    `json:"-" gorm:"column:doc_compressed"`
+	DocHash       string    `json:"doc_hash" gorm:"column:doc_hash;index"`
 	DocType       string    `json:"doc_type" gorm:"column:doc_type"`
 	Revision      string    `json:"revision" gorm:"column:revision;index"`
 	Score         *int      `json:"score" gorm:"column:score"`
-	ServiceID     string    `json:"service_id" gorm:"column:service_id;index"`
+	ServiceID     string    `json:"service_id" gorm:"column:service_id;index;uniqueIndex:idx_spec_service_dedupe_hash,priority:1"`
 	State         string    `json:"state" gorm:"column:state;index"` // Archive, Release, Development, Latest
 	Valid         string    `json:"valid" gorm:"column:valid"`
 	Version       string    `json:"version" gorm:"column:version;index"`
 	CreatedAt     time.Time `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt     time.Time `json:"updated_at" gorm:"column:updated_at"`
 
-	DocOAS *openapi3.T `json:"-" gorm:"-"`
+	// DocHashVersion is the SpecDocHashVersion Spec.DocHash was derived with.
+	DocHashVersion int `json:"-" gorm:"column:doc_hash_version"`
+	// DedupeHash is Spec.DocHash, unique per service, so that identical concurrent uploads can't both be stored.
+	// It's nil for the duplicates stored before identical uploads were deduplicated.
+	DedupeHash *string `json:"-" gorm:"column:dedupe_hash;size:64;uniqueIndex:idx_spec_service_dedupe_hash,priority:2"`
+
+	// OriginalDoc is the doc as uploaded, if Spec.Doc was converted from it (see Spec.ConvertToOAS3).
+	OriginalDoc     SpecDoc `json:"original_doc,omitempty" gorm:"-"`
+	OriginalDocHash string  `json:"original_doc_hash,omitempty" gorm:"column:original_doc_hash"`
+	OriginalDocType string  `json:"original_doc_type,omitempty" gorm:"column:original_doc_type"`
+
+	// Sources contains the original files of a multi-file spec, which Spec.Doc is bundled from.
+	Sources    *SpecSources   `json:"sources,omitempty" gorm:"-"`
+	RawSources datatypes.JSON `json:"-" gorm:"column:sources"`
+
+	// ValidationErrors contains the errors found validating Spec.Doc, if Spec.Valid is SpecInvalid (see Spec.Validate).
+	ValidationErrors    []*oasvalidator.Error `json:"validation_errors,omitempty" gorm:"-"`
+	RawValidationErrors datatypes.JSON        `json:"-" gorm:"column:validation_errors"`
+
+	DocOAS      *openapi3.T        `json:"-" gorm:"-"`
+	DocAsyncAPI *asyncapi.Document `json:"-" gorm:"-"`
+	DocProtobuf *protobuf.Schema   `json:"-" gorm:"-"`
+	DocGraphQL  *ast.Schema        `json:"-" gorm:"-"`
 	// internalDoc is an internal state variable for temporarily storing Spec.Doc between Spec.BeforeSave & Spec.AfterSave for data compression.
 	internalDoc SpecDoc
 }
@@ -66,6 +113,16 @@ func (m *Spec) BeforeCreate(tx *gorm.DB) (err error) {
 // BeforeSave is a hook called before creation by GORM (https://gorm.io/docs/hooks.html).
 // For handling large Spec.Doc(s), compressData conditionally compresses Spec.Doc into Spec.DocCompressed.
 func (m *Spec) BeforeSave(tx *gorm.DB) (err error) {
+	if m.Sources != nil {
+		if m.RawSources, err = json.Marshal(m.Sources); err != nil {
+			return err
+		}
+	}
+	if m.ValidationErrors != nil {
+		if m.RawValidationErrors, err = json.Marshal(m.ValidationErrors); err != nil {
+			return err
+		}
+	}
 
 	// Handle compression.
 	m.DocCompressed, err = compressData([]byte(*m.Doc))
@@ -93,6 +150,17 @@ func (m *Spec) AfterSave(tx *gorm.DB) (err error) {
 // AfterFind is a hook called after querying by GORM (https://gorm.io/docs/hooks.html).
 // For handling large Spec.Doc(s), if Spec.DocCompressed contains the compression, decompresses it back into Spec.Doc.
 func (m *Spec) AfterFind(tx *gorm.DB) (err error) {
+	if m.RawSources != nil {
+		m.Sources = &SpecSources{}
+		if err = json.Unmarshal(m.RawSources, m.Sources); err != nil {
+			return err
+		}
+	}
+	if m.RawValidationErrors != nil {
+		if err = json.Unmarshal(m.RawValidationErrors, &m.ValidationErrors); err != nil {
+			return err
+		}
+	}
 	if m.DocCompressed != nil {
 		decompressed, _, err := utils.GUNZIP(m.DocCompressed)
 		if err != nil {
@@ -135,6 +203,7 @@ func (m *Spec) GetIndexes() map[string]string {
 		"version":    "idx_version",
 		"revision":   "idx_revision",
 		"state":      "idx_state",
+		"doc_hash":   "idx_doc_hash",
 	}
 }
 
@@ -150,6 +219,7 @@ func (m *Spec) GetIndexValues() map[string]string {
 		"version":    m.Version,
 		"revision":   m.Revision,
 		"state":      m.State,
+		"doc_hash":   m.DocHash,
 	}
 }
 
@@ -167,6 +237,44 @@ func (m *Spec) SortableFields() map[string]struct{} {
 	}
 }
 
+// DocKind returns the kind of Spec.Doc, i.e. the family of specifications its Spec.DocType belongs to.
+func (m *Spec) DocKind() string {
+	switch {
+	case strings.HasPrefix(m.DocType, specDocTypeAsyncAPIPrefix):
+		return SpecDocKindAsyncAPI
+	case strings.HasPrefix(m.DocType, specDocTypeProtobufPrefix):
+		return SpecDocKindProtobuf
+	case strings.HasPrefix(m.DocType, specDocTypeGraphQLPrefix):
+		return SpecDocKindGraphQL
+	case m.DocType != "" || m.Doc == nil:
+		return SpecDocKindOpenAPI
+	case asyncapi.IsAsyncAPI([]byte(*m.Doc)):
+		return SpecDocKindAsyncAPI
+	case protobuf.IsProtobuf([]byte(*m.Doc)):
+		return SpecDocKindProtobuf
+	case graphql.IsGraphQL([]byte(*m.Doc)):
+		return SpecDocKindGraphQL
+	}
+	return SpecDocKindOpenAPI
+}
+
+// LoadDoc loads Spec.Doc according to its kind
+// (see Spec.LoadDocAsOAS, Spec.LoadDocAsAsyncAPI, Spec.LoadDocAsProtobuf & Spec.LoadDocAsGraphQL).
+func (m *Spec) LoadDoc(ctx context.Context, validate, setDocType, setVersion bool) error {
+	var err error
+	switch m.DocKind() {
+	case SpecDocKindAsyncAPI:
+		_, err = m.LoadDocAsAsyncAPI(setDocType, setVersion)
+	case SpecDocKindProtobuf:
+		_, err = m.LoadDocAsProtobuf(setDocType, setVersion)
+	case SpecDocKindGraphQL:
+		_, err = m.LoadDocAsGraphQL(setDocType)
+	default:
+		_, err = m.LoadDocAsOAS(ctx, validate, setDocType, setVersion)
+	}
+	return err
+}
+
 // LoadDocAsOAS loads Spec.Doc as an OpenAPI spec & stores it as Spec.DocOAS.
 // Set validate to validate Spec.DocOAS.
 // Set setDocType to derive Spec.DocType from Spec.DocOAS.Version.
@@ -179,12 +287,15 @@ func (m *Spec) LoadDocAsOAS(ctx context.Context, validate, setDocType, setVersio
 		return nil, fmt.Errorf("spec: missing Spec.Doc")
 	}
 	var err error
-	m.DocOAS, err = openapi3.NewLoader().LoadFromData([]byte(*m.Doc))
+	m.DocOAS, err = LoadOAS([]byte(*m.Doc))
 	if err != nil {
 		return nil, fmt.Errorf("spec: failed to load Spec.Doc as OAS")
 	}
 	if validate {
 		err := m.DocOAS.Validate(ctx)
+		if err == nil {
+			err = oas31.Validate([]byte(*m.Doc))
+		}
 		if err != nil {
 			return nil, fmt.Errorf("spec: invalid Spec.Doc: %v", err)
 		}
@@ -210,6 +321,170 @@ func (m *Spec) LoadDocAsOAS(ctx context.Context, validate, setDocType, setVersio
 	return m.DocOAS, nil
 }
 
+// LoadOAS loads data as an OpenAPI spec.
+// OpenAPI 3.1 constructs unsupported by openapi3 are downgraded first (see oas31.Downgrade).
+func LoadOAS(data []byte) (*openapi3.T, error) {
+	data, _, err := oas31.Downgrade(data)
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.NewLoader().LoadFromData(data)
+}
+
+// SetDocHash derives Spec.DocHash (& Spec.OriginalDocHash) from the canonical form of Spec.Doc (& Spec.OriginalDoc) (see utils.CanonicalHash),
+// & sets Spec.DedupeHash to it.
+func (m *Spec) SetDocHash() error {
+	if m.Doc == nil || len(*m.Doc) == 0 {
+		return fmt.Errorf("spec: missing Spec.Doc")
+	}
+	m.DocHash = utils.CanonicalHash([]byte(*m.Doc))
+	if m.OriginalDoc != nil && len(*m.OriginalDoc) > 0 {
+		m.OriginalDocHash = utils.CanonicalHash([]byte(*m.OriginalDoc))
+	}
+	m.DocHashVersion = SpecDocHashVersion
+	dedupeHash := m.DocHash
+	m.DedupeHash = &dedupeHash
+	return nil
+}
+
+// LoadDocFromFiles bundles the files of a multi-file spec into Spec.Doc, resolving external $refs from the root document,
+// & keeps the original files as Spec.Sources. If root is empty, the root document is guessed.
+// Protobuf files are bundled as-is (see Spec.loadDocFromProtobufFiles).
+func (m *Spec) LoadDocFromFiles(files specbundler.Files, root string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("spec: missing spec files")
+	}
+	if isProtobufFiles(files) {
+		return m.loadDocFromProtobufFiles(files)
+	}
+	if root == "" {
+		var err error
+		if root, err = files.GuessRoot(); err != nil {
+			return err
+		}
+	}
+
+	bundle, err := specbundler.BundleFiles(files, root)
+	if err != nil {
+		return err
+	}
+
+	m.Doc = NewSpecDocFromBytes(bundle.Doc)
+	m.DocOAS = nil
+	m.Sources = &SpecSources{
+		Root:      bundle.Root,
+		Files:     make(map[string]string, len(files)),
+		SourceMap: bundle.SourceMap,
+	}
+	for name, data := range files {
+		m.Sources.Files[name] = string(data)
+	}
+	return nil
+}
+
+// From reads a Spec from req, either as an entity, or as a multipart/form-data with a spec archive (zip, tar or tar.gz)
+// or spec files, along with an optional root document path.
+func (m *Spec) From(req *restful.Request) error {
+	if isMultipart, err := m.tryAsMultipartForm(req); isMultipart {
+		return err
+	}
+	return req.ReadEntity(m)
+}
+
+func (m *Spec) tryAsMultipartForm(req *restful.Request) (isMultipart bool, err error) {
+	const (
+		maxMemory     = 32 << 20
+		archiveFile   = "archive"
+		specFiles     = "files"
+		rootFieldName = "root"
+	)
+
+	body := &limitedBody{ReadCloser: req.Request.Body, remaining: specbundler.MaxArchiveSize}
+	req.Request.Body = body
+	if err := req.Request.ParseMultipartForm(maxMemory); err != nil {
+		if err == http.ErrNotMultipart {
+			req.Request.Body = body.ReadCloser
+			return false, err
+		}
+		if body.exceeded {
+			return true, specbundler.ErrArchiveTooLarge
+		}
+		return true, err
+	}
+	defer func() {
+		_ = req.Request.MultipartForm.RemoveAll()
+	}()
+
+	files := specbundler.Files{}
+	if fhs := req.Request.MultipartForm.File[archiveFile]; len(fhs) > 0 {
+		data, err := readFormFile(fhs[0], specbundler.MaxArchiveSize)
+		if err != nil {
+			return true, err
+		}
+		if files, err = specbundler.ReadArchive(data); err != nil {
+			return true, err
+		}
+	}
+	for _, fh := range req.Request.MultipartForm.File[specFiles] {
+		data, err := readFormFile(fh, specbundler.MaxArchiveFileSize)
+		if err != nil {
+			return true, err
+		}
+		// FileHeader.Filename is stripped of its directory, so use the original one to keep the file layout.
+		name := fh.Filename
+		if _, params, err := mime.ParseMediaType(fh.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+			name = params["filename"]
+		}
+		if err := files.Add(name, data); err != nil {
+			return true, err
+		}
+	}
+
+	m.Revision = req.Request.FormValue("revision")
+	m.State = req.Request.FormValue("state")
+	m.Version = req.Request.FormValue("version")
+
+	return true, m.LoadDocFromFiles(files, req.Request.FormValue(rootFieldName))
+}
+
+// readFormFile reads the file of fh, up to limit bytes, or else fails with specbundler.ErrArchiveTooLarge.
+func readFormFile(fh *multipart.FileHeader, limit int64) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: %s", specbundler.ErrArchiveTooLarge, fh.Filename)
+	}
+	return data, nil
+}
+
+// limitedBody reads up to remaining bytes of a request body, then fails with specbundler.ErrArchiveTooLarge.
+// Unlike http.MaxBytesReader, it records whether the limit was exceeded, as multipart parsing doesn't wrap read errors.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		n, b.remaining, b.exceeded = int(b.remaining), 0, true
+		return n, specbundler.ErrArchiveTooLarge
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
 // GetDocAsMap unmarshals Spec.Doc into a map by first parsing as a JSON & if that fails, as a YAML.
 func (m *Spec) GetDocAsMap() (docMap map[string]interface{}, isJSON bool, err error) {
 	if m.Doc == nil {
@@ -238,6 +513,25 @@ type SpecResponse struct {
 	Data []Spec `json:"data"`
 }
 
+// SpecSources represents the original files of a multi-file spec.
+type SpecSources struct {
+	// Root is the path of the root document.
+	Root string `json:"root"`
+	// Files maps file paths to their contents.
+	Files map[string]string `json:"files"`
+	// SourceMap maps JSON pointers of the bundled Spec.Doc to their original file & JSON pointer.
+	SourceMap map[string]specbundler.Source `json:"source_map,omitempty"`
+}
+
+// Locate returns the original file & JSON pointer of a JSON pointer of the bundled Spec.Doc.
+func (s *SpecSources) Locate(pointer string) specbundler.Source {
+	src := specbundler.Locate(s.SourceMap, pointer)
+	if src.File == "" {
+		src.File = s.Root
+	}
+	return src
+}
+
 type SpecDoc *string
 
 func NewSpecDocFromBytes(data []byte) SpecDoc {
@@ -0,0 +1,56 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/pkg/asyncapi"
+)
+
+const (
+	specDocTypeAsyncAPIPrefix = "asyncapi-"
+)
+
+// IsAsyncAPI checks if Spec.Doc is an AsyncAPI document.
+func (m *Spec) IsAsyncAPI() bool {
+	return m.DocKind() == SpecDocKindAsyncAPI
+}
+
+// LoadDocAsAsyncAPI loads Spec.Doc as an AsyncAPI spec & stores it as Spec.DocAsyncAPI.
+// Spec.Doc is always validated, as it can't be loaded otherwise.
+// Set setDocType to derive Spec.DocType from Spec.DocAsyncAPI.Version.
+// Set setVersion to derive Spec.Version from Spec.DocAsyncAPI.Info.Version.
+func (m *Spec) LoadDocAsAsyncAPI(setDocType, setVersion bool) (*asyncapi.Document, error) {
+	if m.DocAsyncAPI != nil {
+		return m.DocAsyncAPI, nil
+	}
+	if m.Doc == nil || len(*m.Doc) == 0 {
+		return nil, fmt.Errorf("spec: missing Spec.Doc")
+	}
+	var err error
+	m.DocAsyncAPI, err = asyncapi.Parse([]byte(*m.Doc))
+	if err != nil {
+		return nil, fmt.Errorf("spec: failed to load Spec.Doc as AsyncAPI: %v", err)
+	}
+	if setDocType && m.DocType == "" {
+		m.DocType = specDocTypeAsyncAPIPrefix + m.DocAsyncAPI.Version
+	}
+	if setVersion && m.Version == "" {
+		m.Version = m.DocAsyncAPI.Info.Version
+	}
+	return m.DocAsyncAPI, nil
+}
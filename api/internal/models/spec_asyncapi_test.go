@@ -0,0 +1,91 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpec_LoadDoc(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         SpecDoc
+		wantKind    string
+		wantDocType string
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name:        "asyncapi",
+			doc:         NewSpecDocFromBytes([]byte("asyncapi: 2.6.0\ninfo:\n  title: a\n  version: 1.2.0\nchannels: {}\n")),
+			wantKind:    SpecDocKindAsyncAPI,
+			wantDocType: "asyncapi-2.6.0",
+			wantVersion: "1.2.0",
+		},
+		{
+			name:     "invalid asyncapi",
+			doc:      NewSpecDocFromBytes([]byte("asyncapi: 2.6.0\n")),
+			wantKind: SpecDocKindAsyncAPI,
+			wantErr:  true,
+		},
+		{
+			name:        "protobuf",
+			doc:         NewSpecDocFromBytes([]byte("syntax = \"proto3\";\npackage acme.billing.v2;\nmessage A {}\n")),
+			wantKind:    SpecDocKindProtobuf,
+			wantDocType: "protobuf-proto3",
+			wantVersion: "v2",
+		},
+		{
+			name:        "graphql",
+			doc:         NewSpecDocFromBytes([]byte("type Query {\n  a: String\n}\n")),
+			wantKind:    SpecDocKindGraphQL,
+			wantDocType: "graphql-sdl",
+		},
+		{
+			name:     "invalid graphql",
+			doc:      NewSpecDocFromBytes([]byte("type Query {\n  a: Missing\n}\n")),
+			wantKind: SpecDocKindGraphQL,
+			wantErr:  true,
+		},
+		{
+			name:        "oas3",
+			doc:         SpecDoc(loadSpec("testdata/sample-api.yaml")),
+			wantKind:    SpecDocKindOpenAPI,
+			wantDocType: "oas-3.0.3",
+			wantVersion: "1.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Spec{Doc: tt.doc}
+			assert.Equal(t, tt.wantKind, m.DocKind())
+			if err := m.LoadDoc(context.Background(), false, true, true); (err != nil) != tt.wantErr {
+				t.Errorf("LoadDoc() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.wantDocType, m.DocType)
+			assert.Equal(t, tt.wantVersion, m.Version)
+			assert.Equal(t, tt.wantKind, m.DocKind())
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	SpecBlobTableName = "spec_blobs"
+)
+
+// SpecBlob represents a content-addressed spec doc, shared by all specs with the same Spec.DocHash.
+type SpecBlob struct {
+	Hash          string    `json:"hash" gorm:"column:hash;primaryKey"`
+	Doc           SpecDoc   `json:"doc" gorm:"column:doc"`
+	DocCompressed []byte    `json:"-" gorm:"column:doc_compressed"`
+	CreatedAt     time.Time `json:"created_at" gorm:"column:created_at"`
+
+	// internalDoc is an internal state variable for temporarily storing SpecBlob.Doc between SpecBlob.BeforeSave & SpecBlob.AfterSave for data compression.
+	internalDoc SpecDoc
+}
+
+// NewSpecBlob constructs a new SpecBlob from spec's Spec.Doc & Spec.DocHash.
+func NewSpecBlob(spec *Spec) (*SpecBlob, error) {
+	if spec.Doc == nil || spec.DocHash == "" {
+		return nil, fmt.Errorf("spec_blob: missing Spec.Doc or Spec.DocHash")
+	}
+	return &SpecBlob{
+		Hash:      spec.DocHash,
+		Doc:       spec.Doc,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// NewOriginalSpecBlob constructs a new SpecBlob from spec's Spec.OriginalDoc & Spec.OriginalDocHash.
+func NewOriginalSpecBlob(spec *Spec) (*SpecBlob, error) {
+	if spec.OriginalDoc == nil || spec.OriginalDocHash == "" {
+		return nil, fmt.Errorf("spec_blob: missing Spec.OriginalDoc or Spec.OriginalDocHash")
+	}
+	return &SpecBlob{
+		Hash:      spec.OriginalDocHash,
+		Doc:       spec.OriginalDoc,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// TableName implements gorm Tabler interface
+func (m *SpecBlob) TableName() string {
+	return SpecBlobTableName
+}
+
+// BeforeSave is a hook called before creation by GORM (https://gorm.io/docs/hooks.html).
+// For handling large SpecBlob.Doc(s), compressData conditionally compresses SpecBlob.Doc into SpecBlob.DocCompressed.
+func (m *SpecBlob) BeforeSave(tx *gorm.DB) (err error) {
+	m.DocCompressed, err = compressData([]byte(*m.Doc))
+	if err != nil {
+		return err
+	} else if m.DocCompressed != nil {
+		m.internalDoc = m.Doc
+		m.Doc = nil
+	}
+	return
+}
+
+// AfterSave is a hook called after creation by GORM (https://gorm.io/docs/hooks.html).
+// For handling large SpecBlob.Doc(s), resets the temporary staging of SpecBlob.Doc.
+func (m *SpecBlob) AfterSave(tx *gorm.DB) (err error) {
+	if m.DocCompressed != nil {
+		m.Doc = m.internalDoc
+		m.internalDoc = nil
+		m.DocCompressed = nil
+	}
+	return
+}
+
+// AfterFind is a hook called after querying by GORM (https://gorm.io/docs/hooks.html).
+// For handling large SpecBlob.Doc(s), if SpecBlob.DocCompressed contains the compression, decompresses it back into SpecBlob.Doc.
+func (m *SpecBlob) AfterFind(tx *gorm.DB) (err error) {
+	if m.DocCompressed != nil {
+		decompressed, _, err := utils.GUNZIP(m.DocCompressed)
+		if err != nil {
+			return err
+		}
+		m.Doc = NewSpecDocFromBytes(decompressed)
+	}
+	return
+}
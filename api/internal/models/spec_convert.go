@@ -0,0 +1,158 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+const (
+	SpecDocTypeOAS2 = "oas-2.0"
+)
+
+// IsSwagger2 checks if Spec.Doc is a Swagger 2.0 document.
+func (m *Spec) IsSwagger2() bool {
+	docMap, _, err := m.GetDocAsMap()
+	if err != nil {
+		return false
+	}
+	version, ok := docMap["swagger"]
+	return ok && fmt.Sprintf("%v", version) == "2.0"
+}
+
+// ConvertToOAS3 converts a Swagger 2.0 Spec.Doc into OAS3, keeping the original as Spec.OriginalDoc.
+// Spec.Doc keeps its format (JSON or YAML). Returns whether Spec.Doc was converted.
+func (m *Spec) ConvertToOAS3() (converted bool, err error) {
+	if !m.IsSwagger2() {
+		return false, nil
+	}
+
+	docJSON, err := utils.CanonicalJSON([]byte(*m.Doc))
+	if err != nil {
+		return false, fmt.Errorf("spec: invalid Spec.Doc: %v", err)
+	}
+	var doc2 openapi2.T
+	if err := json.Unmarshal(docJSON, &doc2); err != nil {
+		return false, fmt.Errorf("spec: failed to load Spec.Doc as Swagger 2.0: %v", err)
+	}
+	doc3, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return false, fmt.Errorf("spec: failed to convert Spec.Doc to OAS3: %v", err)
+	}
+
+	converted3, err := json.MarshalIndent(doc3, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if _, isJSON, _ := m.GetDocAsMap(); !isJSON {
+		var v interface{}
+		if err := yaml.Unmarshal(converted3, &v); err != nil {
+			return false, err
+		}
+		if converted3, err = yaml.Marshal(v); err != nil {
+			return false, err
+		}
+	}
+
+	m.OriginalDoc = m.Doc
+	m.OriginalDocType = SpecDocTypeOAS2
+	m.Doc = NewSpecDocFromBytes(converted3)
+	m.DocOAS = nil
+	m.DocType = fmt.Sprintf("oas-%v", doc3.OpenAPI)
+	return true, nil
+}
+
+// swagger2Components maps OAS3 components to their Swagger 2.0 counterparts.
+var swagger2Components = map[string]string{
+	"schemas":         "definitions",
+	"parameters":      "parameters",
+	"responses":       "responses",
+	"securitySchemes": "securityDefinitions",
+}
+
+// Swagger2Path maps a path of an OAS3 doc converted by Spec.ConvertToOAS3 back to the path of the original Swagger 2.0 doc.
+// Parts without a Swagger 2.0 counterpart are mapped to their closest ancestor.
+func Swagger2Path(path []string) []string {
+	if len(path) == 0 {
+		return path
+	}
+
+	switch path[0] {
+	case "openapi":
+		return []string{"swagger"}
+	case "servers":
+		return []string{"host"}
+	case "components":
+		if len(path) < 2 {
+			return []string{}
+		}
+		if section, ok := swagger2Components[path[1]]; ok {
+			return append([]string{section}, path[2:]...)
+		}
+		return []string{}
+	case "paths":
+		return swagger2OperationPath(path)
+	}
+	return path
+}
+
+// swagger2OperationPath maps paths under paths/{path}/{method}.
+func swagger2OperationPath(path []string) []string {
+	if len(path) < 3 {
+		return path
+	}
+	var (
+		operation = path[:3]
+		rest      = path[3:]
+	)
+	out := append([]string{}, operation...)
+	switch {
+	case len(rest) == 0:
+		return out
+	case rest[0] == "requestBody":
+		// Request bodies were body or formData parameters, whose index is unknown.
+		return append(out, "parameters")
+	case rest[0] == "responses" && len(rest) >= 3 && rest[2] == "content":
+		// responses/{code}/content/{mediaType}/schema/... was responses/{code}/schema/...
+		out = append(out, rest[:2]...)
+		if len(rest) >= 5 && rest[4] == "schema" {
+			return append(out, rest[4:]...)
+		}
+		return out
+	case rest[0] == "servers":
+		return append(out, "schemes")
+	case rest[0] == "parameters" && len(rest) >= 3 && rest[2] == "schema":
+		// Non-body parameters had their schema inlined.
+		out = append(out, rest[:2]...)
+		return append(out, rest[3:]...)
+	}
+	return append(out, rest...)
+}
+
+// OriginalPath maps a path of Spec.Doc back to the path of Spec.OriginalDoc, if Spec.Doc was converted.
+func (m *Spec) OriginalPath(path []string) []string {
+	if strings.EqualFold(m.OriginalDocType, SpecDocTypeOAS2) {
+		return Swagger2Path(path)
+	}
+	return path
+}
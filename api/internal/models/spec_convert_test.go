@@ -0,0 +1,120 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpec_ConvertToOAS3(t *testing.T) {
+	tests := []struct {
+		name          string
+		doc           SpecDoc
+		wantConverted bool
+		wantErr       bool
+	}{
+		{
+			name:          "swagger 2.0",
+			doc:           SpecDoc(loadSpec("testdata/petstore-v2.json")),
+			wantConverted: true,
+		},
+		{
+			name:          "oas3",
+			doc:           SpecDoc(loadSpec("testdata/sample-api.yaml")),
+			wantConverted: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Spec{Doc: tt.doc}
+			gotConverted, err := m.ConvertToOAS3()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertToOAS3() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotConverted != tt.wantConverted {
+				t.Errorf("ConvertToOAS3() gotConverted = %v, want %v", gotConverted, tt.wantConverted)
+			}
+			if !gotConverted {
+				assert.Nil(t, m.OriginalDoc)
+				return
+			}
+			assert.Equal(t, tt.doc, m.OriginalDoc)
+			assert.Equal(t, SpecDocTypeOAS2, m.OriginalDocType)
+			assert.Equal(t, "oas-3.0.3", m.DocType)
+
+			doc, err := m.LoadDocAsOAS(context.Background(), true, false, false)
+			assert.NoError(t, err)
+			assert.Contains(t, doc.Components.Schemas, "Pet")
+		})
+	}
+}
+
+func TestSwagger2Path(t *testing.T) {
+	tests := []struct {
+		name string
+		path []string
+		want []string
+	}{
+		{
+			name: "schema",
+			path: []string{"components", "schemas", "Pet", "properties", "id"},
+			want: []string{"definitions", "Pet", "properties", "id"},
+		},
+		{
+			name: "security scheme",
+			path: []string{"components", "securitySchemes", "basic"},
+			want: []string{"securityDefinitions", "basic"},
+		},
+		{
+			name: "response schema",
+			path: []string{"paths", "/pets", "get", "responses", "200", "content", "application/json", "schema", "items"},
+			want: []string{"paths", "/pets", "get", "responses", "200", "schema", "items"},
+		},
+		{
+			name: "request body",
+			path: []string{"paths", "/pets", "post", "requestBody", "content", "application/json", "schema"},
+			want: []string{"paths", "/pets", "post", "parameters"},
+		},
+		{
+			name: "parameter schema",
+			path: []string{"paths", "/pets", "get", "parameters", "0", "schema", "type"},
+			want: []string{"paths", "/pets", "get", "parameters", "0", "type"},
+		},
+		{
+			name: "servers",
+			path: []string{"servers", "0", "url"},
+			want: []string{"host"},
+		},
+		{
+			name: "unchanged",
+			path: []string{"info", "title"},
+			want: []string{"info", "title"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Swagger2Path(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Swagger2Path() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,645 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DuplicateSnapshotTableName = "duplicate_snapshots"
+	// DuplicateSnapshotID is the ID of the DuplicateSnapshot of the latest specs of all services.
+	DuplicateSnapshotID = "latest"
+
+	DuplicateKindSchema   = "schema"
+	DuplicateKindEndpoint = "endpoint"
+
+	// DefaultDuplicateThreshold is the default similarity from which schemas or endpoints are near-duplicates.
+	DefaultDuplicateThreshold = 0.8
+	// DuplicateMinProperties is the minimum number of properties of the schemas compared, below which
+	// schemas (e.g. wrappers of a single property) would be duplicates of too many others.
+	DuplicateMinProperties = 2
+)
+
+// DuplicateKinds are the kinds of DuplicateCluster.
+var DuplicateKinds = []string{DuplicateKindSchema, DuplicateKindEndpoint}
+
+var (
+	duplicateNameSeparators = regexp.MustCompile(`[^a-z0-9]`)
+	duplicateVersionSegment = regexp.MustCompile(`^v[0-9]+(\.[0-9]+)*$`)
+)
+
+// DuplicateReport represents the clusters of duplicated or near-duplicated component schemas & endpoints
+// across the latest specs of services.
+type DuplicateReport struct {
+	Threshold float64             `json:"threshold"`
+	Services  int                 `json:"services"`  // The number of services compared.
+	Skipped   []string            `json:"skipped"`   // The IDs of the services whose latest spec couldn't be compared.
+	Schemas   int                 `json:"schemas"`   // The number of component schemas compared.
+	Endpoints int                 `json:"endpoints"` // The number of endpoints compared.
+	Clusters  []*DuplicateCluster `json:"clusters"`
+
+	compared DuplicateCompared
+}
+
+// DuplicateSnapshot stores the DuplicateReport of the latest specs of all services at DefaultDuplicateThreshold.
+// It's refreshed in the background as specs are saved, so that duplicates are read without loading any spec doc
+// (see DuplicateSnapshot.Report).
+type DuplicateSnapshot struct {
+	ID        string            `json:"id" gorm:"column:id;primaryKey"`
+	Report    *DuplicateReport  `json:"report" gorm:"column:report"`
+	Compared  DuplicateCompared `json:"compared" gorm:"column:compared"`
+	UpdatedAt time.Time         `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// DuplicateCompared maps the IDs of the services compared to their number of component schemas & endpoints compared.
+type DuplicateCompared map[string]*DuplicateCount
+
+// DuplicateCount represents the number of component schemas & endpoints of a spec compared.
+type DuplicateCount struct {
+	Schemas   int `json:"schemas"`
+	Endpoints int `json:"endpoints"`
+}
+
+// DuplicateCluster represents schemas or endpoints of different services which duplicate each other.
+type DuplicateCluster struct {
+	ID         string              `json:"id"` // Derived from the members, so stable as long as they are.
+	Kind       string              `json:"kind"`
+	Name       string              `json:"name"`       // The name of the canonical schema, or method & path of the canonical endpoint.
+	Exact      bool                `json:"exact"`      // Whether all members are structurally identical.
+	Similarity float64             `json:"similarity"` // The lowest similarity of a member to the canonical definition.
+	Services   int                 `json:"services"`
+	Members    []*DuplicateMember  `json:"members"`
+	Canonical  *DuplicateCanonical `json:"canonical"`
+}
+
+// DuplicateMember represents a schema or an endpoint of the latest spec of a service, member of a DuplicateCluster.
+type DuplicateMember struct {
+	ServiceID     string  `json:"service_id"`
+	ServiceNameID string  `json:"service_name_id"`
+	SpecID        string  `json:"spec_id"`
+	Version       string  `json:"version"`
+	Revision      string  `json:"revision"`
+	Name          string  `json:"name,omitempty"` // The schema name, if a schema.
+	Method        string  `json:"method,omitempty"`
+	Path          string  `json:"path,omitempty"`
+	JSONPath      string  `json:"json_path"`
+	Similarity    float64 `json:"similarity"` // The similarity to the canonical definition.
+
+	fingerprint string
+	properties  map[string]*DuplicateProperty // By normalized property name.
+	segments    []string                      // The normalized path segments.
+}
+
+// DuplicateCanonical represents the definition suggested to be shared by the members of a DuplicateCluster,
+// made of the most common name (or path) & of the properties common to most of the members.
+type DuplicateCanonical struct {
+	Name       string               `json:"name,omitempty"`
+	Method     string               `json:"method,omitempty"`
+	Path       string               `json:"path,omitempty"`
+	Properties []*DuplicateProperty `json:"properties,omitempty"`
+	Definition *openapi3.Schema     `json:"definition,omitempty"`
+}
+
+// DuplicateProperty represents a (top-level) property of a schema.
+type DuplicateProperty struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`                  // e.g. string, object or array<string>.
+	Occurrences int    `json:"occurrences,omitempty"` // The number of members with the property, if canonical.
+}
+
+// DuplicateFindings represents the duplicates of the schemas & endpoints of a service within other services.
+type DuplicateFindings struct {
+	ServiceID string              `json:"service_id"`
+	Threshold float64             `json:"threshold"`
+	Findings  []*DuplicateFinding `json:"findings"`
+}
+
+// DuplicateFinding represents a schema or an endpoint of a service duplicated within other services.
+type DuplicateFinding struct {
+	ClusterID  string              `json:"cluster_id"`
+	Kind       string              `json:"kind"`
+	Name       string              `json:"name"` // The schema name, or method & path of the endpoint.
+	JSONPath   string              `json:"json_path"`
+	Exact      bool                `json:"exact"`
+	Similarity float64             `json:"similarity"` // The similarity to the canonical definition.
+	Matches    []*DuplicateMember  `json:"matches"`    // The members of other services.
+	Canonical  *DuplicateCanonical `json:"canonical"`
+}
+
+// ValidDuplicateKind checks if kind is one of DuplicateKinds.
+func ValidDuplicateKind(kind string) bool {
+	for _, k := range DuplicateKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDuplicateReport compares the component schemas & endpoints of specs, the latest spec of each of services,
+// clustering those of different services with a similarity of at least threshold.
+// Only OpenAPI specs are compared: the others are reported as skipped.
+func NewDuplicateReport(ctx context.Context, services map[string]*Service, specs []*Spec, threshold float64) *DuplicateReport {
+	m := &DuplicateReport{Threshold: threshold, Skipped: []string{}, Clusters: []*DuplicateCluster{}}
+
+	m.compared = DuplicateCompared{}
+
+	var schemas, endpoints []*DuplicateMember
+	for _, spec := range specs {
+		if spec.Doc == nil || spec.DocKind() != SpecDocKindOpenAPI {
+			m.Skipped = append(m.Skipped, spec.ServiceID)
+			continue
+		}
+		doc, err := spec.LoadDocAsOAS(ctx, false, false, false)
+		if err != nil {
+			m.Skipped = append(m.Skipped, spec.ServiceID)
+			continue
+		}
+		m.Services++
+		count := &DuplicateCount{}
+		m.compared[spec.ServiceID] = count
+
+		member := func() *DuplicateMember {
+			mb := &DuplicateMember{ServiceID: spec.ServiceID, SpecID: spec.ID, Version: spec.Version, Revision: spec.Revision}
+			if service := services[spec.ServiceID]; service != nil {
+				mb.ServiceNameID = service.NameID
+			}
+			return mb
+		}
+		for name, schemaRef := range doc.Components.Schemas {
+			if schemaRef == nil || schemaRef.Value == nil {
+				continue
+			}
+			mb := member()
+			mb.Name = name
+			mb.JSONPath = specSearchJSONPath("$.components.schemas", name)
+			mb.properties = newDuplicateProperties(schemaRef.Value)
+			if len(mb.properties) < DuplicateMinProperties {
+				continue
+			}
+			mb.fingerprint = duplicatePropertiesFingerprint(mb.properties)
+			schemas = append(schemas, mb)
+			count.Schemas++
+		}
+		for path, pathItem := range doc.Paths {
+			for method := range pathItem.Operations() {
+				mb := member()
+				mb.Method = method
+				mb.Path = path
+				mb.JSONPath = specSearchJSONPath("$.paths", path, strings.ToLower(method))
+				mb.segments = newDuplicatePathSegments(path)
+				mb.fingerprint = method + " /" + strings.Join(mb.segments, "/")
+				endpoints = append(endpoints, mb)
+				count.Endpoints++
+			}
+		}
+	}
+	sort.Strings(m.Skipped)
+	m.Schemas, m.Endpoints = len(schemas), len(endpoints)
+
+	for _, members := range clusterDuplicates(schemas, threshold, schemaSimilarity) {
+		m.Clusters = append(m.Clusters, newSchemaDuplicateCluster(members))
+	}
+	for _, members := range clusterDuplicates(endpoints, threshold, endpointSimilarity) {
+		m.Clusters = append(m.Clusters, newEndpointDuplicateCluster(members))
+	}
+	sortDuplicateClusters(m.Clusters)
+	return m
+}
+
+// sortDuplicateClusters sorts clusters by decreasing number of services & similarity.
+func sortDuplicateClusters(clusters []*DuplicateCluster) {
+	sort.SliceStable(clusters, func(i, j int) bool {
+		a, b := clusters[i], clusters[j]
+		if a.Services != b.Services {
+			return a.Services > b.Services
+		}
+		if a.Similarity != b.Similarity {
+			return a.Similarity > b.Similarity
+		}
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind
+		}
+		return a.Name < b.Name
+	})
+}
+
+// NewDuplicateSnapshot constructs a new DuplicateSnapshot of specs, the latest spec of each of services
+// (see NewDuplicateReport).
+func NewDuplicateSnapshot(ctx context.Context, services map[string]*Service, specs []*Spec) *DuplicateSnapshot {
+	report := NewDuplicateReport(ctx, services, specs, DefaultDuplicateThreshold)
+	return &DuplicateSnapshot{ID: DuplicateSnapshotID, Report: report, Compared: report.compared}
+}
+
+// TableName implements gorm Tabler interface
+func (m *DuplicateSnapshot) TableName() string {
+	return DuplicateSnapshotTableName
+}
+
+// Filter returns the report of m restricted to the services with serviceIDs, e.g. those accessible to a user:
+// their clusters keep the members of these services, if of at least 2 services.
+// A threshold above that of the report also drops the members less similar to the canonical definition.
+func (m *DuplicateSnapshot) Filter(serviceIDs map[string]bool, threshold float64) *DuplicateReport {
+	report := &DuplicateReport{Threshold: threshold, Skipped: []string{}, Clusters: []*DuplicateCluster{}}
+	for _, serviceID := range m.Report.Skipped {
+		if serviceIDs[serviceID] {
+			report.Skipped = append(report.Skipped, serviceID)
+		}
+	}
+	for serviceID, count := range m.Compared {
+		if serviceIDs[serviceID] {
+			report.Services++
+			report.Schemas += count.Schemas
+			report.Endpoints += count.Endpoints
+		}
+	}
+
+	for _, cluster := range m.Report.Clusters {
+		filtered := *cluster
+		filtered.Members, filtered.Similarity = nil, 1
+		services := map[string]bool{}
+		for _, mb := range cluster.Members {
+			if !serviceIDs[mb.ServiceID] || (threshold > m.Report.Threshold && mb.Similarity < threshold) {
+				continue
+			}
+			filtered.Members = append(filtered.Members, mb)
+			if mb.Similarity < filtered.Similarity {
+				filtered.Similarity = mb.Similarity
+			}
+			services[mb.ServiceID] = true
+		}
+		if len(services) < 2 {
+			continue
+		}
+		filtered.Services = len(services)
+		report.Clusters = append(report.Clusters, &filtered)
+	}
+	sortDuplicateClusters(report.Clusters)
+	return report
+}
+
+// Scan implements sql.Scanner interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m *DuplicateReport) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &m)
+}
+
+// Value implements driver.Valuer interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m *DuplicateReport) Value() (driver.Value, error) { return json.Marshal(m) }
+
+// Scan implements sql.Scanner interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m *DuplicateCompared) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &m)
+}
+
+// Value implements driver.Valuer interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m DuplicateCompared) Value() (driver.Value, error) { return json.Marshal(m) }
+
+// Findings returns the clusters of m with members of the service with serviceID, as findings of the service.
+func (m *DuplicateReport) Findings(serviceID string) *DuplicateFindings {
+	findings := &DuplicateFindings{ServiceID: serviceID, Threshold: m.Threshold, Findings: []*DuplicateFinding{}}
+	for _, cluster := range m.Clusters {
+		var matches []*DuplicateMember
+		for _, mb := range cluster.Members {
+			if mb.ServiceID != serviceID {
+				matches = append(matches, mb)
+			}
+		}
+		for _, mb := range cluster.Members {
+			if mb.ServiceID != serviceID {
+				continue
+			}
+			finding := &DuplicateFinding{
+				ClusterID:  cluster.ID,
+				Kind:       cluster.Kind,
+				Name:       mb.Name,
+				JSONPath:   mb.JSONPath,
+				Exact:      cluster.Exact,
+				Similarity: mb.Similarity,
+				Matches:    matches,
+				Canonical:  cluster.Canonical,
+			}
+			if cluster.Kind == DuplicateKindEndpoint {
+				finding.Name = mb.Method + " " + mb.Path
+			}
+			findings.Findings = append(findings.Findings, finding)
+		}
+	}
+	return findings
+}
+
+// FilterKind removes the clusters of m not of kind.
+func (m *DuplicateReport) FilterKind(kind string) {
+	clusters := make([]*DuplicateCluster, 0, len(m.Clusters))
+	for _, cluster := range m.Clusters {
+		if cluster.Kind == kind {
+			clusters = append(clusters, cluster)
+		}
+	}
+	m.Clusters = clusters
+}
+
+// clusterDuplicates groups members linked by a similarity of at least threshold, between members of different
+// services (single-linkage), returning the groups of more than one member.
+func clusterDuplicates(members []*DuplicateMember, threshold float64, similarity func(a, b *DuplicateMember) float64) [][]*DuplicateMember {
+	parents := make([]int, len(members))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	for i := 0; i < len(members); i++ {
+		for j := i + 1; j < len(members); j++ {
+			if members[i].ServiceID == members[j].ServiceID || find(i) == find(j) {
+				continue
+			}
+			if similarity(members[i], members[j]) >= threshold {
+				parents[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := map[int][]*DuplicateMember{}
+	var roots []int
+	for i, mb := range members {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], mb)
+	}
+	var clusters [][]*DuplicateMember
+	for _, root := range roots {
+		if len(groups[root]) > 1 {
+			clusters = append(clusters, groups[root])
+		}
+	}
+	return clusters
+}
+
+func newSchemaDuplicateCluster(members []*DuplicateMember) *DuplicateCluster {
+	names := make([]string, 0, len(members))
+	for _, mb := range members {
+		names = append(names, mb.Name)
+	}
+	canonical := &DuplicateCanonical{Name: mostCommonDuplicateValue(names)}
+
+	// The canonical properties are those of at least half of the members, of their most common name & type.
+	occurrences := map[string][]*DuplicateProperty{}
+	for _, mb := range members {
+		for key, prop := range mb.properties {
+			occurrences[key] = append(occurrences[key], prop)
+		}
+	}
+	canonicalMember := &DuplicateMember{properties: map[string]*DuplicateProperty{}}
+	for key, props := range occurrences {
+		if 2*len(props) < len(members) {
+			continue
+		}
+		names, types := make([]string, 0, len(props)), make([]string, 0, len(props))
+		for _, prop := range props {
+			names = append(names, prop.Name)
+			types = append(types, prop.Type)
+		}
+		prop := &DuplicateProperty{Name: mostCommonDuplicateValue(names), Type: mostCommonDuplicateValue(types), Occurrences: len(props)}
+		canonical.Properties = append(canonical.Properties, prop)
+		canonicalMember.properties[key] = prop
+	}
+	sort.Slice(canonical.Properties, func(i, j int) bool {
+		return canonical.Properties[i].Name < canonical.Properties[j].Name
+	})
+	canonical.Definition = &openapi3.Schema{Type: "object", Properties: openapi3.Schemas{}}
+	for _, prop := range canonical.Properties {
+		canonical.Definition.Properties[prop.Name] = openapi3.NewSchemaRef("", newDuplicatePropertySchema(prop.Type))
+	}
+
+	return newDuplicateCluster(DuplicateKindSchema, canonical.Name, canonical, members, func(mb *DuplicateMember) float64 {
+		return schemaSimilarity(mb, canonicalMember)
+	})
+}
+
+func newEndpointDuplicateCluster(members []*DuplicateMember) *DuplicateCluster {
+	methods, paths := make([]string, 0, len(members)), make([]string, 0, len(members))
+	for _, mb := range members {
+		methods = append(methods, mb.Method)
+		paths = append(paths, mb.Path)
+	}
+	canonical := &DuplicateCanonical{Method: mostCommonDuplicateValue(methods), Path: mostCommonDuplicateValue(paths)}
+	canonicalMember := &DuplicateMember{Method: canonical.Method, segments: newDuplicatePathSegments(canonical.Path)}
+
+	return newDuplicateCluster(DuplicateKindEndpoint, canonical.Method+" "+canonical.Path, canonical, members, func(mb *DuplicateMember) float64 {
+		return endpointSimilarity(mb, canonicalMember)
+	})
+}
+
+func newDuplicateCluster(kind, name string, canonical *DuplicateCanonical, members []*DuplicateMember, similarity func(mb *DuplicateMember) float64) *DuplicateCluster {
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].ServiceNameID != members[j].ServiceNameID {
+			return members[i].ServiceNameID < members[j].ServiceNameID
+		}
+		return members[i].JSONPath < members[j].JSONPath
+	})
+
+	m := &DuplicateCluster{Kind: kind, Name: name, Exact: true, Similarity: 1, Members: members, Canonical: canonical}
+	services := map[string]bool{}
+	hash := sha1.New()
+	for _, mb := range members {
+		mb.Similarity = roundScore(similarity(mb))
+		if mb.Similarity < m.Similarity {
+			m.Similarity = mb.Similarity
+		}
+		if mb.fingerprint != members[0].fingerprint {
+			m.Exact = false
+		}
+		services[mb.ServiceID] = true
+		_, _ = fmt.Fprintf(hash, "%s %s\n", mb.ServiceID, mb.JSONPath)
+	}
+	m.Services = len(services)
+	m.ID = hex.EncodeToString(hash.Sum(nil))[:12]
+	return m
+}
+
+// schemaSimilarity scores the similarity of the properties of schemas a & b, from 0 to 1: each property of either
+// scores 1 if both have it with the same type, 0.5 if with different types, 0 otherwise.
+func schemaSimilarity(a, b *DuplicateMember) float64 {
+	var union, score float64
+	for key, propA := range a.properties {
+		union++
+		if propB, ok := b.properties[key]; ok {
+			if propA.Type == propB.Type {
+				score++
+			} else {
+				score += 0.5
+			}
+		}
+	}
+	for key := range b.properties {
+		if _, ok := a.properties[key]; !ok {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return score / union
+}
+
+// endpointSimilarity scores the similarity of the paths of endpoints a & b of the same method, from 0 to 1,
+// as the share of their (normalized) path segments matching one another in order.
+func endpointSimilarity(a, b *DuplicateMember) float64 {
+	if a.Method != b.Method {
+		return 0
+	}
+	n := len(a.segments)
+	if len(b.segments) > n {
+		n = len(b.segments)
+	}
+	if n == 0 {
+		return 1
+	}
+	var matches float64
+	for i := 0; i < len(a.segments) && i < len(b.segments); i++ {
+		if a.segments[i] == b.segments[i] {
+			matches++
+		}
+	}
+	return matches / float64(n)
+}
+
+// newDuplicateProperties returns the top-level properties of schema (& of its allOf schemas), by normalized name,
+// so that e.g. zipCode & zip_code are the same property.
+func newDuplicateProperties(schema *openapi3.Schema) map[string]*DuplicateProperty {
+	props := map[string]*DuplicateProperty{}
+	for _, allOf := range schema.AllOf {
+		if allOf != nil && allOf.Value != nil {
+			for key, prop := range newDuplicateProperties(allOf.Value) {
+				props[key] = prop
+			}
+		}
+	}
+	for name, propRef := range schema.Properties {
+		if propRef == nil || propRef.Value == nil {
+			continue
+		}
+		props[normalizeDuplicateName(name)] = &DuplicateProperty{Name: name, Type: duplicateSchemaType(propRef.Value)}
+	}
+	return props
+}
+
+// duplicatePropertiesFingerprint returns the structural fingerprint of props: the hash of their names & types.
+func duplicatePropertiesFingerprint(props map[string]*DuplicateProperty) string {
+	keys := make([]string, 0, len(props))
+	for key, prop := range props {
+		keys = append(keys, key+":"+prop.Type)
+	}
+	sort.Strings(keys)
+	sum := sha1.Sum([]byte(strings.Join(keys, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// duplicateSchemaType returns the type of schema, e.g. string, object or array<string>.
+func duplicateSchemaType(schema *openapi3.Schema) string {
+	switch {
+	case schema.Type == "array" && schema.Items != nil && schema.Items.Value != nil:
+		return "array<" + duplicateSchemaType(schema.Items.Value) + ">"
+	case schema.Type != "":
+		return schema.Type
+	case len(schema.Properties) > 0 || len(schema.AllOf) > 0:
+		return "object"
+	default:
+		return "any"
+	}
+}
+
+// newDuplicatePropertySchema returns the schema of type t (see duplicateSchemaType).
+func newDuplicatePropertySchema(t string) *openapi3.Schema {
+	if strings.HasPrefix(t, "array<") && strings.HasSuffix(t, ">") {
+		return openapi3.NewArraySchema().WithItems(newDuplicatePropertySchema(strings.TrimSuffix(strings.TrimPrefix(t, "array<"), ">")))
+	}
+	if t == "any" {
+		return &openapi3.Schema{}
+	}
+	return &openapi3.Schema{Type: t}
+}
+
+// newDuplicatePathSegments normalizes path into its segments, lower-cased, without version segments (e.g. v1)
+// & with path parameters of any name as {}.
+func newDuplicatePathSegments(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(strings.ToLower(path), "/") {
+		switch {
+		case segment == "" || duplicateVersionSegment.MatchString(segment):
+			continue
+		case specEndpointPathParam.MatchString(segment):
+			segments = append(segments, specEndpointPathParam.ReplaceAllString(segment, "{}"))
+		default:
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// normalizeDuplicateName lower-cases name without separators, e.g. zip_code, zip-code & zipCode are zipcode.
+func normalizeDuplicateName(name string) string {
+	return duplicateNameSeparators.ReplaceAllString(strings.ToLower(name), "")
+}
+
+// mostCommonDuplicateValue returns the most common of values, the shortest & first alphabetically on ties.
+func mostCommonDuplicateValue(values []string) string {
+	counts := map[string]int{}
+	for _, v := range values {
+		counts[v]++
+	}
+	var best string
+	for v, count := range counts {
+		if best == "" || count > counts[best] ||
+			count == counts[best] && (len(v) < len(best) || len(v) == len(best) && v < best) {
+			best = v
+		}
+	}
+	return best
+}
@@ -0,0 +1,224 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDuplicateSpecs returns services & their latest specs, with duplicated & near-duplicated schemas & endpoints.
+func testDuplicateSpecs() (map[string]*Service, []*Spec) {
+	newSpec := func(serviceID, doc string) *Spec {
+		specDoc := SpecDoc(&doc)
+		return &Spec{ID: serviceID + "-spec", ServiceID: serviceID, Version: "1.0.0", Revision: "1", Doc: specDoc}
+	}
+	services := map[string]*Service{
+		"orders":   {ID: "orders", NameID: "orders"},
+		"payments": {ID: "payments", NameID: "payments"},
+		"shipping": {ID: "shipping", NameID: "shipping"},
+	}
+	specs := []*Spec{
+		newSpec("orders", `openapi: 3.0.0
+info: {title: Orders, version: 1.0.0}
+paths:
+  /v1/orders/{orderId}:
+    get:
+      responses: {'200': {description: OK}}
+components:
+  schemas:
+    Address:
+      type: object
+      properties:
+        street: {type: string}
+        city: {type: string}
+        zip_code: {type: string}
+        country: {type: string}
+    Money:
+      type: object
+      properties:
+        amount: {type: number}
+        currency: {type: string}
+`),
+		newSpec("payments", `openapi: 3.0.0
+info: {title: Payments, version: 1.0.0}
+paths:
+  /v2/orders/{id}:
+    get:
+      responses: {'200': {description: OK}}
+  /v2/payments:
+    post:
+      responses: {'201': {description: Created}}
+components:
+  schemas:
+    BillingAddress:
+      type: object
+      properties:
+        street: {type: string}
+        city: {type: string}
+        zipCode: {type: string}
+        country: {type: string}
+        state: {type: string}
+    Amount:
+      type: object
+      properties:
+        amount: {type: number}
+        currency: {type: string}
+`),
+		newSpec("shipping", `openapi: 3.0.0
+info: {title: Shipping, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Address:
+      type: object
+      properties:
+        street: {type: string}
+        city: {type: string}
+        zip: {type: integer}
+        country: {type: string}
+    Parcel:
+      type: object
+      properties:
+        weight: {type: number}
+        tags: {type: array, items: {type: string}}
+`),
+		{ID: "proto-spec", ServiceID: "proto", DocType: "protobuf-3", Doc: SpecDoc(new(string))},
+	}
+	return services, specs
+}
+
+func TestNewDuplicateReport(t *testing.T) {
+	services, specs := testDuplicateSpecs()
+	report := NewDuplicateReport(context.Background(), services, specs, DefaultDuplicateThreshold)
+	assert.Equal(t, 3, report.Services)
+	assert.Equal(t, []string{"proto"}, report.Skipped)
+	assert.Equal(t, 6, report.Schemas)
+	assert.Equal(t, 3, report.Endpoints)
+	require.Len(t, report.Clusters, 3)
+
+	find := func(name string) *DuplicateCluster {
+		for _, c := range report.Clusters {
+			if c.Name == name {
+				return c
+			}
+		}
+		t.Fatalf("cluster %s not found", name)
+		return nil
+	}
+
+	money := find("Money")
+	assert.Equal(t, DuplicateKindSchema, money.Kind)
+	assert.True(t, money.Exact)
+	assert.Equal(t, 1.0, money.Similarity)
+	assert.Equal(t, 2, money.Services)
+
+	// Address & BillingAddress are near-duplicates (zip_code & zipCode being the same property), unlike the shipping
+	// Address of a zip integer.
+	address := find("Address")
+	assert.False(t, address.Exact)
+	assert.Equal(t, 2, address.Services)
+	require.Len(t, address.Members, 2)
+	assert.Equal(t, "$.components.schemas.Address", address.Members[0].JSONPath)
+	assert.Equal(t, "orders", address.Members[0].ServiceID)
+	assert.Equal(t, "BillingAddress", address.Members[1].Name)
+	var props []string
+	for _, prop := range address.Canonical.Properties {
+		props = append(props, prop.Name+":"+prop.Type)
+	}
+	assert.Equal(t, []string{"city:string", "country:string", "state:string", "street:string", "zipCode:string"}, props)
+	assert.Contains(t, address.Canonical.Definition.Properties, "zipCode")
+	assert.Equal(t, 0.8, address.Similarity)
+
+	endpoint := find("GET /v2/orders/{id}")
+	assert.Equal(t, DuplicateKindEndpoint, endpoint.Kind)
+	assert.True(t, endpoint.Exact)
+	assert.Len(t, endpoint.ID, 12)
+
+	findings := report.Findings("payments")
+	assert.Len(t, findings.Findings, 3)
+	for _, finding := range findings.Findings {
+		require.Len(t, finding.Matches, 1)
+		assert.Equal(t, "orders", finding.Matches[0].ServiceID)
+	}
+	assert.Empty(t, report.Findings("shipping").Findings)
+
+	report.FilterKind(DuplicateKindEndpoint)
+	assert.Len(t, report.Clusters, 1)
+}
+
+func TestDuplicateSnapshot_Filter(t *testing.T) {
+	services, specs := testDuplicateSpecs()
+	snapshot := NewDuplicateSnapshot(context.Background(), services, specs)
+	assert.Equal(t, DuplicateSnapshotID, snapshot.ID)
+	assert.Equal(t, DuplicateCompared{
+		"orders":   {Schemas: 2, Endpoints: 1},
+		"payments": {Schemas: 2, Endpoints: 2},
+		"shipping": {Schemas: 2},
+	}, snapshot.Compared)
+
+	// The snapshot is read back from its stored columns.
+	value, err := snapshot.Report.Value()
+	require.NoError(t, err)
+	stored := &DuplicateSnapshot{Report: &DuplicateReport{}}
+	require.NoError(t, stored.Report.Scan(value))
+	value, err = snapshot.Compared.Value()
+	require.NoError(t, err)
+	require.NoError(t, stored.Compared.Scan(value))
+
+	all := map[string]bool{"orders": true, "payments": true, "shipping": true, "proto": true}
+	clusterIDs := func(clusters []*DuplicateCluster) []string {
+		var ids []string
+		for _, cluster := range clusters {
+			ids = append(ids, cluster.ID)
+		}
+		return ids
+	}
+	report := stored.Filter(all, DefaultDuplicateThreshold)
+	assert.Equal(t, clusterIDs(snapshot.Report.Clusters), clusterIDs(report.Clusters))
+	assert.Equal(t, 3, report.Services)
+	assert.Equal(t, []string{"proto"}, report.Skipped)
+	assert.Equal(t, 6, report.Schemas)
+	assert.Equal(t, 3, report.Endpoints)
+
+	// The clusters keep the members of the accessible services, if of at least 2 of them.
+	report = stored.Filter(map[string]bool{"payments": true, "shipping": true}, DefaultDuplicateThreshold)
+	assert.Equal(t, 2, report.Services)
+	assert.Empty(t, report.Skipped)
+	assert.Empty(t, report.Clusters)
+
+	// A higher threshold drops the members less similar to the canonical definition, e.g. the orders Address.
+	report = stored.Filter(all, 0.9)
+	assert.Equal(t, 0.9, report.Threshold)
+	require.Len(t, report.Clusters, 2)
+	for _, cluster := range report.Clusters {
+		assert.NotEqual(t, "Address", cluster.Name)
+	}
+	assert.Len(t, stored.Report.Clusters, 3)
+}
+
+func TestEndpointSimilarity(t *testing.T) {
+	newMember := func(method, path string) *DuplicateMember {
+		return &DuplicateMember{Method: method, segments: newDuplicatePathSegments(path)}
+	}
+	assert.Equal(t, 1.0, endpointSimilarity(newMember("GET", "/v1/Orders/{id}"), newMember("GET", "/orders/{orderId}")))
+	assert.Equal(t, 0.0, endpointSimilarity(newMember("GET", "/orders"), newMember("POST", "/orders")))
+	assert.InDelta(t, 2.0/3, endpointSimilarity(newMember("GET", "/orders/{id}/items"), newMember("GET", "/orders/{id}")), 0.001)
+}
@@ -0,0 +1,276 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SpecEndpointTableName = "spec_endpoints"
+)
+
+// SpecEndpoint represents an operation of a spec, as an entry of the endpoint catalog.
+type SpecEndpoint struct {
+	SpecID string `json:"spec_id" gorm:"column:spec_id;primaryKey"`
+	// EndpointID identifies the endpoint of the service across its specs, whatever its path parameters are named
+	// (see NewSpecEndpointID).
+	EndpointID           string        `json:"endpoint_id" gorm:"column:endpoint_id;primaryKey;index"`
+	ServiceID            string        `json:"service_id" gorm:"column:service_id;index"`
+	Method               string        `json:"method" gorm:"column:method;index"`
+	Path                 string        `json:"path" gorm:"column:path;index"`
+	OperationID          string        `json:"operation_id" gorm:"column:operation_id;index"`
+	Summary              string        `json:"summary" gorm:"column:summary"`
+	Tags                 DelimitedList `json:"tags" gorm:"column:tags"`
+	Deprecated           bool          `json:"deprecated" gorm:"column:deprecated;index"`
+	SecuritySchemes      DelimitedList `json:"security_schemes" gorm:"column:security_schemes"`
+	RequestContentTypes  DelimitedList `json:"request_content_types" gorm:"column:request_content_types"`
+	ResponseContentTypes DelimitedList `json:"response_content_types" gorm:"column:response_content_types"`
+	Latest               bool          `json:"latest" gorm:"column:latest;index"` // Whether the spec is the latest of its service.
+	SpecCreatedAt        time.Time     `json:"spec_created_at" gorm:"column:spec_created_at"`
+}
+
+// SpecEndpointResponse wrappers spec endpoint response
+type SpecEndpointResponse struct {
+	Pagination
+	Data []*SpecEndpoint `json:"data"`
+}
+
+// SpecEndpointFilter represents the filters of SpecEndpoint(s) beyond their indexes (see SpecEndpoint.GetIndexes).
+type SpecEndpointFilter struct {
+	ServiceIDs     []string // Restricts SpecEndpoint(s) to these services.
+	PathPrefix     string
+	Tag            string
+	SecurityScheme string
+	ContentType    string // Matches either request or response content types.
+}
+
+// TableName implements gorm Tabler interface
+func (m *SpecEndpoint) TableName() string {
+	return SpecEndpointTableName
+}
+
+// GetID returns the ID of specEndpoint object
+func (m *SpecEndpoint) GetID() string {
+	return fmt.Sprintf("%v/%v", m.SpecID, m.EndpointID)
+}
+
+// String returns the text representation of specEndpoint object
+func (m *SpecEndpoint) String() string {
+	return fmt.Sprintf("%v", *m)
+}
+
+// GetIndex returns an index for specific field
+func (m *SpecEndpoint) GetIndex(field string) string {
+	return m.GetIndexes()[field]
+}
+
+// GetIndexes returns all the field indexes
+func (m *SpecEndpoint) GetIndexes() map[string]string {
+	return map[string]string{
+		"spec_id":      "idx_spec_id",
+		"endpoint_id":  "idx_endpoint_id",
+		"service_id":   "idx_service_id",
+		"method":       "idx_method",
+		"operation_id": "idx_operation_id",
+		"deprecated":   "idx_deprecated",
+		"latest":       "idx_latest",
+	}
+}
+
+// GetIndexValue return index value for specified field
+func (m *SpecEndpoint) GetIndexValue(field string) string {
+	return m.GetIndexValues()[field]
+}
+
+// GetIndexValues return all field index values
+func (m *SpecEndpoint) GetIndexValues() map[string]string {
+	return map[string]string{
+		"spec_id":      m.SpecID,
+		"endpoint_id":  m.EndpointID,
+		"service_id":   m.ServiceID,
+		"method":       m.Method,
+		"operation_id": m.OperationID,
+		"deprecated":   fmt.Sprintf("%v", m.Deprecated),
+		"latest":       fmt.Sprintf("%v", m.Latest),
+	}
+}
+
+// Sortable checks if field is sortable.
+func (m *SpecEndpoint) Sortable(field string) bool {
+	_, found := m.SortableFields()[field]
+	return found
+}
+
+// SortableFields returns all sortable fields
+func (m *SpecEndpoint) SortableFields() map[string]struct{} {
+	return map[string]struct{}{
+		"path":            {},
+		"method":          {},
+		"operation_id":    {},
+		"spec_created_at": {},
+	}
+}
+
+var specEndpointPathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// NewSpecEndpointID derives a stable identifier of the endpoint method & path of the service with serviceID,
+// so that renaming path parameters (e.g. /pets/{id} & /pets/{petId}) doesn't change it.
+func NewSpecEndpointID(serviceID, method, path string) string {
+	sum := sha1.Sum([]byte(serviceID + " " + strings.ToUpper(method) + " " + specEndpointPathParam.ReplaceAllString(path, "{}")))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewSpecEndpoints decomposes spec's Spec.Doc into its SpecEndpoint(s). Only OpenAPI docs have endpoints.
+func NewSpecEndpoints(ctx context.Context, spec *Spec) ([]*SpecEndpoint, error) {
+	if spec.Doc == nil || spec.DocKind() != SpecDocKindOpenAPI {
+		return nil, nil
+	}
+	doc, err := spec.LoadDocAsOAS(ctx, false, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []*SpecEndpoint
+	for path, pathItem := range doc.Paths {
+		for method, op := range pathItem.Operations() {
+			endpoint := &SpecEndpoint{
+				SpecID:        spec.ID,
+				EndpointID:    NewSpecEndpointID(spec.ServiceID, method, path),
+				ServiceID:     spec.ServiceID,
+				Method:        method,
+				Path:          path,
+				OperationID:   op.OperationID,
+				Summary:       op.Summary,
+				Tags:          DelimitedList(op.Tags),
+				Deprecated:    op.Deprecated,
+				SpecCreatedAt: spec.CreatedAt,
+			}
+
+			security := doc.Security
+			if op.Security != nil {
+				security = *op.Security
+			}
+			schemes := map[string]bool{}
+			for _, requirement := range security {
+				for scheme := range requirement {
+					schemes[scheme] = true
+				}
+			}
+			endpoint.SecuritySchemes = newDelimitedListFromSet(schemes)
+
+			contentTypes := map[string]bool{}
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				for contentType := range op.RequestBody.Value.Content {
+					contentTypes[contentType] = true
+				}
+			}
+			endpoint.RequestContentTypes = newDelimitedListFromSet(contentTypes)
+
+			contentTypes = map[string]bool{}
+			for _, response := range op.Responses {
+				if response.Value == nil {
+					continue
+				}
+				for contentType := range response.Value.Content {
+					contentTypes[contentType] = true
+				}
+			}
+			endpoint.ResponseContentTypes = newDelimitedListFromSet(contentTypes)
+
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+	return endpoints, nil
+}
+
+// DelimitedList represents a list of strings, stored delimited by (& enclosed in) commas, e.g. ",a,b,",
+// so that its items can be matched with a LIKE pattern (see DelimitedListPattern).
+type DelimitedList []string
+
+func newDelimitedListFromSet(set map[string]bool) DelimitedList {
+	list := make(DelimitedList, 0, len(set))
+	for item := range set {
+		list = append(list, item)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// DelimitedListPattern returns the LIKE pattern matching DelimitedList(s) containing item.
+func DelimitedListPattern(item string) string {
+	item = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, ",", "").Replace(item)
+	return "%," + item + ",%"
+}
+
+// Scan implements sql.Scanner interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m *DelimitedList) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case nil:
+	default:
+		return fmt.Errorf("failed to unmarshal delimited list value: %v", value)
+	}
+	*m = DelimitedList{}
+	for _, item := range strings.Split(strings.Trim(s, ","), ",") {
+		if item != "" {
+			*m = append(*m, item)
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m DelimitedList) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return "", nil
+	}
+	items := make([]string, 0, len(m))
+	for _, item := range m {
+		items = append(items, strings.ReplaceAll(item, ",", ""))
+	}
+	return "," + strings.Join(items, ",") + ",", nil
+}
+
+// MarshalJSON implements json.Marshaler interface, marshaling nil lists as empty.
+func (m DelimitedList) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(m))
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSpecEndpoints(t *testing.T) {
+	doc := SpecDoc(new(string))
+	*doc = `openapi: 3.0.0
+info:
+  title: Orders API
+  version: 1.0.0
+security:
+  - oauth2: []
+paths:
+  /v1/orders/{orderId}:
+    get:
+      operationId: getOrder
+      summary: Get an order
+      tags: [orders]
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+    put:
+      operationId: updateOrder
+      deprecated: true
+      security:
+        - apiKey: []
+        - basic: []
+      requestBody:
+        content:
+          application/merge-patch+json:
+            schema:
+              type: object
+          application/json:
+            schema:
+              type: object
+      responses:
+        '204':
+          description: No Content
+`
+	spec := &Spec{ID: "spec", ServiceID: "svc", Doc: doc}
+	endpoints, err := NewSpecEndpoints(context.Background(), spec)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+
+	get, put := endpoints[0], endpoints[1]
+	assert.Equal(t, "GET", get.Method)
+	assert.Equal(t, "/v1/orders/{orderId}", get.Path)
+	assert.Equal(t, "getOrder", get.OperationID)
+	assert.Equal(t, DelimitedList{"orders"}, get.Tags)
+	assert.Equal(t, DelimitedList{"oauth2"}, get.SecuritySchemes)
+	assert.Equal(t, DelimitedList{}, get.RequestContentTypes)
+	assert.Equal(t, DelimitedList{"application/json"}, get.ResponseContentTypes)
+	assert.False(t, get.Deprecated)
+
+	assert.Equal(t, "PUT", put.Method)
+	assert.True(t, put.Deprecated)
+	assert.Equal(t, DelimitedList{"apiKey", "basic"}, put.SecuritySchemes)
+	assert.Equal(t, DelimitedList{"application/json", "application/merge-patch+json"}, put.RequestContentTypes)
+
+	assert.Equal(t, NewSpecEndpointID("svc", "get", "/v1/orders/{id}"), get.EndpointID)
+	assert.NotEqual(t, NewSpecEndpointID("other", "GET", "/v1/orders/{orderId}"), get.EndpointID)
+	assert.NotEqual(t, get.EndpointID, put.EndpointID)
+
+	endpoints, err = NewSpecEndpoints(context.Background(), &Spec{ID: "proto", Doc: doc, DocType: "protobuf-3"})
+	require.NoError(t, err)
+	assert.Empty(t, endpoints)
+}
+
+func TestDelimitedList(t *testing.T) {
+	value, err := DelimitedList{"a", "b,c"}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, ",a,bc,", value)
+
+	value, err = DelimitedList(nil).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "", value)
+
+	var list DelimitedList
+	require.NoError(t, list.Scan([]byte(",a,bc,")))
+	assert.Equal(t, DelimitedList{"a", "bc"}, list)
+	require.NoError(t, list.Scan(""))
+	assert.Equal(t, DelimitedList{}, list)
+	assert.Error(t, list.Scan(1))
+
+	assert.Equal(t, `%,a\_b,%`, DelimitedListPattern("a_b"))
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"sort"
+	"strconv"
+)
+
+// SpecFixRequest represents a request to apply the fixes of the findings of a spec's analyses (see analyzer.FindingFix).
+type SpecFixRequest struct {
+	// FixIDs selects fixes by their analyzer.FindingFix.ID.
+	FixIDs []string `json:"fix_ids,omitempty"`
+	// Rules selects the fixes of all findings of the rules.
+	Rules []rule.NameID `json:"rules,omitempty"`
+	// Save saves the fixed doc as a new spec revision.
+	Save bool `json:"save,omitempty"`
+	// Revision is the revision of the saved spec, defaults to the next revision of the fixed spec (see NextSpecRevision).
+	Revision string `json:"revision,omitempty"`
+}
+
+// Selects returns whether the request selects fix, i.e. all fixes if it selects no fix IDs nor rules.
+func (m *SpecFixRequest) Selects(fix *analyzer.FindingFix) bool {
+	if len(m.FixIDs) == 0 && len(m.Rules) == 0 {
+		return true
+	}
+	for _, id := range m.FixIDs {
+		if id == fix.ID {
+			return true
+		}
+	}
+	for _, ruleNameID := range m.Rules {
+		if ruleNameID == fix.Rule {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectedFixes returns the distinct fixes of the findings of specAnalyses which the request selects.
+func (m *SpecFixRequest) SelectedFixes(specAnalyses []*SpecAnalysis) []*analyzer.FindingFix {
+	var (
+		fixes []*analyzer.FindingFix
+		seen  = map[string]bool{}
+	)
+	for _, specAnalysis := range specAnalyses {
+		if specAnalysis == nil || specAnalysis.Result == nil {
+			continue
+		}
+		specAnalysis.Result.EachFinding(func(_ rule.SeverityName, _ rule.NameID, finding *analyzer.Finding) {
+			if finding.Fix == nil || seen[finding.Fix.ID] || !m.Selects(finding.Fix) {
+				return
+			}
+			seen[finding.Fix.ID] = true
+			fixes = append(fixes, finding.Fix)
+		})
+	}
+	sort.Slice(fixes, func(i, j int) bool { return fixes[i].ID < fixes[j].ID })
+	return fixes
+}
+
+// SpecFixApplyRequest represents a stateless request to apply fixes, e.g. of a SpecAnalysisResponse, to a doc.
+type SpecFixApplyRequest struct {
+	Doc   string                 `json:"doc" validate:"required"`
+	Fixes []*analyzer.FindingFix `json:"fixes"`
+}
+
+// SpecFixResponse represents the response of a SpecFixRequest which doesn't save the fixed doc, or of a SpecFixApplyRequest.
+type SpecFixResponse struct {
+	Doc     string   `json:"doc"`
+	Applied []string `json:"applied"`
+	Skipped []string `json:"skipped"`
+}
+
+// NextSpecRevision returns the revision following revision, i.e. incremented if it's a number.
+func NextSpecRevision(revision string) string {
+	if n, err := strconv.Atoi(revision); err == nil {
+		return strconv.Itoa(n + 1)
+	}
+	if revision == "" {
+		return "1"
+	}
+	return revision + "-fixed"
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecFixRequest_SelectedFixes(t *testing.T) {
+	var (
+		fixA = &analyzer.FindingFix{ID: "a", Rule: "info-description"}
+		fixB = &analyzer.FindingFix{ID: "b", Rule: "operation-operationId"}
+		fixC = &analyzer.FindingFix{ID: "c", Rule: "operation-operationId"}
+	)
+	newAnalysis := func(fixes ...*analyzer.FindingFix) *SpecAnalysis {
+		result := analyzer.NewResult()
+		findings := &analyzer.Findings{Data: []*analyzer.Finding{{}}}
+		for _, fix := range fixes {
+			findings.Data = append(findings.Data, &analyzer.Finding{Fix: fix})
+		}
+		result.Findings[rule.SeverityNameWarning].Rules = map[rule.NameID]*analyzer.Findings{"rule": findings}
+		return &SpecAnalysis{SpecAnalysisResult: SpecAnalysisResult{Result: result}}
+	}
+	specAnalyses := []*SpecAnalysis{newAnalysis(fixC, fixA), newAnalysis(fixB, fixA), {}}
+
+	tests := []struct {
+		name string
+		req  *SpecFixRequest
+		want []*analyzer.FindingFix
+	}{
+		{name: "all", req: &SpecFixRequest{}, want: []*analyzer.FindingFix{fixA, fixB, fixC}},
+		{name: "fix IDs", req: &SpecFixRequest{FixIDs: []string{"c"}}, want: []*analyzer.FindingFix{fixC}},
+		{name: "rules", req: &SpecFixRequest{Rules: []rule.NameID{"operation-operationId"}}, want: []*analyzer.FindingFix{fixB, fixC}},
+		{name: "fix IDs & rules", req: &SpecFixRequest{FixIDs: []string{"a"}, Rules: []rule.NameID{"oas3-api-servers"}}, want: []*analyzer.FindingFix{fixA}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.req.SelectedFixes(specAnalyses))
+		})
+	}
+}
+
+func TestNextSpecRevision(t *testing.T) {
+	assert.Equal(t, "2", NextSpecRevision("1"))
+	assert.Equal(t, "1", NextSpecRevision(""))
+	assert.Equal(t, "1.0-fixed", NextSpecRevision("1.0"))
+}
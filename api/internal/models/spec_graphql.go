@@ -0,0 +1,53 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/pkg/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	specDocTypeGraphQLPrefix = "graphql-"
+	specDocTypeGraphQLSDL    = specDocTypeGraphQLPrefix + "sdl"
+)
+
+// IsGraphQL checks if Spec.Doc is a GraphQL spec.
+func (m *Spec) IsGraphQL() bool {
+	return m.DocKind() == SpecDocKindGraphQL
+}
+
+// LoadDocAsGraphQL loads Spec.Doc as a GraphQL SDL spec & stores it as Spec.DocGraphQL.
+// Set setDocType to set Spec.DocType. GraphQL schemas are versionless, so Spec.Version is left as-is.
+func (m *Spec) LoadDocAsGraphQL(setDocType bool) (*ast.Schema, error) {
+	if m.DocGraphQL != nil {
+		return m.DocGraphQL, nil
+	}
+	if m.Doc == nil || len(*m.Doc) == 0 {
+		return nil, fmt.Errorf("spec: missing Spec.Doc")
+	}
+	var err error
+	m.DocGraphQL, err = graphql.Load([]byte(*m.Doc))
+	if err != nil {
+		return nil, fmt.Errorf("spec: failed to load Spec.Doc as GraphQL: %v", err)
+	}
+	if setDocType && m.DocType == "" {
+		m.DocType = specDocTypeGraphQLSDL
+	}
+	return m.DocGraphQL, nil
+}
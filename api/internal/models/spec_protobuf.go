@@ -0,0 +1,103 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/pkg/protobuf"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/specbundler"
+)
+
+const (
+	specDocTypeProtobufPrefix = "protobuf-"
+)
+
+// IsProtobuf checks if Spec.Doc is a protobuf spec.
+func (m *Spec) IsProtobuf() bool {
+	return m.DocKind() == SpecDocKindProtobuf
+}
+
+// LoadDocAsProtobuf loads Spec.Doc as a protobuf spec & stores it as Spec.DocProtobuf.
+// Set setDocType to derive Spec.DocType from the syntax of Spec.DocProtobuf.
+// Set setVersion to derive Spec.Version from the version suffix of the package of Spec.DocProtobuf, if any.
+func (m *Spec) LoadDocAsProtobuf(setDocType, setVersion bool) (*protobuf.Schema, error) {
+	if m.DocProtobuf != nil {
+		return m.DocProtobuf, nil
+	}
+	if m.Doc == nil || len(*m.Doc) == 0 {
+		return nil, fmt.Errorf("spec: missing Spec.Doc")
+	}
+	var err error
+	m.DocProtobuf, err = protobuf.Load([]byte(*m.Doc))
+	if err != nil {
+		return nil, fmt.Errorf("spec: failed to load Spec.Doc as protobuf: %v", err)
+	}
+	if setDocType && m.DocType == "" {
+		m.DocType = specDocTypeProtobufPrefix + m.DocProtobuf.Syntax()
+	}
+	if setVersion && m.Version == "" {
+		m.Version = m.DocProtobuf.Version()
+	}
+	return m.DocProtobuf, nil
+}
+
+// isProtobufFiles checks if files are .proto files, or a single compiled FileDescriptorSet.
+func isProtobufFiles(files specbundler.Files) bool {
+	if len(files) == 1 {
+		for name := range files {
+			if protobuf.IsDescriptorSetFilename(name) {
+				return true
+			}
+		}
+	}
+	for name := range files {
+		if !protobuf.IsProtoFilename(name) {
+			return false
+		}
+	}
+	return len(files) > 0
+}
+
+// loadDocFromProtobufFiles sets Spec.Doc to the protobuf spec doc of files (see protobuf.BundleFiles & protobuf.DescriptorSetToJSON).
+// The original files are kept as Spec.Sources, except for binary FileDescriptorSets.
+func (m *Spec) loadDocFromProtobufFiles(files specbundler.Files) error {
+	var (
+		doc []byte
+		err error
+	)
+	for name, data := range files {
+		if protobuf.IsDescriptorSetFilename(name) {
+			if doc, err = protobuf.DescriptorSetToJSON(data); err != nil {
+				return err
+			}
+			m.Doc = NewSpecDocFromBytes(doc)
+			m.DocProtobuf = nil
+			return nil
+		}
+	}
+
+	if doc, err = protobuf.BundleFiles(files); err != nil {
+		return err
+	}
+	m.Doc = NewSpecDocFromBytes(doc)
+	m.DocProtobuf = nil
+	m.Sources = &SpecSources{Files: make(map[string]string, len(files))}
+	for name, data := range files {
+		m.Sources.Files[name] = string(data)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/pkg/utils/specbundler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec_LoadDocFromFiles_Protobuf(t *testing.T) {
+	files := specbundler.Files{
+		"billing/v1/billing.proto": []byte("syntax = \"proto3\";\npackage acme.billing.v1;\nimport \"billing/v1/invoice.proto\";\nservice Billing {\n  rpc GetInvoice(Invoice) returns (Invoice);\n}\n"),
+		"billing/v1/invoice.proto": []byte("syntax = \"proto3\";\npackage acme.billing.v1;\nmessage Invoice {\n  string id = 1;\n}\n"),
+	}
+	m := &Spec{}
+	require.NoError(t, m.LoadDocFromFiles(files, ""))
+	require.NotNil(t, m.Sources)
+	assert.Len(t, m.Sources.Files, 2)
+	assert.Equal(t, SpecDocKindProtobuf, m.DocKind())
+
+	require.NoError(t, m.LoadDoc(context.Background(), false, true, true))
+	assert.Equal(t, "protobuf-proto3", m.DocType)
+	assert.Equal(t, "v1", m.Version)
+	require.NotNil(t, m.DocProtobuf)
+	assert.Equal(t, "acme.billing.v1.Invoice", m.DocProtobuf.Services["acme.billing.v1.Billing"].Methods[0].InputType)
+}
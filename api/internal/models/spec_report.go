@@ -0,0 +1,310 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	_ "embed"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SpecReportFormatHTML = "html"
+	SpecReportFormatJSON = "json"
+
+	// SpecReportTrendMaxPoints caps the number of specs of SpecReport.Trend.
+	SpecReportTrendMaxPoints = 20
+)
+
+//go:embed templates/spec_report.html
+var specReportHTML string
+
+var specReportTemplate = template.Must(template.New("spec_report").Funcs(template.FuncMap{
+	"path": func(path []string) string { return strings.Join(path, ".") },
+	"score": func(score *int) string {
+		if score == nil {
+			return "-"
+		}
+		return fmt.Sprint(*score)
+	},
+	"time": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+}).Parse(specReportHTML))
+
+// specReportSeverities orders the severities of SpecReportAnalysis.Severities.
+var specReportSeverities = []rule.SeverityName{rule.SeverityNameError, rule.SeverityNameWarning, rule.SeverityNameInfo, rule.SeverityNameHint}
+
+// SpecReport represents a self-contained report of a service spec: its scores, analyses findings, changes since the
+// previous Release spec & the score trend of the service.
+type SpecReport struct {
+	Service     *Service              `json:"service"`
+	Spec        *Spec                 `json:"spec"`
+	Analyses    []*SpecReportAnalysis `json:"analyses"`
+	Changes     *SpecReportChanges    `json:"changes,omitempty"`
+	Trend       []*SpecReportTrend    `json:"trend"`
+	GeneratedAt time.Time             `json:"generated_at"`
+}
+
+// SpecReportAnalysis represents the findings of an analyzer, grouped by severity & rule.
+type SpecReportAnalysis struct {
+	Analyzer    analyzer.SpecAnalyzer `json:"analyzer"`
+	Title       string                `json:"title"`
+	Score       *int                  `json:"score"`
+	Occurrences int                   `json:"occurrences"`
+	Severities  []*SpecReportSeverity `json:"severities"`
+}
+
+// SpecReportSeverity represents the findings of a severity, grouped by rule.
+type SpecReportSeverity struct {
+	Severity    rule.SeverityName `json:"severity"`
+	Occurrences int               `json:"occurrences"`
+	Rules       []*SpecReportRule `json:"rules"`
+}
+
+// SpecReportRule represents the findings of a rule.
+type SpecReportRule struct {
+	NameID     rule.NameID         `json:"name_id"`
+	Title      string              `json:"title"`
+	Message    string              `json:"message"`
+	Mitigation string              `json:"mitigation"`
+	Findings   []*analyzer.Finding `json:"findings"`
+}
+
+// SpecReportChanges summarizes the changes of a spec since Spec, the previous Release spec.
+type SpecReportChanges struct {
+	Spec       *Spec `json:"spec"`
+	Breaking   bool  `json:"breaking"`
+	Added      int   `json:"added"`
+	Deleted    int   `json:"deleted"`
+	Deprecated int   `json:"deprecated"`
+	Modified   int   `json:"modified"`
+	// BreakingChanges lists the deleted & the breaking modified endpoints.
+	BreakingChanges []*SpecReportChange `json:"breaking_changes"`
+}
+
+// SpecReportChange represents a breaking change of an endpoint.
+type SpecReportChange struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Action  diff.Action `json:"action"`
+	Message string      `json:"message"`
+}
+
+// SpecReportTrend represents the score of a spec of the service, at the time it was created.
+type SpecReportTrend struct {
+	SpecID    string    `json:"spec_id"`
+	Version   string    `json:"version"`
+	Revision  string    `json:"revision"`
+	State     string    `json:"state"`
+	Score     int       `json:"score"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewSpecReport constructs a new SpecReport of spec, a spec of service, from its (distinct) analyses, the analyzers
+// (with rules) that ran them & specs, the specs of service for the score trend.
+func NewSpecReport(service *Service, spec *Spec, analyses []*SpecAnalysis, analyzers []*analyzer.Analyzer, specs []*Spec) *SpecReport {
+	m := &SpecReport{
+		Service:     service,
+		Spec:        spec,
+		Analyses:    []*SpecReportAnalysis{},
+		Trend:       []*SpecReportTrend{},
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	var (
+		analyzersByName = map[analyzer.SpecAnalyzer]*analyzer.Analyzer{}
+		rulesByName     = map[rule.NameID]*analyzer.Rule{}
+	)
+	for _, a := range analyzers {
+		analyzersByName[analyzer.SpecAnalyzer(a.NameID)] = a
+		for _, r := range a.Rules {
+			rulesByName[rule.NameID(r.NameID)] = r
+		}
+	}
+
+	for _, a := range analyses {
+		if a.Result == nil {
+			continue
+		}
+		ra := &SpecReportAnalysis{Analyzer: a.Analyzer, Title: string(a.Analyzer), Score: a.Score}
+		if found, ok := analyzersByName[a.Analyzer]; ok && found.Title != "" {
+			ra.Title = found.Title
+		}
+		for _, severity := range specReportSeverities {
+			ruleFindings := a.Result.Findings[severity]
+			if ruleFindings == nil || len(ruleFindings.Rules) == 0 {
+				continue
+			}
+			rs := &SpecReportSeverity{Severity: severity}
+			for ruleNameID, findings := range ruleFindings.Rules {
+				if findings == nil || len(findings.Data) == 0 {
+					continue
+				}
+				rr := &SpecReportRule{
+					NameID:     ruleNameID,
+					Title:      string(ruleNameID),
+					Message:    findings.Message,
+					Mitigation: findings.Mitigation,
+					Findings:   findings.Data,
+				}
+				if r, ok := rulesByName[ruleNameID]; ok {
+					if r.Title != "" {
+						rr.Title = r.Title
+					}
+					if rr.Mitigation == "" {
+						rr.Mitigation = r.Mitigation
+					}
+				}
+				rs.Rules = append(rs.Rules, rr)
+				rs.Occurrences += len(rr.Findings)
+			}
+			if len(rs.Rules) == 0 {
+				continue
+			}
+			sort.Slice(rs.Rules, func(i, j int) bool { return rs.Rules[i].NameID < rs.Rules[j].NameID })
+			ra.Severities = append(ra.Severities, rs)
+			ra.Occurrences += rs.Occurrences
+		}
+		m.Analyses = append(m.Analyses, ra)
+	}
+	sort.SliceStable(m.Analyses, func(i, j int) bool {
+		pi, pj := analyzerPosition(analyzersByName, m.Analyses[i].Analyzer), analyzerPosition(analyzersByName, m.Analyses[j].Analyzer)
+		if pi != pj {
+			return pi < pj
+		}
+		return m.Analyses[i].Analyzer < m.Analyses[j].Analyzer
+	})
+
+	m.Trend = specReportTrend(spec, specs)
+	return m
+}
+
+func analyzerPosition(analyzersByName map[analyzer.SpecAnalyzer]*analyzer.Analyzer, name analyzer.SpecAnalyzer) int {
+	if a, ok := analyzersByName[name]; ok {
+		return a.Position
+	}
+	return int(^uint(0) >> 1)
+}
+
+// specReportTrend returns the scores of the scored specs created up to spec, oldest first, capped to the latest
+// SpecReportTrendMaxPoints.
+func specReportTrend(spec *Spec, specs []*Spec) []*SpecReportTrend {
+	trend := []*SpecReportTrend{}
+	for _, s := range specs {
+		if s.Score == nil || s.CreatedAt.After(spec.CreatedAt) {
+			continue
+		}
+		trend = append(trend, &SpecReportTrend{
+			SpecID:    s.ID,
+			Version:   s.Version,
+			Revision:  s.Revision,
+			State:     s.State,
+			Score:     *s.Score,
+			CreatedAt: s.CreatedAt,
+		})
+	}
+	sort.SliceStable(trend, func(i, j int) bool { return trend[i].CreatedAt.Before(trend[j].CreatedAt) })
+	if len(trend) > SpecReportTrendMaxPoints {
+		trend = trend[len(trend)-SpecReportTrendMaxPoints:]
+	}
+	return trend
+}
+
+// PreviousReleaseSpec returns the latest Release spec of specs created before spec, or nil if none.
+func PreviousReleaseSpec(spec *Spec, specs []*Spec) *Spec {
+	var previous *Spec
+	for _, s := range specs {
+		if s.ID == spec.ID || s.State != SpecStateRelease || !s.CreatedAt.Before(spec.CreatedAt) {
+			continue
+		}
+		if previous == nil || s.CreatedAt.After(previous.CreatedAt) {
+			previous = s
+		}
+	}
+	return previous
+}
+
+// SetChanges sets the SpecReport.Changes since previous, the previous Release spec, from result, the JSON diff result
+// of previous & SpecReport.Spec.
+func (m *SpecReport) SetChanges(previous *Spec, result *diff.JSONResult) {
+	if previous == nil || result == nil {
+		return
+	}
+	m.Changes = &SpecReportChanges{
+		Spec:            previous,
+		Breaking:        result.Breaking,
+		Added:           len(result.Added),
+		Deleted:         len(result.Deleted),
+		Deprecated:      len(result.Deprecated),
+		Modified:        len(result.Modified),
+		BreakingChanges: breakingChanges(result),
+	}
+}
+
+// breakingChanges returns the deleted & the breaking modified endpoints of result.
+func breakingChanges(result *diff.JSONResult) []*SpecReportChange {
+	changes := []*SpecReportChange{}
+	for _, e := range result.Deleted {
+		changes = append(changes, &SpecReportChange{
+			Method:  e.Method,
+			Path:    e.Path,
+			Action:  diff.ActionDeleted,
+			Message: e.Message,
+		})
+	}
+	for _, e := range result.Modified {
+		if !e.Breaking {
+			continue
+		}
+		changes = append(changes, &SpecReportChange{
+			Method:  e.Method,
+			Path:    e.Path,
+			Action:  diff.ActionModified,
+			Message: e.Message,
+		})
+	}
+	return changes
+}
+
+// TrendPoints returns the SpecReport.Trend scores as the points of an SVG polyline of the given width & height.
+func (m *SpecReport) TrendPoints(width, height int) string {
+	points := make([]string, 0, len(m.Trend))
+	for i, t := range m.Trend {
+		x := width / 2
+		if len(m.Trend) > 1 {
+			x = i * width / (len(m.Trend) - 1)
+		}
+		points = append(points, fmt.Sprintf("%d,%d", x, height-t.Score*height/100))
+	}
+	return strings.Join(points, " ")
+}
+
+// WriteHTML writes the report as a self-contained HTML document to w.
+func (m *SpecReport) WriteHTML(w io.Writer) error {
+	return specReportTemplate.Execute(w, m)
+}
+
+// Filename returns the filename of the report, in the given format.
+func (m *SpecReport) Filename(format string) string {
+	return fmt.Sprintf("report-%s-%s-%s.%s", m.Service.NameID, m.Spec.Version, m.Spec.Revision, format)
+}
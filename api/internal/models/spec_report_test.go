@@ -0,0 +1,114 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSpecReport(t *testing.T) {
+	var (
+		t0      = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		score   = func(score int) *int { return &score }
+		service = &Service{ID: "svc", NameID: "carts", Title: "Carts <API>"}
+		specs   = []*Spec{
+			{ID: "1", Version: "1.0.0", Revision: "1", State: SpecStateRelease, Score: score(60), CreatedAt: t0},
+			{ID: "2", Version: "1.1.0", Revision: "1", State: SpecStateRelease, Score: score(70), CreatedAt: t0.Add(time.Hour)},
+			{ID: "3", Version: "1.2.0", Revision: "1", State: "Development", CreatedAt: t0.Add(2 * time.Hour)},
+			{ID: "4", Version: "2.0.0", Revision: "1", State: "Latest", Score: score(80), CreatedAt: t0.Add(3 * time.Hour)},
+			{ID: "5", Version: "2.1.0", Revision: "1", State: SpecStateRelease, Score: score(90), CreatedAt: t0.Add(4 * time.Hour)},
+		}
+		spec = specs[3]
+
+		result = analyzer.NewResult()
+	)
+	result.Findings[rule.SeverityNameWarning].Rules = map[rule.NameID]*analyzer.Findings{
+		"info-contact": {
+			Message: "Info object must have \"contact\" object.",
+			Data:    []*analyzer.Finding{{Path: []string{"info"}, Range: &analyzer.FindingPositionRange{Start: &analyzer.FindingPosition{Line: 2, Column: 1}}}},
+		},
+		"b-rule": {Message: "b", Data: []*analyzer.Finding{{Path: []string{"paths"}}, {Path: []string{"paths", "/a"}}}},
+	}
+	result.Findings[rule.SeverityNameError].Rules = map[rule.NameID]*analyzer.Findings{
+		"no-data": {Message: "ignored"},
+	}
+	analyses := []*SpecAnalysis{
+		{Analyzer: analyzer.Security, Score: score(100), SpecAnalysisResult: SpecAnalysisResult{Result: analyzer.NewResult()}},
+		{Analyzer: analyzer.CiscoAPIGuidelines, Score: score(75), SpecAnalysisResult: SpecAnalysisResult{Result: result}},
+	}
+	analyzers := []*analyzer.Analyzer{
+		{NameID: "guidelines", Title: "API Guidelines", Position: 1, Rules: []*analyzer.Rule{{NameID: "info-contact", Title: "Contact", Mitigation: "Add a contact."}}},
+		{NameID: "security", Title: "Security", Position: 2},
+	}
+
+	report := NewSpecReport(service, spec, analyses, analyzers, specs)
+	require.Len(t, report.Analyses, 2)
+	assert.Equal(t, "API Guidelines", report.Analyses[0].Title)
+	assert.Equal(t, "Security", report.Analyses[1].Title)
+	assert.Equal(t, 3, report.Analyses[0].Occurrences)
+	require.Len(t, report.Analyses[0].Severities, 1)
+	warnings := report.Analyses[0].Severities[0]
+	assert.Equal(t, rule.SeverityNameWarning, warnings.Severity)
+	require.Len(t, warnings.Rules, 2)
+	assert.Equal(t, rule.NameID("b-rule"), warnings.Rules[0].NameID)
+	assert.Equal(t, "Contact", warnings.Rules[1].Title)
+	assert.Equal(t, "Add a contact.", warnings.Rules[1].Mitigation)
+	assert.Empty(t, report.Analyses[1].Severities)
+
+	var trend []string
+	for _, p := range report.Trend {
+		trend = append(trend, p.SpecID)
+	}
+	assert.Equal(t, []string{"1", "2", "4"}, trend)
+	assert.Equal(t, "0,48 300,36 600,24", report.TrendPoints(600, 120))
+
+	previous := PreviousReleaseSpec(spec, specs)
+	require.NotNil(t, previous)
+	assert.Equal(t, "2", previous.ID)
+	assert.Nil(t, PreviousReleaseSpec(specs[0], specs))
+
+	report.SetChanges(previous, &diff.JSONResult{
+		Breaking: true,
+		Added:    []*diff.EndpointSummary{{Path: "/b", Method: "GET"}},
+		Deleted:  []*diff.EndpointSummary{{Path: "/a", Method: "GET"}},
+		Modified: []*diff.ModifiedSummary{{Path: "/c", Method: "POST", Breaking: true}, {Path: "/d", Method: "PUT"}},
+	})
+	require.NotNil(t, report.Changes)
+	assert.Equal(t, 1, report.Changes.Added)
+	assert.Equal(t, 2, report.Changes.Modified)
+	require.Len(t, report.Changes.BreakingChanges, 2)
+	assert.Equal(t, diff.ActionDeleted, report.Changes.BreakingChanges[0].Action)
+	assert.Equal(t, "/c", report.Changes.BreakingChanges[1].Path)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteHTML(&buf))
+	html := buf.String()
+	assert.Contains(t, html, "<h1>Carts &lt;API&gt;</h1>")
+	assert.Contains(t, html, "Info object must have &#34;contact&#34; object.")
+	assert.Contains(t, html, "<em>Mitigation:</em> Add a contact.")
+	assert.Contains(t, html, `<polyline points="0,48 300,36 600,24"`)
+	assert.Contains(t, html, "<code>POST /c</code>")
+	assert.Equal(t, "report-carts-2.0.0-1.html", report.Filename(SpecReportFormatHTML))
+}
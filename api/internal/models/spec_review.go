@@ -0,0 +1,249 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"sort"
+	"strings"
+)
+
+const (
+	SpecReviewFormatJSON     = "json"
+	SpecReviewFormatMarkdown = "markdown"
+
+	// SpecReviewMaxFindings caps the number of findings listed in each table of SpecReviewResponse.Markdown,
+	// keeping the comment within the size limits of pull request comments.
+	SpecReviewMaxFindings = 100
+)
+
+// SpecReviewRequest represents a request for a review of a spec doc, e.g. the spec changed by a pull request,
+// against a service spec.
+type SpecReviewRequest struct {
+	Doc SpecDoc `json:"doc"`
+	// SpecID is the ID of the service spec to review Doc against, by default the latest service spec.
+	SpecID    string                  `json:"spec_id,omitempty"`
+	Analyzers []analyzer.SpecAnalyzer `json:"analyzers,omitempty"`
+}
+
+// SpecReviewResponse represents the review of a spec doc against a service spec (the base spec):
+// its breaking changes, score delta & new findings, with Markdown, the review as a pull request comment.
+type SpecReviewResponse struct {
+	// SpecID is the ID of the base spec, empty if the service has no specs.
+	SpecID          string               `json:"spec_id,omitempty"`
+	Breaking        bool                 `json:"breaking"`
+	BreakingChanges []*SpecReportChange  `json:"breaking_changes"`
+	Score           int                  `json:"score"`
+	BaseScore       *int                 `json:"base_score"`
+	ScoreDelta      *int                 `json:"score_delta"`
+	NewFindings     []*SpecReviewFinding `json:"new_findings"`
+	FindingsCount   int                  `json:"findings_count"`
+	Markdown        string               `json:"markdown"`
+}
+
+// SpecReviewFinding represents a finding of a reviewed spec doc.
+type SpecReviewFinding struct {
+	Analyzer analyzer.SpecAnalyzer `json:"analyzer"`
+	Severity rule.SeverityName     `json:"severity"`
+	Rule     rule.NameID           `json:"rule"`
+	Message  string                `json:"message"`
+	Path     []string              `json:"path"`
+	Line     int                   `json:"line,omitempty"`
+}
+
+// key identifies the finding among the findings of both specs of a review.
+func (f *SpecReviewFinding) key() string {
+	return string(f.Analyzer) + "\x00" + string(f.Rule) + "\x00" + strings.Join(f.Path, "\x00")
+}
+
+// NewSpecReviewResponse constructs a new SpecReviewResponse from analysisRes, the analyses of the reviewed doc,
+// diffResult, its JSON diff against base, the base spec, & baseAnalyses, the (distinct) analyses of base.
+// base & diffResult are nil when there is no base spec to review against.
+func NewSpecReviewResponse(base *Spec, baseAnalyses []*SpecAnalysis, diffResult *diff.JSONResult, analysisRes *SpecAnalysisResponse) *SpecReviewResponse {
+	m := &SpecReviewResponse{
+		BreakingChanges: []*SpecReportChange{},
+		NewFindings:     []*SpecReviewFinding{},
+		Score:           analysisRes.SpecScore,
+	}
+	if base != nil {
+		m.SpecID = base.ID
+		m.BaseScore = base.Score
+		if base.Score != nil {
+			delta := m.Score - *base.Score
+			m.ScoreDelta = &delta
+		}
+	}
+	if diffResult != nil {
+		m.Breaking = diffResult.Breaking
+		m.BreakingChanges = breakingChanges(diffResult)
+	}
+
+	preexisting := map[string]struct{}{}
+	for _, a := range baseAnalyses {
+		for _, f := range specReviewFindings(a.Analyzer, a.Result) {
+			preexisting[f.key()] = struct{}{}
+		}
+	}
+	var findings []*SpecReviewFinding
+	for name, a := range analysisRes.Results {
+		findings = append(findings, specReviewFindings(name, a.Result)...)
+	}
+	sortSpecReviewFindings(findings)
+	for _, f := range findings {
+		if _, ok := preexisting[f.key()]; !ok {
+			m.NewFindings = append(m.NewFindings, f)
+		}
+	}
+	m.FindingsCount = len(findings)
+
+	m.Markdown = m.markdown(base, diffResult, findings)
+	return m
+}
+
+func specReviewFindings(name analyzer.SpecAnalyzer, result *analyzer.Result) []*SpecReviewFinding {
+	var findings []*SpecReviewFinding
+	if result == nil {
+		return findings
+	}
+	for severity, ruleFindings := range result.Findings {
+		if ruleFindings == nil {
+			continue
+		}
+		for ruleNameID, rf := range ruleFindings.Rules {
+			if rf == nil {
+				continue
+			}
+			for _, finding := range rf.Data {
+				f := &SpecReviewFinding{
+					Analyzer: name,
+					Severity: severity,
+					Rule:     ruleNameID,
+					Message:  rf.Message,
+					Path:     finding.Path,
+				}
+				if finding.Range != nil && finding.Range.Start != nil {
+					f.Line = finding.Range.Start.Line
+				}
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings
+}
+
+// sortSpecReviewFindings sorts findings by severity (most severe first), analyzer, rule & line.
+func sortSpecReviewFindings(findings []*SpecReviewFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity.Severity() > b.Severity.Severity()
+		}
+		if a.Analyzer != b.Analyzer {
+			return a.Analyzer < b.Analyzer
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return strings.Join(a.Path, ".") < strings.Join(b.Path, ".")
+	})
+}
+
+func (m *SpecReviewResponse) markdown(base *Spec, diffResult *diff.JSONResult, findings []*SpecReviewFinding) string {
+	var sb strings.Builder
+
+	sb.WriteString("### API Insights review\n\n")
+
+	sb.WriteString(fmt.Sprintf("**Score:** %d", m.Score))
+	switch {
+	case base == nil:
+		sb.WriteString(" (no previous spec)")
+	case m.ScoreDelta != nil:
+		sb.WriteString(fmt.Sprintf(" (%+d since %s revision %s)", *m.ScoreDelta, base.Version, base.Revision))
+	}
+	sb.WriteString("  \n")
+
+	switch {
+	case diffResult == nil:
+		sb.WriteString("**Breaking changes:** n/a\n\n")
+	case m.Breaking:
+		sb.WriteString(fmt.Sprintf("**Breaking changes:** :warning: %d\n\n", len(m.BreakingChanges)))
+	default:
+		sb.WriteString("**Breaking changes:** none\n\n")
+	}
+
+	if len(m.BreakingChanges) > 0 {
+		sb.WriteString("#### Breaking changes\n\n")
+		for _, c := range m.BreakingChanges {
+			sb.WriteString(fmt.Sprintf("- `%s` %s: %s\n", c.Method, c.Path, c.Action))
+		}
+		sb.WriteString("\n")
+	}
+	if diffResult != nil && strings.TrimSpace(diffResult.Message) != "" {
+		sb.WriteString("<details>\n<summary>API changes</summary>\n\n")
+		sb.WriteString(strings.TrimSpace(diffResult.Message))
+		sb.WriteString("\n\n</details>\n\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("#### New findings (%d)\n\n", len(m.NewFindings)))
+	if len(m.NewFindings) == 0 {
+		sb.WriteString("No new findings.\n\n")
+	} else {
+		writeSpecReviewFindingsTable(&sb, m.NewFindings)
+	}
+
+	sb.WriteString(fmt.Sprintf("<details>\n<summary>All findings (%d)</summary>\n\n", len(findings)))
+	if len(findings) == 0 {
+		sb.WriteString("No findings.\n\n")
+	} else {
+		writeSpecReviewFindingsTable(&sb, findings)
+	}
+	sb.WriteString("</details>\n")
+
+	return sb.String()
+}
+
+func writeSpecReviewFindingsTable(sb *strings.Builder, findings []*SpecReviewFinding) {
+	sb.WriteString("| Severity | Analyzer | Rule | Location | Message |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	for i, f := range findings {
+		if i == SpecReviewMaxFindings {
+			sb.WriteString(fmt.Sprintf("\n_and %d more_\n", len(findings)-i))
+			break
+		}
+		location := markdownTableCell(strings.Join(f.Path, "."))
+		if location != "" {
+			location = "`" + location + "`"
+		}
+		if f.Line > 0 {
+			location += fmt.Sprintf(" (line %d)", f.Line)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s | %s |\n", f.Severity, f.Analyzer, f.Rule, location, markdownTableCell(f.Message)))
+	}
+	sb.WriteString("\n")
+}
+
+// markdownTableCell escapes s for a markdown table cell.
+func markdownTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
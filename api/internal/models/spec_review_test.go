@@ -0,0 +1,89 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSpecReviewResponse(t *testing.T) {
+	var (
+		score     = func(score int) *int { return &score }
+		newResult = func(severity rule.SeverityName, rules map[rule.NameID]*analyzer.Findings) *analyzer.Result {
+			result := analyzer.NewResult()
+			result.Findings[severity].Rules = rules
+			return result
+		}
+		base = &Spec{ID: "base", Version: "1.0.0", Revision: "2", Score: score(78)}
+	)
+	baseAnalyses := []*SpecAnalysis{
+		{Analyzer: analyzer.CiscoAPIGuidelines, SpecAnalysisResult: SpecAnalysisResult{Result: newResult(rule.SeverityNameWarning, map[rule.NameID]*analyzer.Findings{
+			"info-contact": {Message: "Info object must have contact.", Data: []*analyzer.Finding{{Path: []string{"info"}}}},
+		})}},
+	}
+	analysisRes := &SpecAnalysisResponse{
+		SpecScore: 82,
+		Results: map[analyzer.SpecAnalyzer]*SpecAnalysis{
+			analyzer.CiscoAPIGuidelines: {SpecAnalysisResult: SpecAnalysisResult{Result: newResult(rule.SeverityNameWarning, map[rule.NameID]*analyzer.Findings{
+				"info-contact":          {Message: "Info object must have contact.", Data: []*analyzer.Finding{{Path: []string{"info"}}}},
+				"operation-operationId": {Message: "Operation must have | operationId.", Data: []*analyzer.Finding{{Path: []string{"paths", "/pets", "get"}, Range: &analyzer.FindingPositionRange{Start: &analyzer.FindingPosition{Line: 7, Column: 5}}}}},
+			})}},
+			analyzer.Security: {SpecAnalysisResult: SpecAnalysisResult{Result: newResult(rule.SeverityNameError, map[rule.NameID]*analyzer.Findings{
+				"no-auth": {Message: "No auth.", Data: []*analyzer.Finding{{Path: []string{"paths", "/pets", "get"}}}},
+			})}},
+		},
+	}
+	diffResult := &diff.JSONResult{
+		Breaking: true,
+		Deleted:  []*diff.EndpointSummary{{Path: "/pets/{id}", Method: "DELETE"}},
+		Message:  "#### What's Deleted\n\n##### `DELETE` /pets/{id}\n",
+	}
+
+	m := NewSpecReviewResponse(base, baseAnalyses, diffResult, analysisRes)
+	assert.Equal(t, "base", m.SpecID)
+	assert.True(t, m.Breaking)
+	require.NotNil(t, m.ScoreDelta)
+	assert.Equal(t, 4, *m.ScoreDelta)
+	assert.Equal(t, 3, m.FindingsCount)
+	require.Len(t, m.NewFindings, 2)
+	assert.Equal(t, rule.NameID("no-auth"), m.NewFindings[0].Rule)
+	assert.Equal(t, rule.NameID("operation-operationId"), m.NewFindings[1].Rule)
+	assert.Equal(t, 7, m.NewFindings[1].Line)
+
+	assert.Contains(t, m.Markdown, "**Score:** 82 (+4 since 1.0.0 revision 2)")
+	assert.Contains(t, m.Markdown, "**Breaking changes:** :warning: 1")
+	assert.Contains(t, m.Markdown, "- `DELETE` /pets/{id}: deleted")
+	assert.Contains(t, m.Markdown, "#### What's Deleted")
+	assert.Contains(t, m.Markdown, "#### New findings (2)")
+	assert.Contains(t, m.Markdown, "| warning | guidelines | `operation-operationId` | `paths./pets.get` (line 7) | Operation must have \\| operationId. |")
+	assert.Contains(t, m.Markdown, "<summary>All findings (3)</summary>")
+	assert.Equal(t, 1, strings.Count(m.Markdown, "`info-contact`"))
+
+	m = NewSpecReviewResponse(nil, nil, nil, analysisRes)
+	assert.Empty(t, m.SpecID)
+	assert.Nil(t, m.ScoreDelta)
+	assert.Len(t, m.NewFindings, 3)
+	assert.Contains(t, m.Markdown, "**Score:** 82 (no previous spec)")
+	assert.Contains(t, m.Markdown, "**Breaking changes:** n/a")
+}
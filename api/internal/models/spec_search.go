@@ -0,0 +1,311 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"github.com/getkin/kin-openapi/openapi3"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SpecSearchEntryTableName = "spec_search_entries"
+
+	SpecSearchKindSpec      = "spec"
+	SpecSearchKindPath      = "path"
+	SpecSearchKindOperation = "operation"
+	SpecSearchKindParameter = "parameter"
+	SpecSearchKindSchema    = "schema"
+	SpecSearchKindProperty  = "property"
+
+	SpecSearchDefaultLimit = 100
+	SpecSearchMaxLimit     = 1000
+
+	// specSearchMaxDepth caps the depth of nested inline schemas indexed for their properties.
+	specSearchMaxDepth = 8
+	// specSearchMaxText caps the length of indexed summaries & descriptions.
+	specSearchMaxText = 1000
+)
+
+// SpecSearchKinds are the kinds of SpecSearchEntry.
+var SpecSearchKinds = []string{
+	SpecSearchKindSpec,
+	SpecSearchKindPath,
+	SpecSearchKindOperation,
+	SpecSearchKindParameter,
+	SpecSearchKindSchema,
+	SpecSearchKindProperty,
+}
+
+// SpecSearchEntry represents an element (e.g. a path or a schema property) of the latest spec of a service,
+// indexed for searching across services.
+type SpecSearchEntry struct {
+	ID        uint      `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ServiceID string    `json:"service_id" gorm:"column:service_id;index"`
+	SpecID    string    `json:"spec_id" gorm:"column:spec_id;index"`
+	Kind      string    `json:"kind" gorm:"column:kind;index"`
+	Name      string    `json:"name" gorm:"column:name"`               // e.g. the path, operationId, parameter, schema or property name.
+	Term      string    `json:"-" gorm:"column:term;index"`            // Name, lower-cased for case-insensitive matching.
+	Method    string    `json:"method,omitempty" gorm:"column:method"` // The operation method, if any.
+	Path      string    `json:"path,omitempty" gorm:"column:path"`     // The API path, if any.
+	Schema    string    `json:"schema,omitempty" gorm:"column:schema"` // The parent schema of a property, if named.
+	Summary   string    `json:"summary,omitempty" gorm:"column:summary"`
+	Text      string    `json:"-" gorm:"column:text"` // Summary & description, lower-cased for full-text matching.
+	JSONPath  string    `json:"json_path" gorm:"column:json_path"`
+	CreatedAt time.Time `json:"-" gorm:"column:created_at"`
+}
+
+// TableName implements gorm Tabler interface
+func (m *SpecSearchEntry) TableName() string {
+	return SpecSearchEntryTableName
+}
+
+// SpecSearchRequest represents a search across the latest specs of services.
+type SpecSearchRequest struct {
+	Query      string   // Matched against entry names (& their summary & description, unless Exact).
+	Kinds      []string // Restricts matches to entries of these kinds, if any.
+	ServiceIDs []string // Restricts matches to the specs of these services.
+	Exact      bool     // Matches entry names exactly (case-insensitive), instead of containing Query.
+	Limit      int
+}
+
+// SpecSearchResponse represents the results of a SpecSearchRequest.
+type SpecSearchResponse struct {
+	Query    string              `json:"query"`
+	Total    int                 `json:"total"`    // The number of results, before Limit.
+	Services int                 `json:"services"` // The number of services within Results.
+	Results  []*SpecSearchResult `json:"results"`
+}
+
+// SpecSearchResult represents a SpecSearchEntry matching a SpecSearchRequest.
+type SpecSearchResult struct {
+	*SpecSearchEntry
+	ServiceNameID string `json:"service_name_id"`
+	ServiceTitle  string `json:"service_title"`
+	Version       string `json:"version"`
+	Revision      string `json:"revision"`
+}
+
+// ValidSpecSearchKind checks if kind is one of SpecSearchKinds.
+func ValidSpecSearchKind(kind string) bool {
+	for _, k := range SpecSearchKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSpecSearchEntries indexes the elements of spec's Spec.Doc, of which only OpenAPI docs are indexed beyond the spec itself.
+// The spec entry, always present, marks the spec as indexed.
+func NewSpecSearchEntries(ctx context.Context, spec *Spec) ([]*SpecSearchEntry, error) {
+	idx := &specSearchIndexer{spec: spec, now: time.Now().UTC()}
+	if spec.Doc == nil || spec.DocKind() != SpecDocKindOpenAPI {
+		idx.add(&SpecSearchEntry{Kind: SpecSearchKindSpec, JSONPath: "$"})
+		return idx.entries, nil
+	}
+	doc, err := spec.LoadDocAsOAS(ctx, false, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	specEntry := &SpecSearchEntry{Kind: SpecSearchKindSpec, JSONPath: "$"}
+	if doc.Info != nil {
+		specEntry.Name = doc.Info.Title
+		specEntry.Summary = doc.Info.Description
+		specEntry.JSONPath = "$.info"
+	}
+	idx.add(specEntry)
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := doc.Paths[path]
+		pathJSONPath := specSearchJSONPath("$", "paths", path)
+		idx.add(&SpecSearchEntry{Kind: SpecSearchKindPath, Name: path, Path: path, Summary: pathItem.Summary + "\n" + pathItem.Description, JSONPath: pathJSONPath})
+		idx.addParameters(pathItem.Parameters, "", path, pathJSONPath)
+
+		operations := pathItem.Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			op := operations[method]
+			opJSONPath := specSearchJSONPath(pathJSONPath, strings.ToLower(method))
+			idx.add(&SpecSearchEntry{Kind: SpecSearchKindOperation, Name: op.OperationID, Method: method, Path: path, Summary: op.Summary + "\n" + op.Description, JSONPath: opJSONPath})
+			idx.addParameters(op.Parameters, method, path, opJSONPath)
+			if op.RequestBody != nil && op.RequestBody.Ref == "" && op.RequestBody.Value != nil {
+				idx.addContent(op.RequestBody.Value.Content, method, path, specSearchJSONPath(opJSONPath, "requestBody"))
+			}
+			codes := make([]string, 0, len(op.Responses))
+			for code := range op.Responses {
+				codes = append(codes, code)
+			}
+			sort.Strings(codes)
+			for _, code := range codes {
+				if response := op.Responses[code]; response.Ref == "" && response.Value != nil {
+					idx.addContent(response.Value.Content, method, path, specSearchJSONPath(opJSONPath, "responses", code))
+				}
+			}
+		}
+	}
+
+	if doc.Components.Schemas != nil {
+		names := make([]string, 0, len(doc.Components.Schemas))
+		for name := range doc.Components.Schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			schemaRef := doc.Components.Schemas[name]
+			if schemaRef == nil || schemaRef.Value == nil {
+				continue
+			}
+			schemaJSONPath := specSearchJSONPath("$", "components", "schemas", name)
+			idx.add(&SpecSearchEntry{Kind: SpecSearchKindSchema, Name: name, Summary: schemaRef.Value.Title + "\n" + schemaRef.Value.Description, JSONPath: schemaJSONPath})
+			idx.addProperties(schemaRef.Value, name, "", "", schemaJSONPath, 0)
+		}
+	}
+
+	return idx.entries, nil
+}
+
+type specSearchIndexer struct {
+	spec    *Spec
+	now     time.Time
+	entries []*SpecSearchEntry
+}
+
+func (idx *specSearchIndexer) add(entry *SpecSearchEntry) {
+	entry.ServiceID = idx.spec.ServiceID
+	entry.SpecID = idx.spec.ID
+	entry.Term = strings.ToLower(entry.Name)
+	entry.Summary = strings.TrimSpace(entry.Summary)
+	entry.Summary = utils.Truncate(entry.Summary, specSearchMaxText)
+	entry.Text = strings.ToLower(entry.Summary)
+	entry.CreatedAt = idx.now
+	idx.entries = append(idx.entries, entry)
+}
+
+func (idx *specSearchIndexer) addParameters(params openapi3.Parameters, method, path, jsonPath string) {
+	for i, paramRef := range params {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		paramJSONPath := specSearchJSONPath(jsonPath, "parameters", strconv.Itoa(i))
+		idx.add(&SpecSearchEntry{Kind: SpecSearchKindParameter, Name: paramRef.Value.Name, Method: method, Path: path, Summary: paramRef.Value.Description, JSONPath: paramJSONPath})
+		if paramRef.Ref == "" && paramRef.Value.Schema != nil {
+			idx.addProperties(paramRef.Value.Schema.Value, "", method, path, specSearchJSONPath(paramJSONPath, "schema"), 0)
+		}
+	}
+}
+
+func (idx *specSearchIndexer) addContent(content openapi3.Content, method, path, jsonPath string) {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	for _, mediaType := range mediaTypes {
+		if mt := content[mediaType]; mt != nil && mt.Schema != nil && mt.Schema.Ref == "" {
+			idx.addProperties(mt.Schema.Value, "", method, path, specSearchJSONPath(jsonPath, "content", mediaType, "schema"), 0)
+		}
+	}
+}
+
+// addProperties indexes the properties of schema, recursing into inline schemas only: referenced schemas are indexed as components.
+func (idx *specSearchIndexer) addProperties(schema *openapi3.Schema, schemaName, method, path, jsonPath string, depth int) {
+	if schema == nil || depth > specSearchMaxDepth {
+		return
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propRef := schema.Properties[name]
+		if propRef == nil || propRef.Value == nil {
+			continue
+		}
+		propJSONPath := specSearchJSONPath(jsonPath, "properties", name)
+		idx.add(&SpecSearchEntry{Kind: SpecSearchKindProperty, Name: name, Method: method, Path: path, Schema: schemaName, Summary: propRef.Value.Description, JSONPath: propJSONPath})
+		if propRef.Ref == "" {
+			idx.addProperties(propRef.Value, schemaName, method, path, propJSONPath, depth+1)
+		}
+	}
+	if schema.Items != nil && schema.Items.Ref == "" {
+		idx.addProperties(schema.Items.Value, schemaName, method, path, specSearchJSONPath(jsonPath, "items"), depth+1)
+	}
+	for keyword, schemaRefs := range map[string]openapi3.SchemaRefs{"allOf": schema.AllOf, "oneOf": schema.OneOf, "anyOf": schema.AnyOf} {
+		for i, schemaRef := range schemaRefs {
+			if schemaRef != nil && schemaRef.Ref == "" {
+				idx.addProperties(schemaRef.Value, schemaName, method, path, specSearchJSONPath(jsonPath, keyword, strconv.Itoa(i)), depth+1)
+			}
+		}
+	}
+}
+
+// specSearchJSONPath appends tokens to jsonPath, e.g. $.paths['/pets'].get.parameters[0].
+func specSearchJSONPath(jsonPath string, tokens ...string) string {
+	var b strings.Builder
+	b.WriteString(jsonPath)
+	for _, token := range tokens {
+		if isSpecSearchIndexToken(b.String(), token) {
+			b.WriteString("[" + token + "]")
+		} else if isSpecSearchIdentifier(token) {
+			b.WriteString("." + token)
+		} else {
+			b.WriteString("['" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(token) + "']")
+		}
+	}
+	return b.String()
+}
+
+// isSpecSearchIndexToken checks if token is an array index, i.e. a number following an array keyword.
+func isSpecSearchIndexToken(jsonPath, token string) bool {
+	if _, err := strconv.Atoi(token); err != nil {
+		return false
+	}
+	for _, keyword := range []string{".parameters", ".allOf", ".oneOf", ".anyOf"} {
+		if strings.HasSuffix(jsonPath, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpecSearchIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
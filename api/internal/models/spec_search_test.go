@@ -0,0 +1,130 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSpecSearchEntries(t *testing.T) {
+	doc := SpecDoc(new(string))
+	*doc = `openapi: 3.0.0
+info:
+  title: Orders API
+  version: 1.0.0
+paths:
+  /v1/orders/{orderId}:
+    parameters:
+      - name: orderId
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getOrder
+      summary: Get an order
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  customerId:
+                    type: string
+                    description: The customer of the order
+components:
+  schemas:
+    Customer:
+      type: object
+      properties:
+        address:
+          type: object
+          properties:
+            zip-code:
+              type: string
+`
+	spec := &Spec{ID: "spec", ServiceID: "svc", Doc: doc}
+	entries, err := NewSpecSearchEntries(context.Background(), spec)
+	require.NoError(t, err)
+
+	find := func(kind, name string) *SpecSearchEntry {
+		for _, e := range entries {
+			if e.Kind == kind && e.Name == name {
+				return e
+			}
+		}
+		return nil
+	}
+
+	e := find(SpecSearchKindSpec, "Orders API")
+	require.NotNil(t, e)
+	assert.Equal(t, "svc", e.ServiceID)
+	assert.Equal(t, "spec", e.SpecID)
+	assert.Equal(t, "orders api", e.Term)
+
+	e = find(SpecSearchKindPath, "/v1/orders/{orderId}")
+	require.NotNil(t, e)
+	assert.Equal(t, "$.paths['/v1/orders/{orderId}']", e.JSONPath)
+
+	e = find(SpecSearchKindParameter, "orderId")
+	require.NotNil(t, e)
+	assert.Equal(t, "$.paths['/v1/orders/{orderId}'].parameters[0]", e.JSONPath)
+
+	e = find(SpecSearchKindOperation, "getOrder")
+	require.NotNil(t, e)
+	assert.Equal(t, "GET", e.Method)
+	assert.Equal(t, "get an order", e.Text)
+	assert.Equal(t, "$.paths['/v1/orders/{orderId}'].get", e.JSONPath)
+
+	e = find(SpecSearchKindProperty, "customerId")
+	require.NotNil(t, e)
+	assert.Equal(t, "/v1/orders/{orderId}", e.Path)
+	assert.Equal(t, "customerid", e.Term)
+	assert.Equal(t, "$.paths['/v1/orders/{orderId}'].get.responses['200'].content['application/json'].schema.properties.customerId", e.JSONPath)
+
+	require.NotNil(t, find(SpecSearchKindSchema, "Customer"))
+	e = find(SpecSearchKindProperty, "zip-code")
+	require.NotNil(t, e)
+	assert.Equal(t, "Customer", e.Schema)
+	assert.Equal(t, "$.components.schemas.Customer.properties.address.properties['zip-code']", e.JSONPath)
+
+	// Other docs are only indexed as a spec.
+	doc = SpecDoc(new(string))
+	*doc = "syntax = \"proto3\";\nservice Orders {}\n"
+	entries, err = NewSpecSearchEntries(context.Background(), &Spec{ID: "proto", Doc: doc, DocType: "protobuf-3"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, SpecSearchKindSpec, entries[0].Kind)
+}
+
+func TestSpecSearchIndexer_Add(t *testing.T) {
+	idx := &specSearchIndexer{spec: &Spec{ID: "spec", ServiceID: "svc"}}
+	// 2-byte runes offset by 1 byte, so that specSearchMaxText falls within a rune.
+	idx.add(&SpecSearchEntry{Name: "Commande", Summary: "a" + strings.Repeat("é", specSearchMaxText)})
+	require.Len(t, idx.entries, 1)
+	assert.True(t, utf8.ValidString(idx.entries[0].Summary))
+	assert.Equal(t, specSearchMaxText-1, len(idx.entries[0].Summary))
+	assert.True(t, utf8.ValidString(idx.entries[0].Text))
+}
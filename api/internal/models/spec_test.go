@@ -17,12 +17,19 @@
 package models
 
 import (
+	"bytes"
 	"context"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/specbundler"
+	"github.com/emicklei/go-restful/v3"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -785,12 +792,67 @@ func TestSpec_TableName(t *testing.T) {
 	}
 }
 
+func TestSpec_SetDocHash(t *testing.T) {
+	m := &Spec{}
+	assert.Error(t, m.SetDocHash())
+
+	m.Doc = NewSpecDocFromBytes([]byte(`{"openapi": "3.0.0", "info": {"version": "1.0.0", "title": "t"}, "paths": {}}`))
+	assert.NoError(t, m.SetDocHash())
+	assert.Len(t, m.DocHash, 64)
+	assert.Equal(t, SpecDocHashVersion, m.DocHashVersion)
+	if assert.NotNil(t, m.DedupeHash) {
+		assert.Equal(t, m.DocHash, *m.DedupeHash)
+	}
+
+	reformatted := &Spec{Doc: NewSpecDocFromBytes([]byte("openapi: 3.0.0\ninfo:\n  title: t\n  version: 1.0.0\npaths: {}\n"))}
+	assert.NoError(t, reformatted.SetDocHash())
+	assert.Equal(t, m.DocHash, reformatted.DocHash)
+}
+
 func loadSpec(file string) *string {
 	content, _ := os.ReadFile(file)
 	s := string(content)
 	return &s
 }
 
+func TestSpec_From_Multipart(t *testing.T) {
+	defer func(fileSize, size int64) {
+		specbundler.MaxArchiveFileSize, specbundler.MaxArchiveSize = fileSize, size
+	}(specbundler.MaxArchiveFileSize, specbundler.MaxArchiveSize)
+	specbundler.MaxArchiveFileSize, specbundler.MaxArchiveSize = 1<<10, 4<<10
+
+	newReq := func(files map[string]string) *restful.Request {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		for name, content := range files {
+			fw, err := w.CreateFormFile("files", name)
+			assert.NoError(t, err)
+			_, _ = fw.Write([]byte(content))
+		}
+		_ = w.Close()
+		r := httptest.NewRequest(http.MethodPost, "/v1/apiregistry/services/svc/specs", &body)
+		r.Header.Set("Content-Type", w.FormDataContentType())
+		return restful.NewRequest(r)
+	}
+
+	spec := &Spec{}
+	err := spec.From(newReq(map[string]string{"openapi.yaml": "openapi: 3.0.0\ninfo:\n  title: a\n  version: 1.0.0\npaths: {}\n"}))
+	assert.NoError(t, err)
+	assert.NotNil(t, spec.Doc)
+
+	// A file over MaxArchiveFileSize.
+	err = (&Spec{}).From(newReq(map[string]string{"openapi.yaml": strings.Repeat("x", 2<<10)}))
+	assert.ErrorIs(t, err, specbundler.ErrArchiveTooLarge)
+
+	// Files under MaxArchiveFileSize, but a body over MaxArchiveSize.
+	files := map[string]string{}
+	for _, name := range []string{"a.yaml", "b.yaml", "c.yaml", "d.yaml", "e.yaml"} {
+		files[name] = strings.Repeat("x", 1000)
+	}
+	err = (&Spec{}).From(newReq(files))
+	assert.ErrorIs(t, err, specbundler.ErrArchiveTooLarge)
+}
+
 func loadSpecData(file string) []byte {
 	content, _ := os.ReadFile(file)
 	return content
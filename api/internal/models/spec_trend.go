@@ -0,0 +1,283 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"sort"
+	"time"
+)
+
+const (
+	SpecTrendTableName = "spec_trends"
+
+	ServiceTrendsIntervalRevision = "revision"
+	ServiceTrendsIntervalDay      = "day"
+	ServiceTrendsIntervalWeek     = "week"
+	ServiceTrendsIntervalMonth    = "month"
+)
+
+// ServiceTrendsIntervals are the supported intervals for bucketing ServiceTrends.
+var ServiceTrendsIntervals = []string{
+	ServiceTrendsIntervalRevision,
+	ServiceTrendsIntervalDay,
+	ServiceTrendsIntervalWeek,
+	ServiceTrendsIntervalMonth,
+}
+
+// SpecTrend aggregates the score & finding counts of a spec & its latest analyses, as a single row per spec.
+// It's maintained by the spec & spec analysis DAOs as they're saved, so that the trends of a service can be read
+// without loading any of its analysis results.
+type SpecTrend struct {
+	SpecID        string             `json:"spec_id" gorm:"column:spec_id;primaryKey"`
+	ServiceID     string             `json:"service_id" gorm:"column:service_id;index:svc_spec_created_trend_idx"`
+	Version       string             `json:"version" gorm:"column:version"`
+	Revision      string             `json:"revision" gorm:"column:revision"`
+	SpecCreatedAt time.Time          `json:"spec_created_at" gorm:"column:spec_created_at;index:svc_spec_created_trend_idx"`
+	Score         *int               `json:"score" gorm:"column:score"`
+	Counts        SeverityCounts     `json:"counts" gorm:"embedded;embeddedPrefix:count_"`
+	Analyzers     SpecTrendAnalyzers `json:"analyzers" gorm:"column:analyzers"`
+	UpdatedAt     time.Time          `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// SeverityCounts represents the finding occurrences by severity.
+type SeverityCounts struct {
+	Hint    int `json:"hint" gorm:"column:hint"`
+	Info    int `json:"info" gorm:"column:info"`
+	Warning int `json:"warning" gorm:"column:warning"`
+	Error   int `json:"error" gorm:"column:error"`
+}
+
+func (m *SeverityCounts) add(counts SeverityCounts) {
+	m.Hint += counts.Hint
+	m.Info += counts.Info
+	m.Warning += counts.Warning
+	m.Error += counts.Error
+}
+
+// SpecTrendAnalyzer represents the score & finding counts of a spec analysis.
+type SpecTrendAnalyzer struct {
+	AnalysisID string         `json:"analysis_id"`
+	Score      *int           `json:"score"`
+	Counts     SeverityCounts `json:"counts"`
+	CreatedAt  time.Time      `json:"created_at"`
+
+	// Rules maps the violated rules to their finding occurrences, nil if the SpecTrendAnalyzer predates it.
+	Rules map[rule.NameID]int `json:"rules"`
+}
+
+// SpecTrendAnalyzers maps analyzers to the SpecTrendAnalyzer of their latest analysis of a spec.
+type SpecTrendAnalyzers map[analyzer.SpecAnalyzer]*SpecTrendAnalyzer
+
+// Scan implements sql.Scanner interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m *SpecTrendAnalyzers) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+
+	return json.Unmarshal(bytes, &m)
+}
+
+// Value implements driver.Valuer interface.
+// See https://gorm.io/docs/data_types.html#Implements-Customized-Data-Type.
+func (m SpecTrendAnalyzers) Value() (driver.Value, error) { return json.Marshal(m) }
+
+// NewSpecTrend constructs a new SpecTrend of spec.
+func NewSpecTrend(spec *Spec) *SpecTrend {
+	m := &SpecTrend{SpecID: spec.ID, Analyzers: SpecTrendAnalyzers{}}
+	m.SetSpec(spec)
+	return m
+}
+
+// TableName implements gorm Tabler interface
+func (m *SpecTrend) TableName() string {
+	return SpecTrendTableName
+}
+
+// SetSpec sets the fields of m from spec, e.g. after its score is updated.
+func (m *SpecTrend) SetSpec(spec *Spec) {
+	m.ServiceID = spec.ServiceID
+	m.Version = spec.Version
+	m.Revision = spec.Revision
+	m.SpecCreatedAt = spec.CreatedAt
+	m.Score = spec.Score
+	m.UpdatedAt = time.Now().UTC()
+}
+
+// SetAnalysis sets the score & finding counts of specAnalysis in m, unless m already has a later analysis by the same analyzer.
+func (m *SpecTrend) SetAnalysis(specAnalysis *SpecAnalysis) {
+	if m.Analyzers == nil {
+		m.Analyzers = SpecTrendAnalyzers{}
+	}
+	if existing, ok := m.Analyzers[specAnalysis.Analyzer]; ok && existing.AnalysisID != specAnalysis.ID && existing.CreatedAt.After(specAnalysis.CreatedAt) {
+		return
+	}
+
+	a := &SpecTrendAnalyzer{
+		AnalysisID: specAnalysis.ID,
+		Score:      specAnalysis.Score,
+		CreatedAt:  specAnalysis.CreatedAt,
+		Rules:      map[rule.NameID]int{},
+	}
+	if specAnalysis.Result != nil && specAnalysis.Result.Summary != nil && specAnalysis.Result.Summary.Stats != nil {
+		stats := specAnalysis.Result.Summary.Stats
+		for _, severityStats := range []struct {
+			stats *analyzer.RuleFindingsStats
+			count *int
+		}{
+			{stats.Hint, &a.Counts.Hint},
+			{stats.Info, &a.Counts.Info},
+			{stats.Warning, &a.Counts.Warning},
+			{stats.Error, &a.Counts.Error},
+		} {
+			if severityStats.stats == nil {
+				continue
+			}
+			*severityStats.count = severityStats.stats.Occurrences
+			for ruleNameID, occurrences := range severityStats.stats.Data {
+				a.Rules[ruleNameID] += occurrences
+			}
+		}
+	}
+	m.Analyzers[specAnalysis.Analyzer] = a
+
+	m.Counts = SeverityCounts{}
+	for _, a := range m.Analyzers {
+		m.Counts.add(a.Counts)
+	}
+	m.UpdatedAt = time.Now().UTC()
+}
+
+// HasRules checks if the analyzers of m have their violated rules, i.e. m doesn't predate SpecTrendAnalyzer.Rules.
+func (m *SpecTrend) HasRules() bool {
+	for _, a := range m.Analyzers {
+		if a.Rules == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ServiceTrends represents the time-bucketed score & finding counts of a service's specs.
+type ServiceTrends struct {
+	ServiceID string                `json:"service_id"`
+	Interval  string                `json:"interval"`
+	Buckets   []*ServiceTrendBucket `json:"buckets"`
+}
+
+// ServiceTrendBucket represents the score & finding counts of the latest spec revision within a bucket.
+type ServiceTrendBucket struct {
+	Start     time.Time                                          `json:"start"`
+	Specs     int                                                `json:"specs"` // The number of spec revisions within the bucket.
+	SpecID    string                                             `json:"spec_id"`
+	Version   string                                             `json:"version"`
+	Revision  string                                             `json:"revision"`
+	Score     *int                                               `json:"score"`
+	Counts    SeverityCounts                                     `json:"counts"`
+	Analyzers map[analyzer.SpecAnalyzer]*ServiceTrendBucketScore `json:"analyzers"`
+}
+
+// ServiceTrendBucketScore represents the score & finding counts of an analyzer within a ServiceTrendBucket.
+type ServiceTrendBucketScore struct {
+	Score  *int           `json:"score"`
+	Counts SeverityCounts `json:"counts"`
+}
+
+// ValidServiceTrendsInterval checks if interval is one of ServiceTrendsIntervals.
+func ValidServiceTrendsInterval(interval string) bool {
+	for _, i := range ServiceTrendsIntervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// NewServiceTrends buckets trends, the SpecTrend(s) of a service, by interval.
+// Each bucket holds the latest spec revision created within it, i.e. the state of the service at the end of the bucket.
+func NewServiceTrends(serviceID, interval string, trends []*SpecTrend) *ServiceTrends {
+	sorted := make([]*SpecTrend, len(trends))
+	copy(sorted, trends)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].SpecCreatedAt.Before(sorted[j].SpecCreatedAt) })
+
+	m := &ServiceTrends{ServiceID: serviceID, Interval: interval, Buckets: []*ServiceTrendBucket{}}
+	var bucket *ServiceTrendBucket
+	for _, trend := range sorted {
+		start := serviceTrendBucketStart(trend.SpecCreatedAt, interval)
+		if bucket == nil || !bucket.Start.Equal(start) || interval == ServiceTrendsIntervalRevision {
+			bucket = &ServiceTrendBucket{Start: start}
+			m.Buckets = append(m.Buckets, bucket)
+		}
+		bucket.Specs++
+		bucket.SpecID = trend.SpecID
+		bucket.Version = trend.Version
+		bucket.Revision = trend.Revision
+		bucket.Score = trend.Score
+		bucket.Counts = trend.Counts
+		bucket.Analyzers = make(map[analyzer.SpecAnalyzer]*ServiceTrendBucketScore, len(trend.Analyzers))
+		for analyzerName, a := range trend.Analyzers {
+			bucket.Analyzers[analyzerName] = &ServiceTrendBucketScore{Score: a.Score, Counts: a.Counts}
+		}
+	}
+	return m
+}
+
+// serviceTrendBucketStart returns the start (in UTC) of the bucket of t by interval, e.g. the Monday of its week.
+func serviceTrendBucketStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	switch interval {
+	case ServiceTrendsIntervalDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case ServiceTrendsIntervalWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case ServiceTrendsIntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return t
+	}
+}
+
+// nextServiceTrendBucketStart returns the start of the bucket after the one starting at start by interval.
+func nextServiceTrendBucketStart(start time.Time, interval string) time.Time {
+	switch interval {
+	case ServiceTrendsIntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case ServiceTrendsIntervalMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// previousServiceTrendBucketStart returns the start of the bucket before the one starting at start by interval.
+func previousServiceTrendBucketStart(start time.Time, interval string) time.Time {
+	switch interval {
+	case ServiceTrendsIntervalWeek:
+		return start.AddDate(0, 0, -7)
+	case ServiceTrendsIntervalMonth:
+		return start.AddDate(0, -1, 0)
+	default:
+		return start.AddDate(0, 0, -1)
+	}
+}
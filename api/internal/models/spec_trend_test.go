@@ -0,0 +1,127 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecTrend_SetAnalysis(t *testing.T) {
+	score := func(s int) *int { return &s }
+	now := time.Now().UTC()
+	analysis := func(id string, a analyzer.SpecAnalyzer, s int, createdAt time.Time, warnings, errors int) *SpecAnalysis {
+		result := analyzer.NewResult()
+		for i := 0; i < warnings; i++ {
+			result.AddFinding(rule.SeverityNameWarning, "w", &analyzer.Finding{})
+		}
+		for i := 0; i < errors; i++ {
+			result.AddFinding(rule.SeverityNameError, "e", &analyzer.Finding{})
+		}
+		return &SpecAnalysis{
+			ID:                 id,
+			Analyzer:           a,
+			Score:              score(s),
+			CreatedAt:          createdAt,
+			SpecAnalysisResult: SpecAnalysisResult{Result: result},
+		}
+	}
+
+	trend := NewSpecTrend(&Spec{ID: "spec", ServiceID: "svc", Version: "1.0.0", Revision: "1", Score: score(80), CreatedAt: now})
+	trend.SetAnalysis(analysis("a1", analyzer.Completeness, 90, now, 1, 0))
+	trend.SetAnalysis(analysis("a2", analyzer.CiscoAPIGuidelines, 70, now, 2, 3))
+	assert.Equal(t, SeverityCounts{Warning: 3, Error: 3}, trend.Counts)
+	assert.Equal(t, map[rule.NameID]int{"w": 2, "e": 3}, trend.Analyzers[analyzer.CiscoAPIGuidelines].Rules)
+	assert.True(t, trend.HasRules())
+
+	// An earlier analysis doesn't override a later one.
+	trend.SetAnalysis(analysis("a0", analyzer.Completeness, 10, now.Add(-time.Hour), 5, 5))
+	assert.Equal(t, 90, *trend.Analyzers[analyzer.Completeness].Score)
+
+	// A later analysis does.
+	trend.SetAnalysis(analysis("a3", analyzer.Completeness, 100, now.Add(time.Hour), 0, 0))
+	assert.Equal(t, 100, *trend.Analyzers[analyzer.Completeness].Score)
+	assert.Equal(t, SeverityCounts{Warning: 2, Error: 3}, trend.Counts)
+	assert.Equal(t, 80, *trend.Score)
+}
+
+func TestNewServiceTrends(t *testing.T) {
+	score := func(s int) *int { return &s }
+	// 2022-01-03 is a Monday.
+	at := func(day, hour int) time.Time { return time.Date(2022, 1, day, hour, 0, 0, 0, time.UTC) }
+	trends := []*SpecTrend{
+		{SpecID: "3", Revision: "3", SpecCreatedAt: at(4, 9), Score: score(60), Counts: SeverityCounts{Error: 2}},
+		{SpecID: "1", Revision: "1", SpecCreatedAt: at(3, 9), Score: score(40), Analyzers: SpecTrendAnalyzers{
+			analyzer.Completeness: {Score: score(50), Counts: SeverityCounts{Warning: 1}},
+		}},
+		{SpecID: "2", Revision: "2", SpecCreatedAt: at(3, 18), Score: score(50)},
+		{SpecID: "4", Revision: "4", SpecCreatedAt: at(10, 9), Score: score(90)},
+	}
+
+	tests := []struct {
+		interval   string
+		wantStarts []time.Time
+		wantSpecs  []string
+		wantCounts []int
+	}{
+		{
+			interval:   ServiceTrendsIntervalRevision,
+			wantStarts: []time.Time{at(3, 9), at(3, 18), at(4, 9), at(10, 9)},
+			wantSpecs:  []string{"1", "2", "3", "4"},
+			wantCounts: []int{1, 1, 1, 1},
+		},
+		{
+			interval:   ServiceTrendsIntervalDay,
+			wantStarts: []time.Time{at(3, 0), at(4, 0), at(10, 0)},
+			wantSpecs:  []string{"2", "3", "4"},
+			wantCounts: []int{2, 1, 1},
+		},
+		{
+			interval:   ServiceTrendsIntervalWeek,
+			wantStarts: []time.Time{at(3, 0), at(10, 0)},
+			wantSpecs:  []string{"3", "4"},
+			wantCounts: []int{3, 1},
+		},
+		{
+			interval:   ServiceTrendsIntervalMonth,
+			wantStarts: []time.Time{at(1, 0)},
+			wantSpecs:  []string{"4"},
+			wantCounts: []int{4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.interval, func(t *testing.T) {
+			got := NewServiceTrends("svc", tt.interval, trends)
+			require.Len(t, got.Buckets, len(tt.wantSpecs))
+			for i, bucket := range got.Buckets {
+				assert.Equal(t, tt.wantStarts[i], bucket.Start)
+				assert.Equal(t, tt.wantSpecs[i], bucket.SpecID)
+				assert.Equal(t, tt.wantCounts[i], bucket.Specs)
+			}
+		})
+	}
+
+	got := NewServiceTrends("svc", ServiceTrendsIntervalRevision, trends)
+	assert.Equal(t, 50, *got.Buckets[0].Analyzers[analyzer.Completeness].Score)
+	assert.Equal(t, SeverityCounts{Error: 2}, got.Buckets[2].Counts)
+	assert.Empty(t, NewServiceTrends("svc", ServiceTrendsIntervalDay, nil).Buckets)
+}
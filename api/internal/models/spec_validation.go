@@ -0,0 +1,63 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cisco-developer/api-insights/api/pkg/utils/oasvalidator"
+)
+
+const (
+	SpecValid   = "valid"
+	SpecInvalid = "invalid"
+)
+
+// Validate validates Spec.Doc & sets Spec.Valid & Spec.ValidationErrors accordingly.
+// OpenAPI specs are validated against the OpenAPI meta-schema & kin-openapi's semantic checks (see oasvalidator.Validate),
+// specs of other kinds are valid once loaded.
+func (m *Spec) Validate(ctx context.Context) error {
+	if m.DocKind() != SpecDocKindOpenAPI {
+		if err := m.LoadDoc(ctx, false, false, false); err != nil {
+			return err
+		}
+		m.Valid, m.ValidationErrors = SpecValid, nil
+		return nil
+	}
+
+	doc, err := m.LoadDocAsOAS(ctx, false, false, false)
+	if err != nil {
+		return err
+	}
+	errs, err := oasvalidator.Validate(ctx, []byte(*m.Doc), doc)
+	if err != nil {
+		return fmt.Errorf("spec: failed to validate Spec.Doc: %v", err)
+	}
+	m.ValidationErrors = errs
+	if len(errs) > 0 {
+		m.Valid = SpecInvalid
+	} else {
+		m.Valid = SpecValid
+	}
+	return nil
+}
+
+// IsValid checks if Spec.Doc was validated successfully (see Spec.Validate).
+func (m *Spec) IsValid() bool {
+	return m.Valid == SpecValid
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc        string
+		wantValid  string
+		wantErrors int
+	}{
+		{
+			name:      "valid oas",
+			doc:       "openapi: 3.0.3\ninfo:\n  title: a\n  version: 1.0.0\npaths: {}\n",
+			wantValid: SpecValid,
+		},
+		{
+			name:       "invalid oas",
+			doc:        "openapi: 3.0.3\ninfo:\n  title: a\n  version: 1.0.0\npaths:\n  /a:\n    get:\n      responses:\n        '200': {}\n",
+			wantValid:  SpecInvalid,
+			wantErrors: 1,
+		},
+		{
+			name:      "graphql",
+			doc:       "type Query {\n  a: String\n}\n",
+			wantValid: SpecValid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Spec{Doc: NewSpecDocFromBytes([]byte(tt.doc))}
+			require.NoError(t, m.Validate(context.Background()))
+			assert.Equal(t, tt.wantValid, m.Valid)
+			assert.Equal(t, tt.wantValid == SpecValid, m.IsValid())
+			assert.Len(t, m.ValidationErrors, tt.wantErrors)
+		})
+	}
+}
+
+func TestSpec_ValidationErrors_RoundTrip(t *testing.T) {
+	m := &Spec{Doc: NewSpecDocFromBytes([]byte("openapi: 3.0.3\ninfo:\n  title: a\npaths: {}\n"))}
+	require.NoError(t, m.Validate(context.Background()))
+	require.NotEmpty(t, m.ValidationErrors)
+	assert.Equal(t, "$.info", m.ValidationErrors[0].Path)
+	assert.Equal(t, 2, m.ValidationErrors[0].Line)
+
+	require.NoError(t, m.BeforeSave(nil))
+	assert.True(t, json.Valid(m.RawValidationErrors))
+
+	found := &Spec{RawValidationErrors: m.RawValidationErrors}
+	require.NoError(t, found.AfterFind(nil))
+	assert.Equal(t, m.ValidationErrors, found.ValidationErrors)
+}
@@ -0,0 +1,462 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	WebhookTableName         = "webhooks"
+	WebhookDeliveryTableName = "webhook_deliveries"
+
+	WebhookEventSpecCreated       = "spec.created"
+	WebhookEventAnalysisCompleted = "analysis.completed"
+	WebhookEventScoreRegressed    = "score.regressed"
+	WebhookEventDiffBreaking      = "diff.breaking"
+	WebhookEventServiceDeleted    = "service.deleted"
+
+	// WebhookEventConsumerImpacted is the event of the deliveries of ConsumerNotification(s) to consumers, which
+	// webhooks don't subscribe to.
+	WebhookEventConsumerImpacted = "consumer.impacted"
+
+	WebhookDeliveryStatusPending   = "pending"
+	WebhookDeliveryStatusSucceeded = "succeeded"
+	WebhookDeliveryStatusFailed    = "failed"
+
+	// WebhookSignatureHeader is the header of the HMAC-SHA256 signature of a payload & its timestamp (see SignWebhookPayload).
+	WebhookSignatureHeader = "X-API-Insights-Signature-256"
+	// WebhookTimestampHeader is the header of the time (in Unix seconds) a payload is sent at, which receivers
+	// should check is recent to reject replays.
+	WebhookTimestampHeader = "X-API-Insights-Timestamp"
+	WebhookEventHeader     = "X-API-Insights-Event"
+	WebhookDeliveryHeader  = "X-API-Insights-Delivery"
+
+	// webhookDeliveryMaxResponse caps the length of the response bodies logged in WebhookDelivery(s).
+	webhookDeliveryMaxResponse = 2000
+)
+
+// WebhookEvents are the events Webhook(s) subscribe to.
+var WebhookEvents = []string{
+	WebhookEventSpecCreated,
+	WebhookEventAnalysisCompleted,
+	WebhookEventScoreRegressed,
+	WebhookEventDiffBreaking,
+	WebhookEventServiceDeleted,
+}
+
+// Webhook represents a subscription of a URL to the events of the services of an organization, or of a service.
+type Webhook struct {
+	ID             string        `json:"id,omitempty" gorm:"column:id;primaryKey"`
+	OrganizationID string        `json:"organization_id,omitempty" gorm:"column:organization_id;index"` // The organization Name ID, as Service.OrganizationID.
+	ServiceID      string        `json:"service_id,omitempty" gorm:"column:service_id;index"`
+	URL            string        `json:"url" gorm:"column:url" validate:"required,url"`
+	Secret         string        `json:"secret,omitempty" gorm:"column:secret"` // Signs payloads. Generated if not given, only returned on creation.
+	Events         DelimitedList `json:"events" gorm:"column:events" validate:"required,min=1,dive,oneof=spec.created analysis.completed score.regressed diff.breaking service.deleted"`
+	Active         bool          `json:"active" gorm:"column:active;index"`
+	Description    string        `json:"description" gorm:"column:description"`
+	CreatedAt      time.Time     `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt      time.Time     `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// WebhookDelivery represents the delivery of an event payload to a Webhook, or of a ConsumerNotification to
+// the webhook URL of a Consumer, along with its (last) attempt.
+// Pending deliveries are attempted from NextAttemptAt on, so that they're resumed after a restart.
+type WebhookDelivery struct {
+	ID             string         `json:"id,omitempty" gorm:"column:id;primaryKey"`
+	WebhookID      string         `json:"webhook_id,omitempty" gorm:"column:webhook_id;index:webhook_created_idx"`
+	ConsumerID     string         `json:"consumer_id,omitempty" gorm:"column:consumer_id;index"`
+	Event          string         `json:"event" gorm:"column:event;index"`
+	PayloadID      string         `json:"payload_id" gorm:"column:payload_id;index"` // Shared by the deliveries of the same event.
+	Payload        datatypes.JSON `json:"payload" gorm:"column:payload"`
+	Status         string         `json:"status" gorm:"column:status;index"`
+	Attempts       int            `json:"attempts" gorm:"column:attempts"`
+	ResponseStatus int            `json:"response_status,omitempty" gorm:"column:response_status"`
+	ResponseBody   string         `json:"response_body,omitempty" gorm:"column:response_body"`
+	Error          string         `json:"error,omitempty" gorm:"column:error"`
+	Duration       int64          `json:"duration_ms" gorm:"column:duration_ms"` // Of the last attempt.
+	RedeliveryOf   string         `json:"redelivery_of,omitempty" gorm:"column:redelivery_of"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty" gorm:"column:next_attempt_at;index"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"column:created_at;index:webhook_created_idx"`
+	UpdatedAt      time.Time      `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// WebhookPayload represents the payload of an event delivered to Webhook(s).
+type WebhookPayload struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Service   *WebhookService `json:"service"`
+	Data      interface{}     `json:"data,omitempty"`
+}
+
+// WebhookService represents the service of a WebhookPayload.
+type WebhookService struct {
+	ID             string `json:"id"`
+	NameID         string `json:"name_id"`
+	Title          string `json:"title"`
+	OrganizationID string `json:"organization_id"`
+}
+
+// WebhookSpec represents the spec of a WebhookPayload, without its doc.
+type WebhookSpec struct {
+	ID        string    `json:"id"`
+	Version   string    `json:"version"`
+	Revision  string    `json:"revision"`
+	Valid     string    `json:"valid,omitempty"`
+	Score     *int      `json:"score,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookAnalysis represents the data of a WebhookEventAnalysisCompleted payload.
+type WebhookAnalysis struct {
+	Spec     *WebhookSpec                               `json:"spec"`
+	Score    int                                        `json:"score"`
+	Analyses map[analyzer.SpecAnalyzer]*WebhookAnalyzer `json:"analyses"`
+}
+
+// WebhookAnalyzer represents the analysis of a spec by an analyzer, within a WebhookAnalysis.
+type WebhookAnalyzer struct {
+	ID     string `json:"id"`
+	Score  *int   `json:"score"`
+	Status string `json:"status"`
+}
+
+// WebhookScoreRegression represents the data of a WebhookEventScoreRegressed payload.
+type WebhookScoreRegression struct {
+	Spec          *WebhookSpec `json:"spec"`
+	PreviousScore int          `json:"previous_score"`
+	Score         int          `json:"score"`
+}
+
+// WebhookDiff represents the data of a WebhookEventDiffBreaking payload.
+type WebhookDiff struct {
+	ID        string           `json:"id"`
+	OldSpecID string           `json:"old_spec_id"`
+	NewSpecID string           `json:"new_spec_id"`
+	Result    *diff.JSONResult `json:"result"`
+}
+
+// TableName implements gorm Tabler interface
+func (m *Webhook) TableName() string {
+	return WebhookTableName
+}
+
+func (m *Webhook) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = shared.TimeUUID()
+	}
+	return
+}
+
+// GetID returns the ID of webhook object
+func (m *Webhook) GetID() string {
+	return fmt.Sprintf("%v", m.ID)
+}
+
+// String returns the text representation of webhook object
+func (m *Webhook) String() string {
+	return fmt.Sprintf("%v", *m)
+}
+
+// GetIndex returns an index for specific field
+func (m *Webhook) GetIndex(field string) string {
+	return m.GetIndexes()[field]
+}
+
+// GetIndexes returns all the field indexes
+func (m *Webhook) GetIndexes() map[string]string {
+	return map[string]string{
+		"organization_id": "idx_organization_id",
+		"service_id":      "idx_service_id",
+		"active":          "idx_active",
+	}
+}
+
+// GetIndexValue return index value for specified field
+func (m *Webhook) GetIndexValue(field string) string {
+	return m.GetIndexValues()[field]
+}
+
+// GetIndexValues return all field index values
+func (m *Webhook) GetIndexValues() map[string]string {
+	return map[string]string{
+		"organization_id": m.OrganizationID,
+		"service_id":      m.ServiceID,
+		"active":          fmt.Sprintf("%v", m.Active),
+	}
+}
+
+// Sortable checks if field is sortable.
+func (m *Webhook) Sortable(field string) bool {
+	_, found := m.SortableFields()[field]
+	return found
+}
+
+// SortableFields returns all sortable fields
+func (m *Webhook) SortableFields() map[string]struct{} {
+	return map[string]struct{}{
+		"created_at": {},
+		"updated_at": {},
+	}
+}
+
+// Subscribes checks if m is active & subscribed to event.
+func (m *Webhook) Subscribes(event string) bool {
+	if !m.Active {
+		return false
+	}
+	for _, e := range m.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// TableName implements gorm Tabler interface
+func (m *WebhookDelivery) TableName() string {
+	return WebhookDeliveryTableName
+}
+
+func (m *WebhookDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = shared.TimeUUID()
+	}
+	return
+}
+
+// GetID returns the ID of webhookDelivery object
+func (m *WebhookDelivery) GetID() string {
+	return fmt.Sprintf("%v", m.ID)
+}
+
+// String returns the text representation of webhookDelivery object
+func (m *WebhookDelivery) String() string {
+	return fmt.Sprintf("%v", *m)
+}
+
+// GetIndex returns an index for specific field
+func (m *WebhookDelivery) GetIndex(field string) string {
+	return m.GetIndexes()[field]
+}
+
+// GetIndexes returns all the field indexes
+func (m *WebhookDelivery) GetIndexes() map[string]string {
+	return map[string]string{
+		"webhook_id": "webhook_created_idx",
+		"event":      "idx_event",
+		"payload_id": "idx_payload_id",
+		"status":     "idx_status",
+	}
+}
+
+// GetIndexValue return index value for specified field
+func (m *WebhookDelivery) GetIndexValue(field string) string {
+	return m.GetIndexValues()[field]
+}
+
+// GetIndexValues return all field index values
+func (m *WebhookDelivery) GetIndexValues() map[string]string {
+	return map[string]string{
+		"webhook_id": m.WebhookID,
+		"event":      m.Event,
+		"payload_id": m.PayloadID,
+		"status":     m.Status,
+	}
+}
+
+// Sortable checks if field is sortable.
+func (m *WebhookDelivery) Sortable(field string) bool {
+	_, found := m.SortableFields()[field]
+	return found
+}
+
+// SortableFields returns all sortable fields
+func (m *WebhookDelivery) SortableFields() map[string]struct{} {
+	return map[string]struct{}{
+		"created_at": {},
+		"updated_at": {},
+	}
+}
+
+// SetAttempt records an attempt of m, which took duration & got either a response of status & body, or err.
+func (m *WebhookDelivery) SetAttempt(status int, body []byte, err error, duration time.Duration) {
+	m.Attempts++
+	m.Duration = duration.Milliseconds()
+	m.ResponseStatus = status
+	m.ResponseBody = utils.Truncate(string(body), webhookDeliveryMaxResponse)
+	m.Error = ""
+	if err != nil {
+		m.Error = err.Error()
+	}
+	if err == nil && status >= 200 && status < 300 {
+		m.Status = WebhookDeliveryStatusSucceeded
+	} else {
+		m.Status = WebhookDeliveryStatusFailed
+	}
+	m.NextAttemptAt = nil
+	m.UpdatedAt = time.Now().UTC()
+}
+
+// ScheduleRetry keeps m pending, to be attempted again at.
+func (m *WebhookDelivery) ScheduleRetry(at time.Time) {
+	m.Status = WebhookDeliveryStatusPending
+	m.NextAttemptAt = &at
+}
+
+// ValidWebhookEvent checks if event is one of WebhookEvents.
+func ValidWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// SignWebhookPayload returns the signature of payload sent at timestamp (in Unix seconds, sent as WebhookTimestampHeader)
+// with secret (sent as WebhookSignatureHeader): sha256= followed by the hex-encoded HMAC-SHA256 of the timestamp,
+// a dot & payload. As the timestamp is signed, receivers can reject replays of old payloads.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrWebhookAddressNotAllowed is the error of a webhook URL whose host is, or resolves to, an address not allowed
+// (see WebhookAddressAllowed).
+var ErrWebhookAddressNotAllowed = errors.New("webhook address not allowed")
+
+// webhookMetadataAddresses are the addresses of the instance metadata services of cloud providers,
+// other than link-local ones.
+var webhookMetadataAddresses = []net.IP{
+	net.ParseIP("fd00:ec2::254"),   // AWS (IPv6)
+	net.ParseIP("100.100.100.200"), // Alibaba Cloud
+}
+
+// WebhookAddressAllowed checks if webhooks may be delivered to ip, i.e. it's neither a loopback, link-local (e.g.
+// 169.254.169.254), unspecified or multicast address, nor the address of an instance metadata service.
+func WebhookAddressAllowed(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, metadata := range webhookMetadataAddresses {
+		if ip.Equal(metadata) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckWebhookURL checks that rawURL is an HTTP(S) URL whose host is, or resolves to, addresses allowed
+// (see WebhookAddressAllowed). Deliveries check the addresses again as they connect, as DNS may change.
+func CheckWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported webhook URL scheme: %s", u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return errors.New("webhook URL has no host")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if !WebhookAddressAllowed(ip) {
+			return fmt.Errorf("%w: %s", ErrWebhookAddressNotAllowed, host)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host %s: %v", host, err)
+	}
+	for _, addr := range addrs {
+		if !WebhookAddressAllowed(addr.IP) {
+			return fmt.Errorf("%w: %s (%s)", ErrWebhookAddressNotAllowed, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// NewWebhookSecret returns a random secret to sign the payloads of a Webhook.
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// NewWebhookPayload returns the payload of event of service, with data.
+func NewWebhookPayload(event string, service *Service, data interface{}) *WebhookPayload {
+	return &WebhookPayload{
+		ID:        shared.TimeUUID(),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Service: &WebhookService{
+			ID:             service.ID,
+			NameID:         service.NameID,
+			Title:          service.Title,
+			OrganizationID: service.OrganizationID,
+		},
+		Data: data,
+	}
+}
+
+// NewWebhookSpec returns the spec of a WebhookPayload.
+func NewWebhookSpec(spec *Spec) *WebhookSpec {
+	return &WebhookSpec{
+		ID:        spec.ID,
+		Version:   spec.Version,
+		Revision:  spec.Revision,
+		Valid:     spec.Valid,
+		Score:     spec.Score,
+		CreatedAt: spec.CreatedAt,
+	}
+}
+
+// NewWebhookAnalysis returns the data of a WebhookEventAnalysisCompleted payload.
+func NewWebhookAnalysis(spec *Spec, res *SpecAnalysisResponse) *WebhookAnalysis {
+	m := &WebhookAnalysis{Spec: NewWebhookSpec(spec), Score: res.SpecScore, Analyses: map[analyzer.SpecAnalyzer]*WebhookAnalyzer{}}
+	for name, analysis := range res.Results {
+		if analysis != nil {
+			m.Analyses[name] = &WebhookAnalyzer{ID: analysis.ID, Score: analysis.Score, Status: analysis.Status}
+		}
+	}
+	return m
+}
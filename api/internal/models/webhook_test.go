@@ -0,0 +1,107 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	// HMAC-SHA256 of "1700000000.The quick brown fox jumps over the lazy dog" with key.
+	assert.Equal(t, "sha256=2f658d6aef4f246e91cd741bbcded7479e9605f9d41c9e248122a117e0e1765b",
+		SignWebhookPayload("key", 1700000000, []byte("The quick brown fox jumps over the lazy dog")))
+	assert.NotEqual(t, SignWebhookPayload("key", 1700000000, []byte("{}")), SignWebhookPayload("key", 1700000001, []byte("{}")))
+
+	secret, err := NewWebhookSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 64)
+}
+
+func TestWebhook_Subscribes(t *testing.T) {
+	m := &Webhook{Events: DelimitedList{WebhookEventSpecCreated, WebhookEventDiffBreaking}, Active: true}
+	assert.True(t, m.Subscribes(WebhookEventDiffBreaking))
+	assert.False(t, m.Subscribes(WebhookEventScoreRegressed))
+
+	m.Active = false
+	assert.False(t, m.Subscribes(WebhookEventDiffBreaking))
+
+	assert.True(t, ValidWebhookEvent(WebhookEventServiceDeleted))
+	assert.False(t, ValidWebhookEvent("spec.deleted"))
+}
+
+func TestWebhookDelivery_SetAttempt(t *testing.T) {
+	m := &WebhookDelivery{Status: WebhookDeliveryStatusPending}
+
+	m.SetAttempt(0, nil, errors.New("connection refused"), time.Second)
+	assert.Equal(t, WebhookDeliveryStatusFailed, m.Status)
+	assert.Equal(t, "connection refused", m.Error)
+	assert.Equal(t, int64(1000), m.Duration)
+
+	next := time.Now().Add(time.Minute)
+	m.ScheduleRetry(next)
+	assert.Equal(t, WebhookDeliveryStatusPending, m.Status)
+	assert.Equal(t, &next, m.NextAttemptAt)
+
+	m.SetAttempt(http.StatusBadGateway, []byte(strings.Repeat("x", 3000)), nil, 0)
+	assert.Equal(t, WebhookDeliveryStatusFailed, m.Status)
+	assert.Len(t, m.ResponseBody, webhookDeliveryMaxResponse)
+	assert.Nil(t, m.NextAttemptAt)
+
+	// Truncated on a rune boundary.
+	m.SetAttempt(http.StatusBadGateway, []byte("x"+strings.Repeat("é", webhookDeliveryMaxResponse)), nil, 0)
+	assert.Len(t, m.ResponseBody, webhookDeliveryMaxResponse-1)
+	assert.True(t, utf8.ValidString(m.ResponseBody))
+
+	m.SetAttempt(http.StatusNoContent, nil, nil, 0)
+	assert.Equal(t, WebhookDeliveryStatusSucceeded, m.Status)
+	assert.Equal(t, 4, m.Attempts)
+	assert.Empty(t, m.Error)
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	tests := []struct {
+		url        string
+		wantErr    bool
+		notAllowed bool
+	}{
+		{url: "https://10.0.0.1/hooks"},
+		{url: "http://203.0.113.7:8080/hooks"},
+		{url: "http://127.0.0.1/hooks", wantErr: true, notAllowed: true},
+		{url: "http://[::1]/hooks", wantErr: true, notAllowed: true},
+		{url: "http://169.254.169.254/latest/meta-data/", wantErr: true, notAllowed: true},
+		{url: "http://[fe80::1]/hooks", wantErr: true, notAllowed: true},
+		{url: "http://[fd00:ec2::254]/latest/meta-data/", wantErr: true, notAllowed: true},
+		{url: "http://0.0.0.0/hooks", wantErr: true, notAllowed: true},
+		{url: "ftp://10.0.0.1/hooks", wantErr: true},
+		{url: "http:///hooks", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := CheckWebhookURL(context.Background(), tt.url)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.notAllowed, errors.Is(err, ErrWebhookAddressNotAllowed))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package asyncapi implements the analyzers of AsyncAPI specs (see analyzer.AsyncAPICompleteness & analyzer.AsyncAPIGuidelines).
+package asyncapi
+
+import (
+	"fmt"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	asyncapidoc "github.com/cisco-developer/api-insights/api/pkg/asyncapi"
+)
+
+// check finds rule violations in an AsyncAPI document.
+type check func(doc *asyncapidoc.Document) []*analyzer.AsyncAPIFinding
+
+func NewCompletenessClient() (models.SpecDocAnalyzer, error) {
+	return &client{name: analyzer.AsyncAPICompleteness, checks: completenessChecks}, nil
+}
+
+func NewGuidelinesClient() (models.SpecDocAnalyzer, error) {
+	return &client{name: analyzer.AsyncAPIGuidelines, checks: guidelinesChecks}, nil
+}
+
+// client implements models.SpecDocAnalyzer.
+type client struct {
+	name   analyzer.SpecAnalyzer
+	checks []check
+}
+
+func (c *client) Analyze(doc models.SpecDoc, cfgMap analyzer.Config, serviceNameID *string) (*analyzer.Result, error) {
+	if doc == nil || *doc == "" {
+		return nil, fmt.Errorf("doc is nil or empty")
+	}
+	data := []byte(*doc)
+	parsed, err := asyncapidoc.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("analyzer: %v", err)
+	}
+
+	result := &analyzer.AsyncAPIResult{Analyzer: c.name, Doc: data}
+	for _, check := range c.checks {
+		result.Findings = append(result.Findings, check(parsed)...)
+	}
+	return result.Result()
+}
+
+// eachOperation calls fn for each operation of doc.
+func eachOperation(doc *asyncapidoc.Document, fn func(channel *asyncapidoc.Channel, op *asyncapidoc.Operation)) {
+	for _, channel := range doc.Channels {
+		for _, op := range channel.Operations {
+			fn(channel, op)
+		}
+	}
+}
+
+// eachMessage calls fn once for each message of doc, as messages may be shared by operations.
+func eachMessage(doc *asyncapidoc.Document, fn func(msg *asyncapidoc.Message)) {
+	seen := map[*asyncapidoc.Message]bool{}
+	eachOperation(doc, func(_ *asyncapidoc.Channel, op *asyncapidoc.Operation) {
+		for _, msg := range op.Messages {
+			if !seen[msg] {
+				seen[msg] = true
+				fn(msg)
+			}
+		}
+	})
+}
+
+func childPath(path []string, children ...string) []string {
+	return append(append([]string{}, path...), children...)
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package asyncapi
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Analyze(t *testing.T) {
+	data, err := os.ReadFile("../../asyncapi/testdata/streetlights-v2.yaml")
+	require.NoError(t, err)
+	doc := models.NewSpecDocFromBytes(data)
+
+	tests := []struct {
+		name      string
+		newClient func() (models.SpecDocAnalyzer, error)
+		wantRules map[rule.NameID]int
+	}{
+		{
+			name:      "completeness",
+			newClient: NewCompletenessClient,
+			wantRules: map[rule.NameID]int{
+				"asyncapi-channel-description":          1,
+				"asyncapi-operation-description":        1,
+				"asyncapi-message-description":          1,
+				"asyncapi-payload-property-description": 2,
+				"asyncapi-message-examples":             2,
+			},
+		},
+		{
+			name:      "guidelines",
+			newClient: NewGuidelinesClient,
+			wantRules: map[rule.NameID]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.newClient()
+			require.NoError(t, err)
+			result, err := c.Analyze(doc, nil, nil)
+			require.NoError(t, err)
+
+			gotRules := map[rule.NameID]int{}
+			result.EachFinding(func(_ rule.SeverityName, ruleNameID rule.NameID, finding *analyzer.Finding) {
+				gotRules[ruleNameID]++
+				assert.NotNil(t, finding.Range)
+			})
+			assert.Equal(t, tt.wantRules, gotRules)
+		})
+	}
+}
+
+func TestClient_Analyze_Positions(t *testing.T) {
+	doc := models.NewSpecDocFromBytes([]byte("asyncapi: 3.0.0\ninfo:\n  title: a\n  version: 1.0.0\n  description: a\nchannels:\n  a:\n    address: a/\n"))
+	c, err := NewGuidelinesClient()
+	require.NoError(t, err)
+	result, err := c.Analyze(doc, nil, nil)
+	require.NoError(t, err)
+
+	var found bool
+	result.EachFinding(func(_ rule.SeverityName, ruleNameID rule.NameID, finding *analyzer.Finding) {
+		if ruleNameID == "asyncapi-channel-no-trailing-slash" {
+			found = true
+			assert.Equal(t, []string{"channels", "a"}, finding.Path)
+			assert.Equal(t, 7, finding.Range.Start.Line)
+		}
+	})
+	assert.True(t, found)
+}
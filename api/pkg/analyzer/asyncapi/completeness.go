@@ -0,0 +1,155 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package asyncapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	asyncapidoc "github.com/cisco-developer/api-insights/api/pkg/asyncapi"
+)
+
+var completenessChecks = []check{
+	checkInfoDescription,
+	checkChannelDescription,
+	checkOperationDescription,
+	checkMessageDescription,
+	checkMessagePayload,
+	checkPayloadPropertyDescription,
+	checkMessageExamples,
+}
+
+func checkInfoDescription(doc *asyncapidoc.Document) []*analyzer.AsyncAPIFinding {
+	if doc.Info.Description != "" {
+		return nil
+	}
+	return []*analyzer.AsyncAPIFinding{{
+		Rule:     "asyncapi-info-description",
+		Severity: rule.SeverityNameWarning,
+		Message:  "The API should have a description.",
+		Path:     []string{"info"},
+	}}
+}
+
+func checkChannelDescription(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	for _, channel := range doc.Channels {
+		if channel.Description == "" {
+			findings = append(findings, &analyzer.AsyncAPIFinding{
+				Rule:     "asyncapi-channel-description",
+				Severity: rule.SeverityNameWarning,
+				Message:  "Channels should have a description.",
+				Path:     channel.Path,
+			})
+		}
+	}
+	return
+}
+
+func checkOperationDescription(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	eachOperation(doc, func(_ *asyncapidoc.Channel, op *asyncapidoc.Operation) {
+		if op.Summary == "" && op.Description == "" {
+			findings = append(findings, &analyzer.AsyncAPIFinding{
+				Rule:     "asyncapi-operation-description",
+				Severity: rule.SeverityNameInfo,
+				Message:  "Operations should have a summary or description.",
+				Path:     op.Path,
+			})
+		}
+	})
+	return
+}
+
+func checkMessageDescription(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	eachMessage(doc, func(msg *asyncapidoc.Message) {
+		if msg.Title == "" && msg.Summary == "" && msg.Description == "" {
+			findings = append(findings, &analyzer.AsyncAPIFinding{
+				Rule:     "asyncapi-message-description",
+				Severity: rule.SeverityNameWarning,
+				Message:  "Messages should have a title, summary or description.",
+				Path:     msg.Path,
+			})
+		}
+	})
+	return
+}
+
+func checkMessagePayload(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	eachMessage(doc, func(msg *asyncapidoc.Message) {
+		if len(msg.Payload) == 0 {
+			findings = append(findings, &analyzer.AsyncAPIFinding{
+				Rule:     "asyncapi-message-payload",
+				Severity: rule.SeverityNameError,
+				Message:  "Messages must document their payload schema.",
+				Path:     msg.Path,
+			})
+		}
+	})
+	return
+}
+
+func checkPayloadPropertyDescription(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	seen := map[string]bool{}
+	eachMessage(doc, func(msg *asyncapidoc.Message) {
+		// Payload schemas may be shared by messages.
+		key := strings.Join(msg.PayloadPath, "|")
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		properties, _ := msg.Payload["properties"].(map[string]interface{})
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, _ := properties[name].(map[string]interface{})
+			if description, _ := property["description"].(string); description == "" {
+				findings = append(findings, &analyzer.AsyncAPIFinding{
+					Rule:     "asyncapi-payload-property-description",
+					Severity: rule.SeverityNameInfo,
+					Message:  "Payload properties should have a description.",
+					Path:     childPath(msg.PayloadPath, "properties", name),
+				})
+			}
+		}
+	})
+	return
+}
+
+func checkMessageExamples(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	eachMessage(doc, func(msg *asyncapidoc.Message) {
+		if len(msg.Examples) > 0 {
+			return
+		}
+		if _, ok := msg.Payload["examples"]; ok {
+			return
+		}
+		if _, ok := msg.Payload["example"]; ok {
+			return
+		}
+		findings = append(findings, &analyzer.AsyncAPIFinding{
+			Rule:     "asyncapi-message-examples",
+			Severity: rule.SeverityNameWarning,
+			Message:  "Messages should have examples.",
+			Path:     msg.Path,
+		})
+	})
+	return
+}
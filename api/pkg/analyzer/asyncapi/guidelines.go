@@ -0,0 +1,112 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package asyncapi
+
+import (
+	"strings"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	asyncapidoc "github.com/cisco-developer/api-insights/api/pkg/asyncapi"
+)
+
+var guidelinesChecks = []check{
+	checkServers,
+	checkChannelAddress,
+	checkOperationID,
+	checkMessageName,
+	checkMessageContentType,
+}
+
+func checkServers(doc *asyncapidoc.Document) []*analyzer.AsyncAPIFinding {
+	if doc.HasServers {
+		return nil
+	}
+	return []*analyzer.AsyncAPIFinding{{
+		Rule:     "asyncapi-servers",
+		Severity: rule.SeverityNameWarning,
+		Message:  "The API should define at least one server.",
+		Path:     []string{"asyncapi"},
+	}}
+}
+
+func checkChannelAddress(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	for _, channel := range doc.Channels {
+		switch {
+		case strings.Contains(channel.Address, "?"):
+			findings = append(findings, &analyzer.AsyncAPIFinding{
+				Rule:     "asyncapi-channel-no-query",
+				Severity: rule.SeverityNameError,
+				Message:  "Channel addresses must not contain query parameters.",
+				Path:     channel.Path,
+			})
+		case strings.HasSuffix(channel.Address, "/"):
+			findings = append(findings, &analyzer.AsyncAPIFinding{
+				Rule:     "asyncapi-channel-no-trailing-slash",
+				Severity: rule.SeverityNameWarning,
+				Message:  "Channel addresses should not end with a slash.",
+				Path:     channel.Path,
+			})
+		}
+	}
+	return
+}
+
+func checkOperationID(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	eachOperation(doc, func(_ *asyncapidoc.Channel, op *asyncapidoc.Operation) {
+		if op.ID == "" {
+			findings = append(findings, &analyzer.AsyncAPIFinding{
+				Rule:     "asyncapi-operation-operationId",
+				Severity: rule.SeverityNameWarning,
+				Message:  "Operations should have an operationId.",
+				Path:     op.Path,
+			})
+		}
+	})
+	return
+}
+
+func checkMessageName(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	eachMessage(doc, func(msg *asyncapidoc.Message) {
+		if msg.Name == "" {
+			findings = append(findings, &analyzer.AsyncAPIFinding{
+				Rule:     "asyncapi-message-name",
+				Severity: rule.SeverityNameInfo,
+				Message:  "Messages should have a name.",
+				Path:     msg.Path,
+			})
+		}
+	})
+	return
+}
+
+func checkMessageContentType(doc *asyncapidoc.Document) (findings []*analyzer.AsyncAPIFinding) {
+	if doc.DefaultContentType != "" {
+		return nil
+	}
+	eachMessage(doc, func(msg *asyncapidoc.Message) {
+		if msg.ContentType == "" {
+			findings = append(findings, &analyzer.AsyncAPIFinding{
+				Rule:     "asyncapi-message-content-type",
+				Severity: rule.SeverityNameWarning,
+				Message:  "Messages should have a contentType, unless the API has a defaultContentType.",
+				Path:     msg.Path,
+			})
+		}
+	})
+	return
+}
@@ -0,0 +1,244 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fixer attaches machine-applicable fixes (RFC 6902 JSON Patches) to analyzer findings & applies them to docs.
+package fixer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fixer builds the fix of a finding at path in doc, or returns nil if the finding cannot be fixed mechanically.
+// Fixers only need to set FindingFix.Description & FindingFix.Patch.
+type Fixer func(doc *Doc, path []string) *analyzer.FindingFix
+
+var fixers = map[rule.NameID]Fixer{
+	"operation-operationId":                 fixOperationID,
+	"asyncapi-operation-operationId":        fixOperationID,
+	"operation-description":                 fixDescription,
+	"info-description":                      fixDescription,
+	"description-for-every-attribute":       fixDescription,
+	"asyncapi-info-description":             fixDescription,
+	"asyncapi-channel-description":          fixDescription,
+	"asyncapi-operation-description":        fixDescription,
+	"asyncapi-message-description":          fixDescription,
+	"asyncapi-payload-property-description": fixDescription,
+	"oas3-api-servers":                      fixServers,
+	"oas2-field-names-pas-camel-case":       fixFieldNameCasing,
+	"oas3-field-names-pas-camel-case":       fixFieldNameCasing,
+}
+
+// Fixable returns whether findings of rule ruleNameID may carry a fix.
+func Fixable(ruleNameID rule.NameID) bool {
+	_, ok := fixers[ruleNameID]
+	return ok
+}
+
+// Attach attaches fixes to the fixable findings of result, which were found in doc data.
+// Findings which already carry a fix are left as is.
+func Attach(data []byte, result *analyzer.Result) error {
+	if result == nil {
+		return nil
+	}
+	doc, err := NewDoc(data)
+	if err != nil {
+		return err
+	}
+	result.EachFinding(func(_ rule.SeverityName, ruleNameID rule.NameID, finding *analyzer.Finding) {
+		if finding.Fix != nil {
+			return
+		}
+		fixer, ok := fixers[ruleNameID]
+		if !ok {
+			return
+		}
+		fix := fixer(doc, finding.Path)
+		if fix == nil || len(fix.Patch) == 0 {
+			return
+		}
+		fix.ID = FixID(ruleNameID, finding.Path)
+		fix.Rule = ruleNameID
+		finding.Fix = fix
+	})
+	return nil
+}
+
+// FixID returns the ID of the fix of a finding of rule ruleNameID at path,
+// which is stable across analyses of the same doc.
+func FixID(ruleNameID rule.NameID, path []string) string {
+	sum := sha256.Sum256([]byte(string(ruleNameID) + "\x00" + Pointer(path)))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// ApplyResult represents the result of Apply.
+type ApplyResult struct {
+	// Doc is the fixed doc, in the format of the original doc.
+	Doc []byte
+	// Applied contains the IDs of the fixes applied to Doc.
+	Applied []string
+	// Skipped contains the IDs of the fixes which could not be applied, e.g. as they conflicted with other fixes.
+	Skipped []string
+}
+
+// Apply applies fixes to doc data. Each fix is applied atomically: a fix which fails to apply is skipped.
+func Apply(data []byte, fixes []*analyzer.FindingFix) (*ApplyResult, error) {
+	isJSON := bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+	doc, err := utils.ConvertDoc(data, utils.DocFormatJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ApplyResult{Applied: []string{}, Skipped: []string{}}
+	for _, fix := range orderFixes(fixes) {
+		patched, err := applyFix(doc, fix)
+		if err != nil {
+			res.Skipped = append(res.Skipped, fix.ID)
+			continue
+		}
+		doc = patched
+		res.Applied = append(res.Applied, fix.ID)
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, doc, "", "  "); err != nil {
+		return nil, err
+	}
+	res.Doc = out.Bytes()
+	if !isJSON {
+		if res.Doc, err = utils.ConvertDoc(res.Doc, utils.DocFormatYAML); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func applyFix(doc []byte, fix *analyzer.FindingFix) ([]byte, error) {
+	data, err := json.Marshal(fix.Patch)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.DecodePatch(data)
+	if err != nil {
+		return nil, err
+	}
+	return patch.Apply(doc)
+}
+
+// orderFixes orders fixes so that fixes which move values (e.g. renames) are applied last, deepest first,
+// as they invalidate the paths of other fixes.
+func orderFixes(fixes []*analyzer.FindingFix) []*analyzer.FindingFix {
+	var ordered []*analyzer.FindingFix
+	for _, fix := range fixes {
+		if fix != nil {
+			ordered = append(ordered, fix)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		di, dj := moveDepth(ordered[i]), moveDepth(ordered[j])
+		if (di < 0) != (dj < 0) {
+			return di < 0
+		}
+		return di > dj
+	})
+	return ordered
+}
+
+// moveDepth returns the depth of the deepest value moved by fix, or -1 if fix moves no value.
+func moveDepth(fix *analyzer.FindingFix) int {
+	depth := -1
+	for _, op := range fix.Patch {
+		if op.Op == "move" {
+			if d := strings.Count(op.From, "/"); d > depth {
+				depth = d
+			}
+		}
+	}
+	return depth
+}
+
+// Pointer returns the JSON Pointer (RFC 6901) of path.
+func Pointer(path []string) string {
+	var b strings.Builder
+	for _, seg := range path {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(seg, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// Doc represents a doc being fixed.
+type Doc struct {
+	root         interface{}
+	operationIDs map[string]bool
+}
+
+// NewDoc constructs a new Doc from doc data (JSON or YAML).
+func NewDoc(data []byte) (*Doc, error) {
+	jsonData, err := utils.ConvertDoc(data, utils.DocFormatJSON)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Doc{}
+	if err := json.Unmarshal(jsonData, &doc.root); err != nil {
+		return nil, fmt.Errorf("fixer: failed to decode doc: %v", err)
+	}
+	return doc, nil
+}
+
+// Lookup returns the value at path in the doc.
+func (d *Doc) Lookup(path []string) (interface{}, bool) {
+	v := d.root
+	for _, seg := range path {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			child, ok := node[seg]
+			if !ok {
+				return nil, false
+			}
+			v = child
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// lookupObject returns the object at path in the doc.
+func (d *Doc) lookupObject(path []string) (map[string]interface{}, bool) {
+	v, ok := d.Lookup(path)
+	if !ok {
+		return nil, false
+	}
+	obj, ok := v.(map[string]interface{})
+	return obj, ok
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fixer
+
+import (
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDoc = `openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPetsByPetId
+      responses:
+        "200":
+          description: ok
+    delete:
+      responses:
+        "204":
+          description: deleted
+components:
+  schemas:
+    Pet:
+      required:
+        - Pet_Name
+      properties:
+        Pet_Name:
+          type: string
+        id:
+          type: string
+          description: ""
+`
+
+func newTestResult(findings map[rule.NameID][]string) *analyzer.Result {
+	result := analyzer.NewResult()
+	result.Findings[rule.SeverityNameWarning].Rules = map[rule.NameID]*analyzer.Findings{}
+	for ruleNameID, path := range findings {
+		result.Findings[rule.SeverityNameWarning].Rules[ruleNameID] = &analyzer.Findings{
+			Data: []*analyzer.Finding{{Path: path}},
+		}
+	}
+	return result
+}
+
+func fixOf(result *analyzer.Result, ruleNameID rule.NameID) *analyzer.FindingFix {
+	return result.Findings[rule.SeverityNameWarning].Rules[ruleNameID].Data[0].Fix
+}
+
+func TestAttach(t *testing.T) {
+	result := newTestResult(map[rule.NameID][]string{
+		"operation-operationId":           {"paths", "/pets/{petId}", "delete"},
+		"info-description":                {"info"},
+		"description-for-every-attribute": {"components", "schemas", "Pet", "properties", "id", "description"},
+		"oas3-api-servers":                {},
+		"oas3-field-names-pas-camel-case": {"components", "schemas", "Pet", "properties", "Pet_Name"},
+		"operation-tags":                  {"paths", "/pets/{petId}", "get"},
+	})
+	require.NoError(t, Attach([]byte(testDoc), result))
+
+	fix := fixOf(result, "operation-operationId")
+	require.NotNil(t, fix)
+	assert.Equal(t, FixID("operation-operationId", []string{"paths", "/pets/{petId}", "delete"}), fix.ID)
+	assert.Equal(t, rule.NameID("operation-operationId"), fix.Rule)
+	assert.Equal(t, []*analyzer.FindingFixPatchOperation{
+		{Op: "add", Path: "/paths/~1pets~1{petId}/delete/operationId", Value: "deletePetsByPetId"},
+	}, fix.Patch)
+
+	fix = fixOf(result, "description-for-every-attribute")
+	require.NotNil(t, fix)
+	assert.Equal(t, "/components/schemas/Pet/properties/id/description", fix.Patch[0].Path)
+
+	fix = fixOf(result, "oas3-field-names-pas-camel-case")
+	require.NotNil(t, fix)
+	assert.Equal(t, []*analyzer.FindingFixPatchOperation{
+		{Op: "move", From: "/components/schemas/Pet/properties/Pet_Name", Path: "/components/schemas/Pet/properties/petName"},
+		{Op: "replace", Path: "/components/schemas/Pet/required/0", Value: "petName"},
+	}, fix.Patch)
+
+	assert.NotNil(t, fixOf(result, "info-description"))
+	assert.NotNil(t, fixOf(result, "oas3-api-servers"))
+	assert.Nil(t, fixOf(result, "operation-tags"))
+}
+
+func TestApply(t *testing.T) {
+	result := newTestResult(map[rule.NameID][]string{
+		"operation-operationId":           {"paths", "/pets/{petId}", "delete"},
+		"description-for-every-attribute": {"components", "schemas", "Pet", "properties", "Pet_Name"},
+		"oas3-field-names-pas-camel-case": {"components", "schemas", "Pet", "properties", "Pet_Name"},
+	})
+	require.NoError(t, Attach([]byte(testDoc), result))
+
+	var fixes []*analyzer.FindingFix
+	result.EachFinding(func(_ rule.SeverityName, _ rule.NameID, finding *analyzer.Finding) {
+		fixes = append(fixes, finding.Fix)
+	})
+	conflicting := &analyzer.FindingFix{
+		ID:    "conflicting",
+		Patch: []*analyzer.FindingFixPatchOperation{{Op: "replace", Path: "/paths/~1cats/get/operationId", Value: "getCats"}},
+	}
+	fixes = append(fixes, conflicting)
+
+	res, err := Apply([]byte(testDoc), fixes)
+	require.NoError(t, err)
+	assert.Len(t, res.Applied, 3)
+	assert.Equal(t, []string{"conflicting"}, res.Skipped)
+	assert.Equal(t, `openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPetsByPetId
+      responses:
+        "200":
+          description: ok
+    delete:
+      responses:
+        "204":
+          description: deleted
+      operationId: deletePetsByPetId
+components:
+  schemas:
+    Pet:
+      required:
+        - petName
+      properties:
+        id:
+          type: string
+          description: ""
+        petName:
+          type: string
+          description: 'TODO: Add a description.'
+`, string(res.Doc))
+}
+
+func TestApply_MoveAndReplace(t *testing.T) {
+	doc := `{"components":{"schemas":{"Pet":{"properties":{"Pet_Name":{"description":"","properties":{"First_Name":{"type":"string"}}}}}}}}`
+	op := func(op, from, path string, value interface{}) *analyzer.FindingFixPatchOperation {
+		return &analyzer.FindingFixPatchOperation{Op: op, From: from, Path: path, Value: value}
+	}
+	const pet = "/components/schemas/Pet/properties"
+	fixes := []*analyzer.FindingFix{
+		// Moves are listed first, but applied last, deepest first: the paths of the other fixes stay valid.
+		{ID: "move-pet-name", Patch: []*analyzer.FindingFixPatchOperation{op("move", pet+"/Pet_Name", pet+"/petName", nil)}},
+		{ID: "move-first-name", Patch: []*analyzer.FindingFixPatchOperation{op("move", pet+"/Pet_Name/properties/First_Name", pet+"/Pet_Name/properties/firstName", nil)}},
+		{ID: "replace-description", Patch: []*analyzer.FindingFixPatchOperation{op("replace", "", pet+"/Pet_Name/description", "The name.")}},
+		// Conflicts with move-pet-name, which moves the value it replaces.
+		{ID: "replace-moved", Patch: []*analyzer.FindingFixPatchOperation{op("replace", "", pet+"/petName/description", "Conflicting.")}},
+	}
+
+	res, err := Apply([]byte(doc), fixes)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replace-description", "move-first-name", "move-pet-name"}, res.Applied)
+	assert.Equal(t, []string{"replace-moved"}, res.Skipped)
+	assert.JSONEq(t, `{"components":{"schemas":{"Pet":{"properties":{"petName":{"description":"The name.","properties":{"firstName":{"type":"string"}}}}}}}}`, string(res.Doc))
+}
+
+func Test_lowerCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"Pet_Name": "petName",
+		"pet-name": "petName",
+		"PetName":  "petName",
+		"ID":       "id",
+		"user_ID":  "userId",
+		"petName":  "petName",
+	}
+	for s, want := range tests {
+		assert.Equal(t, want, lowerCamelCase(s), s)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fixer
+
+import (
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DescriptionPlaceholder is the description added by description fixes, to be replaced by a real description.
+const DescriptionPlaceholder = "TODO: Add a description."
+
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true,
+}
+
+// fixOperationID adds an operationId, derived from the method (or AsyncAPI action) & the path (or channel) of the operation.
+func fixOperationID(doc *Doc, path []string) *analyzer.FindingFix {
+	if len(path) != 3 {
+		return nil
+	}
+	op, ok := doc.lookupObject(path)
+	if !ok {
+		return nil
+	}
+	if id, _ := op["operationId"].(string); id != "" {
+		return nil
+	}
+	if path[0] == "paths" && !httpMethods[strings.ToLower(path[2])] {
+		return nil
+	}
+	id := doc.uniqueOperationID(operationID(path[2], path[1]))
+	return &analyzer.FindingFix{
+		Description: "Add operationId " + id + ".",
+		Patch: []*analyzer.FindingFixPatchOperation{
+			{Op: "add", Path: Pointer(append(clonePath(path), "operationId")), Value: id},
+		},
+	}
+}
+
+// operationID returns an operationId like getPetsByPetId for action "get" & name "/pets/{petId}".
+func operationID(action, name string) string {
+	id := lowerCamelCase(action)
+	for _, seg := range strings.Split(name, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			id += "By" + upperFirst(lowerCamelCase(strings.Trim(seg, "{}")))
+			continue
+		}
+		id += upperFirst(lowerCamelCase(seg))
+	}
+	return id
+}
+
+// uniqueOperationID returns id, suffixed if it's already used by an operation of the doc (or a previous fix).
+func (d *Doc) uniqueOperationID(id string) string {
+	if d.operationIDs == nil {
+		d.operationIDs = map[string]bool{}
+		d.collectOperationIDs(d.root)
+	}
+	unique := id
+	for i := 2; d.operationIDs[unique]; i++ {
+		unique = id + strconv.Itoa(i)
+	}
+	d.operationIDs[unique] = true
+	return unique
+}
+
+func (d *Doc) collectOperationIDs(v interface{}) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for k, child := range node {
+			if id, ok := child.(string); ok && k == "operationId" {
+				d.operationIDs[id] = true
+				continue
+			}
+			d.collectOperationIDs(child)
+		}
+	case []interface{}:
+		for _, child := range node {
+			d.collectOperationIDs(child)
+		}
+	}
+}
+
+// fixDescription adds a placeholder description to the object at path (or to its parent, if path is its description).
+func fixDescription(doc *Doc, path []string) *analyzer.FindingFix {
+	if n := len(path); n > 0 && path[n-1] == "description" {
+		path = path[:n-1]
+	}
+	obj, ok := doc.lookupObject(path)
+	if !ok {
+		return nil
+	}
+	if description, _ := obj["description"].(string); strings.TrimSpace(description) != "" {
+		return nil
+	}
+	return &analyzer.FindingFix{
+		Description: "Add a placeholder description.",
+		Patch: []*analyzer.FindingFixPatchOperation{
+			{Op: "add", Path: Pointer(append(clonePath(path), "description")), Value: DescriptionPlaceholder},
+		},
+	}
+}
+
+// fixServers adds a servers block with the relative server URL "/" to an OpenAPI 3 doc which has none.
+func fixServers(doc *Doc, _ []string) *analyzer.FindingFix {
+	root, ok := doc.lookupObject(nil)
+	if !ok {
+		return nil
+	}
+	if _, ok := root["openapi"]; !ok {
+		return nil
+	}
+	if servers, _ := root["servers"].([]interface{}); len(servers) > 0 {
+		return nil
+	}
+	return &analyzer.FindingFix{
+		Description: `Add a servers block with server URL "/".`,
+		Patch: []*analyzer.FindingFixPatchOperation{
+			{Op: "add", Path: "/servers", Value: []interface{}{map[string]interface{}{"url": "/"}}},
+		},
+	}
+}
+
+// fixFieldNameCasing renames the property at path (i.e. .../properties/{name}) to lowerCamelCase,
+// along with its entry in the required properties of its schema.
+func fixFieldNameCasing(doc *Doc, path []string) *analyzer.FindingFix {
+	n := len(path)
+	if n < 2 || path[n-2] != "properties" {
+		return nil
+	}
+	properties, ok := doc.lookupObject(path[:n-1])
+	if !ok {
+		return nil
+	}
+	name := path[n-1]
+	newName := lowerCamelCase(name)
+	if newName == "" || newName == name {
+		return nil
+	}
+	if _, exists := properties[newName]; exists {
+		return nil
+	}
+	newPath := append(clonePath(path[:n-1]), newName)
+	fix := &analyzer.FindingFix{
+		Description: "Rename field " + name + " to " + newName + ".",
+		Patch: []*analyzer.FindingFixPatchOperation{
+			{Op: "move", From: Pointer(path), Path: Pointer(newPath)},
+		},
+	}
+	if schema, ok := doc.lookupObject(path[:n-2]); ok {
+		required, _ := schema["required"].([]interface{})
+		for i, r := range required {
+			if r == name {
+				fix.Patch = append(fix.Patch, &analyzer.FindingFixPatchOperation{
+					Op:    "replace",
+					Path:  Pointer(append(clonePath(path[:n-2]), "required", strconv.Itoa(i))),
+					Value: newName,
+				})
+			}
+		}
+	}
+	return fix
+}
+
+// lowerCamelCase converts s (e.g. Pet_Name, pet-name or PetName) to lowerCamelCase (e.g. petName).
+func lowerCamelCase(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for i, word := range words {
+		if isUpper(word) {
+			word = strings.ToLower(word)
+		}
+		if i == 0 {
+			b.WriteString(lowerFirst(word))
+		} else {
+			b.WriteString(upperFirst(word))
+		}
+	}
+	return b.String()
+}
+
+func isUpper(s string) bool {
+	for _, r := range s {
+		if unicode.IsLower(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func clonePath(path []string) []string {
+	return append([]string{}, path...)
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package graphql implements the analyzers of GraphQL specs (see analyzer.GraphQLLint).
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	graphqldoc "github.com/cisco-developer/api-insights/api/pkg/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// check finds rule violations in a GraphQL schema.
+type check func(schema *ast.Schema) []*analyzer.GraphQLFinding
+
+func NewLintClient() (models.SpecDocAnalyzer, error) {
+	return &client{name: analyzer.GraphQLLint, checks: lintChecks}, nil
+}
+
+// client implements models.SpecDocAnalyzer.
+type client struct {
+	name   analyzer.SpecAnalyzer
+	checks []check
+}
+
+func (c *client) Analyze(doc models.SpecDoc, cfgMap analyzer.Config, serviceNameID *string) (*analyzer.Result, error) {
+	if doc == nil || *doc == "" {
+		return nil, fmt.Errorf("doc is nil or empty")
+	}
+	schema, err := graphqldoc.Load([]byte(*doc))
+	if err != nil {
+		return nil, fmt.Errorf("analyzer: %v", err)
+	}
+
+	result := &analyzer.GraphQLResult{Analyzer: c.name}
+	for _, check := range c.checks {
+		result.Findings = append(result.Findings, check(schema)...)
+	}
+	return result.Result()
+}
+
+// newFinding returns a finding of ruleName at the SDL token of pos, identified by path.
+func newFinding(ruleName string, severity rule.SeverityName, message string, pos *ast.Position, path ...string) *analyzer.GraphQLFinding {
+	startLine, startColumn, endLine, endColumn := graphqldoc.Range(pos)
+	return &analyzer.GraphQLFinding{
+		Rule:     ruleName,
+		Severity: severity,
+		Message:  message,
+		Path:     path,
+		Range: &analyzer.FindingPositionRange{
+			Start: &analyzer.FindingPosition{Line: startLine, Column: startColumn},
+			End:   &analyzer.FindingPosition{Line: endLine, Column: endColumn},
+		},
+	}
+}
+
+func typePath(def *ast.Definition) []string {
+	return []string{"types", def.Name}
+}
+
+func fieldPath(def *ast.Definition, field *ast.FieldDefinition) []string {
+	return []string{"types", def.Name, "fields", field.Name}
+}
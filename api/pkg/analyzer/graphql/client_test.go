@@ -0,0 +1,69 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphql
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Analyze(t *testing.T) {
+	data, err := os.ReadFile("../../graphql/testdata/library.graphql")
+	require.NoError(t, err)
+
+	c, err := NewLintClient()
+	require.NoError(t, err)
+	result, err := c.Analyze(models.NewSpecDocFromBytes(data), nil, nil)
+	require.NoError(t, err)
+
+	gotRules := map[rule.NameID]int{}
+	result.EachFinding(func(_ rule.SeverityName, ruleNameID rule.NameID, finding *analyzer.Finding) {
+		gotRules[ruleNameID]++
+		require.NotNil(t, finding.Range)
+		if ruleNameID == "graphql-deprecation-reason" {
+			assert.Equal(t, []string{"types", "Book", "fields", "isbn"}, finding.Path)
+			// The position of a field with a description is its description.
+			assert.Equal(t, 18, finding.Range.Start.Line)
+		}
+		if ruleNameID == "graphql-field-camel-case" {
+			assert.Equal(t, []string{"types", "Author", "fields", "Name"}, finding.Path)
+			assert.Equal(t, &analyzer.FindingPosition{Line: 30, Column: 3}, finding.Range.Start)
+			assert.Equal(t, &analyzer.FindingPosition{Line: 30, Column: 7}, finding.Range.End)
+		}
+	})
+	assert.Equal(t, map[rule.NameID]int{
+		"graphql-type-description":      6,
+		"graphql-field-description":     11,
+		"graphql-field-camel-case":      1,
+		"graphql-argument-camel-case":   1,
+		"graphql-enum-value-upper-case": 1,
+		"graphql-deprecation-reason":    1,
+	}, gotRules)
+}
+
+func TestClient_Analyze_Invalid(t *testing.T) {
+	c, err := NewLintClient()
+	require.NoError(t, err)
+	_, err = c.Analyze(models.NewSpecDocFromBytes([]byte("type Query { a: Missing }")), nil, nil)
+	assert.Error(t, err)
+}
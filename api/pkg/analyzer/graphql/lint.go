@@ -0,0 +1,162 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphql
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	graphqldoc "github.com/cisco-developer/api-insights/api/pkg/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+var lintChecks = []check{
+	checkTypeDescriptions,
+	checkFieldDescriptions,
+	checkTypeNames,
+	checkFieldNames,
+	checkArgumentNames,
+	checkEnumValueNames,
+	checkInputTypeNames,
+	checkDeprecationReasons,
+}
+
+var (
+	pascalCaseRegexp     = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	camelCaseRegexp      = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	upperSnakeCaseRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+)
+
+// hasFields checks if def is a type with fields, i.e. an object, interface or input.
+func hasFields(def *ast.Definition) bool {
+	switch def.Kind {
+	case ast.Object, ast.Interface, ast.InputObject:
+		return true
+	}
+	return false
+}
+
+func checkTypeDescriptions(schema *ast.Schema) (findings []*analyzer.GraphQLFinding) {
+	for _, def := range graphqldoc.Definitions(schema) {
+		if strings.TrimSpace(def.Description) == "" {
+			findings = append(findings, newFinding("graphql-type-description", rule.SeverityNameWarning,
+				"Types should have a description.", def.Position, typePath(def)...))
+		}
+	}
+	return
+}
+
+func checkFieldDescriptions(schema *ast.Schema) (findings []*analyzer.GraphQLFinding) {
+	for _, def := range graphqldoc.Definitions(schema) {
+		if !hasFields(def) {
+			continue
+		}
+		for _, field := range graphqldoc.Fields(def) {
+			if strings.TrimSpace(field.Description) == "" {
+				findings = append(findings, newFinding("graphql-field-description", rule.SeverityNameInfo,
+					"Fields should have a description.", field.Position, fieldPath(def, field)...))
+			}
+		}
+	}
+	return
+}
+
+func checkTypeNames(schema *ast.Schema) (findings []*analyzer.GraphQLFinding) {
+	for _, def := range graphqldoc.Definitions(schema) {
+		if !pascalCaseRegexp.MatchString(def.Name) {
+			findings = append(findings, newFinding("graphql-type-pascal-case", rule.SeverityNameWarning,
+				"Type names should be PascalCase.", def.Position, typePath(def)...))
+		}
+	}
+	return
+}
+
+func checkFieldNames(schema *ast.Schema) (findings []*analyzer.GraphQLFinding) {
+	for _, def := range graphqldoc.Definitions(schema) {
+		if !hasFields(def) {
+			continue
+		}
+		for _, field := range graphqldoc.Fields(def) {
+			if !camelCaseRegexp.MatchString(field.Name) {
+				findings = append(findings, newFinding("graphql-field-camel-case", rule.SeverityNameWarning,
+					"Field names should be camelCase.", field.Position, fieldPath(def, field)...))
+			}
+		}
+	}
+	return
+}
+
+func checkArgumentNames(schema *ast.Schema) (findings []*analyzer.GraphQLFinding) {
+	for _, def := range graphqldoc.Definitions(schema) {
+		if def.Kind != ast.Object && def.Kind != ast.Interface {
+			continue
+		}
+		for _, field := range graphqldoc.Fields(def) {
+			for _, arg := range field.Arguments {
+				if !camelCaseRegexp.MatchString(arg.Name) {
+					findings = append(findings, newFinding("graphql-argument-camel-case", rule.SeverityNameWarning,
+						"Argument names should be camelCase.", arg.Position, append(fieldPath(def, field), "arguments", arg.Name)...))
+				}
+			}
+		}
+	}
+	return
+}
+
+func checkEnumValueNames(schema *ast.Schema) (findings []*analyzer.GraphQLFinding) {
+	for _, def := range graphqldoc.Definitions(schema) {
+		if def.Kind != ast.Enum {
+			continue
+		}
+		for _, value := range def.EnumValues {
+			if !upperSnakeCaseRegexp.MatchString(value.Name) {
+				findings = append(findings, newFinding("graphql-enum-value-upper-case", rule.SeverityNameWarning,
+					"Enum values should be UPPER_CASE.", value.Position, "types", def.Name, "values", value.Name))
+			}
+		}
+	}
+	return
+}
+
+func checkInputTypeNames(schema *ast.Schema) (findings []*analyzer.GraphQLFinding) {
+	for _, def := range graphqldoc.Definitions(schema) {
+		if def.Kind == ast.InputObject && !strings.HasSuffix(def.Name, "Input") {
+			findings = append(findings, newFinding("graphql-input-type-suffix", rule.SeverityNameInfo,
+				"Input type names should end with Input.", def.Position, typePath(def)...))
+		}
+	}
+	return
+}
+
+func checkDeprecationReasons(schema *ast.Schema) (findings []*analyzer.GraphQLFinding) {
+	const ruleName, message = "graphql-deprecation-reason", "Deprecations should have a reason, e.g. the field to use instead."
+	for _, def := range graphqldoc.Definitions(schema) {
+		for _, field := range graphqldoc.Fields(def) {
+			if deprecated, reason := graphqldoc.Deprecation(field.Directives); deprecated && strings.TrimSpace(reason) == "" {
+				findings = append(findings, newFinding(ruleName, rule.SeverityNameWarning, message, field.Position, fieldPath(def, field)...))
+			}
+		}
+		for _, value := range def.EnumValues {
+			if deprecated, reason := graphqldoc.Deprecation(value.Directives); deprecated && strings.TrimSpace(reason) == "" {
+				findings = append(findings, newFinding(ruleName, rule.SeverityNameWarning, message, value.Position, "types", def.Name, "values", value.Name))
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package protobuf implements the analyzers of protobuf specs (see analyzer.ProtobufStyle).
+package protobuf
+
+import (
+	"fmt"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	protodoc "github.com/cisco-developer/api-insights/api/pkg/protobuf"
+)
+
+// check finds rule violations in a protobuf schema.
+type check func(schema *protodoc.Schema) []*analyzer.ProtobufFinding
+
+func NewStyleClient() (models.SpecDocAnalyzer, error) {
+	return &client{name: analyzer.ProtobufStyle, checks: styleChecks}, nil
+}
+
+// client implements models.SpecDocAnalyzer.
+type client struct {
+	name   analyzer.SpecAnalyzer
+	checks []check
+}
+
+func (c *client) Analyze(doc models.SpecDoc, cfgMap analyzer.Config, serviceNameID *string) (*analyzer.Result, error) {
+	if doc == nil || *doc == "" {
+		return nil, fmt.Errorf("doc is nil or empty")
+	}
+	schema, err := protodoc.Load([]byte(*doc))
+	if err != nil {
+		return nil, fmt.Errorf("analyzer: %v", err)
+	}
+
+	result := &analyzer.ProtobufResult{Analyzer: c.name}
+	for _, check := range c.checks {
+		result.Findings = append(result.Findings, check(schema)...)
+	}
+	return result.Result()
+}
+
+// newFinding returns a finding of ruleName at the definition of pos, identified by path.
+func newFinding(ruleName string, severity rule.SeverityName, message string, pos protodoc.Position, path ...string) *analyzer.ProtobufFinding {
+	return &analyzer.ProtobufFinding{
+		Rule:     ruleName,
+		Severity: severity,
+		Message:  message,
+		Path:     append([]string{pos.File}, path...),
+		Line:     pos.Line,
+		Column:   pos.Column,
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuf
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Analyze(t *testing.T) {
+	billing, err := os.ReadFile("../../protobuf/testdata/billing.proto")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		doc       string
+		wantRules map[rule.NameID]int
+	}{
+		{
+			name: "billing",
+			doc:  string(billing),
+			wantRules: map[rule.NameID]int{
+				"proto-comments": 5,
+			},
+		},
+		{
+			name: "style violations",
+			doc: `syntax = "proto3";
+package Acme.Billing;
+
+// a
+enum status {
+  // a
+  paid = 1;
+}
+
+// a
+message invoice {
+  string customerId = 1;
+}
+
+// a
+service billing_service {
+  // a
+  rpc get_invoice(invoice) returns (invoice);
+}
+`,
+			wantRules: map[rule.NameID]int{
+				"proto-package-lower-snake-case":      1,
+				"proto-enum-pascal-case":              1,
+				"proto-enum-value-upper-snake-case":   1,
+				"proto-enum-zero-value-unspecified":   1,
+				"proto-message-pascal-case":           1,
+				"proto-field-lower-snake-case":        1,
+				"proto-service-pascal-case":           1,
+				"proto-rpc-pascal-case":               1,
+				"proto-rpc-request-naming":            1,
+				"proto-rpc-distinct-request-response": 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewStyleClient()
+			require.NoError(t, err)
+			result, err := c.Analyze(models.NewSpecDocFromBytes([]byte(tt.doc)), nil, nil)
+			require.NoError(t, err)
+
+			gotRules := map[rule.NameID]int{}
+			result.EachFinding(func(_ rule.SeverityName, ruleNameID rule.NameID, finding *analyzer.Finding) {
+				gotRules[ruleNameID]++
+				assert.NotNil(t, finding.Range)
+			})
+			assert.Equal(t, tt.wantRules, gotRules)
+		})
+	}
+}
+
+func TestClient_Analyze_Positions(t *testing.T) {
+	doc := models.NewSpecDocFromBytes([]byte("syntax = \"proto3\";\npackage a.v1;\n\n// A\nmessage A {\n  string Name = 1;\n}\n"))
+	c, err := NewStyleClient()
+	require.NoError(t, err)
+	result, err := c.Analyze(doc, nil, nil)
+	require.NoError(t, err)
+
+	var found bool
+	result.EachFinding(func(_ rule.SeverityName, ruleNameID rule.NameID, finding *analyzer.Finding) {
+		if ruleNameID == "proto-field-lower-snake-case" {
+			found = true
+			assert.Equal(t, []string{"spec.proto", "message", "a.v1.A", "field", "Name"}, finding.Path)
+			assert.Equal(t, 6, finding.Range.Start.Line)
+		}
+	})
+	assert.True(t, found)
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuf
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	protodoc "github.com/cisco-developer/api-insights/api/pkg/protobuf"
+)
+
+var styleChecks = []check{
+	checkSyntax,
+	checkPackage,
+	checkMessageNames,
+	checkFieldNames,
+	checkEnums,
+	checkServiceNames,
+	checkRPCs,
+	checkComments,
+}
+
+var (
+	packageRegexp         = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+	pascalCaseRegexp      = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	lowerSnakeCaseRegexp  = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	upperSnakeCaseRegexp  = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+	packageVersionRegexp  = regexp.MustCompile(`\.v\d+((alpha|beta)\d*)?$`)
+	unspecifiedZeroSuffix = "_UNSPECIFIED"
+)
+
+func checkSyntax(schema *protodoc.Schema) (findings []*analyzer.ProtobufFinding) {
+	for _, file := range schema.Files {
+		if file.Syntax == "" {
+			findings = append(findings, newFinding("proto-syntax-defined", rule.SeverityNameWarning,
+				"Files should declare their syntax explicitly.", protodoc.Position{File: file.Name}, "syntax"))
+		}
+	}
+	return
+}
+
+func checkPackage(schema *protodoc.Schema) (findings []*analyzer.ProtobufFinding) {
+	for _, file := range schema.Files {
+		pos := protodoc.Position{File: file.Name}
+		switch {
+		case file.Package == "":
+			findings = append(findings, newFinding("proto-package-defined", rule.SeverityNameError,
+				"Files must declare a package.", pos, "package"))
+		case !packageRegexp.MatchString(file.Package):
+			findings = append(findings, newFinding("proto-package-lower-snake-case", rule.SeverityNameWarning,
+				"Packages should be lower_snake_case & dot-delimited.", pos, "package"))
+		case !packageVersionRegexp.MatchString(file.Package):
+			findings = append(findings, newFinding("proto-package-versioned", rule.SeverityNameInfo,
+				"Packages should end with a version, e.g. v1.", pos, "package"))
+		}
+	}
+	return
+}
+
+func checkMessageNames(schema *protodoc.Schema) (findings []*analyzer.ProtobufFinding) {
+	for _, msg := range schema.SortedMessages() {
+		if !pascalCaseRegexp.MatchString(msg.Name) {
+			findings = append(findings, newFinding("proto-message-pascal-case", rule.SeverityNameWarning,
+				"Message names should be PascalCase.", msg.Pos, "message", msg.FullName))
+		}
+	}
+	return
+}
+
+func checkFieldNames(schema *protodoc.Schema) (findings []*analyzer.ProtobufFinding) {
+	for _, msg := range schema.SortedMessages() {
+		for _, field := range msg.Fields {
+			if !lowerSnakeCaseRegexp.MatchString(field.Name) {
+				findings = append(findings, newFinding("proto-field-lower-snake-case", rule.SeverityNameWarning,
+					"Field names should be lower_snake_case.", field.Pos, "message", msg.FullName, "field", field.Name))
+			}
+		}
+	}
+	return
+}
+
+func checkEnums(schema *protodoc.Schema) (findings []*analyzer.ProtobufFinding) {
+	for _, enum := range schema.SortedEnums() {
+		if !pascalCaseRegexp.MatchString(enum.Name) {
+			findings = append(findings, newFinding("proto-enum-pascal-case", rule.SeverityNameWarning,
+				"Enum names should be PascalCase.", enum.Pos, "enum", enum.FullName))
+		}
+		for i, value := range enum.Values {
+			if !upperSnakeCaseRegexp.MatchString(value.Name) {
+				findings = append(findings, newFinding("proto-enum-value-upper-snake-case", rule.SeverityNameWarning,
+					"Enum value names should be UPPER_SNAKE_CASE.", value.Pos, "enum", enum.FullName, "value", value.Name))
+			}
+			if i == 0 && (value.Number != 0 || !strings.HasSuffix(value.Name, unspecifiedZeroSuffix)) {
+				findings = append(findings, newFinding("proto-enum-zero-value-unspecified", rule.SeverityNameWarning,
+					"The first value of enums should be a zero value suffixed with _UNSPECIFIED.", value.Pos, "enum", enum.FullName, "value", value.Name))
+			}
+		}
+	}
+	return
+}
+
+func checkServiceNames(schema *protodoc.Schema) (findings []*analyzer.ProtobufFinding) {
+	for _, svc := range schema.SortedServices() {
+		if !pascalCaseRegexp.MatchString(svc.Name) {
+			findings = append(findings, newFinding("proto-service-pascal-case", rule.SeverityNameWarning,
+				"Service names should be PascalCase.", svc.Pos, "service", svc.FullName))
+		}
+	}
+	return
+}
+
+func checkRPCs(schema *protodoc.Schema) (findings []*analyzer.ProtobufFinding) {
+	for _, svc := range schema.SortedServices() {
+		for _, method := range svc.Methods {
+			path := []string{"service", svc.FullName, "rpc", method.Name}
+			if !pascalCaseRegexp.MatchString(method.Name) {
+				findings = append(findings, newFinding("proto-rpc-pascal-case", rule.SeverityNameWarning,
+					"RPC names should be PascalCase.", method.Pos, path...))
+			}
+			if shortName(method.InputType) != method.Name+"Request" {
+				findings = append(findings, newFinding("proto-rpc-request-naming", rule.SeverityNameInfo,
+					"RPC requests should be dedicated messages named <RPC>Request.", method.Pos, path...))
+			}
+			if method.InputType == method.OutputType {
+				findings = append(findings, newFinding("proto-rpc-distinct-request-response", rule.SeverityNameWarning,
+					"RPCs should not use the same message as request & response.", method.Pos, path...))
+			}
+		}
+	}
+	return
+}
+
+// checkComments checks that messages, enums, services & RPCs are documented, which is only known for .proto files.
+func checkComments(schema *protodoc.Schema) (findings []*analyzer.ProtobufFinding) {
+	if !schema.HasSourceInfo {
+		return nil
+	}
+	const ruleName, message = "proto-comments", "Messages, enums, services & RPCs should be documented with a leading comment."
+	for _, msg := range schema.SortedMessages() {
+		if msg.Comment == "" {
+			findings = append(findings, newFinding(ruleName, rule.SeverityNameInfo, message, msg.Pos, "message", msg.FullName))
+		}
+	}
+	for _, enum := range schema.SortedEnums() {
+		if enum.Comment == "" {
+			findings = append(findings, newFinding(ruleName, rule.SeverityNameInfo, message, enum.Pos, "enum", enum.FullName))
+		}
+	}
+	for _, svc := range schema.SortedServices() {
+		if svc.Comment == "" {
+			findings = append(findings, newFinding(ruleName, rule.SeverityNameInfo, message, svc.Pos, "service", svc.FullName))
+		}
+		for _, method := range svc.Methods {
+			if method.Comment == "" {
+				findings = append(findings, newFinding(ruleName, rule.SeverityNameInfo, message, method.Pos, "service", svc.FullName, "rpc", method.Name))
+			}
+		}
+	}
+	return
+}
+
+// shortName returns the name of a definition from its full name.
+func shortName(fullName string) string {
+	return fullName[strings.LastIndex(fullName, ".")+1:]
+}
@@ -22,9 +22,14 @@ import (
 	"github.com/cisco-developer/api-insights/api/internal/db"
 	"github.com/cisco-developer/api-insights/api/internal/models"
 	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
 	"github.com/cisco-developer/api-insights/api/pkg/analyzer/apiclarity"
+	"github.com/cisco-developer/api-insights/api/pkg/analyzer/asyncapi"
 	"github.com/cisco-developer/api-insights/api/pkg/analyzer/completeness"
+	"github.com/cisco-developer/api-insights/api/pkg/analyzer/fixer"
+	"github.com/cisco-developer/api-insights/api/pkg/analyzer/graphql"
 	"github.com/cisco-developer/api-insights/api/pkg/analyzer/guidelines"
+	"github.com/cisco-developer/api-insights/api/pkg/analyzer/protobuf"
 	"github.com/cisco-developer/api-insights/api/pkg/analyzer/security"
 	"github.com/cisco-developer/api-insights/api/pkg/analyzer/woke"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
@@ -80,6 +85,30 @@ func (s *service) Reporter(optionalAnalyzers map[analyzer.SpecAnalyzer]*analyzer
 	return reporter, nil
 }
 
+// docKindAnalyzers maps the analyzers of non-OpenAPI specs to the doc kind they analyze (see models.Spec.DocKind).
+// Other analyzers analyze OpenAPI specs, except for the docKindAgnosticAnalyzers, which analyze specs of any kind.
+var (
+	docKindAnalyzers = map[analyzer.SpecAnalyzer]string{
+		analyzer.AsyncAPICompleteness: models.SpecDocKindAsyncAPI,
+		analyzer.AsyncAPIGuidelines:   models.SpecDocKindAsyncAPI,
+		analyzer.ProtobufStyle:        models.SpecDocKindProtobuf,
+		analyzer.GraphQLLint:          models.SpecDocKindGraphQL,
+	}
+	docKindAgnosticAnalyzers = map[analyzer.SpecAnalyzer]struct{}{
+		analyzer.InclusiveLanguage: {},
+	}
+)
+
+func analyzerSupportsDocKind(analyzerName analyzer.SpecAnalyzer, docKind string) bool {
+	if _, ok := docKindAgnosticAnalyzers[analyzerName]; ok {
+		return true
+	}
+	if kind, ok := docKindAnalyzers[analyzerName]; ok {
+		return kind == docKind
+	}
+	return docKind == models.SpecDocKindOpenAPI
+}
+
 func (s *service) Analyze(req *models.SpecAnalysisRequest) (*models.SpecAnalysisResponse, error) {
 	if !req.HasSpec() {
 		return nil, fmt.Errorf("analyzer: SpecAnalysisRequest.Spec cannot be nil")
@@ -89,7 +118,14 @@ func (s *service) Analyze(req *models.SpecAnalysisRequest) (*models.SpecAnalysis
 		Results: make(map[analyzer.SpecAnalyzer]*models.SpecAnalysis, len(req.Analyzers)),
 	}
 
+	docKind := req.Spec.DocKind()
+	// JSON & YAML docs can be located by paths (see analyzer.Result.ResolvePositions) & patched (see fixer.Attach).
+	isStructuredDoc := req.Spec.Doc != nil && (docKind == models.SpecDocKindOpenAPI || docKind == models.SpecDocKindAsyncAPI)
 	for _, analyzerName := range req.Analyzers {
+		if !analyzerSupportsDocKind(analyzerName, docKind) {
+			shared.LogDebugf("skipping analyzer(%s), which does not support %s specs", analyzerName, docKind)
+			continue
+		}
 		var (
 			cfg            = req.AnalyzersConfigs[analyzerName]
 			analyzerClient models.SpecDocAnalyzer
@@ -106,6 +142,14 @@ func (s *service) Analyze(req *models.SpecAnalysisRequest) (*models.SpecAnalysis
 			analyzerClient, err = apiclarity.NewClient()
 		case analyzer.Security:
 			analyzerClient, err = security.NewClient()
+		case analyzer.AsyncAPICompleteness:
+			analyzerClient, err = asyncapi.NewCompletenessClient()
+		case analyzer.AsyncAPIGuidelines:
+			analyzerClient, err = asyncapi.NewGuidelinesClient()
+		case analyzer.ProtobufStyle:
+			analyzerClient, err = protobuf.NewStyleClient()
+		case analyzer.GraphQLLint:
+			analyzerClient, err = graphql.NewLintClient()
 		default:
 			return nil, fmt.Errorf("analyzer: unsupported analyzer(%s)", analyzerName)
 		}
@@ -122,6 +166,26 @@ func (s *service) Analyze(req *models.SpecAnalysisRequest) (*models.SpecAnalysis
 			continue
 			//return nil, err  // TODO Handle analyzer failures.
 		}
+		if isStructuredDoc {
+			// Locate findings in the analyzed doc, whatever analyzer produced them.
+			result.ResolvePositions([]byte(*req.Spec.Doc))
+			if req.Spec.Sources != nil {
+				// Map findings of a bundled doc back to the original files.
+				result.ResolveSources(req.Spec.Sources.Files, req.Spec.Sources.Locate)
+			}
+		}
+		if req.Spec.OriginalDocType != "" {
+			// Map findings of a converted doc back to the original doc.
+			result.EachFinding(func(_ rule.SeverityName, _ rule.NameID, finding *analyzer.Finding) {
+				finding.OriginalPath = req.Spec.OriginalPath(finding.Path)
+			})
+		} else if isStructuredDoc && req.Spec.Sources == nil {
+			// Fixes are patches against the analyzed doc, so they're only attached if it's the original doc,
+			// i.e. neither converted nor bundled from multiple files.
+			if err := fixer.Attach([]byte(*req.Spec.Doc), result); err != nil {
+				shared.LogErrorf("failed to attach fixes of analyzer(%s) findings: %v", analyzerName, err)
+			}
+		}
 		now := time.Now().UTC()
 		specAnalysis := &models.SpecAnalysis{
 			ID:        shared.TimeUUID(),
@@ -0,0 +1,74 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer"
+	"github.com/cisco-developer/api-insights/api/internal/models/analyzer/rule"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/specbundler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Analyze_Sources(t *testing.T) {
+	spec := &models.Spec{}
+	require.NoError(t, spec.LoadDocFromFiles(specbundler.Files{
+		"openapi.yaml": []byte(`openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    $ref: paths/pets.yaml
+`),
+		"paths/pets.yaml": []byte(`get:
+  responses:
+    "200":
+      description: The blacklisted pets.
+`),
+	}, "openapi.yaml"))
+
+	svc := &service{}
+	res, err := svc.Analyze(&models.SpecAnalysisRequest{
+		Spec:            spec,
+		Analyzers:       []analyzer.SpecAnalyzer{analyzer.InclusiveLanguage},
+		ActiveAnalyzers: map[analyzer.SpecAnalyzer]*analyzer.Analyzer{analyzer.InclusiveLanguage: {NameID: string(analyzer.InclusiveLanguage)}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, res.Results, analyzer.InclusiveLanguage)
+
+	result := res.Results[analyzer.InclusiveLanguage].Result
+	require.NotNil(t, result)
+
+	var sources []*analyzer.FindingSource
+	result.EachFinding(func(_ rule.SeverityName, _ rule.NameID, finding *analyzer.Finding) {
+		sources = append(sources, finding.Source)
+	})
+	require.NotEmpty(t, sources)
+	for _, source := range sources {
+		if assert.NotNil(t, source) {
+			assert.Equal(t, "paths/pets.yaml", source.File)
+			assert.Equal(t, []string{"get", "responses", "200", "description"}, source.Path)
+			if assert.NotNil(t, source.Range) {
+				assert.Equal(t, 4, source.Range.Start.Line)
+			}
+		}
+	}
+}
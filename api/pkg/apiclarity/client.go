@@ -25,10 +25,12 @@ import (
 	operations2 "github.com/cisco-developer/api-insights/api/pkg/apiclarity/client/operations"
 	apiclaritymodels "github.com/cisco-developer/api-insights/api/pkg/apiclarity/models"
 	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"github.com/go-openapi/strfmt"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 	"github.com/urfave/cli/v2/altsrc"
 	"net/url"
+	"time"
 )
 
 const (
@@ -36,6 +38,9 @@ const (
 	APIEventSortKeyTime     = "time"
 	APIInventorySortKeyName = "name"
 	APITypeInternal         = "INTERNAL"
+
+	apiEventsPageSize = 500
+	apiEventsMaxPages = 20
 )
 
 var ErrNoAPITrafficFound = errors.New("apiclarity: no API traffic found")
@@ -146,3 +151,37 @@ func ReconstructSpec(ctx context.Context, client *apiclarityclient.APIClarityAPI
 
 	return models.NewSpecDocFromBytes(data), nil
 }
+
+// ListConsumerTraffic lists the API events of the API with apiName since, as the traffic of its consumers by source IP,
+// up to apiEventsMaxPages pages.
+func ListConsumerTraffic(ctx context.Context, client *apiclarityclient.APIClarityAPIs, apiName string, since time.Time) ([]*models.ConsumerTrafficEvent, error) {
+	var events []*models.ConsumerTrafficEvent
+	for page := int64(1); page <= apiEventsMaxPages; page++ {
+		res, err := client.Operations.GetAPIEvents(&operations2.GetAPIEventsParams{
+			SpecIs:    []string{apiName},
+			StartTime: strfmt.DateTime(since),
+			EndTime:   strfmt.DateTime(time.Now().UTC()),
+			Page:      page,
+			PageSize:  apiEventsPageSize,
+			SortDir:   utils.StringPtr(SortDirDesc),
+			SortKey:   APIEventSortKeyTime,
+			Context:   ctx,
+		})
+		if err != nil {
+			return nil, err
+		} else if res == nil || res.Payload == nil {
+			return nil, fmt.Errorf("apiclarity.ListConsumerTraffic(%s): unexpected response for GetAPIEvents (null res/res.Payload)", apiName)
+		}
+
+		for _, item := range res.Payload.Items {
+			if item == nil {
+				continue
+			}
+			events = append(events, &models.ConsumerTrafficEvent{Source: item.SourceIP, Method: string(item.Method), Path: item.Path})
+		}
+		if len(res.Payload.Items) < apiEventsPageSize || res.Payload.Total != nil && int64(len(events)) >= *res.Payload.Total {
+			break
+		}
+	}
+	return events, nil
+}
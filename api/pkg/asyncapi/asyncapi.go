@@ -0,0 +1,463 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package asyncapi parses AsyncAPI 2.x & 3.x documents into a version-agnostic model of channels, operations & messages.
+package asyncapi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ActionPublish   = "publish"   // 2.x: the application receives messages
+	ActionSubscribe = "subscribe" // 2.x: the application sends messages
+	ActionSend      = "send"      // 3.x
+	ActionReceive   = "receive"   // 3.x
+)
+
+// Document represents an AsyncAPI document.
+type Document struct {
+	Version            string
+	Info               Info
+	DefaultContentType string
+	HasServers         bool
+	Channels           []*Channel
+}
+
+// Info represents the info of a Document.
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// Channel represents a channel of a Document.
+type Channel struct {
+	Name        string
+	Address     string
+	Description string
+	Path        []string
+	Operations  []*Operation
+}
+
+// Operation represents an operation on a Channel.
+type Operation struct {
+	ID          string
+	Action      string
+	Summary     string
+	Description string
+	Path        []string
+	Messages    []*Message
+}
+
+// Sends checks if the application sends the messages of the operation, i.e. consumers receive them.
+func (o *Operation) Sends() bool {
+	return o.Action == ActionSubscribe || o.Action == ActionSend
+}
+
+// Message represents a message of an Operation.
+type Message struct {
+	Name        string
+	Title       string
+	Summary     string
+	Description string
+	ContentType string
+	Payload     map[string]interface{}
+	Examples    []interface{}
+	Path        []string
+	// PayloadPath is the path of the payload schema, which may be shared by messages.
+	PayloadPath []string
+}
+
+// IsAsyncAPI checks if data is an AsyncAPI document.
+func IsAsyncAPI(data []byte) bool {
+	doc, err := unmarshal(data)
+	if err != nil {
+		return false
+	}
+	_, ok := doc["asyncapi"]
+	return ok
+}
+
+// Parse parses data as an AsyncAPI 2.x or 3.x document.
+func Parse(data []byte) (*Document, error) {
+	raw, err := unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{raw: raw}
+	d := &Document{
+		Version:            str(raw["asyncapi"]),
+		DefaultContentType: str(raw["defaultContentType"]),
+	}
+	if d.Version == "" {
+		return nil, fmt.Errorf("asyncapi: missing asyncapi version")
+	}
+	if servers, ok := raw["servers"].(map[string]interface{}); ok && len(servers) > 0 {
+		d.HasServers = true
+	}
+
+	info, ok := raw["info"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("asyncapi: missing info")
+	}
+	d.Info = Info{Title: str(info["title"]), Version: str(info["version"]), Description: str(info["description"])}
+	if d.Info.Title == "" || d.Info.Version == "" {
+		return nil, fmt.Errorf("asyncapi: info.title & info.version are required")
+	}
+
+	switch {
+	case strings.HasPrefix(d.Version, "2."):
+		d.Channels, err = p.channelsV2()
+	case strings.HasPrefix(d.Version, "3."):
+		d.Channels, err = p.channelsV3()
+	default:
+		return nil, fmt.Errorf("asyncapi: unsupported asyncapi version %s", d.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+type parser struct {
+	raw map[string]interface{}
+}
+
+func (p *parser) channelsV2() ([]*Channel, error) {
+	channelsMap, err := optionalMap(p.raw, "channels")
+	if err != nil {
+		return nil, err
+	}
+
+	var channels []*Channel
+	for _, name := range sortedKeys(channelsMap) {
+		path := []string{"channels", name}
+		c, _, err := p.deref(channelsMap[name], path)
+		if err != nil {
+			return nil, err
+		}
+		channel := &Channel{Name: name, Address: name, Description: str(c["description"]), Path: path}
+		for _, action := range []string{ActionPublish, ActionSubscribe} {
+			opPath := append(append([]string{}, path...), action)
+			o, ok := c[action].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			op := &Operation{
+				ID:          str(o["operationId"]),
+				Action:      action,
+				Summary:     str(o["summary"]),
+				Description: str(o["description"]),
+				Path:        opPath,
+			}
+			if m, ok := o["message"]; ok {
+				msgPath := append(append([]string{}, opPath...), "message")
+				if op.Messages, err = p.messagesV2(m, msgPath); err != nil {
+					return nil, err
+				}
+			}
+			channel.Operations = append(channel.Operations, op)
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+func (p *parser) messagesV2(node interface{}, path []string) ([]*Message, error) {
+	m, refPath, err := p.deref(node, path)
+	if err != nil {
+		return nil, err
+	}
+	if oneOf, ok := m["oneOf"].([]interface{}); ok {
+		var messages []*Message
+		for i, o := range oneOf {
+			ms, err := p.messagesV2(o, append(append([]string{}, refPath...), "oneOf", strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, ms...)
+		}
+		return messages, nil
+	}
+	msg, err := p.message(m, refPath)
+	if err != nil {
+		return nil, err
+	}
+	return []*Message{msg}, nil
+}
+
+func (p *parser) channelsV3() ([]*Channel, error) {
+	channelsMap, err := optionalMap(p.raw, "channels")
+	if err != nil {
+		return nil, err
+	}
+	operationsMap, err := optionalMap(p.raw, "operations")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		channels      []*Channel
+		channelsByRef = map[string]*Channel{}
+		messagesByRef = map[string]*Message{}
+		// channelMessages are the messages of each channel, by channel ref.
+		channelMessages = map[string][]*Message{}
+	)
+	for _, name := range sortedKeys(channelsMap) {
+		path := []string{"channels", name}
+		c, _, err := p.deref(channelsMap[name], path)
+		if err != nil {
+			return nil, err
+		}
+		address := str(c["address"])
+		if address == "" {
+			address = name
+		}
+		channel := &Channel{Name: name, Address: address, Description: str(c["description"]), Path: path}
+		channels = append(channels, channel)
+		channelsByRef["#/channels/"+escape(name)] = channel
+
+		if messagesMap, ok := c["messages"].(map[string]interface{}); ok {
+			for _, msgName := range sortedKeys(messagesMap) {
+				msgPath := append(append([]string{}, path...), "messages", msgName)
+				m, refPath, err := p.deref(messagesMap[msgName], msgPath)
+				if err != nil {
+					return nil, err
+				}
+				msg, err := p.message(m, refPath)
+				if err != nil {
+					return nil, err
+				}
+				if msg.Name == "" {
+					msg.Name = msgName
+				}
+				messagesByRef["#/channels/"+escape(name)+"/messages/"+escape(msgName)] = msg
+				channelMessages["#/channels/"+escape(name)] = append(channelMessages["#/channels/"+escape(name)], msg)
+			}
+		}
+	}
+
+	for _, id := range sortedKeys(operationsMap) {
+		path := []string{"operations", id}
+		o, _, err := p.deref(operationsMap[id], path)
+		if err != nil {
+			return nil, err
+		}
+		channelRef, _ := o["channel"].(map[string]interface{})
+		channel, ok := channelsByRef[str(channelRef["$ref"])]
+		if !ok {
+			return nil, fmt.Errorf("asyncapi: operation %s references an unknown channel", id)
+		}
+		op := &Operation{
+			ID:          id,
+			Action:      str(o["action"]),
+			Summary:     str(o["summary"]),
+			Description: str(o["description"]),
+			Path:        path,
+		}
+		if op.Action != ActionSend && op.Action != ActionReceive {
+			return nil, fmt.Errorf("asyncapi: operation %s has an invalid action %q", id, op.Action)
+		}
+		if refs, ok := o["messages"].([]interface{}); ok {
+			for _, ref := range refs {
+				refMap, _ := ref.(map[string]interface{})
+				msg, ok := messagesByRef[str(refMap["$ref"])]
+				if !ok {
+					return nil, fmt.Errorf("asyncapi: operation %s references an unknown message", id)
+				}
+				op.Messages = append(op.Messages, msg)
+			}
+		} else {
+			// Operations without messages use all messages of their channel.
+			op.Messages = channelMessages[str(channelRef["$ref"])]
+		}
+		channel.Operations = append(channel.Operations, op)
+	}
+
+	return channels, nil
+}
+
+func (p *parser) message(m map[string]interface{}, path []string) (*Message, error) {
+	msg := &Message{
+		Name:        str(m["name"]),
+		Title:       str(m["title"]),
+		Summary:     str(m["summary"]),
+		Description: str(m["description"]),
+		ContentType: str(m["contentType"]),
+		Path:        path,
+	}
+	if payload, ok := m["payload"]; ok {
+		msg.PayloadPath = append(append([]string{}, path...), "payload")
+		// 3.x multi-format schemas wrap the schema.
+		if multiFormat, ok := payload.(map[string]interface{}); ok {
+			if schema, ok := multiFormat["schema"]; ok && multiFormat["schemaFormat"] != nil {
+				payload = schema
+				msg.PayloadPath = append(msg.PayloadPath, "schema")
+			}
+		}
+		if _, refPath, err := p.deref(payload, msg.PayloadPath); err == nil {
+			msg.PayloadPath = refPath
+		}
+		resolved, err := p.resolve(payload, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		msg.Payload, _ = resolved.(map[string]interface{})
+	}
+	if examples, ok := m["examples"].([]interface{}); ok {
+		msg.Examples = examples
+	}
+	return msg, nil
+}
+
+// deref follows a local $ref of node, returning the referenced map & its path.
+func (p *parser) deref(node interface{}, path []string) (map[string]interface{}, []string, error) {
+	for i := 0; i < 32; i++ {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("asyncapi: %s is not an object", strings.Join(path, "."))
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m, path, nil
+		}
+		if node, path, ok = p.lookup(ref); !ok {
+			return nil, nil, fmt.Errorf("asyncapi: unresolved $ref %s", ref)
+		}
+	}
+	return nil, nil, fmt.Errorf("asyncapi: too many nested $refs at %s", strings.Join(path, "."))
+}
+
+// resolve returns a copy of node with its local $refs resolved (recursive refs are left as-is).
+func (p *parser) resolve(node interface{}, resolving map[string]bool) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok {
+			if resolving[ref] {
+				return n, nil
+			}
+			target, _, ok := p.lookup(ref)
+			if !ok {
+				return nil, fmt.Errorf("asyncapi: unresolved $ref %s", ref)
+			}
+			resolving[ref] = true
+			defer delete(resolving, ref)
+			return p.resolve(target, resolving)
+		}
+		out := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			child, err := p.resolve(v, resolving)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = child
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(n))
+		for i, v := range n {
+			child, err := p.resolve(v, resolving)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = child
+		}
+		return out, nil
+	}
+	return node, nil
+}
+
+func (p *parser) lookup(ref string) (interface{}, []string, bool) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, nil, false
+	}
+	var (
+		node interface{} = p.raw
+		path []string
+	)
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = unescape(token)
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, nil, false
+		}
+		if node, ok = m[token]; !ok {
+			return nil, nil, false
+		}
+		path = append(path, token)
+	}
+	return node, path, true
+}
+
+func unmarshal(data []byte) (map[string]interface{}, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	doc, ok := utils.NormalizeYAML(v).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("asyncapi: document is not an object")
+	}
+	return doc, nil
+}
+
+func optionalMap(m map[string]interface{}, key string) (map[string]interface{}, error) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return map[string]interface{}{}, nil
+	}
+	vm, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("asyncapi: %s must be an object", key)
+	}
+	return vm, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func str(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
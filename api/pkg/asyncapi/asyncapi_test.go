@@ -0,0 +1,101 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package asyncapi
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_V2(t *testing.T) {
+	data, err := os.ReadFile("testdata/streetlights-v2.yaml")
+	require.NoError(t, err)
+	assert.True(t, IsAsyncAPI(data))
+
+	doc, err := Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, "2.6.0", doc.Version)
+	assert.Equal(t, "1.0.0", doc.Info.Version)
+	assert.True(t, doc.HasServers)
+	require.Len(t, doc.Channels, 2)
+
+	measured := doc.Channels[0]
+	assert.Equal(t, "smartylighting/streetlights/{streetlightId}/lighting/measured", measured.Address)
+	require.Len(t, measured.Operations, 1)
+	op := measured.Operations[0]
+	assert.Equal(t, ActionPublish, op.Action)
+	assert.False(t, op.Sends())
+	require.Len(t, op.Messages, 1)
+	assert.Equal(t, "lightMeasured", op.Messages[0].Name)
+	assert.Equal(t, []string{"components", "messages", "lightMeasured"}, op.Messages[0].Path)
+	assert.Contains(t, op.Messages[0].Payload["properties"], "lumens")
+	assert.Len(t, op.Messages[0].Examples, 1)
+
+	turn := doc.Channels[1].Operations[0]
+	assert.True(t, turn.Sends())
+	require.Len(t, turn.Messages, 2)
+	assert.Equal(t, "turnOff", turn.Messages[1].Name)
+}
+
+func TestParse_V3(t *testing.T) {
+	data, err := os.ReadFile("testdata/streetlights-v3.yaml")
+	require.NoError(t, err)
+
+	doc, err := Parse(data)
+	require.NoError(t, err)
+	assert.False(t, doc.HasServers)
+	require.Len(t, doc.Channels, 2)
+
+	// Channels are sorted by name.
+	measured := doc.Channels[1]
+	assert.Equal(t, "lightingMeasured", measured.Name)
+	require.Len(t, measured.Operations, 1)
+	op := measured.Operations[0]
+	assert.Equal(t, "receiveLightMeasurement", op.ID)
+	assert.Equal(t, []string{"operations", "receiveLightMeasurement"}, op.Path)
+	require.Len(t, op.Messages, 1)
+	assert.Equal(t, "lightMeasured", op.Messages[0].Name)
+	assert.Equal(t, "object", op.Messages[0].Payload["type"])
+
+	turnOn := doc.Channels[0].Operations[0]
+	assert.Equal(t, ActionSend, turnOn.Action)
+	require.Len(t, turnOn.Messages, 1)
+	assert.Equal(t, []string{"channels", "lightTurnOn", "messages", "turnOn"}, turnOn.Messages[0].Path)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{name: "missing version", doc: "info:\n  title: a\n  version: 1.0.0\n"},
+		{name: "missing info", doc: "asyncapi: 2.6.0\n"},
+		{name: "unsupported version", doc: "asyncapi: 1.2.0\ninfo:\n  title: a\n  version: 1.0.0\n"},
+		{name: "unresolved ref", doc: "asyncapi: 2.6.0\ninfo:\n  title: a\n  version: 1.0.0\nchannels:\n  a:\n    publish:\n      message:\n        $ref: '#/components/messages/b'\n"},
+		{name: "unknown channel", doc: "asyncapi: 3.0.0\ninfo:\n  title: a\n  version: 1.0.0\noperations:\n  a:\n    action: send\n    channel:\n      $ref: '#/channels/b'\n"},
+		{name: "invalid action", doc: "asyncapi: 3.0.0\ninfo:\n  title: a\n  version: 1.0.0\nchannels:\n  b: {}\noperations:\n  a:\n    action: publish\n    channel:\n      $ref: '#/channels/b'\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.doc))
+			assert.Error(t, err)
+		})
+	}
+}
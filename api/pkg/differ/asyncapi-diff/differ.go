@@ -0,0 +1,369 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package asyncapidiff diffs AsyncAPI documents at the channel, operation & message level.
+package asyncapidiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/cisco-developer/api-insights/api/pkg/asyncapi"
+)
+
+type Differ interface {
+	DiffDocuments(oldDoc, newDoc models.SpecDoc, cfg *diff.Config) (*diff.Result, error)
+}
+
+func NewClient() (Differ, error) {
+	return &client{}, nil
+}
+
+// client implements Differ.
+type client struct {
+}
+
+func (c *client) DiffDocuments(oldDoc, newDoc models.SpecDoc, cfg *diff.Config) (*diff.Result, error) {
+	if cfg == nil {
+		cfg = &diff.Config{}
+	}
+	if cfg.OutputFormat == "" {
+		cfg.OutputFormat = "json"
+	}
+
+	if oldDoc == nil || *oldDoc == "" {
+		return nil, fmt.Errorf("oldDoc is nil or empty")
+	}
+	if newDoc == nil || *newDoc == "" {
+		return nil, fmt.Errorf("newDoc is nil or empty")
+	}
+	oldParsed, err := asyncapi.Parse([]byte(*oldDoc))
+	if err != nil {
+		return nil, err
+	}
+	newParsed, err := asyncapi.Parse([]byte(*newDoc))
+	if err != nil {
+		return nil, err
+	}
+
+	resJSON := NewResultFrom(oldParsed, newParsed, diff.NewMarkdownSummaryMessageBuilder())
+
+	res := &diff.Result{}
+	switch cfg.OutputFormat {
+	case "json":
+		res.JSON = resJSON
+	case "markdown":
+		res.Markdown = resJSON.Message
+	case "text":
+		res.Text = resJSON.Message
+	default:
+		return nil, fmt.Errorf("differ: unsupported output format(%s) for AsyncAPI specs", cfg.OutputFormat)
+	}
+	return res, nil
+}
+
+// operationKey identifies an operation across docs, whatever their AsyncAPI version.
+type operationKey struct {
+	address string
+	sends   bool
+}
+
+type operationRef struct {
+	channel *asyncapi.Channel
+	op      *asyncapi.Operation
+}
+
+func indexOperations(doc *asyncapi.Document) (map[operationKey]*operationRef, []operationKey) {
+	var (
+		ops  = map[operationKey]*operationRef{}
+		keys []operationKey
+	)
+	for _, channel := range doc.Channels {
+		for _, op := range channel.Operations {
+			key := operationKey{address: channel.Address, sends: op.Sends()}
+			if _, ok := ops[key]; ok {
+				continue
+			}
+			ops[key] = &operationRef{channel: channel, op: op}
+			keys = append(keys, key)
+		}
+	}
+	return ops, keys
+}
+
+func description(op *asyncapi.Operation) string {
+	if op.Summary != "" {
+		return op.Summary
+	}
+	return op.Description
+}
+
+// NewResultFrom diffs the channels, operations & messages of oldDoc & newDoc.
+//
+// Deleted operations & messages are breaking. Payload changes are breaking according to the direction of the messages:
+// consumers of messages the application sends break on deleted properties, receivers of messages the application
+// receives break on added required properties, & both break on type changes.
+func NewResultFrom(oldDoc, newDoc *asyncapi.Document, msgBuilder diff.SummaryMessageBuilder) *diff.JSONResult {
+	result := &diff.JSONResult{}
+
+	oldOps, oldKeys := indexOperations(oldDoc)
+	newOps, newKeys := indexOperations(newDoc)
+
+	for _, key := range newKeys {
+		if _, ok := oldOps[key]; ok {
+			continue
+		}
+		ref := newOps[key]
+		result.Added = append(result.Added, &diff.EndpointSummary{Path: key.address, Method: ref.op.Action, Description: description(ref.op), Message: fmt.Sprintf("%s - Added", key.address)})
+	}
+
+	for _, key := range oldKeys {
+		if _, ok := newOps[key]; ok {
+			continue
+		}
+		ref := oldOps[key]
+		result.Deleted = append(result.Deleted, &diff.EndpointSummary{Path: key.address, Method: ref.op.Action, Description: description(ref.op), Message: fmt.Sprintf("%s - Deleted", key.address)})
+		result.Breaking = true
+	}
+
+	for _, key := range newKeys {
+		oldRef, ok := oldOps[key]
+		if !ok {
+			continue
+		}
+		newRef := newOps[key]
+		messagesSummary := buildMessagesSummary(oldRef.op, newRef.op, msgBuilder)
+		if messagesSummary == nil {
+			continue
+		}
+		modifiedSummary := &diff.ModifiedSummary{
+			Path:            key.address,
+			Method:          newRef.op.Action,
+			Summary:         newRef.op.Summary,
+			Description:     newRef.op.Description,
+			Breaking:        messagesSummary.Breaking,
+			MessagesSummary: messagesSummary,
+		}
+		modifiedSummary.Message = msgBuilder.BuildModifiedSummaryMessage(modifiedSummary)
+		result.Modified = append(result.Modified, modifiedSummary)
+		result.Breaking = result.Breaking || modifiedSummary.Breaking
+	}
+
+	result.Message = msgBuilder.BuildResultSummaryMessage(result)
+
+	return result
+}
+
+func messageName(msg *asyncapi.Message) string {
+	if msg.Name != "" {
+		return msg.Name
+	}
+	return msg.Path[len(msg.Path)-1]
+}
+
+func messageDescription(msg *asyncapi.Message) string {
+	if msg.Summary != "" {
+		return msg.Summary
+	}
+	if msg.Description != "" {
+		return msg.Description
+	}
+	return msg.Title
+}
+
+func buildMessagesSummary(oldOp, newOp *asyncapi.Operation, msgBuilder diff.SummaryMessageBuilder) *diff.MessagesSummary {
+	var (
+		s           = &diff.MessagesSummary{}
+		sends       = newOp.Sends()
+		oldMessages = map[string]*asyncapi.Message{}
+		newMessages = map[string]*asyncapi.Message{}
+	)
+	for _, msg := range oldOp.Messages {
+		oldMessages[messageName(msg)] = msg
+	}
+	for _, msg := range newOp.Messages {
+		newMessages[messageName(msg)] = msg
+	}
+
+	for _, msg := range newOp.Messages {
+		name := messageName(msg)
+		if _, ok := oldMessages[name]; ok {
+			continue
+		}
+		d := &diff.MessageSummaryDetail{Name: name, Description: messageDescription(msg), Action: diff.ActionAdded}
+		d.Message = msgBuilder.BuildMessageSummaryDetailMessage(d)
+		s.Details = append(s.Details, d)
+	}
+
+	for _, msg := range oldOp.Messages {
+		name := messageName(msg)
+		if _, ok := newMessages[name]; ok {
+			continue
+		}
+		d := &diff.MessageSummaryDetail{Name: name, Description: messageDescription(msg), Action: diff.ActionDeleted, Breaking: true}
+		d.Message = msgBuilder.BuildMessageSummaryDetailMessage(d)
+		s.Details = append(s.Details, d)
+	}
+
+	for _, msg := range newOp.Messages {
+		name := messageName(msg)
+		oldMsg, ok := oldMessages[name]
+		if !ok {
+			continue
+		}
+		properties := diffSchemas("", oldMsg.Payload, msg.Payload, sends)
+		if len(properties) == 0 {
+			continue
+		}
+		d := &diff.MessageSummaryDetail{Name: name, Description: messageDescription(msg), Action: diff.ActionModified, Properties: properties}
+		for _, p := range properties {
+			p.Message = msgBuilder.BuildPropertiesSummaryMessage(p, 0)
+			d.Breaking = d.Breaking || p.Breaking
+		}
+		d.Message = msgBuilder.BuildMessageSummaryDetailMessage(d)
+		s.Details = append(s.Details, d)
+	}
+
+	if len(s.Details) == 0 {
+		return nil
+	}
+	for _, d := range s.Details {
+		s.Breaking = s.Breaking || d.Breaking
+	}
+	s.Message = msgBuilder.BuildMessagesSummaryMessage(s)
+	return s
+}
+
+// diffSchemas diffs the properties of the old & new payload schemas at name, flattening nested properties into dotted names.
+func diffSchemas(name string, oldSchema, newSchema map[string]interface{}, sends bool) (properties []*diff.PropertiesSummary) {
+	oldType, newType := schemaType(oldSchema), schemaType(newSchema)
+	if oldType != "" && newType != "" && oldType != newType {
+		if name == "" {
+			name = "payload"
+		}
+		return []*diff.PropertiesSummary{{
+			Name:        name,
+			Type:        newType,
+			Description: fmt.Sprintf("Type changed from %s to %s", oldType, newType),
+			Action:      diff.ActionModified,
+			Breaking:    true,
+		}}
+	}
+
+	if items, ok := newSchema["items"].(map[string]interface{}); ok {
+		oldItems, _ := oldSchema["items"].(map[string]interface{})
+		properties = append(properties, diffSchemas(name+"[]", oldItems, items, sends)...)
+	}
+
+	var (
+		oldProperties, _ = oldSchema["properties"].(map[string]interface{})
+		newProperties, _ = newSchema["properties"].(map[string]interface{})
+		oldRequired      = requiredSet(oldSchema)
+		newRequired      = requiredSet(newSchema)
+	)
+	for _, key := range unionKeys(oldProperties, newProperties) {
+		var (
+			propertyName   = strings.TrimPrefix(name+"."+key, ".")
+			oldProperty, _ = oldProperties[key].(map[string]interface{})
+			newProperty, _ = newProperties[key].(map[string]interface{})
+		)
+		switch {
+		case oldProperty == nil:
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        propertyName,
+				Type:        schemaType(newProperty),
+				Description: schemaDescription(newProperty),
+				Action:      diff.ActionAdded,
+				// Receivers reject messages lacking a new required property.
+				Breaking: !sends && newRequired[key],
+			})
+		case newProperty == nil:
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        propertyName,
+				Type:        schemaType(oldProperty),
+				Description: schemaDescription(oldProperty),
+				Action:      diff.ActionDeleted,
+				// Consumers may rely on a property the application no longer sends.
+				Breaking: sends,
+			})
+		default:
+			if !oldRequired[key] && newRequired[key] && !sends {
+				properties = append(properties, &diff.PropertiesSummary{
+					Name:        propertyName,
+					Type:        schemaType(newProperty),
+					Description: "Became required",
+					Action:      diff.ActionModified,
+					Breaking:    true,
+				})
+			} else if oldRequired[key] && !newRequired[key] && sends {
+				properties = append(properties, &diff.PropertiesSummary{
+					Name:        propertyName,
+					Type:        schemaType(newProperty),
+					Description: "Became optional",
+					Action:      diff.ActionModified,
+					Breaking:    true,
+				})
+			}
+			properties = append(properties, diffSchemas(propertyName, oldProperty, newProperty, sends)...)
+		}
+	}
+	return properties
+}
+
+func schemaType(schema map[string]interface{}) string {
+	if t, ok := schema["type"].(string); ok {
+		return t
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func schemaDescription(schema map[string]interface{}) string {
+	description, _ := schema["description"].(string)
+	return description
+}
+
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	set := map[string]bool{}
+	required, _ := schema["required"].([]interface{})
+	for _, r := range required {
+		if s, ok := r.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	set := map[string]struct{}{}
+	for k := range a {
+		set[k] = struct{}{}
+	}
+	for k := range b {
+		set[k] = struct{}{}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package asyncapidiff
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadDoc(t *testing.T, filename string) models.SpecDoc {
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	return models.NewSpecDocFromBytes(data)
+}
+
+func TestClient_DiffDocuments(t *testing.T) {
+	c, err := NewClient()
+	require.NoError(t, err)
+
+	res, err := c.DiffDocuments(loadDoc(t, "testdata/old.yaml"), loadDoc(t, "testdata/new.yaml"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, res.JSON)
+	assert.True(t, res.JSON.Breaking)
+
+	require.Len(t, res.JSON.Added, 1)
+	assert.Equal(t, "lighting/status", res.JSON.Added[0].Path)
+	assert.Equal(t, "send", res.JSON.Added[0].Method)
+
+	require.Len(t, res.JSON.Deleted, 1)
+	assert.Equal(t, "lighting/dim", res.JSON.Deleted[0].Path)
+
+	require.Len(t, res.JSON.Modified, 2)
+	properties := func(m *diff.ModifiedSummary) map[string]*diff.PropertiesSummary {
+		require.NotNil(t, m.MessagesSummary)
+		require.Len(t, m.MessagesSummary.Details, 1)
+		out := map[string]*diff.PropertiesSummary{}
+		for _, p := range m.MessagesSummary.Details[0].Properties {
+			out[p.Name+" "+string(p.Action)] = p
+		}
+		return out
+	}
+
+	// lighting/measured is received by the application, so new required properties break its producers.
+	measured := res.JSON.Modified[0]
+	assert.Equal(t, "lighting/measured", measured.Path)
+	assert.Equal(t, "receive", measured.Method)
+	assert.True(t, measured.Breaking)
+	measuredProperties := properties(measured)
+	assert.True(t, measuredProperties["lumens modified"].Breaking)
+	assert.True(t, measuredProperties["sentAt added"].Breaking)
+
+	// lighting/turn is sent by the application, so deleted & optional properties break its consumers.
+	turn := res.JSON.Modified[1]
+	assert.Equal(t, "lighting/turn", turn.Path)
+	assert.True(t, turn.Breaking)
+	turnProperties := properties(turn)
+	assert.True(t, turnProperties["at deleted"].Breaking)
+	assert.True(t, turnProperties["command modified"].Breaking)
+	assert.False(t, turnProperties["reason added"].Breaking)
+
+	assert.Contains(t, res.JSON.Message, "What's Modified")
+	assert.Contains(t, turn.Message, "Messages:")
+}
+
+func TestClient_DiffDocuments_Formats(t *testing.T) {
+	c, err := NewClient()
+	require.NoError(t, err)
+	doc := loadDoc(t, "testdata/old.yaml")
+
+	res, err := c.DiffDocuments(doc, loadDoc(t, "testdata/new.yaml"), &diff.Config{OutputFormat: "markdown"})
+	require.NoError(t, err)
+	assert.Contains(t, res.Markdown, "What's New")
+
+	res, err = c.DiffDocuments(doc, doc, &diff.Config{OutputFormat: "text"})
+	require.NoError(t, err)
+	assert.Empty(t, res.Text)
+
+	_, err = c.DiffDocuments(doc, doc, &diff.Config{OutputFormat: "html"})
+	assert.Error(t, err)
+}
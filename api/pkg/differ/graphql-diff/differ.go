@@ -0,0 +1,437 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package graphqldiff diffs GraphQL schemas at the root field & type level.
+package graphqldiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/cisco-developer/api-insights/api/pkg/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type Differ interface {
+	DiffDocuments(oldDoc, newDoc models.SpecDoc, cfg *diff.Config) (*diff.Result, error)
+}
+
+func NewClient() (Differ, error) {
+	return &client{}, nil
+}
+
+// client implements Differ.
+type client struct {
+}
+
+func (c *client) DiffDocuments(oldDoc, newDoc models.SpecDoc, cfg *diff.Config) (*diff.Result, error) {
+	if cfg == nil {
+		cfg = &diff.Config{}
+	}
+	if cfg.OutputFormat == "" {
+		cfg.OutputFormat = "json"
+	}
+
+	if oldDoc == nil || *oldDoc == "" {
+		return nil, fmt.Errorf("oldDoc is nil or empty")
+	}
+	if newDoc == nil || *newDoc == "" {
+		return nil, fmt.Errorf("newDoc is nil or empty")
+	}
+	oldSchema, err := graphql.Load([]byte(*oldDoc))
+	if err != nil {
+		return nil, err
+	}
+	newSchema, err := graphql.Load([]byte(*newDoc))
+	if err != nil {
+		return nil, err
+	}
+
+	resJSON := NewResultFrom(oldSchema, newSchema, diff.NewMarkdownSummaryMessageBuilder())
+
+	res := &diff.Result{}
+	switch cfg.OutputFormat {
+	case "json":
+		res.JSON = resJSON
+	case "markdown":
+		res.Markdown = resJSON.Message
+	case "text":
+		res.Text = resJSON.Message
+	default:
+		return nil, fmt.Errorf("differ: unsupported output format(%s) for GraphQL specs", cfg.OutputFormat)
+	}
+	return res, nil
+}
+
+// rootField is a field of a root operation type, i.e. a GraphQL "endpoint".
+type rootField struct {
+	operation string
+	typeName  string
+	field     *ast.FieldDefinition
+}
+
+func (f *rootField) path() string {
+	return f.typeName + "." + f.field.Name
+}
+
+// indexRootFields indexes the root fields of schema by operation & name.
+func indexRootFields(schema *ast.Schema) (map[string]*rootField, []string) {
+	var (
+		fields = map[string]*rootField{}
+		keys   []string
+	)
+	for _, def := range []*ast.Definition{schema.Query, schema.Mutation, schema.Subscription} {
+		if def == nil {
+			continue
+		}
+		operation := graphql.RootOperation(schema, def)
+		for _, field := range graphql.Fields(def) {
+			key := operation + " " + field.Name
+			fields[key] = &rootField{operation: operation, typeName: def.Name, field: field}
+			keys = append(keys, key)
+		}
+	}
+	return fields, keys
+}
+
+func newEndpointSummary(path, method, description, action string) *diff.EndpointSummary {
+	return &diff.EndpointSummary{Path: path, Method: method, Description: description, Message: fmt.Sprintf("%s - %s", path, action)}
+}
+
+// NewResultFrom diffs the root fields (e.g. Query.book) & the other types of oldSchema & newSchema. Root fields are
+// reported with their root type & name as Path & their operation (query, mutation or subscription) as Method, & other
+// types with their name as Path & their SDL keyword (e.g. type, input or enum) as Method.
+//
+// Changes that break existing operations are breaking, e.g. deleted fields, types, arguments & enum values, changed
+// argument & field types (except for output types becoming non-null & input types becoming nullable), & new required
+// arguments & input fields.
+func NewResultFrom(oldSchema, newSchema *ast.Schema, msgBuilder diff.SummaryMessageBuilder) *diff.JSONResult {
+	result := &diff.JSONResult{}
+
+	oldFields, oldKeys := indexRootFields(oldSchema)
+	newFields, newKeys := indexRootFields(newSchema)
+	oldTypes, newTypes := nonRootDefinitions(oldSchema), nonRootDefinitions(newSchema)
+
+	for _, key := range newKeys {
+		if _, ok := oldFields[key]; !ok {
+			f := newFields[key]
+			result.Added = append(result.Added, newEndpointSummary(f.path(), f.operation, f.field.Description, "Added"))
+		}
+	}
+	for _, def := range newTypes {
+		if oldSchema.Types[def.Name] == nil {
+			result.Added = append(result.Added, newEndpointSummary(def.Name, graphql.Keyword(def), def.Description, "Added"))
+		}
+	}
+
+	for _, key := range oldKeys {
+		if _, ok := newFields[key]; !ok {
+			f := oldFields[key]
+			result.Deleted = append(result.Deleted, newEndpointSummary(f.path(), f.operation, f.field.Description, "Deleted"))
+		}
+	}
+	for _, def := range oldTypes {
+		if newSchema.Types[def.Name] == nil {
+			result.Deleted = append(result.Deleted, newEndpointSummary(def.Name, graphql.Keyword(def), def.Description, "Deleted"))
+		}
+	}
+	result.Breaking = len(result.Deleted) > 0
+
+	for _, key := range newKeys {
+		oldField, ok := oldFields[key]
+		if !ok {
+			continue
+		}
+		newField := newFields[key]
+		if isNewlyDeprecated(oldField.field.Directives, newField.field.Directives) {
+			result.Deprecated = append(result.Deprecated, newEndpointSummary(newField.path(), newField.operation, newField.field.Description, "Deprecated"))
+		}
+		properties := diffField(newField.field.Name, oldField.field, newField.field)
+		addModified(result, newField.path(), newField.operation, newField.field.Description, newField.field.Name, properties, msgBuilder)
+	}
+	for _, def := range newTypes {
+		oldDef := oldSchema.Types[def.Name]
+		if oldDef == nil {
+			continue
+		}
+		for _, field := range graphql.Fields(def) {
+			if oldField := oldDef.Fields.ForName(field.Name); oldField != nil && isNewlyDeprecated(oldField.Directives, field.Directives) {
+				path := def.Name + "." + field.Name
+				result.Deprecated = append(result.Deprecated, newEndpointSummary(path, "field", field.Description, "Deprecated"))
+			}
+		}
+		addModified(result, def.Name, graphql.Keyword(def), def.Description, def.Name, diffDefinitions(oldDef, def), msgBuilder)
+	}
+
+	result.Message = msgBuilder.BuildResultSummaryMessage(result)
+
+	return result
+}
+
+// nonRootDefinitions returns the types of schema, except for its root operation types.
+func nonRootDefinitions(schema *ast.Schema) (defs []*ast.Definition) {
+	for _, def := range graphql.Definitions(schema) {
+		if graphql.RootOperation(schema, def) == "" {
+			defs = append(defs, def)
+		}
+	}
+	return
+}
+
+// addModified adds a diff.ModifiedSummary of the changed properties of a modified root field or type to result, if any.
+func addModified(result *diff.JSONResult, path, method, description, name string, properties []*diff.PropertiesSummary, msgBuilder diff.SummaryMessageBuilder) {
+	if len(properties) == 0 {
+		return
+	}
+	detail := &diff.MessageSummaryDetail{Name: name, Description: description, Action: diff.ActionModified, Properties: properties}
+	for _, p := range properties {
+		p.Message = msgBuilder.BuildPropertiesSummaryMessage(p, 0)
+		detail.Breaking = detail.Breaking || p.Breaking
+	}
+	detail.Message = msgBuilder.BuildMessageSummaryDetailMessage(detail)
+
+	messagesSummary := &diff.MessagesSummary{Breaking: detail.Breaking, Details: []*diff.MessageSummaryDetail{detail}}
+	messagesSummary.Message = msgBuilder.BuildMessagesSummaryMessage(messagesSummary)
+
+	modifiedSummary := &diff.ModifiedSummary{
+		Path:            path,
+		Method:          method,
+		Description:     description,
+		Breaking:        messagesSummary.Breaking,
+		MessagesSummary: messagesSummary,
+	}
+	modifiedSummary.Message = msgBuilder.BuildModifiedSummaryMessage(modifiedSummary)
+	result.Modified = append(result.Modified, modifiedSummary)
+	result.Breaking = result.Breaking || modifiedSummary.Breaking
+}
+
+func isNewlyDeprecated(oldDirectives, newDirectives ast.DirectiveList) bool {
+	oldDeprecated, _ := graphql.Deprecation(oldDirectives)
+	newDeprecated, _ := graphql.Deprecation(newDirectives)
+	return newDeprecated && !oldDeprecated
+}
+
+// diffDefinitions diffs the fields, input fields, enum values, union members & interfaces of a type.
+func diffDefinitions(oldDef, newDef *ast.Definition) []*diff.PropertiesSummary {
+	if oldDef.Kind != newDef.Kind {
+		return []*diff.PropertiesSummary{{
+			Name:        newDef.Name,
+			Type:        graphql.Keyword(newDef),
+			Description: fmt.Sprintf("Kind changed from %s to %s", graphql.Keyword(oldDef), graphql.Keyword(newDef)),
+			Action:      diff.ActionModified,
+			Breaking:    true,
+		}}
+	}
+
+	switch newDef.Kind {
+	case ast.Object, ast.Interface:
+		return append(diffFields(oldDef, newDef), diffNames("interface", oldDef.Interfaces, newDef.Interfaces)...)
+	case ast.InputObject:
+		return diffInputFields(oldDef, newDef)
+	case ast.Enum:
+		var oldValues, newValues []string
+		for _, v := range oldDef.EnumValues {
+			oldValues = append(oldValues, v.Name)
+		}
+		for _, v := range newDef.EnumValues {
+			newValues = append(newValues, v.Name)
+		}
+		return diffNames("value", oldValues, newValues)
+	case ast.Union:
+		return diffNames("member", oldDef.Types, newDef.Types)
+	}
+	return nil
+}
+
+// diffNames diffs the enum values, union members or interfaces of a type, whose deletion is breaking.
+func diffNames(kind string, oldNames, newNames []string) (properties []*diff.PropertiesSummary) {
+	oldSet, newSet := map[string]bool{}, map[string]bool{}
+	for _, n := range oldNames {
+		oldSet[n] = true
+	}
+	for _, n := range newNames {
+		newSet[n] = true
+	}
+	for _, n := range oldNames {
+		if !newSet[n] {
+			properties = append(properties, &diff.PropertiesSummary{Name: n, Type: kind, Action: diff.ActionDeleted, Breaking: true})
+		}
+	}
+	for _, n := range newNames {
+		if !oldSet[n] {
+			properties = append(properties, &diff.PropertiesSummary{Name: n, Type: kind, Action: diff.ActionAdded})
+		}
+	}
+	return
+}
+
+// diffFields diffs the output fields of an object or interface.
+func diffFields(oldDef, newDef *ast.Definition) (properties []*diff.PropertiesSummary) {
+	for _, oldField := range graphql.Fields(oldDef) {
+		if newDef.Fields.ForName(oldField.Name) == nil {
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        oldField.Name,
+				Type:        oldField.Type.String(),
+				Description: oldField.Description,
+				Action:      diff.ActionDeleted,
+				Breaking:    true,
+			})
+		}
+	}
+	for _, newField := range graphql.Fields(newDef) {
+		oldField := oldDef.Fields.ForName(newField.Name)
+		if oldField == nil {
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        newField.Name,
+				Type:        newField.Type.String(),
+				Description: newField.Description,
+				Action:      diff.ActionAdded,
+			})
+			continue
+		}
+		properties = append(properties, diffField(newField.Name, oldField, newField)...)
+	}
+	return
+}
+
+// diffField diffs the type & arguments of an output field, whose properties are named after name.
+func diffField(name string, oldField, newField *ast.FieldDefinition) (properties []*diff.PropertiesSummary) {
+	if oldType, newType := oldField.Type.String(), newField.Type.String(); oldType != newType {
+		properties = append(properties, &diff.PropertiesSummary{
+			Name:        name,
+			Type:        newType,
+			Description: fmt.Sprintf("Type changed from %s to %s", oldType, newType),
+			Action:      diff.ActionModified,
+			Breaking:    !isSafeOutputTypeChange(oldField.Type, newField.Type),
+		})
+	}
+
+	for _, oldArg := range oldField.Arguments {
+		if newField.Arguments.ForName(oldArg.Name) == nil {
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        fmt.Sprintf("%s(%s)", name, oldArg.Name),
+				Type:        oldArg.Type.String(),
+				Description: "Argument deleted",
+				Action:      diff.ActionDeleted,
+				Breaking:    true,
+			})
+		}
+	}
+	for _, newArg := range newField.Arguments {
+		oldArg := oldField.Arguments.ForName(newArg.Name)
+		properties = append(properties, diffInputValue(fmt.Sprintf("%s(%s)", name, newArg.Name), "Argument", oldArg, newArg)...)
+	}
+	return
+}
+
+// diffInputFields diffs the fields of an input type.
+func diffInputFields(oldDef, newDef *ast.Definition) (properties []*diff.PropertiesSummary) {
+	for _, oldField := range oldDef.Fields {
+		if newDef.Fields.ForName(oldField.Name) == nil {
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        oldField.Name,
+				Type:        oldField.Type.String(),
+				Description: "Input field deleted",
+				Action:      diff.ActionDeleted,
+				Breaking:    true,
+			})
+		}
+	}
+	for _, newField := range newDef.Fields {
+		var oldInput *ast.ArgumentDefinition
+		if oldField := oldDef.Fields.ForName(newField.Name); oldField != nil {
+			oldInput = &ast.ArgumentDefinition{Name: oldField.Name, Type: oldField.Type, DefaultValue: oldField.DefaultValue}
+		}
+		newInput := &ast.ArgumentDefinition{Name: newField.Name, Type: newField.Type, DefaultValue: newField.DefaultValue}
+		properties = append(properties, diffInputValue(newField.Name, "Input field", oldInput, newInput)...)
+	}
+	return
+}
+
+// diffInputValue diffs an argument or input field, which is new if oldValue is nil.
+func diffInputValue(name, kind string, oldValue, newValue *ast.ArgumentDefinition) []*diff.PropertiesSummary {
+	if oldValue == nil {
+		required := newValue.Type.NonNull && newValue.DefaultValue == nil
+		description := kind + " added"
+		if required {
+			description = "Required " + strings.ToLower(kind) + " added"
+		}
+		return []*diff.PropertiesSummary{{
+			Name:        name,
+			Type:        newValue.Type.String(),
+			Description: description,
+			Action:      diff.ActionAdded,
+			Breaking:    required,
+		}}
+	}
+	oldType, newType := oldValue.Type.String(), newValue.Type.String()
+	if oldType == newType {
+		return nil
+	}
+	description := fmt.Sprintf("Type changed from %s to %s", oldType, newType)
+	if oldType+"!" == newType {
+		description = "Became non-null"
+	}
+	return []*diff.PropertiesSummary{{
+		Name:        name,
+		Type:        newType,
+		Description: description,
+		Action:      diff.ActionModified,
+		Breaking:    !isSafeInputTypeChange(oldValue.Type, newValue.Type),
+	}}
+}
+
+// isSafeOutputTypeChange checks if clients of an output field of oldType keep working with newType,
+// i.e. if newType is oldType, or oldType made non-null at any level.
+func isSafeOutputTypeChange(oldType, newType *ast.Type) bool {
+	if oldType == nil || newType == nil {
+		return oldType == newType
+	}
+	if newType.NonNull && !oldType.NonNull {
+		nullable := *newType
+		nullable.NonNull = false
+		return isSafeOutputTypeChange(oldType, &nullable)
+	}
+	if oldType.NonNull != newType.NonNull {
+		return false
+	}
+	if oldType.Elem != nil || newType.Elem != nil {
+		return isSafeOutputTypeChange(oldType.Elem, newType.Elem)
+	}
+	return oldType.NamedType == newType.NamedType
+}
+
+// isSafeInputTypeChange checks if clients sending an argument or input field of oldType keep working with newType,
+// i.e. if newType is oldType, or oldType made nullable at any level.
+func isSafeInputTypeChange(oldType, newType *ast.Type) bool {
+	if oldType == nil || newType == nil {
+		return oldType == newType
+	}
+	if oldType.NonNull && !newType.NonNull {
+		nullable := *oldType
+		nullable.NonNull = false
+		return isSafeInputTypeChange(&nullable, newType)
+	}
+	if oldType.NonNull != newType.NonNull {
+		return false
+	}
+	if oldType.Elem != nil || newType.Elem != nil {
+		return isSafeInputTypeChange(oldType.Elem, newType.Elem)
+	}
+	return oldType.NamedType == newType.NamedType
+}
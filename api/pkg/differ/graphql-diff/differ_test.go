@@ -0,0 +1,111 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphqldiff
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadDoc(t *testing.T, filename string) models.SpecDoc {
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	return models.NewSpecDocFromBytes(data)
+}
+
+func TestClient_DiffDocuments(t *testing.T) {
+	c, err := NewClient()
+	require.NoError(t, err)
+
+	res, err := c.DiffDocuments(loadDoc(t, "testdata/old.graphql"), loadDoc(t, "testdata/new.graphql"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, res.JSON)
+	assert.True(t, res.JSON.Breaking)
+
+	endpoints := func(summaries []*diff.EndpointSummary) (out []string) {
+		for _, e := range summaries {
+			out = append(out, e.Method+" "+e.Path)
+		}
+		return
+	}
+	assert.Equal(t, []string{"query Query.node", "input BookFilter"}, endpoints(res.JSON.Added))
+	assert.Equal(t, []string{"query Query.authors"}, endpoints(res.JSON.Deleted))
+	assert.Equal(t, []string{"query Query.book"}, endpoints(res.JSON.Deprecated))
+
+	modified := map[string]map[string]*diff.PropertiesSummary{}
+	for _, m := range res.JSON.Modified {
+		require.NotNil(t, m.MessagesSummary)
+		require.Len(t, m.MessagesSummary.Details, 1)
+		properties := map[string]*diff.PropertiesSummary{}
+		for _, p := range m.MessagesSummary.Details[0].Properties {
+			properties[p.Name+" "+string(p.Action)] = p
+		}
+		modified[m.Method+" "+m.Path] = properties
+	}
+	assert.Len(t, modified, 5)
+
+	books := modified["query Query.books"]
+	require.NotNil(t, books)
+	assert.False(t, books["books modified"].Breaking, "output types may become non-null")
+	assert.False(t, books["books(after) added"].Breaking)
+	assert.True(t, books["books(filter) added"].Breaking)
+
+	book := modified["type Book"]
+	require.NotNil(t, book)
+	assert.False(t, book["title modified"].Breaking)
+	assert.True(t, book["isbn modified"].Breaking)
+	assert.False(t, book["pages added"].Breaking)
+
+	input := modified["input AddBookInput"]
+	require.NotNil(t, input)
+	assert.False(t, input["title modified"].Breaking, "input types may become nullable")
+	assert.True(t, input["isbn modified"].Breaking)
+	assert.Equal(t, "Became non-null", input["isbn modified"].Description)
+	assert.True(t, input["pages added"].Breaking)
+	assert.True(t, input["genre deleted"].Breaking)
+
+	genre := modified["enum Genre"]
+	require.NotNil(t, genre)
+	assert.True(t, genre["POETRY deleted"].Breaking)
+	assert.False(t, genre["NON_FICTION added"].Breaking)
+
+	assert.True(t, modified["union SearchResult"]["Author deleted"].Breaking)
+
+	assert.Contains(t, res.JSON.Message, "What's Modified")
+}
+
+func TestClient_DiffDocuments_Formats(t *testing.T) {
+	c, err := NewClient()
+	require.NoError(t, err)
+	doc := loadDoc(t, "testdata/old.graphql")
+
+	res, err := c.DiffDocuments(doc, loadDoc(t, "testdata/new.graphql"), &diff.Config{OutputFormat: "markdown"})
+	require.NoError(t, err)
+	assert.Contains(t, res.Markdown, "What's New")
+
+	res, err = c.DiffDocuments(doc, doc, &diff.Config{OutputFormat: "text"})
+	require.NoError(t, err)
+	assert.Empty(t, res.Text)
+
+	_, err = c.DiffDocuments(doc, doc, &diff.Config{OutputFormat: "html"})
+	assert.Error(t, err)
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/cisco-developer/api-insights/api/internal/models"
 	"github.com/cisco-developer/api-insights/api/internal/models/diff"
 	"github.com/cisco-developer/api-insights/api/pkg/differ/openapi-diff/result"
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/oas31"
 	"github.com/cisco-developer/api-insights/api/pkg/utils/shared"
 	"github.com/getkin/kin-openapi/openapi3"
 	"os"
@@ -37,6 +39,16 @@ func NewClient() (Differ, error) {
 	return &cliClient{}, nil
 }
 
+// prepareDoc downgrades doc (see oas31.Downgrade) & normalizes it into JSON (see utils.NormalizeDoc),
+// so that both docs are diffed in the same form, whatever their serialization.
+func prepareDoc(doc models.SpecDoc) ([]byte, error) {
+	data, _, err := oas31.Downgrade([]byte(*doc))
+	if err != nil {
+		return nil, err
+	}
+	return utils.NormalizeDoc(data, utils.DocFormatJSON)
+}
+
 // cliClient implements Differ.
 type cliClient struct {
 }
@@ -73,7 +85,11 @@ func (c *cliClient) DiffDocuments(oldDoc, newDoc models.SpecDoc, cfg *diff.Confi
 	if oldDoc == nil || *oldDoc == "" {
 		return nil, fmt.Errorf("oldDoc is nil or empty")
 	}
-	_, err = oldFile.Write([]byte(*oldDoc))
+	oldData, err := prepareDoc(oldDoc)
+	if err != nil {
+		return nil, err
+	}
+	_, err = oldFile.Write(oldData)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +104,11 @@ func (c *cliClient) DiffDocuments(oldDoc, newDoc models.SpecDoc, cfg *diff.Confi
 	if newDoc == nil || *newDoc == "" {
 		return nil, fmt.Errorf("newDoc is nil or empty")
 	}
-	_, err = newFile.Write([]byte(*newDoc))
+	newData, err := prepareDoc(newDoc)
+	if err != nil {
+		return nil, err
+	}
+	_, err = newFile.Write(newData)
 	if err != nil {
 		return nil, err
 	}
@@ -125,11 +145,11 @@ func (c *cliClient) DiffDocuments(oldDoc, newDoc models.SpecDoc, cfg *diff.Confi
 		if err != nil {
 			return nil, err
 		}
-		changedOpenAPI.OldSpecOpenAPI, err = openapi3.NewLoader().LoadFromData([]byte(*oldDoc))
+		changedOpenAPI.OldSpecOpenAPI, err = openapi3.NewLoader().LoadFromData(oldData)
 		if err != nil {
 			return nil, err
 		}
-		changedOpenAPI.NewSpecOpenAPI, err = openapi3.NewLoader().LoadFromData([]byte(*newDoc))
+		changedOpenAPI.NewSpecOpenAPI, err = openapi3.NewLoader().LoadFromData(newData)
 		if err != nil {
 			return nil, err
 		}
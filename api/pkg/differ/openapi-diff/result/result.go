@@ -18,6 +18,7 @@ package result
 
 import (
 	"encoding/json"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/oas31"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
@@ -294,8 +295,18 @@ type DiffResult struct {
 }
 
 func NewChangedOpenAPIFromBytes(data []byte) (*ChangedOpenAPI, error) {
+	// Downgrade any OpenAPI 3.1 (JSON Schema 2020-12) schema constructs, which openapi3.Schema fails to unmarshal.
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(oas31.DowngradeSchemas(raw))
+	if err != nil {
+		return nil, err
+	}
+
 	var changedOpenAPI *ChangedOpenAPI
-	err := json.Unmarshal(data, &changedOpenAPI)
+	err = json.Unmarshal(data, &changedOpenAPI)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,428 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package protobufdiff diffs protobuf schemas at the RPC, message & enum level.
+package protobufdiff
+
+import (
+	"fmt"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/cisco-developer/api-insights/api/pkg/protobuf"
+)
+
+const (
+	kindRPC     = "rpc"
+	kindMessage = "message"
+	kindEnum    = "enum"
+)
+
+type Differ interface {
+	DiffDocuments(oldDoc, newDoc models.SpecDoc, cfg *diff.Config) (*diff.Result, error)
+}
+
+func NewClient() (Differ, error) {
+	return &client{}, nil
+}
+
+// client implements Differ.
+type client struct {
+}
+
+func (c *client) DiffDocuments(oldDoc, newDoc models.SpecDoc, cfg *diff.Config) (*diff.Result, error) {
+	if cfg == nil {
+		cfg = &diff.Config{}
+	}
+	if cfg.OutputFormat == "" {
+		cfg.OutputFormat = "json"
+	}
+
+	if oldDoc == nil || *oldDoc == "" {
+		return nil, fmt.Errorf("oldDoc is nil or empty")
+	}
+	if newDoc == nil || *newDoc == "" {
+		return nil, fmt.Errorf("newDoc is nil or empty")
+	}
+	oldSchema, err := protobuf.Load([]byte(*oldDoc))
+	if err != nil {
+		return nil, err
+	}
+	newSchema, err := protobuf.Load([]byte(*newDoc))
+	if err != nil {
+		return nil, err
+	}
+
+	resJSON := NewResultFrom(oldSchema, newSchema, diff.NewMarkdownSummaryMessageBuilder())
+
+	res := &diff.Result{}
+	switch cfg.OutputFormat {
+	case "json":
+		res.JSON = resJSON
+	case "markdown":
+		res.Markdown = resJSON.Message
+	case "text":
+		res.Text = resJSON.Message
+	default:
+		return nil, fmt.Errorf("differ: unsupported output format(%s) for protobuf specs", cfg.OutputFormat)
+	}
+	return res, nil
+}
+
+type methodRef struct {
+	service *protobuf.Service
+	method  *protobuf.Method
+}
+
+// indexMethods indexes the RPCs of schema by their gRPC path.
+func indexMethods(schema *protobuf.Schema) (map[string]*methodRef, []string) {
+	var (
+		methods = map[string]*methodRef{}
+		paths   []string
+	)
+	for _, svc := range schema.SortedServices() {
+		for _, method := range svc.Methods {
+			path := method.Path(svc)
+			methods[path] = &methodRef{service: svc, method: method}
+			paths = append(paths, path)
+		}
+	}
+	return methods, paths
+}
+
+func newEndpointSummary(path, kind, description, action string) *diff.EndpointSummary {
+	return &diff.EndpointSummary{Path: path, Method: kind, Description: description, Message: fmt.Sprintf("%s - %s", path, action)}
+}
+
+// NewResultFrom diffs the RPCs, messages & enums of oldSchema & newSchema, which are reported with their gRPC path or
+// full name as Path & their kind (rpc, message or enum) as Method.
+//
+// Changes that break either the wire format or the generated code are breaking, e.g. deleted RPCs, messages, enums,
+// fields & enum values, renumbered or renamed fields, type & label changes, & changed streaming modes.
+func NewResultFrom(oldSchema, newSchema *protobuf.Schema, msgBuilder diff.SummaryMessageBuilder) *diff.JSONResult {
+	result := &diff.JSONResult{}
+
+	oldMethods, oldPaths := indexMethods(oldSchema)
+	newMethods, newPaths := indexMethods(newSchema)
+
+	for _, path := range newPaths {
+		if _, ok := oldMethods[path]; !ok {
+			result.Added = append(result.Added, newEndpointSummary(path, kindRPC, newMethods[path].method.Comment, "Added"))
+		}
+	}
+	for _, msg := range newSchema.SortedMessages() {
+		if _, ok := oldSchema.Messages[msg.FullName]; !ok {
+			result.Added = append(result.Added, newEndpointSummary(msg.FullName, kindMessage, msg.Comment, "Added"))
+		}
+	}
+	for _, enum := range newSchema.SortedEnums() {
+		if _, ok := oldSchema.Enums[enum.FullName]; !ok {
+			result.Added = append(result.Added, newEndpointSummary(enum.FullName, kindEnum, enum.Comment, "Added"))
+		}
+	}
+
+	for _, path := range oldPaths {
+		if _, ok := newMethods[path]; !ok {
+			result.Deleted = append(result.Deleted, newEndpointSummary(path, kindRPC, oldMethods[path].method.Comment, "Deleted"))
+		}
+	}
+	for _, msg := range oldSchema.SortedMessages() {
+		if _, ok := newSchema.Messages[msg.FullName]; !ok {
+			result.Deleted = append(result.Deleted, newEndpointSummary(msg.FullName, kindMessage, msg.Comment, "Deleted"))
+		}
+	}
+	for _, enum := range oldSchema.SortedEnums() {
+		if _, ok := newSchema.Enums[enum.FullName]; !ok {
+			result.Deleted = append(result.Deleted, newEndpointSummary(enum.FullName, kindEnum, enum.Comment, "Deleted"))
+		}
+	}
+	result.Breaking = len(result.Deleted) > 0
+
+	for _, path := range newPaths {
+		oldRef, ok := oldMethods[path]
+		if !ok {
+			continue
+		}
+		newRef := newMethods[path]
+		if newRef.method.Deprecated && !oldRef.method.Deprecated {
+			result.Deprecated = append(result.Deprecated, newEndpointSummary(path, kindRPC, newRef.method.Comment, "Deprecated"))
+		}
+		addModified(result, path, kindRPC, newRef.method.Comment, diffMethods(oldRef.method, newRef.method, msgBuilder), msgBuilder)
+	}
+	for _, msg := range newSchema.SortedMessages() {
+		if oldMsg, ok := oldSchema.Messages[msg.FullName]; ok {
+			addModified(result, msg.FullName, kindMessage, msg.Comment, diffMessages(oldMsg, msg, msgBuilder), msgBuilder)
+		}
+	}
+	for _, enum := range newSchema.SortedEnums() {
+		if oldEnum, ok := oldSchema.Enums[enum.FullName]; ok {
+			addModified(result, enum.FullName, kindEnum, enum.Comment, diffEnums(oldEnum, enum, msgBuilder), msgBuilder)
+		}
+	}
+
+	result.Message = msgBuilder.BuildResultSummaryMessage(result)
+
+	return result
+}
+
+// addModified adds a diff.ModifiedSummary of the details of a modified definition to result, if any.
+func addModified(result *diff.JSONResult, path, kind, description string, details []*diff.MessageSummaryDetail, msgBuilder diff.SummaryMessageBuilder) {
+	if len(details) == 0 {
+		return
+	}
+	messagesSummary := &diff.MessagesSummary{Details: details}
+	for _, d := range details {
+		messagesSummary.Breaking = messagesSummary.Breaking || d.Breaking
+	}
+	messagesSummary.Message = msgBuilder.BuildMessagesSummaryMessage(messagesSummary)
+
+	modifiedSummary := &diff.ModifiedSummary{
+		Path:            path,
+		Method:          kind,
+		Description:     description,
+		Breaking:        messagesSummary.Breaking,
+		MessagesSummary: messagesSummary,
+	}
+	modifiedSummary.Message = msgBuilder.BuildModifiedSummaryMessage(modifiedSummary)
+	result.Modified = append(result.Modified, modifiedSummary)
+	result.Breaking = result.Breaking || modifiedSummary.Breaking
+}
+
+// newDetail returns a modified diff.MessageSummaryDetail of properties, if any.
+func newDetail(name, description string, properties []*diff.PropertiesSummary, msgBuilder diff.SummaryMessageBuilder) []*diff.MessageSummaryDetail {
+	if len(properties) == 0 {
+		return nil
+	}
+	d := &diff.MessageSummaryDetail{Name: name, Description: description, Action: diff.ActionModified, Properties: properties}
+	for _, p := range properties {
+		p.Message = msgBuilder.BuildPropertiesSummaryMessage(p, 0)
+		d.Breaking = d.Breaking || p.Breaking
+	}
+	d.Message = msgBuilder.BuildMessageSummaryDetailMessage(d)
+	return []*diff.MessageSummaryDetail{d}
+}
+
+// diffMethods diffs the request & response of an RPC, i.e. their message types & whether they are streamed.
+func diffMethods(oldMethod, newMethod *protobuf.Method, msgBuilder diff.SummaryMessageBuilder) (details []*diff.MessageSummaryDetail) {
+	sides := []struct {
+		name                       string
+		oldType, newType           string
+		oldStreaming, newStreaming bool
+	}{
+		{"request", oldMethod.InputType, newMethod.InputType, oldMethod.ClientStreaming, newMethod.ClientStreaming},
+		{"response", oldMethod.OutputType, newMethod.OutputType, oldMethod.ServerStreaming, newMethod.ServerStreaming},
+	}
+	for _, side := range sides {
+		var properties []*diff.PropertiesSummary
+		if side.oldType != side.newType {
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        side.name,
+				Type:        side.newType,
+				Description: fmt.Sprintf("Type changed from %s to %s", side.oldType, side.newType),
+				Action:      diff.ActionModified,
+				Breaking:    true,
+			})
+		}
+		if side.oldStreaming != side.newStreaming {
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        side.name,
+				Type:        side.newType,
+				Description: fmt.Sprintf("Streaming mode changed from %s to %s", oldMethod.StreamingMode(), newMethod.StreamingMode()),
+				Action:      diff.ActionModified,
+				Breaking:    true,
+			})
+		}
+		details = append(details, newDetail(side.name, side.newType, properties, msgBuilder)...)
+	}
+	return details
+}
+
+// diffMessages diffs the fields of a message, which are matched by number, then by name to detect renumbered fields.
+func diffMessages(oldMsg, newMsg *protobuf.Message, msgBuilder diff.SummaryMessageBuilder) []*diff.MessageSummaryDetail {
+	var (
+		properties      []*diff.PropertiesSummary
+		oldByNumber     = map[int]*protobuf.Field{}
+		newByNumber     = map[int]*protobuf.Field{}
+		oldByName       = map[string]*protobuf.Field{}
+		newByName       = map[string]*protobuf.Field{}
+		renumberedNames = map[string]bool{}
+	)
+	for _, f := range oldMsg.Fields {
+		oldByNumber[f.Number] = f
+		oldByName[f.Name] = f
+	}
+	for _, f := range newMsg.Fields {
+		newByNumber[f.Number] = f
+		newByName[f.Name] = f
+	}
+
+	for _, oldField := range oldMsg.Fields {
+		if _, ok := newByNumber[oldField.Number]; ok {
+			continue
+		}
+		if newField, ok := newByName[oldField.Name]; ok && oldByNumber[newField.Number] == nil {
+			renumberedNames[oldField.Name] = true
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        oldField.Name,
+				Type:        newField.Type,
+				Description: fmt.Sprintf("Renumbered from %d to %d", oldField.Number, newField.Number),
+				Action:      diff.ActionModified,
+				Breaking:    true,
+			})
+			continue
+		}
+		description := fmt.Sprintf("Field %d deleted", oldField.Number)
+		if !newMsg.IsReserved(oldField.Number, oldField.Name) {
+			description += " without being reserved"
+		}
+		properties = append(properties, &diff.PropertiesSummary{
+			Name:        oldField.Name,
+			Type:        oldField.Type,
+			Description: description,
+			Action:      diff.ActionDeleted,
+			Breaking:    true,
+		})
+	}
+
+	for _, newField := range newMsg.Fields {
+		if renumberedNames[newField.Name] {
+			continue
+		}
+		oldField, ok := oldByNumber[newField.Number]
+		if !ok {
+			p := &diff.PropertiesSummary{
+				Name:        newField.Name,
+				Type:        newField.Type,
+				Description: newField.Comment,
+				Action:      diff.ActionAdded,
+				Breaking:    newField.Label == protobuf.LabelRequired,
+			}
+			if oldMsg.IsReserved(newField.Number, "") {
+				p.Description = fmt.Sprintf("Reuses reserved field %d", newField.Number)
+				p.Breaking = true
+			}
+			properties = append(properties, p)
+			continue
+		}
+		properties = append(properties, diffFields(oldField, newField)...)
+	}
+
+	return newDetail(newMsg.FullName, newMsg.Comment, properties, msgBuilder)
+}
+
+// diffFields diffs fields of the same number.
+func diffFields(oldField, newField *protobuf.Field) (properties []*diff.PropertiesSummary) {
+	modified := func(description string, breaking bool) {
+		properties = append(properties, &diff.PropertiesSummary{
+			Name:        newField.Name,
+			Type:        newField.Type,
+			Description: description,
+			Action:      diff.ActionModified,
+			Breaking:    breaking,
+		})
+	}
+	if oldField.Name != newField.Name {
+		modified(fmt.Sprintf("Renamed from %s", oldField.Name), true)
+	}
+	if oldField.Type != newField.Type {
+		description := fmt.Sprintf("Type changed from %s to %s", oldField.Type, newField.Type)
+		if wireCompatible(oldField.Type, newField.Type) {
+			description += " (wire-compatible)"
+		}
+		modified(description, true)
+	}
+	if oldField.Label != newField.Label {
+		modified(fmt.Sprintf("Label changed from %s to %s", labelName(oldField.Label), labelName(newField.Label)), true)
+	}
+	if oldField.OneOf != newField.OneOf {
+		modified(fmt.Sprintf("Oneof changed from %s to %s", labelName(oldField.OneOf), labelName(newField.OneOf)), true)
+	}
+	if newField.Deprecated && !oldField.Deprecated {
+		modified("Deprecated", false)
+	}
+	return properties
+}
+
+// diffEnums diffs the values of an enum, which are matched by name.
+func diffEnums(oldEnum, newEnum *protobuf.Enum, msgBuilder diff.SummaryMessageBuilder) []*diff.MessageSummaryDetail {
+	var (
+		properties []*diff.PropertiesSummary
+		oldByName  = map[string]*protobuf.EnumValue{}
+		newByName  = map[string]*protobuf.EnumValue{}
+	)
+	for _, v := range oldEnum.Values {
+		oldByName[v.Name] = v
+	}
+	for _, v := range newEnum.Values {
+		newByName[v.Name] = v
+	}
+
+	for _, oldValue := range oldEnum.Values {
+		newValue, ok := newByName[oldValue.Name]
+		switch {
+		case !ok:
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        oldValue.Name,
+				Type:        kindEnum,
+				Description: fmt.Sprintf("Value %d deleted", oldValue.Number),
+				Action:      diff.ActionDeleted,
+				Breaking:    true,
+			})
+		case oldValue.Number != newValue.Number:
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        oldValue.Name,
+				Type:        kindEnum,
+				Description: fmt.Sprintf("Renumbered from %d to %d", oldValue.Number, newValue.Number),
+				Action:      diff.ActionModified,
+				Breaking:    true,
+			})
+		}
+	}
+	for _, newValue := range newEnum.Values {
+		if _, ok := oldByName[newValue.Name]; !ok {
+			properties = append(properties, &diff.PropertiesSummary{
+				Name:        newValue.Name,
+				Type:        kindEnum,
+				Description: fmt.Sprintf("Value %d added", newValue.Number),
+				Action:      diff.ActionAdded,
+			})
+		}
+	}
+
+	return newDetail(newEnum.FullName, newEnum.Comment, properties, msgBuilder)
+}
+
+// wireCompatibleTypes groups the scalar types that share a wire encoding, so that changing a field between types of a
+// group keeps decoding old messages, although possibly truncating values & breaking generated code.
+var wireCompatibleTypes = map[string]int{
+	"int32": 1, "uint32": 1, "int64": 1, "uint64": 1, "bool": 1,
+	"sint32": 2, "sint64": 2,
+	"fixed32": 3, "sfixed32": 3,
+	"fixed64": 4, "sfixed64": 4,
+	"string": 5, "bytes": 5,
+}
+
+func wireCompatible(oldType, newType string) bool {
+	oldGroup, ok := wireCompatibleTypes[oldType]
+	return ok && oldGroup == wireCompatibleTypes[newType]
+}
+
+func labelName(label string) string {
+	if label == "" {
+		return "none"
+	}
+	return label
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package protobufdiff
+
+import (
+	"os"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/api/internal/models"
+	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadDoc(t *testing.T, filename string) models.SpecDoc {
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	return models.NewSpecDocFromBytes(data)
+}
+
+func TestClient_DiffDocuments(t *testing.T) {
+	c, err := NewClient()
+	require.NoError(t, err)
+
+	res, err := c.DiffDocuments(loadDoc(t, "testdata/old.proto"), loadDoc(t, "testdata/new.proto"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, res.JSON)
+	assert.True(t, res.JSON.Breaking)
+
+	endpoints := func(summaries []*diff.EndpointSummary) (out []string) {
+		for _, e := range summaries {
+			out = append(out, e.Method+" "+e.Path)
+		}
+		return
+	}
+	assert.Equal(t, []string{
+		"rpc /acme.billing.v1.Billing/ListInvoices",
+		"message acme.billing.v1.ListInvoicesRequest",
+		"message acme.billing.v1.ListInvoicesResponse",
+	}, endpoints(res.JSON.Added))
+	assert.Equal(t, []string{
+		"rpc /acme.billing.v1.Billing/DeleteInvoice",
+		"message acme.billing.v1.DeleteInvoiceRequest",
+	}, endpoints(res.JSON.Deleted))
+	assert.Equal(t, []string{"rpc /acme.billing.v1.Billing/GetInvoice"}, endpoints(res.JSON.Deprecated))
+
+	modified := map[string]*diff.ModifiedSummary{}
+	for _, m := range res.JSON.Modified {
+		require.NotNil(t, m.MessagesSummary)
+		modified[m.Method+" "+m.Path] = m
+	}
+	require.Len(t, modified, 3)
+	properties := func(key string) map[string]*diff.PropertiesSummary {
+		m := modified[key]
+		require.NotNil(t, m, key)
+		out := map[string]*diff.PropertiesSummary{}
+		for _, d := range m.MessagesSummary.Details {
+			for _, p := range d.Properties {
+				out[p.Name+" "+string(p.Action)] = p
+			}
+		}
+		return out
+	}
+
+	watch := properties("rpc /acme.billing.v1.Billing/WatchInvoices")
+	require.Contains(t, watch, "response modified")
+	assert.True(t, watch["response modified"].Breaking)
+	assert.Equal(t, "Streaming mode changed from server-streaming to unary", watch["response modified"].Description)
+
+	invoice := properties("message acme.billing.v1.Invoice")
+	assert.Len(t, invoice, 5)
+	assert.Equal(t, "Field 4 deleted", invoice["legacy_total deleted"].Description)
+	assert.True(t, invoice["legacy_total deleted"].Breaking)
+	assert.Equal(t, "Renumbered from 5 to 7", invoice["customer_id modified"].Description)
+	assert.True(t, invoice["customer_id modified"].Breaking)
+	assert.Equal(t, "Type changed from int32 to int64 (wire-compatible)", invoice["total modified"].Description)
+	assert.Equal(t, "Label changed from repeated to none", invoice["tags modified"].Description)
+	assert.False(t, invoice["note added"].Breaking)
+
+	status := properties("enum acme.billing.v1.Invoice.Status")
+	assert.True(t, status["STATUS_PAID modified"].Breaking)
+	assert.True(t, status["STATUS_VOID deleted"].Breaking)
+
+	assert.Contains(t, res.JSON.Message, "What's Modified")
+	assert.Contains(t, modified["message acme.billing.v1.Invoice"].Message, "Messages:")
+}
+
+func TestClient_DiffDocuments_Formats(t *testing.T) {
+	c, err := NewClient()
+	require.NoError(t, err)
+	doc := loadDoc(t, "testdata/old.proto")
+
+	res, err := c.DiffDocuments(doc, loadDoc(t, "testdata/new.proto"), &diff.Config{OutputFormat: "markdown"})
+	require.NoError(t, err)
+	assert.Contains(t, res.Markdown, "What's New")
+
+	res, err = c.DiffDocuments(doc, doc, &diff.Config{OutputFormat: "text"})
+	require.NoError(t, err)
+	assert.Empty(t, res.Text)
+
+	_, err = c.DiffDocuments(doc, doc, &diff.Config{OutputFormat: "html"})
+	assert.Error(t, err)
+}
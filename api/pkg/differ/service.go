@@ -20,7 +20,13 @@ import (
 	"fmt"
 	"github.com/cisco-developer/api-insights/api/internal/models"
 	"github.com/cisco-developer/api-insights/api/internal/models/diff"
+	"github.com/cisco-developer/api-insights/api/pkg/asyncapi"
+	asyncapidiff "github.com/cisco-developer/api-insights/api/pkg/differ/asyncapi-diff"
+	graphqldiff "github.com/cisco-developer/api-insights/api/pkg/differ/graphql-diff"
 	openapidiff "github.com/cisco-developer/api-insights/api/pkg/differ/openapi-diff"
+	protobufdiff "github.com/cisco-developer/api-insights/api/pkg/differ/protobuf-diff"
+	"github.com/cisco-developer/api-insights/api/pkg/graphql"
+	"github.com/cisco-developer/api-insights/api/pkg/protobuf"
 )
 
 type Service interface {
@@ -35,7 +41,31 @@ type service struct {
 }
 
 func (service) Diff(req *models.SpecDiffRequest) (*diff.Result, error) {
-	// Select implementation of differ to run. ATM, only openapidiff differ is supported.
+	// Select implementation of differ to run, according to the kind of docs.
+
+	if isAsyncAPI(req.OldSpecDoc) && isAsyncAPI(req.NewSpecDoc) {
+		differClient, err := asyncapidiff.NewClient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create differ(asyncapidiff): %v", err)
+		}
+		return differClient.DiffDocuments(req.OldSpecDoc, req.NewSpecDoc, req.Config)
+	}
+
+	if isProtobuf(req.OldSpecDoc) && isProtobuf(req.NewSpecDoc) {
+		differClient, err := protobufdiff.NewClient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create differ(protobufdiff): %v", err)
+		}
+		return differClient.DiffDocuments(req.OldSpecDoc, req.NewSpecDoc, req.Config)
+	}
+
+	if isGraphQL(req.OldSpecDoc) && isGraphQL(req.NewSpecDoc) {
+		differClient, err := graphqldiff.NewClient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create differ(graphqldiff): %v", err)
+		}
+		return differClient.DiffDocuments(req.OldSpecDoc, req.NewSpecDoc, req.Config)
+	}
 
 	// openapidiff differ implementation
 	differClient, err := openapidiff.NewClient()
@@ -49,3 +79,15 @@ func (service) Diff(req *models.SpecDiffRequest) (*diff.Result, error) {
 
 	return diffRes, nil
 }
+
+func isAsyncAPI(doc models.SpecDoc) bool {
+	return doc != nil && asyncapi.IsAsyncAPI([]byte(*doc))
+}
+
+func isProtobuf(doc models.SpecDoc) bool {
+	return doc != nil && protobuf.IsProtobuf([]byte(*doc))
+}
+
+func isGraphQL(doc models.SpecDoc) bool {
+	return doc != nil && graphql.IsGraphQL([]byte(*doc))
+}
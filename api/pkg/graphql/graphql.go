@@ -0,0 +1,140 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package graphql loads GraphQL schemas from their SDL (schema definition language) source.
+package graphql
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const (
+	// SourceName is the name of the SDL source of a schema, as reported in its positions.
+	SourceName = "schema.graphql"
+
+	OperationQuery        = "query"
+	OperationMutation     = "mutation"
+	OperationSubscription = "subscription"
+)
+
+// IsGraphQL checks if data is a GraphQL SDL document.
+func IsGraphQL(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	doc, err := parser.ParseSchema(&ast.Source{Name: SourceName, Input: string(data)})
+	if err != nil {
+		return false
+	}
+	return len(doc.Definitions)+len(doc.Extensions)+len(doc.Schema)+len(doc.Directives) > 0
+}
+
+// Load loads & validates the schema of the SDL document data.
+func Load(data []byte) (*ast.Schema, error) {
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: SourceName, Input: string(data)})
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Definitions returns the types defined by schema, i.e. excluding built-in types, sorted by name.
+func Definitions(schema *ast.Schema) []*ast.Definition {
+	defs := make([]*ast.Definition, 0, len(schema.Types))
+	for _, def := range schema.Types {
+		if !def.BuiltIn {
+			defs = append(defs, def)
+		}
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// Fields returns the fields of def, excluding the introspection fields gqlparser adds to the query type.
+func Fields(def *ast.Definition) ast.FieldList {
+	fields := make(ast.FieldList, 0, len(def.Fields))
+	for _, field := range def.Fields {
+		if !strings.HasPrefix(field.Name, "__") {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// RootOperation returns the operation (e.g. OperationQuery) def is the root type of in schema, if any.
+func RootOperation(schema *ast.Schema, def *ast.Definition) string {
+	switch def {
+	case nil:
+		return ""
+	case schema.Query:
+		return OperationQuery
+	case schema.Mutation:
+		return OperationMutation
+	case schema.Subscription:
+		return OperationSubscription
+	}
+	return ""
+}
+
+// Keyword returns the SDL keyword of the kind of def, e.g. type or input.
+func Keyword(def *ast.Definition) string {
+	switch def.Kind {
+	case ast.Object:
+		return "type"
+	case ast.InputObject:
+		return "input"
+	}
+	return strings.ToLower(string(def.Kind))
+}
+
+// Deprecation returns whether directives deprecate their definition & the reason, if any.
+func Deprecation(directives ast.DirectiveList) (deprecated bool, reason string) {
+	d := directives.ForName("deprecated")
+	if d == nil {
+		return false, ""
+	}
+	if arg := d.Arguments.ForName("reason"); arg != nil && arg.Value != nil {
+		reason = arg.Value.Raw
+	}
+	return true, reason
+}
+
+// Range returns the start & end of the token at pos, as 1-based lines & columns in its source.
+func Range(pos *ast.Position) (startLine, startColumn, endLine, endColumn int) {
+	if pos == nil {
+		return 1, 1, 1, 1
+	}
+	startLine, startColumn = pos.Line, pos.Column
+	endLine, endColumn = startLine, startColumn
+	if pos.Src == nil || pos.End <= pos.Start || pos.End > len(pos.Src.Input) {
+		return
+	}
+	for _, r := range pos.Src.Input[pos.Start:pos.End] {
+		if r == '\n' {
+			endLine++
+			endColumn = 1
+		} else {
+			endColumn++
+		}
+	}
+	return
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphql
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsGraphQL(t *testing.T) {
+	data, err := os.ReadFile("testdata/library.graphql")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{"sdl", string(data), true},
+		{"openapi yaml", "openapi: 3.0.3\ninfo:\n  title: a\n", false},
+		{"openapi json", `{"openapi": "3.0.3"}`, false},
+		{"proto", "syntax = \"proto3\";\nmessage A {}\n", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsGraphQL([]byte(tt.data)))
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	data, err := os.ReadFile("testdata/library.graphql")
+	require.NoError(t, err)
+
+	schema, err := Load(data)
+	require.NoError(t, err)
+
+	var names []string
+	for _, def := range Definitions(schema) {
+		names = append(names, def.Name)
+	}
+	assert.Equal(t, []string{"AddBookInput", "Author", "Book", "Genre", "Mutation", "Node", "Query", "SearchResult"}, names)
+
+	assert.Len(t, Fields(schema.Query), 2)
+	assert.Equal(t, OperationQuery, RootOperation(schema, schema.Types["Query"]))
+	assert.Equal(t, OperationMutation, RootOperation(schema, schema.Types["Mutation"]))
+	assert.Empty(t, RootOperation(schema, schema.Types["Book"]))
+	assert.Equal(t, "input", Keyword(schema.Types["AddBookInput"]))
+	assert.Equal(t, "type", Keyword(schema.Types["Book"]))
+
+	deprecated, reason := Deprecation(schema.Types["Book"].Fields.ForName("isbn").Directives)
+	assert.True(t, deprecated)
+	assert.Empty(t, reason)
+
+	startLine, startColumn, endLine, endColumn := Range(schema.Types["Book"].Position)
+	assert.Equal(t, []int{15, 6, 15, 10}, []int{startLine, startColumn, endLine, endColumn})
+
+	_, err = Load([]byte("type Query { a: Missing }"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNotConfigured = errors.New("mailer: no SMTP host configured")
+
+type ClientConfig struct {
+	smtpHost     string
+	smtpPort     int
+	smtpUsername string
+	smtpPassword string
+	smtpFrom     string
+}
+
+var (
+	defaultClientCfg = &ClientConfig{
+		smtpPort: 587,
+		smtpFrom: "api-insights@localhost",
+	}
+)
+
+func Flags() []cli.Flag {
+	return []cli.Flag{
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        "smtp-host",
+			Usage:       "SMTP server host to send emails (e.g. digests) through; emails are disabled if empty",
+			Value:       defaultClientCfg.smtpHost,
+			Destination: &defaultClientCfg.smtpHost,
+			EnvVars:     []string{"SMTP_HOST"},
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:        "smtp-port",
+			Usage:       "SMTP server port",
+			Value:       defaultClientCfg.smtpPort,
+			Destination: &defaultClientCfg.smtpPort,
+			EnvVars:     []string{"SMTP_PORT"},
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        "smtp-username",
+			Usage:       "SMTP username, if the server requires authentication",
+			Value:       defaultClientCfg.smtpUsername,
+			Destination: &defaultClientCfg.smtpUsername,
+			EnvVars:     []string{"SMTP_USERNAME"},
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        "smtp-password",
+			Usage:       "SMTP password, if the server requires authentication",
+			Value:       defaultClientCfg.smtpPassword,
+			Destination: &defaultClientCfg.smtpPassword,
+			EnvVars:     []string{"SMTP_PASSWORD"},
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:        "smtp-from",
+			Usage:       "sender address of emails",
+			Value:       defaultClientCfg.smtpFrom,
+			Destination: &defaultClientCfg.smtpFrom,
+			EnvVars:     []string{"SMTP_FROM"},
+		}),
+	}
+}
+
+// Configured checks if emails can be sent, i.e. an SMTP host is configured.
+func Configured() bool {
+	return defaultClientCfg.smtpHost != ""
+}
+
+// Send sends a plain text email with subject & body to the to addresses, through the configured SMTP server.
+func Send(to []string, subject, body string) error {
+	cfg := defaultClientCfg
+	if cfg.smtpHost == "" {
+		return ErrNotConfigured
+	}
+
+	var auth smtp.Auth
+	if cfg.smtpUsername != "" {
+		auth = smtp.PlainAuth("", cfg.smtpUsername, cfg.smtpPassword, cfg.smtpHost)
+	}
+	addr := net.JoinHostPort(cfg.smtpHost, strconv.Itoa(cfg.smtpPort))
+	return smtp.SendMail(addr, auth, cfg.smtpFrom, to, Message(cfg.smtpFrom, to, subject, body, time.Now()))
+}
+
+// Message returns the RFC 5322 message of a plain text email.
+func Message(from string, to []string, subject, body string, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
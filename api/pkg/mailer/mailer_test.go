@@ -0,0 +1,41 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mailer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage(t *testing.T) {
+	date := time.Date(2022, 3, 8, 9, 0, 0, 0, time.UTC)
+	msg := Message("api-insights@example.com", []string{"lead@example.com", "dev@example.com"}, "Weekly digest → shop", "# Digest\n\n- Carts\n", date)
+	assert.Equal(t, "From: api-insights@example.com\r\n"+
+		"To: lead@example.com, dev@example.com\r\n"+
+		"Subject: =?utf-8?q?Weekly_digest_=E2=86=92_shop?=\r\n"+
+		"Date: Tue, 08 Mar 2022 09:00:00 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n"+
+		"Content-Transfer-Encoding: 8bit\r\n"+
+		"\r\n"+
+		"# Digest\r\n\r\n- Carts\r\n", string(msg))
+
+	assert.False(t, Configured())
+	assert.ErrorIs(t, Send([]string{"lead@example.com"}, "subject", "body"), ErrNotConfigured)
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuf
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DescriptorSetToJSON converts a binary FileDescriptorSet (e.g. protoc --descriptor_set_out) into its JSON encoding.
+func DescriptorSetToJSON(data []byte) ([]byte, error) {
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, fds); err != nil {
+		return nil, fmt.Errorf("protobuf: invalid FileDescriptorSet: %v", err)
+	}
+	if len(fds.File) == 0 {
+		return nil, fmt.Errorf("protobuf: empty FileDescriptorSet")
+	}
+	return protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(fds)
+}
+
+// ParseDescriptorSetJSON parses a FileDescriptorSet in its JSON encoding into a Schema.
+func ParseDescriptorSetJSON(data []byte) (*Schema, error) {
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := protojson.Unmarshal(data, fds); err != nil {
+		return nil, fmt.Errorf("protobuf: invalid FileDescriptorSet: %v", err)
+	}
+	return ParseDescriptorSet(fds)
+}
+
+// ParseDescriptorSet parses a FileDescriptorSet into a Schema.
+func ParseDescriptorSet(fds *descriptorpb.FileDescriptorSet) (*Schema, error) {
+	s := newSchema()
+	for _, fd := range fds.File {
+		file := &File{Name: fd.GetName(), Syntax: fd.GetSyntax(), Package: fd.GetPackage()}
+		if file.Syntax == "" {
+			file.Syntax = SyntaxProto2
+		}
+		s.Files = append(s.Files, file)
+
+		for _, md := range fd.MessageType {
+			parseMessageDescriptor(s, file, file.Package, md)
+		}
+		for _, ed := range fd.EnumType {
+			parseEnumDescriptor(s, file, file.Package, ed)
+		}
+		for _, sd := range fd.Service {
+			svc := &Service{FullName: qualify(file.Package, sd.GetName()), Name: sd.GetName(), File: file.Name}
+			for _, md := range sd.Method {
+				svc.Methods = append(svc.Methods, &Method{
+					Name:            md.GetName(),
+					InputType:       strings.TrimPrefix(md.GetInputType(), "."),
+					OutputType:      strings.TrimPrefix(md.GetOutputType(), "."),
+					ClientStreaming: md.GetClientStreaming(),
+					ServerStreaming: md.GetServerStreaming(),
+					Deprecated:      md.GetOptions().GetDeprecated(),
+				})
+			}
+			s.Services[svc.FullName] = svc
+		}
+	}
+	return s, nil
+}
+
+func parseMessageDescriptor(s *Schema, file *File, scope string, md *descriptorpb.DescriptorProto) {
+	m := &Message{FullName: qualify(scope, md.GetName()), Name: md.GetName(), File: file.Name}
+	s.Messages[m.FullName] = m
+
+	// Map fields are repeated fields of synthetic nested map entry messages.
+	mapEntries := map[string]*descriptorpb.DescriptorProto{}
+	for _, nested := range md.NestedType {
+		if nested.GetOptions().GetMapEntry() {
+			mapEntries[qualify(m.FullName, nested.GetName())] = nested
+			continue
+		}
+		parseMessageDescriptor(s, file, m.FullName, nested)
+	}
+	for _, ed := range md.EnumType {
+		parseEnumDescriptor(s, file, m.FullName, ed)
+	}
+
+	for _, fd := range md.Field {
+		f := &Field{
+			Name:       fd.GetName(),
+			Number:     int(fd.GetNumber()),
+			Type:       fieldType(fd),
+			Deprecated: fd.GetOptions().GetDeprecated(),
+		}
+		switch {
+		case fd.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED:
+			f.Label = LabelRepeated
+			if entry, ok := mapEntries[f.Type]; ok && len(entry.Field) == 2 {
+				f.Label = ""
+				f.Type = "map<" + fieldType(entry.Field[0]) + "," + fieldType(entry.Field[1]) + ">"
+			}
+		case fd.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED:
+			f.Label = LabelRequired
+		case fd.GetProto3Optional() || file.Syntax == SyntaxProto2:
+			f.Label = LabelOptional
+		}
+		// Synthetic oneofs of proto3 optional fields aren't oneofs.
+		if fd.OneofIndex != nil && !fd.GetProto3Optional() && int(fd.GetOneofIndex()) < len(md.OneofDecl) {
+			f.OneOf = md.OneofDecl[fd.GetOneofIndex()].GetName()
+		}
+		m.Fields = append(m.Fields, f)
+	}
+
+	m.ReservedNames = append(m.ReservedNames, md.ReservedName...)
+	for _, r := range md.ReservedRange {
+		// Descriptor ranges are end-exclusive.
+		m.Reserved = append(m.Reserved, Range{From: int(r.GetStart()), To: int(r.GetEnd()) - 1})
+	}
+}
+
+func parseEnumDescriptor(s *Schema, file *File, scope string, ed *descriptorpb.EnumDescriptorProto) {
+	e := &Enum{FullName: qualify(scope, ed.GetName()), Name: ed.GetName(), File: file.Name}
+	for _, v := range ed.Value {
+		e.Values = append(e.Values, &EnumValue{Name: v.GetName(), Number: int(v.GetNumber())})
+	}
+	s.Enums[e.FullName] = e
+}
+
+func fieldType(fd *descriptorpb.FieldDescriptorProto) string {
+	switch fd.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_ENUM, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return strings.TrimPrefix(fd.GetTypeName(), ".")
+	}
+	// e.g. TYPE_INT32 is int32.
+	return strings.ToLower(strings.TrimPrefix(fd.GetType().String(), "TYPE_"))
+}
@@ -0,0 +1,342 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package protobuf loads protobuf definitions, either .proto files or a compiled FileDescriptorSet, into a Schema of
+// fully-qualified messages, enums & services.
+//
+// A protobuf spec doc is either the source of a single .proto file, a bundle of .proto files (see BundleFiles), or a
+// FileDescriptorSet in its JSON encoding (see DescriptorSetToJSON).
+package protobuf
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	SyntaxProto2 = "proto2"
+	SyntaxProto3 = "proto3"
+
+	LabelOptional = "optional"
+	LabelRepeated = "repeated"
+	LabelRequired = "required"
+
+	// bundleKey is the key of the files of a bundle doc (see BundleFiles).
+	bundleKey = "proto_files"
+)
+
+// Schema represents the definitions of a set of protobuf files.
+type Schema struct {
+	Files    []*File
+	Messages map[string]*Message // by full name
+	Enums    map[string]*Enum    // by full name
+	Services map[string]*Service // by full name
+
+	// HasSourceInfo is set if the definitions have comments & positions, i.e. were loaded from .proto files.
+	HasSourceInfo bool
+}
+
+// File represents a protobuf file.
+type File struct {
+	Name    string
+	Syntax  string
+	Package string
+}
+
+// Position represents the position of a definition in a .proto file.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// Message represents a message.
+type Message struct {
+	FullName      string
+	Name          string
+	File          string
+	Fields        []*Field
+	ReservedNames []string
+	Reserved      []Range
+	Comment       string
+	Pos           Position
+}
+
+// Range represents an inclusive range of reserved field numbers.
+type Range struct{ From, To int }
+
+// IsReserved checks if number or name are reserved in m.
+func (m *Message) IsReserved(number int, name string) bool {
+	for _, r := range m.Reserved {
+		if number >= r.From && number <= r.To {
+			return true
+		}
+	}
+	for _, n := range m.ReservedNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Field represents a message field.
+type Field struct {
+	Name       string
+	Number     int
+	Type       string // scalar type, full name of a message or enum, or map<key,value>
+	Label      string // LabelOptional, LabelRepeated, LabelRequired or none
+	OneOf      string
+	Deprecated bool
+	Comment    string
+	Pos        Position
+}
+
+// Enum represents an enum.
+type Enum struct {
+	FullName string
+	Name     string
+	File     string
+	Values   []*EnumValue
+	Comment  string
+	Pos      Position
+}
+
+// EnumValue represents an enum value.
+type EnumValue struct {
+	Name   string
+	Number int
+	Pos    Position
+}
+
+// Service represents a service.
+type Service struct {
+	FullName string
+	Name     string
+	File     string
+	Methods  []*Method
+	Comment  string
+	Pos      Position
+}
+
+// Method represents an RPC of a Service.
+type Method struct {
+	Name            string
+	InputType       string
+	OutputType      string
+	ClientStreaming bool
+	ServerStreaming bool
+	Deprecated      bool
+	Comment         string
+	Pos             Position
+}
+
+// Path returns the gRPC path of m, i.e. /{service}/{method}.
+func (m *Method) Path(s *Service) string {
+	return "/" + s.FullName + "/" + m.Name
+}
+
+// StreamingMode returns the streaming mode of m, i.e. unary, client-streaming, server-streaming or bidi-streaming.
+func (m *Method) StreamingMode() string {
+	switch {
+	case m.ClientStreaming && m.ServerStreaming:
+		return "bidi-streaming"
+	case m.ClientStreaming:
+		return "client-streaming"
+	case m.ServerStreaming:
+		return "server-streaming"
+	}
+	return "unary"
+}
+
+func newSchema() *Schema {
+	return &Schema{
+		Messages: map[string]*Message{},
+		Enums:    map[string]*Enum{},
+		Services: map[string]*Service{},
+	}
+}
+
+// Syntax returns the syntax of the first file of s.
+func (s *Schema) Syntax() string {
+	if len(s.Files) == 0 {
+		return SyntaxProto2
+	}
+	return s.Files[0].Syntax
+}
+
+// Package returns the package of the first file of s.
+func (s *Schema) Package() string {
+	if len(s.Files) == 0 {
+		return ""
+	}
+	return s.Files[0].Package
+}
+
+var packageVersionPattern = regexp.MustCompile(`^v\d+((alpha|beta)\d*)?$`)
+
+// Version returns the version suffix of the package of s (e.g. v1 for acme.billing.v1), if any.
+func (s *Schema) Version() string {
+	parts := strings.Split(s.Package(), ".")
+	if last := parts[len(parts)-1]; packageVersionPattern.MatchString(last) {
+		return last
+	}
+	return ""
+}
+
+// SortedMessages returns the messages of s, sorted by full name.
+func (s *Schema) SortedMessages() []*Message {
+	out := make([]*Message, 0, len(s.Messages))
+	for _, m := range s.Messages {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].FullName < out[j].FullName })
+	return out
+}
+
+// SortedEnums returns the enums of s, sorted by full name.
+func (s *Schema) SortedEnums() []*Enum {
+	out := make([]*Enum, 0, len(s.Enums))
+	for _, e := range s.Enums {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].FullName < out[j].FullName })
+	return out
+}
+
+// SortedServices returns the services of s, sorted by full name.
+func (s *Schema) SortedServices() []*Service {
+	out := make([]*Service, 0, len(s.Services))
+	for _, svc := range s.Services {
+		out = append(out, svc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].FullName < out[j].FullName })
+	return out
+}
+
+var protoSourcePattern = regexp.MustCompile(`(?m)^\s*(syntax\s*=\s*["']proto[23]["']\s*;|package\s+[\w.]+\s*;|message\s+\w+\s*\{|service\s+\w+\s*\{)`)
+
+// IsProtoSource checks if data is the source of a .proto file.
+func IsProtoSource(data []byte) bool {
+	if !utf8.Valid(data) || !protoSourcePattern.Match(data) {
+		return false
+	}
+	// JSON & YAML docs are maps, which .proto files are not.
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err == nil {
+		if _, isMap := utils.NormalizeYAML(v).(map[string]interface{}); isMap {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProtobuf checks if data is a protobuf spec doc.
+func IsProtobuf(data []byte) bool {
+	if IsProtoSource(data) {
+		return true
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+	if _, ok := doc[bundleKey]; ok {
+		return true
+	}
+	return isDescriptorSetJSON(doc)
+}
+
+func isDescriptorSetJSON(doc map[string]json.RawMessage) bool {
+	if len(doc) != 1 {
+		return false
+	}
+	var files []map[string]json.RawMessage
+	if err := json.Unmarshal(doc["file"], &files); err != nil || len(files) == 0 {
+		return false
+	}
+	for _, f := range files {
+		if _, ok := f["name"]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProtoFilename checks if name is a .proto file.
+func IsProtoFilename(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".proto")
+}
+
+// IsDescriptorSetFilename checks if name is a compiled FileDescriptorSet file (e.g. protoc --descriptor_set_out).
+func IsDescriptorSetFilename(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".pb", ".desc", ".binpb", ".protoset":
+		return true
+	}
+	return false
+}
+
+// BundleFiles returns the spec doc of .proto files: the source of a single file, or a JSON bundle of all files.
+func BundleFiles(files map[string][]byte) ([]byte, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("protobuf: missing .proto files")
+	}
+	if len(files) == 1 {
+		for _, data := range files {
+			return data, nil
+		}
+	}
+	bundle := make(map[string]string, len(files))
+	for name, data := range files {
+		bundle[name] = string(data)
+	}
+	return json.MarshalIndent(map[string]interface{}{bundleKey: bundle}, "", "  ")
+}
+
+// Load loads a protobuf spec doc.
+func Load(data []byte) (*Schema, error) {
+	if IsProtoSource(data) {
+		return ParseFiles(map[string][]byte{"spec.proto": data})
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("protobuf: unsupported doc: %v", err)
+	}
+	if raw, ok := doc[bundleKey]; ok {
+		var bundle map[string]string
+		if err := json.Unmarshal(raw, &bundle); err != nil {
+			return nil, fmt.Errorf("protobuf: invalid %s: %v", bundleKey, err)
+		}
+		files := make(map[string][]byte, len(bundle))
+		for name, src := range bundle {
+			files[name] = []byte(src)
+		}
+		return ParseFiles(files)
+	}
+	if isDescriptorSetJSON(doc) {
+		return ParseDescriptorSetJSON(data)
+	}
+	return nil, fmt.Errorf("protobuf: unsupported doc")
+}
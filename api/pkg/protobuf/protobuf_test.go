@@ -0,0 +1,152 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuf
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestIsProtobuf(t *testing.T) {
+	source, err := os.ReadFile("testdata/billing.proto")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{name: "proto source", data: string(source), want: true},
+		{name: "bundle", data: `{"proto_files": {"a.proto": "syntax = \"proto3\";"}}`, want: true},
+		{name: "descriptor set", data: `{"file": [{"name": "a.proto"}]}`, want: true},
+		{name: "openapi json", data: `{"openapi": "3.0.3", "info": {"title": "message Foo {"}}`},
+		{name: "openapi yaml", data: "openapi: 3.0.3\ninfo:\n  title: a\n  description: |\n    message Foo {\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsProtobuf([]byte(tt.data)))
+		})
+	}
+}
+
+func TestLoad_Source(t *testing.T) {
+	data, err := os.ReadFile("testdata/billing.proto")
+	require.NoError(t, err)
+
+	s, err := Load(data)
+	require.NoError(t, err)
+	assert.True(t, s.HasSourceInfo)
+	assert.Equal(t, SyntaxProto3, s.Syntax())
+	assert.Equal(t, "v1", s.Version())
+
+	svc := s.Services["acme.billing.v1.Billing"]
+	require.NotNil(t, svc)
+	assert.Equal(t, "Billing manages invoices.", svc.Comment)
+	require.Len(t, svc.Methods, 2)
+	assert.Equal(t, "acme.billing.v1.GetInvoiceRequest", svc.Methods[0].InputType)
+	assert.Equal(t, "unary", svc.Methods[0].StreamingMode())
+	assert.Equal(t, "server-streaming", svc.Methods[1].StreamingMode())
+	assert.True(t, svc.Methods[1].Deprecated)
+	assert.Equal(t, "/acme.billing.v1.Billing/GetInvoice", svc.Methods[0].Path(svc))
+
+	invoice := s.Messages["acme.billing.v1.Invoice"]
+	require.NotNil(t, invoice)
+	assert.Equal(t, 25, invoice.Pos.Line)
+	assert.True(t, invoice.IsReserved(4, ""))
+	assert.True(t, invoice.IsReserved(100, ""))
+	assert.True(t, invoice.IsReserved(0, "legacy_total"))
+
+	fields := map[string]*Field{}
+	for _, f := range invoice.Fields {
+		fields[f.Name] = f
+	}
+	assert.Equal(t, "acme.billing.v1.Invoice.Status", fields["status"].Type)
+	assert.Equal(t, LabelRepeated, fields["lines"].Label)
+	assert.Equal(t, "acme.billing.v1.Invoice.Line", fields["lines"].Type)
+	assert.Equal(t, "map<string,acme.billing.v1.Invoice.Line>", fields["lines_by_sku"].Type)
+	assert.Equal(t, "google.protobuf.Timestamp", fields["created_at"].Type)
+	assert.Equal(t, LabelOptional, fields["note"].Label)
+	assert.True(t, fields["note"].Deprecated)
+	assert.Equal(t, "payer", fields["org_id"].OneOf)
+
+	assert.Contains(t, s.Enums, "acme.billing.v1.Invoice.Status")
+	assert.Contains(t, s.Messages, "acme.billing.v1.Invoice.Line")
+}
+
+func TestLoad_Bundle(t *testing.T) {
+	doc, err := BundleFiles(map[string][]byte{
+		"a.proto": []byte("syntax = \"proto3\";\npackage a;\nimport \"b.proto\";\nmessage A { b.B b = 1; }\n"),
+		"b.proto": []byte("syntax = \"proto3\";\npackage b;\nmessage B { string id = 1; }\n"),
+	})
+	require.NoError(t, err)
+
+	s, err := Load(doc)
+	require.NoError(t, err)
+	require.Len(t, s.Files, 2)
+	assert.Equal(t, "b.B", s.Messages["a.A"].Fields[0].Type)
+}
+
+func TestLoad_DescriptorSet(t *testing.T) {
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("a.proto"),
+		Package: proto.String("a.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("A"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("id"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("tags"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(), TypeName: proto.String(".a.v1.A.TagsEntry")},
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("TagsEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("key"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+					{Name: proto.String("value"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()},
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+			ReservedRange: []*descriptorpb.DescriptorProto_ReservedRange{{Start: proto.Int32(5), End: proto.Int32(6)}},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name:   proto.String("Svc"),
+			Method: []*descriptorpb.MethodDescriptorProto{{Name: proto.String("Get"), InputType: proto.String(".a.v1.A"), OutputType: proto.String(".a.v1.A"), ClientStreaming: proto.Bool(true)}},
+		}},
+	}}}
+	binary, err := proto.Marshal(fds)
+	require.NoError(t, err)
+
+	doc, err := DescriptorSetToJSON(binary)
+	require.NoError(t, err)
+	assert.True(t, IsProtobuf(doc))
+
+	s, err := Load(doc)
+	require.NoError(t, err)
+	assert.False(t, s.HasSourceInfo)
+	a := s.Messages["a.v1.A"]
+	require.NotNil(t, a)
+	assert.NotContains(t, s.Messages, "a.v1.A.TagsEntry")
+	assert.Equal(t, "", a.Fields[0].Label)
+	assert.Equal(t, "map<string,int64>", a.Fields[1].Type)
+	assert.True(t, a.IsReserved(5, ""))
+	assert.False(t, a.IsReserved(6, ""))
+	assert.Equal(t, "client-streaming", s.Services["a.v1.Svc"].Methods[0].StreamingMode())
+}
@@ -0,0 +1,283 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/scanner"
+
+	"github.com/emicklei/proto"
+)
+
+// maxFieldNumber is the max field number, which reserved ranges ending with max stand for.
+const maxFieldNumber = 536870911
+
+var scalarTypes = map[string]struct{}{
+	"double": {}, "float": {}, "int32": {}, "int64": {}, "uint32": {}, "uint64": {}, "sint32": {}, "sint64": {},
+	"fixed32": {}, "fixed64": {}, "sfixed32": {}, "sfixed64": {}, "bool": {}, "string": {}, "bytes": {},
+}
+
+// ParseFiles parses .proto files, by name, into a Schema, resolving the types of fields & methods to full names.
+// Types imported from files not in files (e.g. google/protobuf/timestamp.proto) are kept as referenced.
+func ParseFiles(files map[string][]byte) (*Schema, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p := &sourceParser{schema: newSchema(), scopes: map[*Field]string{}, methodScopes: map[*Method]string{}}
+	p.schema.HasSourceInfo = true
+	for _, name := range names {
+		parser := proto.NewParser(bytes.NewReader(files[name]))
+		parser.Filename(name)
+		def, err := parser.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("protobuf: %v", err)
+		}
+		p.parseFile(name, def)
+	}
+	p.resolveTypes()
+	return p.schema, nil
+}
+
+type sourceParser struct {
+	schema *Schema
+	// scopes are the full names of the messages declaring fields, for resolving their types.
+	scopes       map[*Field]string
+	methodScopes map[*Method]string
+}
+
+func (p *sourceParser) parseFile(name string, def *proto.Proto) {
+	file := &File{Name: name, Syntax: SyntaxProto2}
+	for _, e := range def.Elements {
+		switch v := e.(type) {
+		case *proto.Syntax:
+			file.Syntax = v.Value
+		case *proto.Package:
+			file.Package = v.Name
+		}
+	}
+	p.schema.Files = append(p.schema.Files, file)
+
+	for _, e := range def.Elements {
+		switch v := e.(type) {
+		case *proto.Message:
+			p.parseMessage(file, file.Package, v)
+		case *proto.Enum:
+			p.parseEnum(file, file.Package, v)
+		case *proto.Service:
+			p.parseService(file, v)
+		}
+	}
+}
+
+func (p *sourceParser) parseMessage(file *File, scope string, v *proto.Message) {
+	if v.IsExtend {
+		return
+	}
+	m := &Message{
+		FullName: qualify(scope, v.Name),
+		Name:     v.Name,
+		File:     file.Name,
+		Comment:  comment(v.Comment),
+		Pos:      position(file.Name, v.Position),
+	}
+	p.schema.Messages[m.FullName] = m
+
+	for _, e := range v.Elements {
+		switch ev := e.(type) {
+		case *proto.NormalField:
+			f := p.newField(file, ev.Field)
+			switch {
+			case ev.Repeated:
+				f.Label = LabelRepeated
+			case ev.Required:
+				f.Label = LabelRequired
+			case ev.Optional:
+				f.Label = LabelOptional
+			}
+			p.addField(m, f)
+		case *proto.MapField:
+			f := p.newField(file, ev.Field)
+			f.Type = "map<" + ev.KeyType + "," + ev.Type + ">"
+			p.addField(m, f)
+		case *proto.Oneof:
+			for _, oe := range ev.Elements {
+				if of, ok := oe.(*proto.OneOfField); ok {
+					f := p.newField(file, of.Field)
+					f.OneOf = ev.Name
+					p.addField(m, f)
+				}
+			}
+		case *proto.Reserved:
+			m.ReservedNames = append(m.ReservedNames, ev.FieldNames...)
+			for _, r := range ev.Ranges {
+				to := r.To
+				if r.Max {
+					to = maxFieldNumber
+				}
+				m.Reserved = append(m.Reserved, Range{From: r.From, To: to})
+			}
+		case *proto.Message:
+			p.parseMessage(file, m.FullName, ev)
+		case *proto.Enum:
+			p.parseEnum(file, m.FullName, ev)
+		}
+	}
+}
+
+func (p *sourceParser) newField(file *File, v *proto.Field) *Field {
+	f := &Field{
+		Name:    v.Name,
+		Number:  v.Sequence,
+		Type:    v.Type,
+		Comment: comment(v.Comment, v.InlineComment),
+		Pos:     position(file.Name, v.Position),
+	}
+	for _, o := range v.Options {
+		if o.Name == "deprecated" && o.Constant.Source == "true" {
+			f.Deprecated = true
+		}
+	}
+	return f
+}
+
+func (p *sourceParser) addField(m *Message, f *Field) {
+	m.Fields = append(m.Fields, f)
+	p.scopes[f] = m.FullName
+}
+
+func (p *sourceParser) parseEnum(file *File, scope string, v *proto.Enum) {
+	e := &Enum{
+		FullName: qualify(scope, v.Name),
+		Name:     v.Name,
+		File:     file.Name,
+		Comment:  comment(v.Comment),
+		Pos:      position(file.Name, v.Position),
+	}
+	for _, ee := range v.Elements {
+		if ev, ok := ee.(*proto.EnumField); ok {
+			e.Values = append(e.Values, &EnumValue{Name: ev.Name, Number: ev.Integer, Pos: position(file.Name, ev.Position)})
+		}
+	}
+	p.schema.Enums[e.FullName] = e
+}
+
+func (p *sourceParser) parseService(file *File, v *proto.Service) {
+	s := &Service{
+		FullName: qualify(file.Package, v.Name),
+		Name:     v.Name,
+		File:     file.Name,
+		Comment:  comment(v.Comment),
+		Pos:      position(file.Name, v.Position),
+	}
+	for _, e := range v.Elements {
+		rpc, ok := e.(*proto.RPC)
+		if !ok {
+			continue
+		}
+		m := &Method{
+			Name:            rpc.Name,
+			InputType:       rpc.RequestType,
+			OutputType:      rpc.ReturnsType,
+			ClientStreaming: rpc.StreamsRequest,
+			ServerStreaming: rpc.StreamsReturns,
+			Comment:         comment(rpc.Comment, rpc.InlineComment),
+			Pos:             position(file.Name, rpc.Position),
+		}
+		for _, re := range rpc.Elements {
+			if o, ok := re.(*proto.Option); ok && o.Name == "deprecated" && o.Constant.Source == "true" {
+				m.Deprecated = true
+			}
+		}
+		s.Methods = append(s.Methods, m)
+		p.methodScopes[m] = file.Package
+	}
+	p.schema.Services[s.FullName] = s
+}
+
+func (p *sourceParser) resolveTypes() {
+	for f, scope := range p.scopes {
+		if strings.HasPrefix(f.Type, "map<") {
+			kv := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(f.Type, "map<"), ">"), ",", 2)
+			f.Type = "map<" + kv[0] + "," + p.resolve(scope, kv[1]) + ">"
+			continue
+		}
+		f.Type = p.resolve(scope, f.Type)
+	}
+	for m, scope := range p.methodScopes {
+		m.InputType = p.resolve(scope, m.InputType)
+		m.OutputType = p.resolve(scope, m.OutputType)
+	}
+}
+
+// resolve resolves the type name referenced from scope, searching from the innermost scope outwards, like protoc.
+func (p *sourceParser) resolve(scope, name string) string {
+	if _, ok := scalarTypes[name]; ok {
+		return name
+	}
+	if strings.HasPrefix(name, ".") {
+		return name[1:]
+	}
+	for {
+		candidate := qualify(scope, name)
+		if _, ok := p.schema.Messages[candidate]; ok {
+			return candidate
+		}
+		if _, ok := p.schema.Enums[candidate]; ok {
+			return candidate
+		}
+		if scope == "" {
+			return name
+		}
+		if i := strings.LastIndex(scope, "."); i >= 0 {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
+
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func comment(comments ...*proto.Comment) string {
+	var lines []string
+	for _, c := range comments {
+		if c == nil {
+			continue
+		}
+		for _, line := range c.Lines {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func position(file string, pos scanner.Position) Position {
+	return Position{File: file, Line: pos.Line, Column: pos.Column}
+}
@@ -20,10 +20,14 @@ import (
 	"bytes"
 	"compress/gzip"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"unicode/utf8"
 )
 
 // Different returns the difference between two slices
@@ -80,6 +84,53 @@ func Hash(text string) string {
 	return encoded
 }
 
+// CanonicalHash returns the hex-encoded SHA-256 of data's canonical form (see CanonicalJSON),
+// so that formatting, key order, $ref aliasing & serialization format don't affect the hash.
+// Data that cannot be decoded as either JSON or YAML is hashed as-is.
+func CanonicalHash(data []byte) string {
+	canonical, err := CanonicalJSON(data)
+	if err != nil {
+		canonical = data
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// CanonicalJSON decodes data, by first parsing as a JSON & if that fails, as a YAML,
+// & re-encodes it in its normal form (see NormalizeDoc) as compact JSON.
+func CanonicalJSON(data []byte) ([]byte, error) {
+	d, err := parseDoc(data)
+	if err != nil {
+		return nil, err
+	}
+	d.normalize()
+	return d.encode(DocFormatJSON, "", true)
+}
+
+// NormalizeYAML converts YAML-decoded values into JSON-encodable values,
+// i.e. map[interface{}]interface{} (e.g. maps w/ integer keys like response codes) into map[string]interface{}.
+func NormalizeYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[fmt.Sprintf("%v", k)] = NormalizeYAML(v)
+		}
+		return m
+	case map[string]interface{}:
+		for k, v := range t {
+			t[k] = NormalizeYAML(v)
+		}
+		return t
+	case []interface{}:
+		for i, v := range t {
+			t[i] = NormalizeYAML(v)
+		}
+		return t
+	}
+	return v
+}
+
 // GetEnvironment return the current environment name
 func GetEnvironment() string {
 	if os.Getenv("AUTH_COOKIE_EXTENSION") == "_stage" {
@@ -88,6 +139,17 @@ func GetEnvironment() string {
 	return "production"
 }
 
+// Truncate returns s truncated to at most n bytes, on a rune boundary.
+func Truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
 // BoolPtr returns a pointer to a bool
 func BoolPtr(b bool) *bool { return &b }
 
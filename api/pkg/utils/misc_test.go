@@ -32,3 +32,27 @@ func Test_Intersect(t *testing.T) {
 	assert.Equal(t, []string{"b", "c"}, Intersect([]string{"a", "b", "c"}, []string{"b", "c"}))
 	assert.Equal(t, []string{"c"}, Intersect([]string{"a", "c"}, []string{"b", "c"}))
 }
+
+func Test_Truncate(t *testing.T) {
+	assert.Equal(t, "abc", Truncate("abc", 5))
+	assert.Equal(t, "ab", Truncate("abc", 2))
+	assert.Equal(t, "h", Truncate("hé", 2))
+	assert.Equal(t, "hé", Truncate("hé", 3))
+	assert.Equal(t, "", Truncate("日本", 2))
+	assert.Equal(t, "日", Truncate("日本", 5))
+}
+
+func Test_CanonicalHash(t *testing.T) {
+	var (
+		jsonDoc         = []byte(`{"openapi":"3.0.0","info":{"title":"a","version":"1.0.0"},"paths":{"/a":{"get":{"responses":{"200":{"description":"ok"}}}}}}`)
+		jsonDocReformat = []byte("{\n  \"info\": {\"version\": \"1.0.0\", \"title\": \"a\"},\n  \"openapi\": \"3.0.0\",\n  \"paths\": {\"/a\": {\"get\": {\"responses\": {\"200\": {\"description\": \"ok\"}}}}}\n}")
+		yamlDoc         = []byte("openapi: 3.0.0\ninfo:\n  title: a\n  version: 1.0.0\npaths:\n  /a:\n    get:\n      responses:\n        200:\n          description: ok\n")
+		otherDoc        = []byte(`{"openapi":"3.0.0","info":{"title":"b","version":"1.0.0"},"paths":{}}`)
+	)
+
+	assert.Len(t, CanonicalHash(jsonDoc), 64)
+	assert.Equal(t, CanonicalHash(jsonDoc), CanonicalHash(jsonDocReformat))
+	assert.Equal(t, CanonicalHash(jsonDoc), CanonicalHash(yamlDoc))
+	assert.NotEqual(t, CanonicalHash(jsonDoc), CanonicalHash(otherDoc))
+	assert.Equal(t, CanonicalHash([]byte("{not valid")), CanonicalHash([]byte("{not valid")))
+}
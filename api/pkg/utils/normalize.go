@@ -0,0 +1,421 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DocFormatJSON = "json"
+	DocFormatYAML = "yaml"
+)
+
+// ConvertDoc re-encodes data, a JSON or YAML document, as format (DocFormatJSON or DocFormatYAML), keeping its key order.
+// YAML anchors & aliases are expanded. Data already in format is returned as-is.
+func ConvertDoc(data []byte, format string) ([]byte, error) {
+	d, err := parseDoc(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" || format == d.format() {
+		return data, nil
+	}
+	return d.encode(format, "  ", false)
+}
+
+// NormalizeDoc re-encodes data, a JSON or YAML document, as format (DocFormatJSON or DocFormatYAML, or data's format if empty)
+// in its normal form (see normalize), indented by 2 spaces, so that documents differing only in serialization
+// (key order, indentation, aliasing $refs) are encoded identically.
+func NormalizeDoc(data []byte, format string) ([]byte, error) {
+	d, err := parseDoc(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = d.format()
+	}
+	d.normalize()
+	return d.encode(format, "  ", false)
+}
+
+// parsedDoc is a JSON or YAML document parsed as a YAML node tree, which keeps key order.
+type parsedDoc struct {
+	root   *yaml.Node
+	isJSON bool
+}
+
+// parseDoc parses data, by first parsing as a JSON & if that fails, as a YAML.
+func parseDoc(data []byte) (*parsedDoc, error) {
+	if json.Valid(data) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		root, err := decodeJSONNode(dec)
+		if err != nil {
+			return nil, err
+		}
+		return &parsedDoc{root: root, isJSON: true}, nil
+	}
+
+	var n yaml.Node
+	if err := yaml.Unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+	root := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		root = n.Content[0]
+	}
+	var count int
+	root, err := expandAliases(root, &count)
+	if err != nil {
+		return nil, err
+	}
+	return &parsedDoc{root: root}, nil
+}
+
+func (d *parsedDoc) format() string {
+	if d.isJSON {
+		return DocFormatJSON
+	}
+	return DocFormatYAML
+}
+
+// decodeJSONNode decodes the next JSON value of dec as a YAML node, keeping key order & number literals.
+func decodeJSONNode(dec *json.Decoder) (*yaml.Node, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if t == '{' {
+			n.Kind, n.Tag = yaml.MappingNode, "!!map"
+		}
+		for dec.More() {
+			if n.Kind == yaml.MappingNode {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprintf("%v", key)})
+			}
+			value, err := decodeJSONNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, value)
+		}
+		// Consume the closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprintf("%v", t)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("utils: unexpected JSON token %v", token)
+}
+
+// maxExpandedNodes caps the number of nodes of a YAML document with its aliases expanded, against recursive aliases &
+// "billion laughs" documents.
+const maxExpandedNodes = 10000000
+
+// expandAliases replaces the YAML aliases of n by the nodes they refer to & drops anchors.
+func expandAliases(n *yaml.Node, count *int) (*yaml.Node, error) {
+	if *count++; *count > maxExpandedNodes {
+		return nil, fmt.Errorf("utils: document contains excessive aliasing")
+	}
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		return expandAliases(n.Alias, count)
+	}
+	n.Anchor = ""
+	for i, c := range n.Content {
+		expanded, err := expandAliases(c, count)
+		if err != nil {
+			return nil, err
+		}
+		n.Content[i] = expanded
+	}
+	return n, nil
+}
+
+// normalize sorts object keys & resolves internal $ref aliases, i.e. $refs to objects that are only a $ref themselves,
+// to the object eventually referred to.
+func (d *parsedDoc) normalize() {
+	resolveRefAliases(d.root, d.root)
+	sortKeys(d.root, map[*yaml.Node]bool{})
+}
+
+func sortKeys(n *yaml.Node, visited map[*yaml.Node]bool) {
+	// Expanded aliases share nodes.
+	if visited[n] {
+		return
+	}
+	visited[n] = true
+	if n.Kind == yaml.MappingNode {
+		pairs := make([][2]*yaml.Node, 0, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			pairs = append(pairs, [2]*yaml.Node{n.Content[i], n.Content[i+1]})
+		}
+		sort.SliceStable(pairs, func(i, j int) bool { return pairs[i][0].Value < pairs[j][0].Value })
+		for i, p := range pairs {
+			n.Content[2*i], n.Content[2*i+1] = p[0], p[1]
+		}
+	}
+	for _, c := range n.Content {
+		sortKeys(c, visited)
+	}
+}
+
+func resolveRefAliases(root, n *yaml.Node) {
+	if ref := refValue(n); ref != nil && strings.HasPrefix(ref.Value, "#") {
+		var (
+			target = ref.Value
+			seen   = map[string]bool{target: true}
+		)
+		for {
+			next := refValue(lookupPointer(root, target))
+			if next == nil || !isAlias(lookupPointer(root, target)) || !strings.HasPrefix(next.Value, "#") || seen[next.Value] {
+				break
+			}
+			target = next.Value
+			seen[target] = true
+		}
+		ref.Value = target
+	}
+	for _, c := range n.Content {
+		resolveRefAliases(root, c)
+	}
+}
+
+// refValue returns the $ref value node of n, if n is an object with a $ref.
+func refValue(n *yaml.Node) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == "$ref" && n.Content[i+1].Kind == yaml.ScalarNode {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// isAlias checks if n is an object with only a $ref.
+func isAlias(n *yaml.Node) bool {
+	return refValue(n) != nil && len(n.Content) == 2
+}
+
+// lookupPointer returns the node of root at ref, an internal JSON pointer reference (e.g. #/components/schemas/Pet).
+func lookupPointer(root *yaml.Node, ref string) *yaml.Node {
+	ptr := strings.TrimPrefix(ref, "#")
+	if unescaped, err := url.PathUnescape(ptr); err == nil {
+		ptr = unescaped
+	}
+	if ptr == "" {
+		return root
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil
+	}
+	n := root
+	for _, token := range strings.Split(ptr[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		var next *yaml.Node
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == token {
+					next = n.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			var i int
+			if _, err := fmt.Sscanf(token, "%d", &i); err == nil && i >= 0 && i < len(n.Content) {
+				next = n.Content[i]
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
+
+// encode encodes d as format, indented by indent (JSON only, YAML is always indented by 2 spaces) or compact if empty.
+// Set escapeHTML to escape HTML characters in JSON strings.
+func (d *parsedDoc) encode(format, indent string, escapeHTML bool) ([]byte, error) {
+	switch format {
+	case DocFormatJSON:
+		w := &jsonWriter{indent: indent, escapeHTML: escapeHTML, literalNumbers: d.isJSON}
+		if err := w.write(d.root, 0); err != nil {
+			return nil, err
+		}
+		if indent != "" {
+			w.buf.WriteByte('\n')
+		}
+		return w.buf.Bytes(), nil
+	case DocFormatYAML:
+		resetStyles(d.root, map[*yaml.Node]bool{})
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{d.root}}); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("utils: unsupported doc format %q", format)
+}
+
+// resetStyles resets the styles of n, e.g. JSON's flow style, to YAML's default block style.
+func resetStyles(n *yaml.Node, visited map[*yaml.Node]bool) {
+	if visited[n] {
+		return
+	}
+	visited[n] = true
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyles(c, visited)
+	}
+}
+
+type jsonWriter struct {
+	buf            bytes.Buffer
+	indent         string
+	escapeHTML     bool
+	literalNumbers bool
+}
+
+func (w *jsonWriter) newline(depth int) {
+	if w.indent == "" {
+		return
+	}
+	w.buf.WriteByte('\n')
+	w.buf.WriteString(strings.Repeat(w.indent, depth))
+}
+
+func (w *jsonWriter) write(n *yaml.Node, depth int) error {
+	switch n.Kind {
+	case yaml.MappingNode:
+		if len(n.Content) == 0 {
+			w.buf.WriteString("{}")
+			return nil
+		}
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.newline(depth + 1)
+			if err := w.writeString(n.Content[i].Value); err != nil {
+				return err
+			}
+			w.buf.WriteByte(':')
+			if w.indent != "" {
+				w.buf.WriteByte(' ')
+			}
+			if err := w.write(n.Content[i+1], depth+1); err != nil {
+				return err
+			}
+		}
+		w.newline(depth)
+		w.buf.WriteByte('}')
+	case yaml.SequenceNode:
+		if len(n.Content) == 0 {
+			w.buf.WriteString("[]")
+			return nil
+		}
+		w.buf.WriteByte('[')
+		for i, c := range n.Content {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.newline(depth + 1)
+			if err := w.write(c, depth+1); err != nil {
+				return err
+			}
+		}
+		w.newline(depth)
+		w.buf.WriteByte(']')
+	case yaml.ScalarNode:
+		return w.writeScalar(n)
+	default:
+		return fmt.Errorf("utils: unexpected YAML node kind %v", n.Kind)
+	}
+	return nil
+}
+
+func (w *jsonWriter) writeScalar(n *yaml.Node) error {
+	switch n.ShortTag() {
+	case "!!null":
+		w.buf.WriteString("null")
+		return nil
+	case "!!bool", "!!int", "!!float":
+		if w.literalNumbers {
+			w.buf.WriteString(n.Value)
+			return nil
+		}
+		var v interface{}
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			// e.g. .inf & .nan have no JSON representation.
+			return w.writeString(n.Value)
+		}
+		w.buf.Write(data)
+		return nil
+	}
+	return w.writeString(n.Value)
+}
+
+func (w *jsonWriter) writeString(s string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(w.escapeHTML)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	_, err := io.Copy(&w.buf, bytes.NewReader(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))))
+	return err
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NormalizeDoc(t *testing.T) {
+	var (
+		yamlDoc = []byte(`paths:
+  /b:
+    get:
+      responses:
+        "200":
+          $ref: '#/components/responses/Alias'
+  /a: {}
+openapi: 3.0.0
+components:
+  responses:
+    Alias:
+      $ref: '#/components/responses/Ok'
+    Ok: &ok
+      description: ok
+    Copy: *ok
+`)
+		jsonDoc = []byte(`{"paths": {"/b": {"get": {"responses": {"200": {"$ref": "#/components/responses/Alias"}}}}, "/a": {}},
+	"openapi": "3.0.0",
+	"components": {"responses": {"Alias": {"$ref": "#/components/responses/Ok"}, "Ok": {"description": "ok"}, "Copy": {"description": "ok"}}}}`)
+		wantYAML = `components:
+  responses:
+    Alias:
+      $ref: '#/components/responses/Ok'
+    Copy:
+      description: ok
+    Ok:
+      description: ok
+openapi: 3.0.0
+paths:
+  /a: {}
+  /b:
+    get:
+      responses:
+        "200":
+          $ref: '#/components/responses/Ok'
+`
+		wantJSON = `{
+  "components": {
+    "responses": {
+      "Alias": {
+        "$ref": "#/components/responses/Ok"
+      },
+      "Copy": {
+        "description": "ok"
+      },
+      "Ok": {
+        "description": "ok"
+      }
+    }
+  },
+  "openapi": "3.0.0",
+  "paths": {
+    "/a": {},
+    "/b": {
+      "get": {
+        "responses": {
+          "200": {
+            "$ref": "#/components/responses/Ok"
+          }
+        }
+      }
+    }
+  }
+}
+`
+	)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		format string
+		want   string
+	}{
+		{name: "yaml", data: yamlDoc, want: wantYAML},
+		{name: "yaml to json", data: yamlDoc, format: DocFormatJSON, want: wantJSON},
+		{name: "json", data: jsonDoc, want: wantJSON},
+		{name: "json to yaml", data: jsonDoc, format: DocFormatYAML, want: wantYAML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeDoc(tt.data, tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	assert.Equal(t, CanonicalHash(yamlDoc), CanonicalHash(jsonDoc))
+
+	_, err := NormalizeDoc(jsonDoc, "xml")
+	assert.Error(t, err)
+}
+
+func Test_ConvertDoc(t *testing.T) {
+	var (
+		yamlDoc = []byte("openapi: 3.0.0\ninfo:\n  version: 1.0.0\n  title: a <b>\n  x-code: 0x10\n  x-id: \"200\"\npaths: {}\n")
+		jsonDoc = "{\n  \"openapi\": \"3.0.0\",\n  \"info\": {\n    \"version\": \"1.0.0\",\n    \"title\": \"a <b>\",\n    \"x-code\": 16,\n    \"x-id\": \"200\"\n  },\n  \"paths\": {}\n}\n"
+	)
+
+	got, err := ConvertDoc(yamlDoc, DocFormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, jsonDoc, string(got))
+
+	got, err = ConvertDoc([]byte(jsonDoc), DocFormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, "openapi: 3.0.0\ninfo:\n  version: 1.0.0\n  title: a <b>\n  x-code: 16\n  x-id: \"200\"\npaths: {}\n", string(got))
+
+	got, err = ConvertDoc(yamlDoc, DocFormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, yamlDoc, got)
+}
+
+func Test_parseDoc_ExcessiveAliasing(t *testing.T) {
+	_, err := NormalizeDoc([]byte("a: &a [x, x, x, x, x, x, x, x, x, x]\nb: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a, *a]\nc: &c [*b, *b, *b, *b, *b, *b, *b, *b, *b, *b]\nd: &d [*c, *c, *c, *c, *c, *c, *c, *c, *c, *c]\ne: &e [*d, *d, *d, *d, *d, *d, *d, *d, *d, *d]\nf: &f [*e, *e, *e, *e, *e, *e, *e, *e, *e, *e]\ng: &g [*f, *f, *f, *f, *f, *f, *f, *f, *f, *f]\nh: [*g, *g, *g, *g, *g, *g, *g, *g, *g, *g]\n"), "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,224 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package oas31 adapts OpenAPI 3.1 documents to the OpenAPI 3.0 model of kin-openapi's openapi3 package.
+package oas31
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/oasschema"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// IsOAS31 checks if data is an OpenAPI 3.1 document.
+func IsOAS31(data []byte) bool {
+	doc, err := unmarshal(data)
+	if err != nil {
+		return false
+	}
+	return isOAS31(doc)
+}
+
+func isOAS31(doc map[string]interface{}) bool {
+	version, _ := doc["openapi"].(string)
+	return strings.HasPrefix(version, "3.1")
+}
+
+// Downgrade rewrites the OpenAPI 3.1 (JSON Schema 2020-12) constructs of data, which openapi3 fails to unmarshal,
+// into their OpenAPI 3.0 equivalents (see DowngradeSchemas), keeping the openapi version as-is.
+// Documents of other versions are returned unchanged. Returns whether data was downgraded.
+func Downgrade(data []byte) ([]byte, bool, error) {
+	doc, err := unmarshal(data)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isOAS31(doc) {
+		return data, false, nil
+	}
+
+	// paths is optional in 3.1.
+	if _, ok := doc["paths"]; !ok {
+		doc["paths"] = map[string]interface{}{}
+	}
+	DowngradeSchemas(doc)
+
+	downgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	return downgraded, true, nil
+}
+
+// DowngradeSchemas rewrites, in place, the JSON Schema 2020-12 keywords of any schema found in v:
+//   - type arrays become a single type, or a oneOf of single-typed schemas, & nullable
+//   - numeric exclusiveMinimum/exclusiveMaximum become minimum/maximum & boolean exclusiveMinimum/exclusiveMaximum
+//   - const becomes a single-value enum
+//   - examples arrays become example
+//
+// Other 2020-12 keywords are ignored by openapi3 & are kept.
+func DowngradeSchemas(v interface{}) interface{} {
+	downgradeSchemas(v, false)
+	return v
+}
+
+// downgradeSchemas walks v, where isNameMap is set for maps keyed by user-defined names (e.g. properties).
+func downgradeSchemas(v interface{}, isNameMap bool) {
+	switch n := v.(type) {
+	case map[string]interface{}:
+		if isNameMap {
+			for _, child := range n {
+				downgradeSchemas(child, false)
+			}
+			return
+		}
+		downgradeSchema(n)
+		for k, child := range n {
+			if isValueKeyword(k) {
+				continue
+			}
+			downgradeSchemas(child, isNameMapKeyword(k))
+		}
+	case []interface{}:
+		for _, child := range n {
+			downgradeSchemas(child, false)
+		}
+	}
+}
+
+// isNameMapKeyword checks if the value of keyword k is a map keyed by user-defined names.
+func isNameMapKeyword(k string) bool {
+	switch k {
+	case "properties", "patternProperties", "$defs", "dependentSchemas", "schemas", "definitions",
+		// openapi-diff results.
+		"changedProperties", "increasedProperties", "missingProperties":
+		return true
+	}
+	return false
+}
+
+// isValueKeyword checks if the value of keyword k is user data, rather than part of the document model.
+func isValueKeyword(k string) bool {
+	switch k {
+	case "example", "default", "enum", "const":
+		return true
+	}
+	return strings.HasPrefix(k, "x-")
+}
+
+func downgradeSchema(m map[string]interface{}) {
+	if types, ok := m["type"].([]interface{}); ok {
+		downgradeTypes(m, types)
+	}
+
+	for _, bound := range []string{"Minimum", "Maximum"} {
+		exclusive := "exclusive" + bound
+		if limit, ok := m[exclusive]; ok && isNumber(limit) {
+			m[strings.ToLower(bound)] = limit
+			m[exclusive] = true
+		}
+	}
+
+	if c, ok := m["const"]; ok {
+		if _, hasEnum := m["enum"]; !hasEnum {
+			m["enum"] = []interface{}{c}
+		}
+	}
+
+	// Only schema examples are arrays, parameter & media type examples are maps.
+	if examples, ok := m["examples"].([]interface{}); ok {
+		if _, hasExample := m["example"]; !hasExample && len(examples) > 0 {
+			m["example"] = examples[0]
+		}
+		delete(m, "examples")
+	}
+}
+
+// downgradeTypes rewrites m's type array, types, into a single type, or a oneOf of single-typed schemas, "null"
+// becoming nullable.
+func downgradeTypes(m map[string]interface{}, types []interface{}) {
+	var nonNull []interface{}
+	for _, t := range types {
+		if t == "null" {
+			m["nullable"] = true
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+	switch len(nonNull) {
+	case 0:
+		delete(m, "type")
+	case 1:
+		m["type"] = nonNull[0]
+	default:
+		delete(m, "type")
+		alternatives := make([]interface{}, 0, len(nonNull))
+		for _, t := range nonNull {
+			alternatives = append(alternatives, map[string]interface{}{"type": t})
+		}
+		if _, hasOneOf := m["oneOf"]; !hasOneOf {
+			m["oneOf"] = alternatives
+			break
+		}
+		// Keep the existing oneOf, both must hold.
+		allOf, _ := m["allOf"].([]interface{})
+		m["allOf"] = append(allOf, map[string]interface{}{"oneOf": alternatives})
+	}
+}
+
+// Validate validates data, an OpenAPI 3.1 document, against the OpenAPI 3.1 meta-schema, which openapi3 does not
+// model. Documents of other versions are not validated.
+func Validate(data []byte) error {
+	doc, err := unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if !isOAS31(doc) {
+		return nil
+	}
+	if err := oasschema.Validate(oasschema.V31, doc); err != nil {
+		if ve, ok := err.(*jsonschema.ValidationError); ok {
+			// The detailed form lists the leaf errors, rather than only the failed root schema.
+			return fmt.Errorf("oas31: %#v", ve)
+		}
+		return err
+	}
+	return nil
+}
+
+func unmarshal(data []byte) (map[string]interface{}, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	doc, ok := utils.NormalizeYAML(v).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("oas31: document is not an object")
+	}
+	return doc, nil
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int64, uint64, float64, json.Number:
+		return true
+	}
+	return false
+}
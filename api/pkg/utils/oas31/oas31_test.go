@@ -0,0 +1,186 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package oas31
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDoc = `openapi: 3.1.0
+info:
+  title: pets
+  version: 1.0.0
+  license:
+    name: MIT
+    identifier: MIT
+jsonSchemaDialect: https://spec.openapis.org/oas/3.1/dialect/base
+webhooks:
+  newPet:
+    post:
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: [string, "null"]
+          examples: [rex]
+        age:
+          type: integer
+          exclusiveMinimum: 0
+        kind:
+          const: dog
+        default:
+          type: [integer, "null"]
+          default: [1]
+        id:
+          type: [string, integer, "null"]
+          minLength: 1
+`
+
+func TestDowngrade(t *testing.T) {
+	assert.True(t, IsOAS31([]byte(testDoc)))
+
+	data, downgraded, err := Downgrade([]byte(testDoc))
+	require.NoError(t, err)
+	assert.True(t, downgraded)
+
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	require.NoError(t, err)
+	assert.NoError(t, doc.Validate(context.Background()))
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+
+	pet := doc.Components.Schemas["Pet"].Value
+	assert.Equal(t, "string", pet.Properties["name"].Value.Type)
+	assert.True(t, pet.Properties["name"].Value.Nullable)
+	assert.Equal(t, "rex", pet.Properties["name"].Value.Example)
+	assert.True(t, pet.Properties["age"].Value.ExclusiveMin)
+	assert.Equal(t, float64(0), *pet.Properties["age"].Value.Min)
+	assert.Equal(t, []interface{}{"dog"}, pet.Properties["kind"].Value.Enum)
+	assert.Equal(t, "integer", pet.Properties["default"].Value.Type)
+	assert.Equal(t, []interface{}{float64(1)}, pet.Properties["default"].Value.Default)
+
+	id := pet.Properties["id"].Value
+	assert.Empty(t, id.Type)
+	assert.True(t, id.Nullable)
+	require.Len(t, id.OneOf, 2)
+	assert.Equal(t, "string", id.OneOf[0].Value.Type)
+	assert.Equal(t, "integer", id.OneOf[1].Value.Type)
+	for _, value := range []interface{}{"a", float64(1), nil} {
+		assert.NoError(t, id.VisitJSON(value), value)
+	}
+	assert.Error(t, id.VisitJSON(""))
+	assert.Error(t, id.VisitJSON(true))
+}
+
+func TestDowngradeSchemas_OneOf(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":  []interface{}{"string", "integer"},
+		"oneOf": []interface{}{map[string]interface{}{"minLength": 1}, map[string]interface{}{"maxLength": 0}},
+	}
+	DowngradeSchemas(schema)
+
+	assert.NotContains(t, schema, "type")
+	assert.NotContains(t, schema, "nullable")
+	assert.Len(t, schema["oneOf"], 2)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"oneOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "integer"},
+		}},
+	}, schema["allOf"])
+}
+
+func TestDowngrade_OAS30(t *testing.T) {
+	doc := []byte("openapi: 3.0.3\ninfo:\n  title: a\n  version: 1.0.0\npaths: {}\n")
+	data, downgraded, err := Downgrade(doc)
+	require.NoError(t, err)
+	assert.False(t, downgraded)
+	assert.Equal(t, doc, data)
+}
+
+func TestValidate(t *testing.T) {
+	const info = "info:\n  title: a\n  version: 1.0.0\n"
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			doc:  testDoc,
+		},
+		{
+			name: "not 3.1",
+			doc:  "openapi: 3.0.3\n",
+		},
+		{
+			name:    "missing info",
+			doc:     "openapi: 3.1.0\npaths: {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing paths, components & webhooks",
+			doc:     "openapi: 3.1.0\n" + info,
+			wantErr: true,
+		},
+		{
+			name:    "relative jsonSchemaDialect",
+			doc:     "openapi: 3.1.0\n" + info + "jsonSchemaDialect: dialect\npaths: {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "license identifier & url",
+			doc:     "openapi: 3.1.0\ninfo:\n  title: a\n  version: 1.0.0\n  license:\n    name: MIT\n    identifier: MIT\n    url: https://mit.edu\npaths: {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid webhook",
+			doc:     "openapi: 3.1.0\n" + info + "webhooks:\n  a: []\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid parameter",
+			doc:     "openapi: 3.1.0\n" + info + "paths:\n  /a:\n    get:\n      parameters:\n        - name: a\n          in: body\n          schema: {}\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown property",
+			doc:     "openapi: 3.1.0\n" + info + "paths: {}\nextra: true\n",
+			wantErr: true,
+		},
+		{
+			name: "extension",
+			doc:  "openapi: 3.1.0\n" + info + "paths: {}\nx-extra: true\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate([]byte(tt.doc)); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package oasschema embeds the official OpenAPI 3.0 & 3.1 meta-schemas, vendored from
+// https://github.com/OAI/OpenAPI-Specification (see NOTICE).
+package oasschema
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const (
+	V30URL = "https://spec.openapis.org/oas/3.0/schema/2021-09-28"
+	V31URL = "https://spec.openapis.org/oas/3.1/schema/2022-10-07"
+)
+
+var (
+	//go:embed schema-v3.0.json
+	schemaV30 []byte
+	//go:embed schema-v3.1.json
+	schemaV31 []byte
+)
+
+var (
+	// V30 is the OpenAPI 3.0 meta-schema.
+	V30 = mustCompile(V30URL, schemaV30)
+	// V31 is the OpenAPI 3.1 meta-schema, which does not validate Schema Objects.
+	V31 = mustCompile(V31URL, schemaV31)
+)
+
+// Validate validates doc, a document unmarshalled from JSON or YAML, against schema.
+// Returns a *jsonschema.ValidationError if doc is invalid.
+func Validate(schema *jsonschema.Schema, doc interface{}) error {
+	// Round-trip through JSON so that doc only holds the types jsonschema supports.
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return err
+	}
+	return schema.Validate(v)
+}
+
+// mustCompile compiles schema, using the JSON schema draft declared by its $schema.
+func mustCompile(url string, schema []byte) *jsonschema.Schema {
+	compiler := jsonschema.NewCompiler()
+	// Formats are only asserted by default up to draft 7, i.e. for 3.0.
+	compiler.AssertFormat = true
+	if err := compiler.AddResource(url, bytes.NewReader(schema)); err != nil {
+		panic(err)
+	}
+	return compiler.MustCompile(url)
+}
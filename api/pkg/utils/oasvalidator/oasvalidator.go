@@ -0,0 +1,317 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package oasvalidator validates OpenAPI documents against the OpenAPI meta-schema & kin-openapi's semantic checks,
+// locating each error in the document.
+package oasvalidator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/oasschema"
+	"github.com/cisco-developer/api-insights/api/pkg/utils/speciterator"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// MaxErrors caps the number of errors reported for a single document.
+const MaxErrors = 100
+
+// Error is a validation error of an OpenAPI document.
+type Error struct {
+	Message string `json:"message"`
+	// Path is the JSON path of the invalid value, e.g. $.paths['/pets'].get, empty when unknown.
+	Path   string `json:"path,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate validates data, an OpenAPI document, against the OpenAPI 3.0 or 3.1 meta-schema matching its version,
+// then, if structurally valid, doc, the openapi3 model loaded from data, against kin-openapi's semantic checks.
+//
+// Returns the validation errors found, none if data is valid, or an error if data fails to parse.
+func Validate(ctx context.Context, data []byte, doc *openapi3.T) ([]*Error, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	root, ok := utils.NormalizeYAML(v).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("oasvalidator: document is not an object")
+	}
+
+	var errs []*Error
+	if metaSchema := metaSchemaOf(root); metaSchema != nil {
+		schemaErrs, err := validateSchema(metaSchema, root)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, schemaErrs...)
+	}
+
+	// kin-openapi stops at the first error, which mostly duplicates a structural one: only check the semantics of
+	// structurally valid docs.
+	if doc != nil && len(errs) == 0 {
+		if err := doc.Validate(ctx); err != nil {
+			errs = appendSemanticError(errs, err)
+		}
+	}
+
+	locate(data, errs)
+	if len(errs) > MaxErrors {
+		errs = errs[:MaxErrors]
+	}
+	return errs, nil
+}
+
+// metaSchemaOf returns the meta-schema of doc's OpenAPI version, nil if unsupported.
+func metaSchemaOf(doc map[string]interface{}) *jsonschema.Schema {
+	version, _ := doc["openapi"].(string)
+	switch {
+	case strings.HasPrefix(version, "3.0"):
+		return oasschema.V30
+	case strings.HasPrefix(version, "3.1"):
+		return oasschema.V31
+	}
+	return nil
+}
+
+// validateSchema validates doc against schema, returning the leaf errors.
+func validateSchema(schema *jsonschema.Schema, doc map[string]interface{}) ([]*Error, error) {
+	err := oasschema.Validate(schema, doc)
+	if err == nil {
+		return nil, nil
+	}
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, err
+	}
+
+	var (
+		errs []*Error
+		seen = map[string]bool{}
+	)
+	all := leaves(ve)
+	invalid := map[string]bool{}
+	for _, leaf := range all {
+		if !isUnevaluated(leaf) {
+			invalid[leaf.InstanceLocation] = true
+		}
+	}
+	for _, leaf := range all {
+		if isUnevaluated(leaf) && invalid[leaf.InstanceLocation] {
+			// An invalid property is left unevaluated too (3.1): only report why it is invalid.
+			continue
+		}
+		e := &Error{Message: leaf.Message, Path: jsonPath(doc, leaf.InstanceLocation)}
+		key := e.Error()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		errs = append(errs, e)
+	}
+	return errs, nil
+}
+
+// leaves returns the leaf causes of ve, skipping the $ref alternative of oneOf [Reference, X] keywords (3.0):
+// an invalid X is more useful to report than the missing $ref.
+func leaves(ve *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(ve.Causes) == 0 {
+		return []*jsonschema.ValidationError{ve}
+	}
+	var (
+		causes = ve.Causes
+		res    []*jsonschema.ValidationError
+	)
+	if strings.HasSuffix(ve.KeywordLocation, "/oneOf") && len(causes) > 1 {
+		var nonRef []*jsonschema.ValidationError
+		for _, c := range causes {
+			if !isReferenceAlternative(c) {
+				nonRef = append(nonRef, c)
+			}
+		}
+		if len(nonRef) > 0 {
+			causes = nonRef
+		}
+	}
+	for _, c := range causes {
+		res = append(res, leaves(c)...)
+	}
+	return res
+}
+
+// isUnevaluated checks if ve is the failure of an unevaluatedProperties keyword.
+func isUnevaluated(ve *jsonschema.ValidationError) bool {
+	// jsonschema reports the keyword capitalized.
+	return strings.HasSuffix(strings.ToLower(ve.KeywordLocation), "/unevaluatedproperties")
+}
+
+// isReferenceAlternative checks if ve is the failure of an alternative referring to the Reference definition.
+func isReferenceAlternative(ve *jsonschema.ValidationError) bool {
+	if strings.HasSuffix(ve.AbsoluteKeywordLocation, referenceDefinition) {
+		return true
+	}
+	for _, c := range ve.Causes {
+		if !strings.Contains(c.AbsoluteKeywordLocation, referenceDefinition+"/") {
+			return false
+		}
+	}
+	return len(ve.Causes) > 0
+}
+
+// referenceDefinition is the location of the Reference definition in the 3.0 meta-schema. The 3.1 meta-schema
+// picks the reference alternative with if/then/else instead of oneOf, so needs no special casing.
+const referenceDefinition = "#/definitions/Reference"
+
+func appendSemanticError(errs []*Error, err error) []*Error {
+	if me, ok := err.(openapi3.MultiError); ok {
+		for _, e := range me {
+			errs = appendSemanticError(errs, e)
+		}
+		return errs
+	}
+	return append(errs, &Error{Message: err.Error()})
+}
+
+// jsonPath converts ptr, a JSON pointer into doc (e.g. /paths/~1pets/get/parameters/0), to a JSON path
+// (e.g. $.paths['/pets'].get.parameters[0]).
+func jsonPath(doc interface{}, ptr string) string {
+	path := "$"
+	for _, token := range pointerTokens(ptr) {
+		switch t := doc.(type) {
+		case []interface{}:
+			path += "[" + token + "]"
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(t) {
+				doc = t[i]
+			} else {
+				doc = nil
+			}
+			continue
+		case map[string]interface{}:
+			doc = t[token]
+		default:
+			doc = nil
+		}
+		if isIdentifier(token) {
+			path += "." + token
+		} else {
+			path += "['" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(token) + "']"
+		}
+	}
+	return path
+}
+
+func pointerTokens(ptr string) []string {
+	ptr = strings.TrimPrefix(ptr, "#")
+	if ptr == "" || ptr == "/" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// locate sets the line & column of errs, falling back to the closest located ancestor of their path,
+// & sorts them by position.
+func locate(data []byte, errs []*Error) {
+	if len(errs) == 0 {
+		return
+	}
+	positions := speciterator.NewPositions(data)
+	for _, e := range errs {
+		if e.Path == "" {
+			continue
+		}
+		pos, _ := positions.Position(jsonPathTokens(e.Path))
+		e.Line, e.Column = pos.Line, pos.Column
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			// Unlocated (semantic) errors go last.
+			return errs[j].Line == 0 || errs[i].Line != 0 && errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+}
+
+// jsonPathTokens splits path, a JSON path built by jsonPath, into its tokens.
+func jsonPathTokens(path string) []string {
+	var tokens []string
+	path = strings.TrimPrefix(path, "$")
+	for path != "" {
+		switch {
+		case strings.HasPrefix(path, "['"):
+			var token strings.Builder
+			i := 2
+			for ; i < len(path); i++ {
+				if path[i] == '\\' && i+1 < len(path) {
+					i++
+				} else if path[i] == '\'' {
+					break
+				}
+				token.WriteByte(path[i])
+			}
+			tokens = append(tokens, token.String())
+			path = strings.TrimPrefix(path[i:], "']")
+		case strings.HasPrefix(path, "["):
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, path[1:end])
+			path = path[end+1:]
+		case strings.HasPrefix(path, "."):
+			end := strings.IndexAny(path[1:], ".[")
+			if end < 0 {
+				end = len(path) - 1
+			}
+			tokens = append(tokens, path[1:end+1])
+			path = path[end+1:]
+		default:
+			return tokens
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package oasvalidator
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func load(t *testing.T, data []byte) *openapi3.T {
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestValidate(t *testing.T) {
+	data, err := os.ReadFile("testdata/invalid.yaml")
+	require.NoError(t, err)
+
+	errs, err := Validate(context.Background(), data, load(t, data))
+	require.NoError(t, err)
+
+	type located struct {
+		Path   string
+		Line   int
+		Column int
+	}
+	var got []located
+	for _, e := range errs {
+		assert.NotEmpty(t, e.Message)
+		// The Reference alternative of oneOf [Reference, Response] is not reported.
+		assert.NotContains(t, e.Message, "$ref")
+		got = append(got, located{Path: e.Path, Line: e.Line, Column: e.Column})
+	}
+	for _, want := range []located{
+		{Path: "$.info", Line: 2, Column: 1},
+		{Path: "$.paths['/pets'].get.parameters[0].in", Line: 9, Column: 11},
+		{Path: "$.paths['/pets'].get.responses['200']", Line: 11, Column: 9},
+		{Path: "$.paths['/pets'].get.responses['200'].content['application/json'].schema.type", Line: 15, Column: 17},
+	} {
+		assert.Contains(t, got, want)
+	}
+
+}
+
+func TestValidate_Semantic(t *testing.T) {
+	data := []byte("openapi: 3.0.3\ninfo:\n  title: Pets\n  version: 1.0.0\npaths:\n  /pets/{petId}:\n    get:\n      responses:\n        '200':\n          description: A pet\n")
+
+	errs, err := Validate(context.Background(), data, load(t, data))
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	// kin-openapi's semantic errors are not located.
+	assert.Empty(t, errs[0].Path)
+	assert.Zero(t, errs[0].Line)
+	assert.Contains(t, errs[0].Message, "petId")
+}
+
+func TestValidate_Valid(t *testing.T) {
+	data, err := os.ReadFile("../../../internal/models/testdata/sample-api.yaml")
+	require.NoError(t, err)
+
+	errs, err := Validate(context.Background(), data, load(t, data))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestJSONPath(t *testing.T) {
+	doc := map[string]interface{}{
+		"paths": map[string]interface{}{
+			"/pets/{id}": map[string]interface{}{
+				"get": map[string]interface{}{
+					"parameters": []interface{}{map[string]interface{}{}},
+					"responses":  map[string]interface{}{"200": map[string]interface{}{}},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		ptr  string
+		want string
+	}{
+		{ptr: "", want: "$"},
+		{ptr: "/info/title", want: "$.info.title"},
+		{ptr: "/paths/~1pets~1{id}/get/parameters/0", want: "$.paths['/pets/{id}'].get.parameters[0]"},
+		{ptr: "/paths/~1pets~1{id}/get/responses/200", want: "$.paths['/pets/{id}'].get.responses['200']"},
+		{ptr: "/components/schemas/a~0b", want: "$.components.schemas['a~b']"},
+		{ptr: "/x/it's", want: `$.x['it\'s']`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ptr, func(t *testing.T) {
+			path := jsonPath(doc, tt.ptr)
+			assert.Equal(t, tt.want, path)
+			assert.Equal(t, pointerTokens(tt.ptr), jsonPathTokens(path))
+		})
+	}
+}
+
+func TestValidate_V31(t *testing.T) {
+	data, err := os.ReadFile("testdata/invalid-v3.1.yaml")
+	require.NoError(t, err)
+
+	errs, err := Validate(context.Background(), data, nil)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, e := range errs {
+		assert.NotEmpty(t, e.Message)
+		paths = append(paths, e.Path)
+	}
+	assert.Contains(t, paths, "$.info")
+	assert.Contains(t, paths, "$.components.securitySchemes.token.in")
+	for _, e := range errs {
+		// The invalid in is not also reported as an unevaluated property.
+		assert.NotEqual(t, "not allowed", e.Message)
+	}
+
+	data, err = os.ReadFile("testdata/valid-v3.1.yaml")
+	require.NoError(t, err)
+
+	errs, err = Validate(context.Background(), data, nil)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
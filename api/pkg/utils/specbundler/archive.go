@@ -0,0 +1,237 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package specbundler
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+var (
+	// MaxArchiveFileSize is the max uncompressed size of a spec file in an archive.
+	MaxArchiveFileSize int64 = 16 << 20
+	// MaxArchiveSize is the max total uncompressed size of the spec files in an archive.
+	MaxArchiveSize int64 = 64 << 20
+)
+
+// ErrArchiveTooLarge is returned when the uncompressed spec files of an archive exceed MaxArchiveFileSize or MaxArchiveSize.
+var ErrArchiveTooLarge = errors.New("specbundler: archive too large")
+
+// Files maps slash-separated, archive-relative file paths to their contents.
+type Files map[string][]byte
+
+// Paths returns the sorted file paths.
+func (f Files) Paths() []string {
+	paths := make([]string, 0, len(f))
+	for p := range f {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Add adds a file, cleaning its path.
+func (f Files) Add(name string, data []byte) error {
+	p, err := cleanPath(name)
+	if err != nil {
+		return err
+	}
+	f[p] = data
+	return nil
+}
+
+// GuessRoot returns the root document of the files, i.e. the only spec file with a top-level openapi/swagger field,
+// preferring the shallowest one when there are several.
+func (f Files) GuessRoot() (string, error) {
+	var candidates []string
+	for _, p := range f.Paths() {
+		if !isSpecFile(p) {
+			continue
+		}
+		doc, err := unmarshal(f[p])
+		if err != nil {
+			continue
+		}
+		if m, ok := doc.(map[string]interface{}); ok {
+			if _, ok := m["openapi"]; ok {
+				candidates = append(candidates, p)
+			} else if _, ok := m["swagger"]; ok {
+				candidates = append(candidates, p)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("specbundler: no root document found")
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return strings.Count(candidates[i], "/") < strings.Count(candidates[j], "/")
+	})
+	if len(candidates) > 1 && strings.Count(candidates[0], "/") == strings.Count(candidates[1], "/") {
+		return "", fmt.Errorf("specbundler: ambiguous root document (%s, %s), please specify one", candidates[0], candidates[1])
+	}
+	return candidates[0], nil
+}
+
+// ReadArchive reads the spec files from a zip, tar or gzipped tar archive.
+func ReadArchive(data []byte) (Files, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return readZip(data)
+	case bytes.HasPrefix(data, []byte("\x1f\x8b")):
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		return readTar(gr)
+	case len(data) > 262 && string(data[257:262]) == "ustar":
+		return readTar(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("specbundler: unsupported archive format")
+	}
+}
+
+// IsArchive checks if data is a supported archive.
+func IsArchive(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04")) ||
+		bytes.HasPrefix(data, []byte("\x1f\x8b")) ||
+		(len(data) > 262 && string(data[257:262]) == "ustar")
+}
+
+func readZip(data []byte) (Files, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := Files{}
+	remaining := MaxArchiveSize
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || !isSpecFile(zf.Name) {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		b, err := readLimited(rc, &remaining)
+		_ = rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := files.Add(zf.Name, b); err != nil {
+			return nil, err
+		}
+	}
+	return files.trimCommonDir(), nil
+}
+
+func readTar(r io.Reader) (Files, error) {
+	tr := tar.NewReader(r)
+
+	files := Files{}
+	remaining := MaxArchiveSize
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg || !isSpecFile(hdr.Name) {
+			continue
+		}
+		b, err := readLimited(tr, &remaining)
+		if err != nil {
+			return nil, err
+		}
+		if err := files.Add(hdr.Name, b); err != nil {
+			return nil, err
+		}
+	}
+	return files.trimCommonDir(), nil
+}
+
+// readLimited reads an archived file from r, up to MaxArchiveFileSize & the remaining total size of the archive,
+// which it deducts the size of the file from. It doesn't trust the sizes declared by archive headers, which may be forged.
+func readLimited(r io.Reader, remaining *int64) ([]byte, error) {
+	limit := MaxArchiveFileSize
+	if *remaining < limit {
+		limit = *remaining
+	}
+	b, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return nil, ErrArchiveTooLarge
+	}
+	*remaining -= int64(len(b))
+	return b, nil
+}
+
+// trimCommonDir strips the single top-level directory archives commonly wrap their contents in.
+func (f Files) trimCommonDir() Files {
+	var dir string
+	for p := range f {
+		i := strings.Index(p, "/")
+		if i < 0 {
+			return f
+		}
+		if dir == "" {
+			dir = p[:i+1]
+		} else if !strings.HasPrefix(p, dir) {
+			return f
+		}
+	}
+	if dir == "" {
+		return f
+	}
+	trimmed := make(Files, len(f))
+	for p, b := range f {
+		trimmed[strings.TrimPrefix(p, dir)] = b
+	}
+	return trimmed
+}
+
+func cleanPath(name string) (string, error) {
+	p := path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))[1:]
+	if p == "" || strings.HasPrefix(p, "__MACOSX/") {
+		return "", fmt.Errorf("specbundler: invalid file path %q", name)
+	}
+	return p, nil
+}
+
+// isSpecFile checks if name is an OpenAPI, AsyncAPI or protobuf spec file.
+func isSpecFile(name string) bool {
+	if strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+		return false
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json", ".yaml", ".yml", ".proto":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,353 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package specbundler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cisco-developer/api-insights/api/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// hoistableRef matches refs to reusable definitions, which are bundled into the same location of the root document.
+var hoistableRef = regexp.MustCompile(`^/((?:components/[^/]+)|definitions|parameters|responses)/([^/]+)$`)
+
+// Source is the location of a bundled document node in the original files.
+type Source struct {
+	File    string `json:"file"`
+	Pointer string `json:"pointer"`
+}
+
+// Bundle is a single document bundled from a multi-file spec.
+type Bundle struct {
+	Root string
+	Doc  []byte
+	// SourceMap maps JSON pointers of the bundled document to their Source, for every node resolved from an external $ref.
+	// Nodes without an entry belong to the closest mapped ancestor (see Bundle.Locate).
+	SourceMap map[string]Source
+}
+
+// Locate returns the Source of a JSON pointer of the bundled document.
+func (b *Bundle) Locate(pointer string) Source {
+	return Locate(b.SourceMap, pointer)
+}
+
+// Locate returns the Source of a JSON pointer of a bundled document with the given source map.
+func Locate(sourceMap map[string]Source, pointer string) Source {
+	for p := pointer; ; {
+		if src, ok := sourceMap[p]; ok {
+			return Source{File: src.File, Pointer: src.Pointer + strings.TrimPrefix(pointer, p)}
+		}
+		if p == "" {
+			return Source{Pointer: pointer}
+		}
+		p = p[:strings.LastIndex(p, "/")]
+	}
+}
+
+type bundler struct {
+	files     Files
+	root      string
+	docs      map[string]interface{}
+	hoisted   map[string]string
+	hoists    map[string]map[string]interface{}
+	sourceMap map[string]Source
+}
+
+// BundleFiles resolves the external $refs of the root document within files & bundles them into one document.
+// Refs to reusable definitions (e.g. other.yaml#/components/schemas/Pet) are hoisted into the root document,
+// while any other ref is inlined. The bundled document keeps the format (JSON or YAML) of the root document.
+func BundleFiles(files Files, root string) (*Bundle, error) {
+	root, err := cleanPath(root)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := files[root]; !ok {
+		return nil, fmt.Errorf("specbundler: root document %s not found", root)
+	}
+
+	b := &bundler{
+		files:     files,
+		root:      root,
+		docs:      map[string]interface{}{},
+		hoisted:   map[string]string{},
+		hoists:    map[string]map[string]interface{}{},
+		sourceMap: map[string]Source{"": {File: root}},
+	}
+
+	rootDoc, err := b.load(root)
+	if err != nil {
+		return nil, err
+	}
+	bundled, err := b.resolve(rootDoc, root, "", "", map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	if err := b.mergeHoists(bundled); err != nil {
+		return nil, err
+	}
+
+	var doc []byte
+	if isJSON(files[root]) {
+		doc, err = json.MarshalIndent(bundled, "", "  ")
+	} else {
+		doc, err = yaml.Marshal(bundled)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bundle{Root: root, Doc: doc, SourceMap: b.sourceMap}, nil
+}
+
+func (b *bundler) load(file string) (interface{}, error) {
+	if doc, ok := b.docs[file]; ok {
+		return doc, nil
+	}
+	data, ok := b.files[file]
+	if !ok {
+		return nil, fmt.Errorf("specbundler: referenced file %s not found", file)
+	}
+	doc, err := unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("specbundler: failed to parse %s: %v", file, err)
+	}
+	b.docs[file] = doc
+	return doc, nil
+}
+
+// resolve returns a copy of node (found at srcPtr of file) with its external $refs resolved, to be placed at ptr of the bundled document.
+func (b *bundler) resolve(node interface{}, file, ptr, srcPtr string, inlining map[string]bool) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok {
+			return b.resolveRef(ref, file, ptr, inlining)
+		}
+		out := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			child, err := b.resolve(v, file, ptr+"/"+escape(k), srcPtr+"/"+escape(k), inlining)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = child
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(n))
+		for i, v := range n {
+			child, err := b.resolve(v, file, ptr+"/"+strconv.Itoa(i), srcPtr+"/"+strconv.Itoa(i), inlining)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = child
+		}
+		return out, nil
+	default:
+		return node, nil
+	}
+}
+
+func (b *bundler) resolveRef(ref, file, ptr string, inlining map[string]bool) (interface{}, error) {
+	if strings.Contains(ref, "://") {
+		// Remote refs are left as-is.
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+
+	refFile, refPtr := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		refFile, refPtr = ref[:i], ref[i+1:]
+	}
+	if refFile == "" {
+		refFile = file
+	} else {
+		refFile = path.Join(path.Dir(file), refFile)
+	}
+
+	if refFile == b.root {
+		return map[string]interface{}{"$ref": "#" + refPtr}, nil
+	}
+
+	key := refFile + "#" + refPtr
+
+	if m := hoistableRef.FindStringSubmatch(refPtr); m != nil {
+		if local, ok := b.hoisted[key]; ok {
+			return map[string]interface{}{"$ref": local}, nil
+		}
+		container, name := m[1], unescape(m[2])
+		name = b.hoistName(container, name)
+		local := "#/" + container + "/" + escape(name)
+		// Register before resolving, so recursive definitions refer back to the hoisted one.
+		b.hoisted[key] = local
+
+		value, err := b.lookup(refFile, refPtr)
+		if err != nil {
+			return nil, err
+		}
+		hoistPtr := local[1:]
+		b.sourceMap[hoistPtr] = Source{File: refFile, Pointer: refPtr}
+		resolved, err := b.resolve(value, refFile, hoistPtr, refPtr, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		b.hoists[container][name] = resolved
+		return map[string]interface{}{"$ref": local}, nil
+	}
+
+	if inlining[key] {
+		return nil, fmt.Errorf("specbundler: circular reference %s", key)
+	}
+	value, err := b.lookup(refFile, refPtr)
+	if err != nil {
+		return nil, err
+	}
+	b.sourceMap[ptr] = Source{File: refFile, Pointer: refPtr}
+
+	inlining[key] = true
+	defer delete(inlining, key)
+	return b.resolve(value, refFile, ptr, refPtr, inlining)
+}
+
+// hoistName returns a name for a hoisted definition that does not collide with the root document or earlier hoists.
+func (b *bundler) hoistName(container, name string) string {
+	if b.hoists[container] == nil {
+		b.hoists[container] = map[string]interface{}{}
+	}
+	existing, _ := b.lookup(b.root, "/"+container)
+	existingMap, _ := existing.(map[string]interface{})
+
+	candidate := name
+	for i := 2; ; i++ {
+		_, inRoot := existingMap[candidate]
+		_, inHoists := b.hoists[container][candidate]
+		if !inRoot && !inHoists {
+			// Reserve the name.
+			b.hoists[container][candidate] = nil
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// mergeHoists adds the hoisted definitions into the bundled root document.
+func (b *bundler) mergeHoists(bundled interface{}) error {
+	doc, ok := bundled.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("specbundler: root document %s is not an object", b.root)
+	}
+
+	containers := make([]string, 0, len(b.hoists))
+	for container := range b.hoists {
+		containers = append(containers, container)
+	}
+	sort.Strings(containers)
+
+	for _, container := range containers {
+		parent := doc
+		for _, key := range strings.Split(container, "/") {
+			child, ok := parent[key].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[key] = child
+			}
+			parent = child
+		}
+		for name, value := range b.hoists[container] {
+			parent[name] = value
+		}
+	}
+	return nil
+}
+
+func (b *bundler) lookup(file, pointer string) (interface{}, error) {
+	node, err := b.load(file)
+	if err != nil {
+		return nil, err
+	}
+	if pointer == "" || pointer == "/" {
+		return node, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = unescape(token)
+		switch n := node.(type) {
+		case map[string]interface{}:
+			v, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("specbundler: %s#%s not found", file, pointer)
+			}
+			node = v
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("specbundler: %s#%s not found", file, pointer)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("specbundler: %s#%s not found", file, pointer)
+		}
+	}
+	return node, nil
+}
+
+func unmarshal(data []byte) (interface{}, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return utils.NormalizeYAML(doc), nil
+}
+
+func isJSON(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeftFunc(data, unicode.IsSpace), []byte("{"))
+}
+
+// Pointer returns the JSON pointer of path.
+func Pointer(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		b.WriteString("/")
+		b.WriteString(escape(token))
+	}
+	return b.String()
+}
+
+// Path returns the path of a JSON pointer.
+func Path(pointer string) []string {
+	if pointer == "" {
+		return []string{}
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = unescape(token)
+	}
+	return tokens
+}
+
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
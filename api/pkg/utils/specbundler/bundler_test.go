@@ -0,0 +1,174 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package specbundler
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFiles = map[string]string{
+	"api/openapi.yaml": `openapi: 3.0.0
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /pets:
+    $ref: paths/pets.yaml
+components:
+  schemas:
+    Pet:
+      type: string
+`,
+	"api/paths/pets.yaml": `get:
+  responses:
+    "200":
+      description: ok
+      content:
+        application/json:
+          schema:
+            $ref: ../schemas.yaml#/components/schemas/Pet
+`,
+	"api/schemas.yaml": `components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: "#/components/schemas/Owner"
+        friends:
+          type: array
+          items:
+            $ref: "#/components/schemas/Pet"
+    Owner:
+      type: object
+`,
+}
+
+func testArchive(t *testing.T) []byte {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range testFiles {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestBundleFiles(t *testing.T) {
+	data := testArchive(t)
+	assert.True(t, IsArchive(data))
+
+	files, err := ReadArchive(data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"openapi.yaml", "paths/pets.yaml", "schemas.yaml"}, files.Paths())
+
+	root, err := files.GuessRoot()
+	require.NoError(t, err)
+	assert.Equal(t, "openapi.yaml", root)
+
+	bundle, err := BundleFiles(files, root)
+	require.NoError(t, err)
+
+	doc, err := openapi3.NewLoader().LoadFromData(bundle.Doc)
+	require.NoError(t, err)
+	assert.Equal(t, "string", doc.Components.Schemas["Pet"].Value.Type)
+	assert.Equal(t, "#/components/schemas/Owner", doc.Components.Schemas["Pet2"].Value.Properties["owner"].Ref)
+	assert.Equal(t, "#/components/schemas/Pet2", doc.Components.Schemas["Pet2"].Value.Properties["friends"].Value.Items.Ref)
+	assert.Equal(t, "#/components/schemas/Pet2", doc.Paths["/pets"].Get.Responses["200"].Value.Content["application/json"].Schema.Ref)
+
+	assert.Equal(t, Source{File: "paths/pets.yaml", Pointer: "/get/responses/200"}, bundle.Locate("/paths/~1pets/get/responses/200"))
+	assert.Equal(t, Source{File: "schemas.yaml", Pointer: "/components/schemas/Pet/properties"}, bundle.Locate("/components/schemas/Pet2/properties"))
+	assert.Equal(t, Source{File: "openapi.yaml", Pointer: "/info/title"}, bundle.Locate("/info/title"))
+}
+
+func TestBundleFiles_Errors(t *testing.T) {
+	_, err := BundleFiles(Files{"openapi.yaml": []byte("openapi: 3.0.0\npaths:\n  /a:\n    $ref: missing.yaml\n")}, "openapi.yaml")
+	assert.Error(t, err)
+
+	_, err = BundleFiles(Files{
+		"openapi.yaml": []byte("openapi: 3.0.0\npaths:\n  /a:\n    $ref: a.yaml\n"),
+		"a.yaml":       []byte("get:\n  $ref: a.yaml\n"),
+	}, "openapi.yaml")
+	assert.Error(t, err)
+
+	_, err = BundleFiles(Files{}, "openapi.yaml")
+	assert.Error(t, err)
+}
+
+func TestReadArchive_TooLarge(t *testing.T) {
+	defer func(fileSize, size int64) { MaxArchiveFileSize, MaxArchiveSize = fileSize, size }(MaxArchiveFileSize, MaxArchiveSize)
+	MaxArchiveFileSize, MaxArchiveSize = 1<<10, 3<<10
+
+	zipArchive := func(sizes ...int) []byte {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		for i, size := range sizes {
+			w, err := zw.Create(strings.Repeat("a", i+1) + ".yaml")
+			require.NoError(t, err)
+			_, err = w.Write(bytes.Repeat([]byte(" "), size))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+		return buf.Bytes()
+	}
+	tarGzArchive := func(size int) []byte {
+		buf := &bytes.Buffer{}
+		gw := gzip.NewWriter(buf)
+		tw := tar.NewWriter(gw)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "openapi.yaml", Mode: 0600, Size: int64(size), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(bytes.Repeat([]byte(" "), size))
+		require.NoError(t, err)
+		require.NoError(t, tw.Close())
+		require.NoError(t, gw.Close())
+		return buf.Bytes()
+	}
+
+	// A highly compressible file, i.e. a zip bomb, is only read up to the limit.
+	bomb := zipArchive(1 << 20)
+	assert.Less(t, len(bomb), 4<<10)
+	_, err := ReadArchive(bomb)
+	assert.ErrorIs(t, err, ErrArchiveTooLarge)
+
+	_, err = ReadArchive(zipArchive(1<<10, 1<<10, 1<<10))
+	assert.NoError(t, err)
+	_, err = ReadArchive(zipArchive(1<<10, 1<<10, 1<<10, 1))
+	assert.ErrorIs(t, err, ErrArchiveTooLarge)
+
+	_, err = ReadArchive(tarGzArchive(1 << 10))
+	assert.NoError(t, err)
+	_, err = ReadArchive(tarGzArchive(1<<10 + 1))
+	assert.ErrorIs(t, err, ErrArchiveTooLarge)
+}
+
+func TestPointer(t *testing.T) {
+	path := []string{"paths", "/pets/{id}", "get", "a~b"}
+	assert.Equal(t, "/paths/~1pets~1{id}/get/a~0b", Pointer(path))
+	assert.Equal(t, path, Path(Pointer(path)))
+	assert.Equal(t, []string{}, Path(""))
+}
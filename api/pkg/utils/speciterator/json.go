@@ -17,10 +17,10 @@
 package speciterator
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
 	"github.com/buger/jsonparser"
-	"strings"
+	"sort"
 )
 
 type jsonParser struct {
@@ -75,30 +75,30 @@ func (p *jsonParser) iterateArray(data []byte, path *Path, indent int, cb Callba
 }
 
 type locator struct {
+	// lines contains the offsets of the ends of lines (i.e. past their newlines), starting with 0.
 	lines []int
 }
 
 func newLocator(data []byte) *locator {
 	lines := []int{0}
-	last := 0
-	scanner := bufio.NewScanner(strings.NewReader(string(data)))
-	for scanner.Scan() {
-		txt := scanner.Text()
-		length := len([]byte(txt)) + 1
-		lines = append(lines, length+last)
-		last = length + last
+	for offset := 0; offset < len(data); {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			lines = append(lines, len(data)+1)
+			break
+		}
+		offset += i + 1
+		lines = append(lines, offset)
 	}
 	return &locator{lines: lines}
 }
 
 func (l *locator) GetPos(offset int) *Pos {
-	for i, v := range l.lines {
-		if offset <= v {
-			return &Pos{
-				Line:   i,
-				Column: offset - l.lines[i-1] + 1,
-				Offset: offset,
-			}
+	if i := sort.SearchInts(l.lines, offset); i > 0 && i < len(l.lines) {
+		return &Pos{
+			Line:   i,
+			Column: offset - l.lines[i-1] + 1,
+			Offset: offset,
 		}
 	}
 	return &Pos{
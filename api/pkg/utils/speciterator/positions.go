@@ -0,0 +1,85 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package speciterator
+
+import (
+	"strings"
+)
+
+// Positions indexes the positions of the items of a spec doc by their paths.
+type Positions struct {
+	items  []*positionedItem
+	byPath map[string]*Pos
+}
+
+type positionedItem struct {
+	path []string
+	pos  *Pos
+}
+
+// NewPositions iterates data (see NewSpecIterator) & indexes the positions of its items.
+// The index is empty if data can't be parsed.
+func NewPositions(data []byte) *Positions {
+	p := &Positions{byPath: map[string]*Pos{}}
+	_ = NewSpecIterator(data).Iterate(func(path *Path, pos *Pos) {
+		if pos == nil || pos.Line == 0 {
+			return
+		}
+		p.items = append(p.items, &positionedItem{path: append([]string{}, path.path...), pos: pos})
+		if _, found := p.byPath[joinPath(path.path)]; !found {
+			p.byPath[joinPath(path.path)] = pos
+		}
+	})
+	return p
+}
+
+// Len returns the number of indexed items.
+func (p *Positions) Len() int {
+	return len(p.items)
+}
+
+// Position returns the position of the item at path, falling back to the closest indexed ancestor of path
+// (exact is false then). The root (or a path without indexed ancestors) is located at 1:1.
+func (p *Positions) Position(path []string) (pos *Pos, exact bool) {
+	for i := len(path); i > 0; i-- {
+		if pos, found := p.byPath[joinPath(path[:i])]; found {
+			return pos, i == len(path)
+		}
+	}
+	return &Pos{Line: 1, Column: 1}, len(path) == 0
+}
+
+// PathAt returns the path of the innermost item at (or preceding) line & column, or nil if there's none.
+func (p *Positions) PathAt(line, column int) []string {
+	var found *positionedItem
+	for _, item := range p.items {
+		if item.pos.Line > line || item.pos.Line == line && item.pos.Column > column {
+			continue
+		}
+		if found == nil || item.pos.Line > found.pos.Line || item.pos.Line == found.pos.Line && item.pos.Column >= found.pos.Column {
+			found = item
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	return append([]string{}, found.path...)
+}
+
+func joinPath(path []string) string {
+	return strings.Join(path, "\x00")
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/cisco-developer/api-insights/cli/pkg/model"
+	"github.com/cisco-developer/api-insights/cli/pkg/report"
 	"github.com/cisco-developer/api-insights/cli/pkg/utils"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -35,11 +36,16 @@ const (
 
 	flagAnalyzer       = "analyzer"
 	flagFailBelowScore = "fail-below-score"
+	flagFormat         = "format"
+
+	formatTable = "table"
+	formatJSON  = "json"
 )
 
 var (
 	analyzer       string
 	failBelowScore int
+	analyzeFormat  = formatTable
 )
 
 func init() {
@@ -62,13 +68,20 @@ func analyzeCmd() *cobra.Command {
   api-insights-cli analyze testdata/carts.json --analyzer completeness
   api-insights-cli analyze testdata/carts.json --analyzer inclusive-language
   api-insights-cli analyze testdata/carts.json --analyzer drift
-  api-insights-cli analyze testdata/carts.json --analyzer security`,
+  api-insights-cli analyze testdata/carts.json --analyzer security
+
+  # Analyze local spec & report findings for CI, e.g. as SARIF for GitHub code scanning
+  api-insights-cli analyze testdata/carts.json --format sarif > api-insights.sarif
+  api-insights-cli analyze testdata/carts.json --format junit > api-insights.xml
+  api-insights-cli analyze testdata/carts.json --format github
+  api-insights-cli analyze testdata/carts.json --format gitlab-codequality > gl-code-quality-report.json`,
 		Run:  analyzeSpec,
 		Args: cobra.MinimumNArgs(1),
 	}
 
 	cmd.Flags().StringVarP(&analyzer, flagAnalyzer, "a", "", "API spec analyzer")
 	cmd.Flags().IntVarP(&failBelowScore, flagFailBelowScore, "", 0, "Fail if API score is below specified score, defaults to 0")
+	cmd.Flags().StringVarP(&analyzeFormat, flagFormat, "f", formatTable, "output format, one of table (default), json, sarif, junit, github, gitlab-codequality")
 	err := viper.BindPFlags(cmd.Flags())
 	if err != nil {
 		logDebugln("Failed to bind flags", err.Error())
@@ -92,7 +105,17 @@ func analyzeSpec(cmd *cobra.Command, args []string) {
 	}
 	logDebugf("loaded local spec: %s\n", filename)
 
-	analyzers, err := apiInsightsClient.ListAnalyzers(cmd.Context(), map[string]string{"status": "active"})
+	analyzeFormat = viper.GetString(flagFormat)
+	if analyzeFormat != formatTable && analyzeFormat != formatJSON && !report.IsFormat(analyzeFormat) {
+		utils.ExitWithCode(utils.ExitInvalidInput, fmt.Errorf("invalid format, choices: %s", strings.Join(append([]string{formatTable, formatJSON}, report.Formats...), ", ")))
+	}
+
+	queries := map[string]string{"status": "active"}
+	if report.IsFormat(analyzeFormat) {
+		// Rule metadata of reports.
+		queries["withRules"] = "true"
+	}
+	analyzers, err := apiInsightsClient.ListAnalyzers(cmd.Context(), queries)
 	if err != nil {
 		utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to list analyzers: %s", err.Error()))
 	}
@@ -130,7 +153,16 @@ func analyzeSpec(cmd *cobra.Command, args []string) {
 	if err != nil {
 		utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to analyze spec: %s", err.Error()))
 	}
-	res.Print(os.Stdout, as)
+	switch {
+	case analyzeFormat == formatJSON:
+		fmt.Print(utils.Pretty(res))
+	case report.IsFormat(analyzeFormat):
+		if err := report.NewAnalysisReport(filename, spec, res, analyzers).Write(os.Stdout, analyzeFormat); err != nil {
+			utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to write report: %s", err.Error()))
+		}
+	default:
+		res.Print(os.Stdout, as)
+	}
 
 	if res.SpecScore < failBelowScore {
 		utils.ExitWithCode(utils.ExitFailBelowScore)
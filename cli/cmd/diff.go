@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/cisco-developer/api-insights/cli/pkg/model"
+	"github.com/cisco-developer/api-insights/cli/pkg/report"
 	"github.com/cisco-developer/api-insights/cli/pkg/utils"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -64,12 +65,18 @@ func diffCmd() *cobra.Command {
   api-insights-cli diff testdata/carts.json -s carts --latest -o text
   api-insights-cli diff testdata/carts.json -s carts --latest -o json
   api-insights-cli diff testdata/carts.json -s carts --latest -o markdown
-  api-insights-cli diff testdata/carts.json -s carts --latest -o html`,
+  api-insights-cli diff testdata/carts.json -s carts --latest -o html
+
+  # Diff specs & report breaking changes for CI, e.g. as GitHub annotations
+  api-insights-cli diff testdata/carts.json -s carts --latest -o github
+  api-insights-cli diff testdata/carts.json -s carts --latest -o sarif > api-insights-diff.sarif
+  api-insights-cli diff testdata/carts.json -s carts --latest -o junit > api-insights-diff.xml
+  api-insights-cli diff testdata/carts.json -s carts --latest -o gitlab-codequality > gl-code-quality-report.json`,
 		Run:  diffSpecs,
 		Args: cobra.MinimumNArgs(1),
 	}
 
-	cmd.Flags().StringVarP(&output, flagOutput, "o", model.DiffOutputText, "diff output, one of text (default), json, markdown, html")
+	cmd.Flags().StringVarP(&output, flagOutput, "o", model.DiffOutputText, "diff output, one of text (default), json, markdown, html, sarif, junit, github, gitlab-codequality")
 	cmd.Flags().StringVarP(&service, flagService, "s", "", "service id or nameId for API spec")
 	cmd.Flags().StringVarP(&specVersion, flagVersion, "", "", "API spec version")
 	cmd.Flags().StringVarP(&specRevision, flagRevision, "", "", "API spec revision")
@@ -121,10 +128,15 @@ func diffSpecs(cmd *cobra.Command, args []string) {
 	logDebugf("loaded remote spec for service %s: version(%s), revision(%s), docType(%s), score(%d), state(%s)\n", serviceID, remoteSpec.Version, remoteSpec.Revision, remoteSpec.DocType, remoteSpec.Score, remoteSpec.State)
 
 	outputFormat := viper.GetString(flagOutput)
+	diffFormat := outputFormat
+	if report.IsFormat(outputFormat) {
+		// Reports are built from the JSON diff.
+		diffFormat = model.DiffOutputJSON
+	}
 	req := &model.SpecDiffRequest{
 		OldSpecDoc: remoteSpec.Doc,
 		NewSpecDoc: model.NewSpecDoc(localSpec),
-		Config:     &model.SpecDiffConfig{OutputFormat: outputFormat},
+		Config:     &model.SpecDiffConfig{OutputFormat: diffFormat},
 	}
 
 	logDebugf("comparing specs for service %s\n", serviceID)
@@ -134,7 +146,17 @@ func diffSpecs(cmd *cobra.Command, args []string) {
 	}
 	logDebugf("compared specs for service %s\n", serviceID)
 
-	res.Result.Print(os.Stdout, outputFormat)
+	if report.IsFormat(outputFormat) {
+		var jsonRes *model.JSONResult
+		if res.Result != nil {
+			jsonRes = res.Result.JSON
+		}
+		if err := report.NewDiffReport(filename, localSpec, jsonRes).Write(os.Stdout, outputFormat); err != nil {
+			utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to write report: %s", err.Error()))
+		}
+	} else {
+		res.Result.Print(os.Stdout, outputFormat)
+	}
 
 	if failOnIncompatible && hasBreakingChanges(cmd.Context(), req, res) {
 		utils.ExitWithCode(utils.ExitIncompatibleAPISpec)
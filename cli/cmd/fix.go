@@ -0,0 +1,145 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cisco-developer/api-insights/cli/pkg/model"
+	"github.com/cisco-developer/api-insights/cli/pkg/utils"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const (
+	flagFixRule   = "rule"
+	flagFixWrite  = "write"
+	flagFixOutput = "output"
+	flagFixDryRun = "dry-run"
+)
+
+var (
+	fixRules  []string
+	fixWrite  bool
+	fixOutput string
+	fixDryRun bool
+)
+
+func init() {
+	rootCmd.AddCommand(fixCmd())
+}
+
+func fixCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fix LOCAL_SPEC",
+		Short: "Apply the fixes of the findings of local API spec",
+		Long: `Analyze local API spec with all analyzers & apply the machine-applicable fixes of its findings,
+e.g. missing operationIds, description placeholders or field name casing.
+The fixed spec is printed, unless written back with --write or to another file with --output.`,
+		Example: `  # Print the fixed spec
+  api-insights-cli fix testdata/carts.json
+
+  # Fix the spec in place, only for specific rules
+  api-insights-cli fix testdata/carts.json --write --rule operation-operationId --rule info-description
+
+  # List the fixes without applying them
+  api-insights-cli fix testdata/carts.json --dry-run`,
+		Run:  fixSpec,
+		Args: cobra.MinimumNArgs(1),
+	}
+
+	cmd.Flags().StringSliceVarP(&fixRules, flagFixRule, "r", nil, "Only apply the fixes of findings of the rule(s)")
+	cmd.Flags().BoolVarP(&fixWrite, flagFixWrite, "w", false, "Write the fixed spec back to LOCAL_SPEC")
+	cmd.Flags().StringVarP(&fixOutput, flagFixOutput, "o", "", "Write the fixed spec to the file")
+	cmd.Flags().BoolVarP(&fixDryRun, flagFixDryRun, "", false, "List the fixes without applying them")
+
+	return cmd
+}
+
+func fixSpec(cmd *cobra.Command, args []string) {
+	logDebugln("started")
+	if len(args) < 1 {
+		utils.ExitWithCode(utils.ExitInvalidInput, errors.New("API spec file is required, for example: api-insights-cli fix api.yaml"))
+	}
+	if fixWrite && fixOutput != "" {
+		utils.ExitWithCode(utils.ExitInvalidInput, errors.New("--write and --output are mutually exclusive"))
+	}
+
+	filename := args[0]
+	logDebugf("loading local spec: %s\n", filename)
+
+	spec, err := os.ReadFile(filename)
+	if err != nil {
+		utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to load spec: %s", err.Error()))
+	}
+	logDebugf("loaded local spec: %s\n", filename)
+
+	analyzers, err := apiInsightsClient.ListAnalyzers(cmd.Context(), map[string]string{"status": "active"})
+	if err != nil {
+		utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to list analyzers: %s", err.Error()))
+	}
+
+	req := &model.SpecAnalysisRequest{
+		Service: &model.Service{NameID: dummyServiceNameID},
+		Spec: &model.Spec{
+			ID:        dummySpecID,
+			ServiceID: dummyServiceID,
+			Doc:       model.NewSpecDoc(spec),
+		},
+	}
+	for _, a := range analyzers {
+		req.Analyzers = append(req.Analyzers, model.SpecAnalyzer(a.NameID))
+	}
+
+	logDebugf("analyzing local spec: %s, analyzers: %v\n", filename, req.Analyzers)
+	res, err := apiInsightsClient.AnalyzeAPISpec(cmd.Context(), req)
+	if err != nil {
+		utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to analyze spec: %s", err.Error()))
+	}
+
+	fixes := res.Fixes(fixRules)
+	if fixDryRun {
+		for _, fix := range fixes {
+			fmt.Printf("%s\t%s\t%s\n", fix.ID, fix.Rule, fix.Description)
+		}
+		utils.ExitWithCode(utils.ExitSuccess)
+	}
+
+	// Fixes are applied by the server, which orders them so that they don't invalidate each other.
+	fixed, err := apiInsightsClient.ApplyFixes(cmd.Context(), &model.SpecFixApplyRequest{Doc: string(spec), Fixes: fixes})
+	if err != nil {
+		utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to apply fixes: %s", err.Error()))
+	}
+	for _, id := range fixed.Skipped {
+		logDebugf("skipped conflicting fix %s\n", id)
+	}
+	fmt.Fprintf(os.Stderr, "Applied %d fix(es), skipped %d conflicting fix(es).\n", len(fixed.Applied), len(fixed.Skipped))
+
+	switch {
+	case fixWrite:
+		fixOutput = filename
+		fallthrough
+	case fixOutput != "":
+		if err := os.WriteFile(fixOutput, []byte(fixed.Doc), 0644); err != nil {
+			utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to write spec: %s", err.Error()))
+		}
+	default:
+		_, _ = os.Stdout.WriteString(fixed.Doc)
+	}
+
+	utils.ExitWithCode(utils.ExitSuccess)
+}
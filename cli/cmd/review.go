@@ -0,0 +1,121 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cisco-developer/api-insights/cli/pkg/model"
+	"github.com/cisco-developer/api-insights/cli/pkg/utils"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var (
+	reviewOutput = model.ReviewOutputMarkdown
+)
+
+func init() {
+	rootCmd.AddCommand(reviewCmd())
+}
+
+func reviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review LOCAL_SPEC",
+		Short: "Review local API spec against remote API spec as a pull request comment",
+		Long: `Review local API spec against remote API spec (by default the latest spec of the service).
+
+The review combines the breaking changes, the score delta, the new findings (not found in the remote spec) and all findings
+of the local spec into a single markdown comment, e.g. for an API review bot to post on pull requests.`,
+		Example: `  # Review local test data carts spec against latest remote spec for service carts
+  api-insights-cli review testdata/carts.json -s carts
+
+  # Review against specific remote spec, and fail if API changes broke backward compatibility
+  api-insights-cli review testdata/carts.json -s carts --version 0.0.1 --state Release --fail-on-incompatible
+
+  # Review as JSON, e.g. to post the markdown comment and act on the breaking changes & score delta
+  api-insights-cli review testdata/carts.json -s carts -o json`,
+		Run:  reviewSpec,
+		Args: cobra.MinimumNArgs(1),
+	}
+
+	// Flags are read directly, not through viper: binding them would shadow the same-named flags of other commands.
+	cmd.Flags().StringVarP(&reviewOutput, flagOutput, "o", model.ReviewOutputMarkdown, "review output, one of markdown (default), json")
+	cmd.Flags().StringVarP(&service, flagService, "s", "", "service id or nameId for API spec")
+	cmd.Flags().StringVarP(&specVersion, flagVersion, "", "", "remote API spec version, defaults to latest spec")
+	cmd.Flags().StringVarP(&specRevision, flagRevision, "", "", "remote API spec revision, defaults to latest spec")
+	cmd.Flags().StringVarP(&specState, flagSpecState, "", "", "remote API spec state, defaults to latest spec")
+	cmd.Flags().BoolVarP(&failOnIncompatible, flagFailOnIncompatible, "", false, "fail if API changes broke backward compatibility")
+	cmd.Flags().IntVarP(&failBelowScore, flagFailBelowScore, "", 0, "Fail if API score is below specified score, defaults to 0")
+	cmd.MarkFlagRequired(flagService)
+
+	return cmd
+}
+
+func reviewSpec(cmd *cobra.Command, args []string) {
+	logDebugln("started")
+	if len(args) < 1 {
+		utils.ExitWithCode(utils.ExitInvalidInput, errors.New("API spec file is required, for example: api-insights-cli review api.yaml -s <service_id or service_nameId>"))
+	}
+	if reviewOutput != model.ReviewOutputMarkdown && reviewOutput != model.ReviewOutputJSON {
+		utils.ExitWithCode(utils.ExitInvalidInput, fmt.Errorf("invalid output, choices: %s, %s", model.ReviewOutputMarkdown, model.ReviewOutputJSON))
+	}
+
+	serviceID := service
+	filename := args[0]
+	logDebugf("loading local spec for service %s: %s\n", serviceID, filename)
+
+	localSpec, err := os.ReadFile(filename)
+	if err != nil {
+		utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to load spec: %s", err.Error()))
+	}
+	logDebugf("loaded local spec for service %s: %s\n", serviceID, filename)
+
+	req := &model.SpecReviewRequest{Doc: model.NewSpecDoc(localSpec)}
+	if specVersion != "" || specRevision != "" || specState != "" {
+		queries := map[string]string{
+			flagVersion:   specVersion,
+			flagRevision:  specRevision,
+			flagSpecState: specState,
+		}
+		remoteSpec, err := apiInsightsClient.GetServiceSpec(cmd.Context(), serviceID, queries)
+		if err != nil {
+			utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to load remote spec for service %s: %s", serviceID, err.Error()))
+		}
+		req.SpecID = remoteSpec.ID
+	}
+
+	logDebugf("reviewing local spec for service %s\n", serviceID)
+	res, err := apiInsightsClient.ReviewSpec(cmd.Context(), serviceID, req)
+	if err != nil {
+		utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to review spec: %s", err.Error()))
+	}
+	logDebugf("reviewed local spec for service %s against spec %s\n", serviceID, res.SpecID)
+
+	if reviewOutput == model.ReviewOutputJSON {
+		fmt.Print(utils.Pretty(res))
+	} else {
+		fmt.Print(res.Markdown)
+	}
+
+	if failOnIncompatible && res.Breaking {
+		utils.ExitWithCode(utils.ExitIncompatibleAPISpec)
+	}
+	if res.Score < failBelowScore {
+		utils.ExitWithCode(utils.ExitFailBelowScore)
+	}
+}
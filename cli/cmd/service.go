@@ -33,6 +33,7 @@ const (
 	flagRevision = "revision"
 	flagFile     = "file"
 	flagData     = "data"
+	flagRoot     = "root"
 )
 
 var (
@@ -41,6 +42,7 @@ var (
 	specRevision string
 	file         string
 	data         string
+	specRoot     string
 )
 
 func init() {
@@ -207,7 +209,7 @@ func serviceDeleteCmd() *cobra.Command {
 func serviceUploadSpecCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "uploadspec LOCAL_SPEC",
-		Short: "Upload local spec for a service under analysis",
+		Short: "Upload local spec (a file, or a directory or archive of a multi-file spec) for a service under analysis",
 		Example: `  # Upload local spec with specific service name_id, spec version, revision
   api-insights-cli service uploadspec testdata/carts.json -s carts -v 1.0.0 -r 1
 
@@ -218,7 +220,13 @@ func serviceUploadSpecCmd() *cobra.Command {
   api-insights-cli service uploadspec testdata/carts.json -s carts -r 1
 
   # Upload local spec with specific service id and spec revision (spec version will be derived from spec)
-  api-insights-cli service uploadspec testdata/carts.json -s 1555b762-b9d3-11ec-af7b-a6db741213e2 -r 1`,
+  api-insights-cli service uploadspec testdata/carts.json -s 1555b762-b9d3-11ec-af7b-a6db741213e2 -r 1
+
+  # Upload local multi-file spec directory, with its root document
+  api-insights-cli service uploadspec testdata/carts -s carts -r 1 --root openapi.yaml
+
+  # Upload local multi-file spec archive (zip, tar or tar.gz), guessing its root document
+  api-insights-cli service uploadspec testdata/carts.zip -s carts -r 1`,
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			logDebugln("started")
@@ -233,6 +241,33 @@ func serviceUploadSpecCmd() *cobra.Command {
 			filename := args[0]
 			logDebugf("loading spec: %s\n", filename)
 
+			if fi, err := os.Stat(filename); err == nil && (fi.IsDir() || utils.IsArchive(filename)) {
+				var archive []byte
+				if fi.IsDir() {
+					archive, err = utils.ZipDir(filename)
+				} else {
+					archive, err = os.ReadFile(filename)
+				}
+				if err != nil {
+					utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to load spec: %s", err.Error()))
+				}
+				logDebugf("loaded multi-file spec: %s\n", filename)
+
+				logDebugln("uploading multi-file spec")
+				spec := &model.Spec{
+					Revision:  revision,
+					ServiceID: serviceID,
+					Version:   version,
+				}
+				res, err := apiInsightsClient.UploadSpecArchive(cmd.Context(), serviceID, archive, viper.GetString(flagRoot), spec)
+				if err != nil {
+					utils.ExitWithCode(utils.ExitError, err)
+				}
+				logDebugf("uploaded spec %s: %s\n", filename, res.ID)
+				logDebugln("completed")
+				return
+			}
+
 			data, err := os.ReadFile(filename)
 			if err != nil {
 				utils.ExitWithCode(utils.ExitError, fmt.Errorf("failed to load spec: %s", err.Error()))
@@ -258,6 +293,7 @@ func serviceUploadSpecCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&service, flagService, "s", "", "service id or nameId for API spec")
 	cmd.Flags().StringVarP(&specVersion, flagVersion, "v", "", "API spec version, optional if version value is provided in spec")
 	cmd.Flags().StringVarP(&specRevision, flagRevision, "r", "", "API spec revision")
+	cmd.Flags().StringVar(&specRoot, flagRoot, "", "root document path of a multi-file API spec directory or archive, guessed if not provided")
 	err := viper.BindPFlags(cmd.Flags())
 	if err != nil {
 		fmt.Println("failed to bind flags", err.Error())
@@ -3,10 +3,15 @@ module github.com/cisco-developer/api-insights/cli
 go 1.16
 
 require (
+	github.com/evanphx/json-patch/v5 v5.2.0
 	github.com/getkin/kin-openapi v0.94.0
 	github.com/go-resty/resty/v2 v2.7.0
 	github.com/olekukonko/tablewriter v0.0.5
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/santhosh-tekuri/jsonschema/v5 v5.0.0
 	github.com/spf13/cobra v1.5.0
 	github.com/spf13/viper v1.10.1
+	github.com/stretchr/testify v1.7.0
 	golang.org/x/oauth2 v0.0.0-20220722155238-128564f6959c
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
@@ -17,6 +17,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -27,6 +28,7 @@ import (
 
 type APIInsightsClient interface {
 	AnalyzeAPISpec(ctx context.Context, req *model.SpecAnalysisRequest) (*model.SpecAnalysisResponse, error)
+	ApplyFixes(ctx context.Context, req *model.SpecFixApplyRequest) (*model.SpecFixResponse, error)
 
 	ListServices(ctx context.Context) (model.ServiceList, error)
 	GetService(ctx context.Context, id string) (*model.Service, error)
@@ -35,6 +37,7 @@ type APIInsightsClient interface {
 	GetLatestSpec(ctx context.Context, serviceID string) (*model.Spec, error)
 	GetServiceSpec(ctx context.Context, serviceID string, queries map[string]string) (*model.Spec, error)
 	UploadSpec(ctx context.Context, serviceID string, spec *model.Spec) (*model.Spec, error)
+	UploadSpecArchive(ctx context.Context, serviceID string, archive []byte, root string, spec *model.Spec) (*model.Spec, error)
 
 	ListSpecs(ctx context.Context, serviceID string) (model.SpecList, error)
 	GetSpec(ctx context.Context, serviceID, id string) (*model.Spec, error)
@@ -42,6 +45,7 @@ type APIInsightsClient interface {
 	ListSpecAnalyses(ctx context.Context, serviceID, specID string) (model.SpecAnalysisList, error)
 
 	Diff(ctx context.Context, req *model.SpecDiffRequest) (*model.SpecDiff, error)
+	ReviewSpec(ctx context.Context, serviceID string, req *model.SpecReviewRequest) (*model.SpecReviewResponse, error)
 
 	ListAnalyzers(ctx context.Context, queries map[string]string) (model.AnalyzerList, error)
 	GetAnalyzer(ctx context.Context, id string) (*model.Analyzer, error)
@@ -102,6 +106,30 @@ func (c *apiInsightsClient) AnalyzeAPISpec(ctx context.Context, req *model.SpecA
 	return result, nil
 }
 
+func (c *apiInsightsClient) ApplyFixes(ctx context.Context, req *model.SpecFixApplyRequest) (*model.SpecFixResponse, error) {
+	client, err := c.newRestyClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *model.SpecFixResponse
+	res, err := client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeaders(c.headers).
+		SetBody(req).
+		SetResult(&result).
+		Post(fmt.Sprintf("%s/specs/analyses/fix", c.basePath))
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSuccess() {
+		return nil, errors.New(res.Status())
+	}
+
+	return result, nil
+}
+
 func (c *apiInsightsClient) ListServices(ctx context.Context) (services model.ServiceList, err error) {
 	client, err := c.newRestyClient(ctx)
 	if err != nil {
@@ -272,6 +300,37 @@ func (c *apiInsightsClient) UploadSpec(ctx context.Context, serviceID string, sp
 	return s, nil
 }
 
+// UploadSpecArchive uploads a multi-file spec archive (zip, tar or tar.gz), with the given root document path.
+func (c *apiInsightsClient) UploadSpecArchive(ctx context.Context, serviceID string, archive []byte, root string, spec *model.Spec) (*model.Spec, error) {
+	client, err := c.newRestyClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var s *model.Spec
+	url := fmt.Sprintf("%s/services/%s/specs", c.basePath, serviceID)
+
+	res, err := client.R().
+		SetContext(ctx).
+		SetHeaders(c.headers).
+		SetFileReader("archive", "spec.zip", bytes.NewReader(archive)).
+		SetFormData(map[string]string{
+			"root":     root,
+			"revision": spec.Revision,
+			"version":  spec.Version,
+		}).
+		SetResult(&s).
+		Post(url)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSuccess() {
+		return nil, errors.New(res.Status())
+	}
+
+	return s, nil
+}
+
 func (c *apiInsightsClient) ListSpecs(ctx context.Context, serviceID string) (specs model.SpecList, err error) {
 	client, err := c.newRestyClient(ctx)
 	if err != nil {
@@ -362,6 +421,30 @@ func (c *apiInsightsClient) Diff(ctx context.Context, req *model.SpecDiffRequest
 	return result, nil
 }
 
+func (c *apiInsightsClient) ReviewSpec(ctx context.Context, serviceID string, req *model.SpecReviewRequest) (*model.SpecReviewResponse, error) {
+	client, err := c.newRestyClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *model.SpecReviewResponse
+	res, err := client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeaders(c.headers).
+		SetBody(req).
+		SetResult(&result).
+		Post(fmt.Sprintf("%s/services/%s/specs/review", c.basePath, serviceID))
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSuccess() {
+		return nil, errors.New(res.Status())
+	}
+
+	return result, nil
+}
+
 func (c *apiInsightsClient) ListAnalyzers(ctx context.Context, queries map[string]string) (analyzers model.AnalyzerList, err error) {
 	client, err := c.newRestyClient(ctx)
 	if err != nil {
@@ -151,6 +151,7 @@ type Finding struct {
 	Path  []string              `json:"path"`
 	Range *FindingPositionRange `json:"range,omitempty"`
 	Diff  *FindingDiff          `json:"diff,omitempty"`
+	Fix   *FindingFix           `json:"fix,omitempty"`
 }
 
 func (f *Finding) Start() string {
@@ -195,6 +196,22 @@ type FindingDiff struct {
 	New string `json:"new"`
 }
 
+// FindingFix represents a machine-applicable fix of a Finding, as an RFC 6902 JSON Patch against the analyzed doc.
+type FindingFix struct {
+	ID          string                      `json:"id"`
+	Rule        NameID                      `json:"rule"`
+	Description string                      `json:"description"`
+	Patch       []*FindingFixPatchOperation `json:"patch"`
+}
+
+// FindingFixPatchOperation represents an operation of an RFC 6902 JSON Patch.
+type FindingFixPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
 type NameID string
 
 type FindingType string
@@ -21,6 +21,7 @@ import (
 	"github.com/cisco-developer/api-insights/cli/pkg/utils"
 	"github.com/olekukonko/tablewriter"
 	"io"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -86,6 +87,38 @@ type SpecAnalysisResponse struct {
 	SpecScore int                            `json:"spec_score"`
 }
 
+// Fixes returns the distinct fixes of the findings of all analyses, optionally limited to the fixes of rules.
+func (s *SpecAnalysisResponse) Fixes(rules []string) []*FindingFix {
+	var (
+		fixes []*FindingFix
+		seen  = map[string]bool{}
+	)
+	for _, analysis := range s.Results {
+		if analysis == nil || analysis.Result == nil {
+			continue
+		}
+		for _, ruleFindings := range analysis.Result.Findings {
+			if ruleFindings == nil {
+				continue
+			}
+			for ruleNameID, findings := range ruleFindings.Rules {
+				if findings == nil || (len(rules) > 0 && !utils.ContainsString(rules, string(ruleNameID))) {
+					continue
+				}
+				for _, finding := range findings.Data {
+					if finding.Fix == nil || seen[finding.Fix.ID] {
+						continue
+					}
+					seen[finding.Fix.ID] = true
+					fixes = append(fixes, finding.Fix)
+				}
+			}
+		}
+	}
+	sort.Slice(fixes, func(i, j int) bool { return fixes[i].ID < fixes[j].ID })
+	return fixes
+}
+
 // ExitCode returns exit code as per analysis findings
 func (s *SpecAnalysisResponse) ExitCode() int {
 	for _, analysis := range s.Results {
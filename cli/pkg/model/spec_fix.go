@@ -0,0 +1,30 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+// SpecFixApplyRequest represents a request to apply fixes, e.g. of a SpecAnalysisResponse (see SpecAnalysisResponse.Fixes), to a local spec.
+type SpecFixApplyRequest struct {
+	Doc   string        `json:"doc"`
+	Fixes []*FindingFix `json:"fixes"`
+}
+
+// SpecFixResponse represents the fixed spec of a SpecFixApplyRequest, with the IDs of the applied & skipped (i.e. conflicting) fixes.
+type SpecFixResponse struct {
+	Doc     string   `json:"doc"`
+	Applied []string `json:"applied"`
+	Skipped []string `json:"skipped"`
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+const (
+	ReviewOutputJSON     = "json"
+	ReviewOutputMarkdown = "markdown"
+)
+
+// SpecReviewRequest represents a request for a review of a local spec against a remote service spec.
+type SpecReviewRequest struct {
+	Doc SpecDoc `json:"doc"`
+	// SpecID is the ID of the remote spec to review against, by default the latest spec of the service.
+	SpecID string `json:"spec_id,omitempty"`
+}
+
+// SpecReviewResponse represents the review of a local spec: its breaking changes, score delta & new findings,
+// with Markdown, the review as a pull request comment.
+type SpecReviewResponse struct {
+	SpecID          string               `json:"spec_id,omitempty"`
+	Breaking        bool                 `json:"breaking"`
+	BreakingChanges []*SpecReviewChange  `json:"breaking_changes"`
+	Score           int                  `json:"score"`
+	BaseScore       *int                 `json:"base_score"`
+	ScoreDelta      *int                 `json:"score_delta"`
+	NewFindings     []*SpecReviewFinding `json:"new_findings"`
+	FindingsCount   int                  `json:"findings_count"`
+	Markdown        string               `json:"markdown"`
+}
+
+// SpecReviewChange represents a breaking change of an endpoint.
+type SpecReviewChange struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Action  string `json:"action"`
+	Message string `json:"message"`
+}
+
+// SpecReviewFinding represents a finding of a reviewed spec.
+type SpecReviewFinding struct {
+	Analyzer string   `json:"analyzer"`
+	Severity string   `json:"severity"`
+	Rule     string   `json:"rule"`
+	Message  string   `json:"message"`
+	Path     []string `json:"path"`
+	Line     int      `json:"line,omitempty"`
+}
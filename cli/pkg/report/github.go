@@ -0,0 +1,74 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeGitHub writes the report as GitHub Actions workflow commands, which annotate the spec file in PRs.
+func (r *Report) writeGitHub(w io.Writer) error {
+	for _, issue := range r.Issues {
+		props := []string{"file=" + escapeGitHubProperty(r.uri())}
+		if issue.Line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", issue.Line))
+			if issue.Column > 0 {
+				props = append(props, fmt.Sprintf("col=%d", issue.Column))
+			}
+			if issue.EndLine >= issue.Line {
+				props = append(props, fmt.Sprintf("endLine=%d", issue.EndLine))
+				if issue.EndLine == issue.Line && issue.EndColumn > issue.Column {
+					props = append(props, fmt.Sprintf("endColumn=%d", issue.EndColumn))
+				}
+			}
+		}
+		props = append(props, "title="+escapeGitHubProperty(fmt.Sprintf("%s: %s", issue.qualifiedRuleID(), nonEmpty(issue.Rule.Title, issue.Rule.ID))))
+
+		message := issue.Message
+		if loc := issue.location(); loc != "" {
+			message += " (" + loc + ")"
+		}
+		if issue.Rule.Mitigation != "" {
+			message += "\n" + issue.Rule.Mitigation
+		}
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", gitHubCommand(issue.Severity), strings.Join(props, ","), escapeGitHubData(message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gitHubCommand maps a severity to a GitHub workflow annotation command.
+func gitHubCommand(severity string) string {
+	switch severity {
+	case "error":
+		return "error"
+	case "warning":
+		return "warning"
+	}
+	return "notice"
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type (
+	gitLabCodeQualityIssue struct {
+		Description string                    `json:"description"`
+		CheckName   string                    `json:"check_name"`
+		Fingerprint string                    `json:"fingerprint"`
+		Severity    string                    `json:"severity"`
+		Location    gitLabCodeQualityLocation `json:"location"`
+	}
+	gitLabCodeQualityLocation struct {
+		Path  string                 `json:"path"`
+		Lines gitLabCodeQualityLines `json:"lines"`
+	}
+	gitLabCodeQualityLines struct {
+		Begin int `json:"begin"`
+	}
+)
+
+// writeGitLabCodeQuality writes the report as a GitLab Code Quality report, which annotates the spec file in MRs.
+func (r *Report) writeGitLabCodeQuality(w io.Writer) error {
+	issues := []*gitLabCodeQualityIssue{}
+	for _, issue := range r.Issues {
+		line := issue.Line
+		if line < 1 {
+			line = 1
+		}
+		description := issue.Message
+		if loc := issue.location(); loc != "" {
+			description += " (" + loc + ")"
+		}
+		fingerprint := md5.Sum([]byte(fmt.Sprintf("%s|%s|%s|%s", r.uri(), issue.qualifiedRuleID(), issue.location(), issue.Message)))
+		issues = append(issues, &gitLabCodeQualityIssue{
+			Description: description,
+			CheckName:   issue.qualifiedRuleID(),
+			Fingerprint: hex.EncodeToString(fingerprint[:]),
+			Severity:    gitLabSeverity(issue.Severity),
+			Location: gitLabCodeQualityLocation{
+				Path:  r.uri(),
+				Lines: gitLabCodeQualityLines{Begin: line},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// gitLabSeverity maps a severity to a GitLab Code Quality severity.
+func gitLabSeverity(severity string) string {
+	switch severity {
+	case "error":
+		return "critical"
+	case "warning":
+		return "major"
+	case "info":
+		return "minor"
+	}
+	return "info"
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type (
+	junitTestSuites struct {
+		XMLName  xml.Name          `xml:"testsuites"`
+		Name     string            `xml:"name,attr"`
+		Tests    int               `xml:"tests,attr"`
+		Failures int               `xml:"failures,attr"`
+		Skipped  int               `xml:"skipped,attr"`
+		Suites   []*junitTestSuite `xml:"testsuite"`
+	}
+	junitTestSuite struct {
+		Name     string           `xml:"name,attr"`
+		Tests    int              `xml:"tests,attr"`
+		Failures int              `xml:"failures,attr"`
+		Skipped  int              `xml:"skipped,attr"`
+		Cases    []*junitTestCase `xml:"testcase"`
+	}
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		File      string        `xml:"file,attr,omitempty"`
+		Line      int           `xml:"line,attr,omitempty"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+		Skipped   *junitFailure `xml:"skipped,omitempty"`
+	}
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr,omitempty"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// writeJUnit writes the report as JUnit XML, with a test suite per tool & a test case per issue.
+// Errors & warnings are failures, while infos & hints are skipped test cases; a tool without issues has a passing test case.
+func (r *Report) writeJUnit(w io.Writer) error {
+	suites := &junitTestSuites{Name: toolName}
+	byTool := map[string]*junitTestSuite{}
+	for _, tool := range r.Tools {
+		suite := &junitTestSuite{Name: tool}
+		byTool[tool] = suite
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	for _, issue := range r.Issues {
+		suite, ok := byTool[issue.Tool]
+		if !ok {
+			suite = &junitTestSuite{Name: issue.Tool}
+			byTool[issue.Tool] = suite
+			suites.Suites = append(suites.Suites, suite)
+		}
+		name := issue.Rule.ID
+		if loc := issue.location(); loc != "" {
+			name += ": " + loc
+		}
+		tc := &junitTestCase{
+			Name:      name,
+			ClassName: issue.Tool,
+			File:      r.uri(),
+			Line:      issue.Line,
+		}
+		details := &junitFailure{
+			Message: issue.Message,
+			Type:    issue.Severity,
+			Text:    fmt.Sprintf("%s:%d:%d: %s", r.uri(), issue.Line, issue.Column, nonEmpty(issue.Rule.Mitigation, issue.Message)),
+		}
+		switch issue.Severity {
+		case "error", "warning":
+			tc.Failure = details
+			suite.Failures++
+		default:
+			tc.Skipped = details
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, suite := range suites.Suites {
+		if len(suite.Cases) == 0 {
+			suite.Cases = append(suite.Cases, &junitTestCase{Name: suite.Name, ClassName: suite.Name, File: r.uri()})
+		}
+		suite.Tests = len(suite.Cases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,246 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package report renders spec analyses & diffs as CI reports: SARIF, JUnit XML, GitHub & GitLab annotations.
+package report
+
+import (
+	"fmt"
+	"github.com/cisco-developer/api-insights/cli/pkg/model"
+	"github.com/cisco-developer/api-insights/cli/pkg/utils"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatSARIF             = "sarif"
+	FormatJUnit             = "junit"
+	FormatGitHub            = "github"
+	FormatGitLabCodeQuality = "gitlab-codequality"
+)
+
+const (
+	toolName             = "api-insights"
+	toolInformationURI   = "https://developer.cisco.com/docs/api-insights/"
+	diffToolName         = "diff"
+	ruleEndpointDeleted  = "endpoint-deleted"
+	ruleEndpointModified = "endpoint-breaking-change"
+	severityError        = string(model.SeverityNameError)
+)
+
+// Formats contains the supported report formats.
+var Formats = []string{FormatSARIF, FormatJUnit, FormatGitHub, FormatGitLabCodeQuality}
+
+// IsFormat returns whether format is a supported report format.
+func IsFormat(format string) bool {
+	return utils.ContainsString(Formats, format)
+}
+
+// Rule represents the metadata of the rule of an Issue.
+type Rule struct {
+	ID          string
+	Title       string
+	Description string
+	Mitigation  string
+	Severity    string
+}
+
+// Issue represents a finding (or a breaking change) of a tool (i.e. an analyzer, or the differ), located in a spec file.
+type Issue struct {
+	Tool     string
+	Rule     *Rule
+	Severity string
+	Message  string
+	Path     []string
+
+	Line, Column, EndLine, EndColumn int
+}
+
+// Report represents the issues found in a spec file by tools.
+type Report struct {
+	Filename string
+	Tools    []string
+	Issues   []*Issue
+}
+
+// NewAnalysisReport builds the report of res, the analyses of spec file filename (with content data).
+// analyzers provide the metadata of rules (see model.Analyzer.Rules), if listed with their rules.
+func NewAnalysisReport(filename string, data []byte, res *model.SpecAnalysisResponse, analyzers model.AnalyzerList) *Report {
+	rules := map[string]*model.AnalyzerRule{}
+	for _, a := range analyzers {
+		for i := range a.Rules {
+			rules[a.NameID+"/"+a.Rules[i].NameID] = &a.Rules[i]
+		}
+	}
+
+	r := &Report{Filename: filename}
+	for analyzer, analysis := range res.Results {
+		r.Tools = append(r.Tools, string(analyzer))
+		if analysis == nil || analysis.Result == nil {
+			continue
+		}
+		for severity, ruleFindings := range analysis.Result.Findings {
+			if ruleFindings == nil {
+				continue
+			}
+			for ruleNameID, findings := range ruleFindings.Rules {
+				if findings == nil {
+					continue
+				}
+				rule := &Rule{
+					ID:          string(ruleNameID),
+					Title:       string(ruleNameID),
+					Description: findings.Message,
+					Mitigation:  findings.Mitigation,
+					Severity:    string(severity),
+				}
+				if ar, ok := rules[string(analyzer)+"/"+string(ruleNameID)]; ok {
+					rule.Title = nonEmpty(ar.Title, rule.Title)
+					rule.Description = nonEmpty(ar.Description, rule.Description)
+					rule.Mitigation = nonEmpty(ar.Mitigation, rule.Mitigation)
+				}
+				for _, finding := range findings.Data {
+					issue := &Issue{
+						Tool:     string(analyzer),
+						Rule:     rule,
+						Severity: string(severity),
+						Message:  nonEmpty(findings.Message, rule.Title),
+						Path:     finding.Path,
+					}
+					if finding.Range != nil && finding.Range.Start != nil && finding.Range.Start.Line > 0 {
+						issue.Line, issue.Column = finding.Range.Start.Line, finding.Range.Start.Column
+						if finding.Range.End != nil {
+							issue.EndLine, issue.EndColumn = finding.Range.End.Line, finding.Range.End.Column
+						}
+					} else {
+						issue.Line, issue.Column = utils.LocatePath(data, finding.Path)
+					}
+					r.Issues = append(r.Issues, issue)
+				}
+			}
+		}
+	}
+	r.sort()
+	return r
+}
+
+// NewDiffReport builds the report of the breaking changes of res, the diff of spec file filename (with content data)
+// against a previous spec.
+func NewDiffReport(filename string, data []byte, res *model.JSONResult) *Report {
+	r := &Report{Filename: filename, Tools: []string{diffToolName}}
+	if res == nil {
+		return r
+	}
+	deleted := &Rule{
+		ID:          ruleEndpointDeleted,
+		Title:       "Endpoint deleted",
+		Description: "An endpoint of the previous spec was deleted, which breaks backward compatibility.",
+		Mitigation:  "Please deprecate the endpoint before deleting it, or restore it.",
+		Severity:    severityError,
+	}
+	modified := &Rule{
+		ID:          ruleEndpointModified,
+		Title:       "Endpoint changed incompatibly",
+		Description: "An endpoint of the previous spec was changed in a way which breaks backward compatibility.",
+		Mitigation:  "Please make backward compatible changes only, or release a new major API version.",
+		Severity:    severityError,
+	}
+	for _, e := range res.Deleted {
+		r.Issues = append(r.Issues, &Issue{
+			Tool:     diffToolName,
+			Rule:     deleted,
+			Severity: severityError,
+			Message:  nonEmpty(e.Message, fmt.Sprintf("%s %s was deleted", strings.ToUpper(e.Method), e.Path)),
+			Path:     []string{"paths", e.Path, strings.ToLower(e.Method)},
+			Line:     1,
+			Column:   1,
+		})
+	}
+	for _, m := range res.Modified {
+		if !m.Breaking {
+			continue
+		}
+		issue := &Issue{
+			Tool:     diffToolName,
+			Rule:     modified,
+			Severity: severityError,
+			Message:  nonEmpty(m.Message, fmt.Sprintf("%s %s was changed incompatibly", strings.ToUpper(m.Method), m.Path)),
+			Path:     []string{"paths", m.Path, strings.ToLower(m.Method)},
+		}
+		issue.Line, issue.Column = utils.LocatePath(data, issue.Path)
+		r.Issues = append(r.Issues, issue)
+	}
+	r.sort()
+	return r
+}
+
+func (r *Report) sort() {
+	sort.Strings(r.Tools)
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		a, b := r.Issues[i], r.Issues[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		if a.Tool != b.Tool {
+			return a.Tool < b.Tool
+		}
+		return a.Rule.ID < b.Rule.ID
+	})
+}
+
+// Write writes the report to w in format.
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatSARIF:
+		return r.writeSARIF(w)
+	case FormatJUnit:
+		return r.writeJUnit(w)
+	case FormatGitHub:
+		return r.writeGitHub(w)
+	case FormatGitLabCodeQuality:
+		return r.writeGitLabCodeQuality(w)
+	}
+	return fmt.Errorf("unsupported report format: %s", format)
+}
+
+// uri returns the report filename as a relative URI (with forward slashes).
+func (r *Report) uri() string {
+	return filepath.ToSlash(filepath.Clean(r.Filename))
+}
+
+// location returns the location of issue, like "paths./pets.get".
+func (i *Issue) location() string {
+	return strings.Join(i.Path, ".")
+}
+
+// qualifiedRuleID returns the ID of the issue rule, qualified by its tool.
+func (i *Issue) qualifiedRuleID() string {
+	return i.Tool + "/" + i.Rule.ID
+}
+
+func nonEmpty(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
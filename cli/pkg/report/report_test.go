@@ -0,0 +1,149 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cisco-developer/api-insights/cli/pkg/model"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+func readJSON(t *testing.T, filename string, v interface{}) {
+	data, err := os.ReadFile(filepath.Join("testdata", filename))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
+
+// testReports returns the reports of the analyses & the diff of testdata/spec.yaml, by name.
+func testReports(t *testing.T) map[string]*Report {
+	data, err := os.ReadFile("testdata/spec.yaml")
+	require.NoError(t, err)
+
+	var (
+		analysis  model.SpecAnalysisResponse
+		analyzers model.AnalyzerList
+		diff      model.JSONResult
+	)
+	readJSON(t, "analysis.json", &analysis)
+	readJSON(t, "analyzers.json", &analyzers)
+	readJSON(t, "diff.json", &diff)
+
+	return map[string]*Report{
+		"analysis": NewAnalysisReport("specs/../testdata/spec.yaml", data, &analysis, analyzers),
+		"diff":     NewDiffReport("testdata/spec.yaml", data, &diff),
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	extensions := map[string]string{
+		FormatSARIF:             ".sarif.json",
+		FormatJUnit:             ".junit.xml",
+		FormatGitHub:            ".github.txt",
+		FormatGitLabCodeQuality: ".gitlab.json",
+	}
+	for name, r := range testReports(t) {
+		for _, format := range Formats {
+			t.Run(name+"/"+format, func(t *testing.T) {
+				var buf bytes.Buffer
+				require.NoError(t, r.Write(&buf, format))
+
+				golden := filepath.Join("testdata", name+extensions[format])
+				if *update {
+					require.NoError(t, os.WriteFile(golden, buf.Bytes(), 0644))
+				}
+				want, err := os.ReadFile(golden)
+				require.NoError(t, err)
+				assert.Equal(t, string(want), buf.String())
+			})
+		}
+	}
+}
+
+func TestReport_Write_Unsupported(t *testing.T) {
+	assert.Error(t, (&Report{}).Write(&bytes.Buffer{}, "html"))
+}
+
+func TestReport_writeSARIF(t *testing.T) {
+	schema, err := jsonschema.Compile("testdata/sarif-schema-2.1.0.json")
+	require.NoError(t, err)
+
+	for name, r := range testReports(t) {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, r.writeSARIF(&buf))
+
+			var v interface{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &v))
+			assert.NoError(t, schema.Validate(v))
+		})
+	}
+	t.Run("invalid", func(t *testing.T) {
+		var v interface{}
+		require.NoError(t, json.Unmarshal([]byte(`{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"a"}},"results":[{"message":{"text":"a"},"locations":[{"physicalLocation":{"artifactLocation":{"uri":"a.yaml"},"region":{"startLine":0}}}]}]}]}`), &v))
+		assert.Error(t, schema.Validate(v))
+	})
+
+	// Issues map to the regions of their finding range, or of the item at their path, in testdata/spec.yaml.
+	var log sarifLog
+	var buf bytes.Buffer
+	require.NoError(t, testReports(t)["analysis"].writeSARIF(&buf))
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	require.Len(t, log.Runs, 1)
+
+	type located struct {
+		RuleID string
+		Region sarifRegion
+	}
+	var got []located
+	for _, res := range log.Runs[0].Results {
+		require.Len(t, res.Locations, 1)
+		loc := res.Locations[0].PhysicalLocation
+		assert.Equal(t, "testdata/spec.yaml", loc.ArtifactLocation.URI)
+		got = append(got, located{RuleID: res.RuleID, Region: *loc.Region})
+	}
+	assert.Equal(t, []located{
+		{RuleID: "guidelines/oas3-api-servers", Region: sarifRegion{StartLine: 1, StartColumn: 1, EndLine: 15, EndColumn: 30}},
+		{RuleID: "guidelines/operation-description", Region: sarifRegion{StartLine: 7, StartColumn: 5}},
+		{RuleID: "inclusive-language/blacklist", Region: sarifRegion{StartLine: 8, StartColumn: 25, EndLine: 8, EndColumn: 36}},
+		{RuleID: "guidelines/operation-description", Region: sarifRegion{StartLine: 12, StartColumn: 5}},
+	}, got)
+}
+
+func TestReport_writeJUnit(t *testing.T) {
+	for name, r := range testReports(t) {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, r.writeJUnit(&buf))
+
+			var suites junitTestSuites
+			require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+			// Errors & warnings fail, infos & hints are skipped.
+			assert.Equal(t, len(r.Issues), suites.Failures+suites.Skipped)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 Cisco Systems, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type (
+	sarifLog struct {
+		Schema  string      `json:"$schema"`
+		Version string      `json:"version"`
+		Runs    []*sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool      `json:"tool"`
+		Results []*sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string       `json:"name"`
+		InformationURI string       `json:"informationUri"`
+		Rules          []*sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID                   string             `json:"id"`
+		Name                 string             `json:"name,omitempty"`
+		ShortDescription     *sarifMessage      `json:"shortDescription,omitempty"`
+		FullDescription      *sarifMessage      `json:"fullDescription,omitempty"`
+		Help                 *sarifMessage      `json:"help,omitempty"`
+		DefaultConfiguration *sarifRuleDefaults `json:"defaultConfiguration,omitempty"`
+	}
+	sarifRuleDefaults struct {
+		Level string `json:"level"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string           `json:"ruleId"`
+		RuleIndex int              `json:"ruleIndex"`
+		Level     string           `json:"level"`
+		Message   sarifMessage     `json:"message"`
+		Locations []*sarifLocation `json:"locations"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion          `json:"region,omitempty"`
+	}
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+		EndLine     int `json:"endLine,omitempty"`
+		EndColumn   int `json:"endColumn,omitempty"`
+	}
+)
+
+// writeSARIF writes the report as a SARIF 2.1.0 log, e.g. for GitHub code scanning.
+func (r *Report) writeSARIF(w io.Writer) error {
+	run := &sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: toolInformationURI,
+			Rules:          []*sarifRule{},
+		}},
+		Results: []*sarifResult{},
+	}
+	ruleIndexes := map[string]int{}
+	for _, issue := range r.Issues {
+		ruleID := issue.qualifiedRuleID()
+		index, ok := ruleIndexes[ruleID]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndexes[ruleID] = index
+			rule := &sarifRule{
+				ID:                   ruleID,
+				Name:                 issue.Rule.ID,
+				ShortDescription:     &sarifMessage{Text: nonEmpty(issue.Rule.Title, issue.Rule.ID)},
+				DefaultConfiguration: &sarifRuleDefaults{Level: sarifLevel(issue.Rule.Severity)},
+			}
+			if issue.Rule.Description != "" {
+				rule.FullDescription = &sarifMessage{Text: issue.Rule.Description}
+			}
+			if issue.Rule.Mitigation != "" {
+				rule.Help = &sarifMessage{Text: issue.Rule.Mitigation}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		region := &sarifRegion{StartLine: issue.Line, StartColumn: issue.Column}
+		if issue.EndLine >= issue.Line && issue.EndLine > 0 {
+			region.EndLine, region.EndColumn = issue.EndLine, issue.EndColumn
+		}
+		if region.StartLine < 1 {
+			region = &sarifRegion{StartLine: 1}
+		}
+		message := issue.Message
+		if loc := issue.location(); loc != "" {
+			message += " (" + loc + ")"
+		}
+		run.Results = append(run.Results, &sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: index,
+			Level:     sarifLevel(issue.Severity),
+			Message:   sarifMessage{Text: message},
+			Locations: []*sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: r.uri()},
+				Region:           region,
+			}}},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []*sarifRun{run}})
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "error":
+		return "error"
+	case "warning":
+		return "warning"
+	}
+	return "note"
+}
@@ -31,3 +31,13 @@ func Pretty(v interface{}) string {
 
 	return b.String()
 }
+
+// ContainsString returns whether slice contains s.
+func ContainsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}